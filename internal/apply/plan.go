@@ -1,16 +1,65 @@
 package apply
 
-import "fmt"
+import (
+    "fmt"
+    "strings"
+)
 
 type Change struct {
-    Kind   string // Service/Route/Upstream/Target/Plugin
-    Name   string
-    Action string // create/update/delete/none
-    Diff   string // 人类可读的差异
+    Kind   string   `json:"kind" yaml:"kind"`     // Service/Route/Upstream/Target/Plugin
+    Name   string   `json:"name" yaml:"name"`
+    Action string   `json:"action" yaml:"action"` // create/update/delete/none
+    Diff   string   `json:"diff,omitempty" yaml:"diff,omitempty"`   // 人类可读的差异
+    Notes  []string `json:"notes,omitempty" yaml:"notes,omitempty"` // 附加提示（如路由匹配优先级）
+}
+
+// FieldDiff 为单个字段的差异：标量字段给出 old/new，集合字段给出删除/新增的元素
+type FieldDiff struct {
+    Field   string   `json:"field" yaml:"field"`
+    Old     *string  `json:"old,omitempty" yaml:"old,omitempty"`
+    New     *string  `json:"new,omitempty" yaml:"new,omitempty"`
+    Removed []string `json:"removed,omitempty" yaml:"removed,omitempty"`
+    Added   []string `json:"added,omitempty" yaml:"added,omitempty"`
+}
+
+// Fields 将 Diff 文本（"field: old -> new" 或 "field:" 后接 "- x"/"+ x" 行）解析为字段级差异；
+// Diff 需为不含颜色的纯文本
+func (c Change) Fields() []FieldDiff {
+    var out []FieldDiff
+    var cur *FieldDiff
+    for _, line := range strings.Split(c.Diff, "\n") {
+        if line == "" { continue }
+        if cur != nil && (strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "+ ")) {
+            if line[0] == '-' {
+                cur.Removed = append(cur.Removed, line[2:])
+            } else {
+                cur.Added = append(cur.Added, line[2:])
+            }
+            continue
+        }
+        cur = nil
+        field, rest, ok := strings.Cut(line, ":")
+        if !ok || field == "" || strings.ContainsAny(field, " \t") { continue }
+        rest = strings.TrimPrefix(rest, " ")
+        if rest == "" {
+            out = append(out, FieldDiff{Field: field})
+            cur = &out[len(out)-1]
+            continue
+        }
+        if old, nw, ok := strings.Cut(rest, " -> "); ok {
+            out = append(out, FieldDiff{Field: field, Old: &old, New: &nw})
+        }
+    }
+    // 没有元素变化的集合字段（"field: 无变更"）不输出
+    kept := out[:0]
+    for _, f := range out {
+        if f.Old != nil || len(f.Removed) > 0 || len(f.Added) > 0 { kept = append(kept, f) }
+    }
+    return kept
 }
 
 type Plan struct {
-    Items []Change
+    Items []Change `json:"items"`
 }
 
 func (p Plan) String() string {
@@ -23,6 +72,9 @@ func (p Plan) String() string {
         if it.Diff != "" {
             s += it.Diff + "\n"
         }
+        for _, n := range it.Notes {
+            s += "  * " + n + "\n"
+        }
     }
     return s
 }
@@ -0,0 +1,225 @@
+package apply
+
+import (
+    "fmt"
+    "regexp"
+    "sort"
+    "strings"
+)
+
+// RouteRule 为路由匹配与优先级计算所需的最小字段集
+type RouteRule struct {
+    Name          string
+    Hosts         []string
+    Paths         []string
+    Methods       []string
+    Headers       map[string][]string
+    RegexPriority int
+}
+
+// Precedence 描述某条路由在整体路由表中的匹配优先级
+type Precedence struct {
+    Rank       int      // 1 表示最先匹配
+    Total      int
+    Steals     []string // 优先级更高且覆盖其路径的已有路由（新路由将接管其流量）
+    ShadowedBy []string // 优先级更高且覆盖本路由路径的路由（本路由可能收不到流量）
+}
+
+// pathEntry 为路由表中的单条 (route, path) 记录；Kong 按 path 粒度排序
+type pathEntry struct {
+    rule  *RouteRule
+    path  string
+    regex bool
+    re    *regexp.Regexp // regex 路径编译结果；编译失败时为 nil，不参与匹配
+}
+
+// categories 统计路由使用的匹配维度数量（hosts/methods/headers），维度越多越优先
+func categories(r *RouteRule) int {
+    n := 0
+    if len(r.Hosts) > 0 { n++ }
+    if len(r.Methods) > 0 { n++ }
+    if len(r.Headers) > 0 { n++ }
+    return n
+}
+
+// less 近似 Kong traditional 路由器的排序规则：
+// 匹配维度多者优先；regex 路径按 regex_priority 降序且先于前缀路径；前缀路径按长度降序。
+func less(a, b pathEntry) bool {
+    ca, cb := categories(a.rule), categories(b.rule)
+    if ca != cb { return ca > cb }
+    if a.regex != b.regex { return a.regex }
+    if a.regex && a.rule.RegexPriority != b.rule.RegexPriority {
+        return a.rule.RegexPriority > b.rule.RegexPriority
+    }
+    if len(a.path) != len(b.path) { return len(a.path) > len(b.path) }
+    return a.rule.Name < b.rule.Name
+}
+
+// Router 为按优先级排序后的路由表
+type Router struct {
+    entries []pathEntry
+    // Invalid 为无法编译的 regex 路径（如 Go 正则不支持的 PCRE 语法），这些路径不参与匹配
+    Invalid []RegexError
+}
+
+// RegexError 描述路由中无法编译的 regex 路径
+type RegexError struct {
+    Route string
+    Path  string
+    Err   error
+}
+
+func (e RegexError) Error() string {
+    return fmt.Sprintf("route %s 的正则路径 %s 无效：%v", e.Route, e.Path, e.Err)
+}
+
+// NewRouter 依据给定路由构建路由表（未声明 paths 的路由视为匹配 "/"）；regex 路径在此一次性编译
+func NewRouter(rules []RouteRule) *Router {
+    rt := &Router{}
+    for i := range rules {
+        r := &rules[i]
+        paths := r.Paths
+        if len(paths) == 0 { paths = []string{"/"} }
+        for _, p := range paths {
+            e := pathEntry{rule: r, path: p, regex: strings.HasPrefix(p, "~")}
+            if e.regex {
+                re, err := regexp.Compile("^" + strings.TrimPrefix(p, "~"))
+                if err != nil {
+                    rt.Invalid = append(rt.Invalid, RegexError{Route: r.Name, Path: p, Err: err})
+                }
+                e.re = re
+            }
+            rt.entries = append(rt.entries, e)
+        }
+    }
+    sort.SliceStable(rt.entries, func(i, j int) bool { return less(rt.entries[i], rt.entries[j]) })
+    return rt
+}
+
+// Match 返回首个匹配请求的路由名称；无匹配返回空串
+func (rt *Router) Match(host, method, path string, headers map[string]string) string {
+    for _, e := range rt.entries {
+        if !matchHost(e.rule.Hosts, host) || !matchMethod(e.rule.Methods, method) || !matchHeaders(e.rule.Headers, headers) {
+            continue
+        }
+        if e.regex {
+            if e.re == nil || !e.re.MatchString(path) { continue }
+            return e.rule.Name
+        }
+        if strings.HasPrefix(path, e.path) {
+            return e.rule.Name
+        }
+    }
+    return ""
+}
+
+// PrecedenceOf 计算指定路由的优先级，以及与其他路由的抢占/遮蔽关系
+func (rt *Router) PrecedenceOf(name string) Precedence {
+    var pr Precedence
+    byRoute := map[string]bool{}
+    for _, e := range rt.entries {
+        if !byRoute[e.rule.Name] {
+            byRoute[e.rule.Name] = true
+            pr.Total++
+            if e.rule.Name == name && pr.Rank == 0 { pr.Rank = pr.Total }
+        }
+    }
+    steals := map[string]bool{}
+    shadowed := map[string]bool{}
+    for i, a := range rt.entries {
+        if a.rule.Name != name { continue }
+        for j, b := range rt.entries {
+            if b.rule.Name == name || !overlaps(a.rule, b.rule) { continue }
+            switch {
+            case i < j && covers(a, b):
+                steals[b.rule.Name] = true
+            case j < i && covers(b, a):
+                shadowed[b.rule.Name] = true
+            }
+        }
+    }
+    pr.Steals = sortedKeys(steals)
+    pr.ShadowedBy = sortedKeys(shadowed)
+    return pr
+}
+
+// covers 判断 a 的路径是否覆盖 b 的路径（命中 b 的请求也会命中 a）
+func covers(a, b pathEntry) bool {
+    if a.regex || b.regex {
+        return a.regex == b.regex && a.path == b.path
+    }
+    return strings.HasPrefix(b.path, a.path)
+}
+
+// overlaps 判断两条路由的 hosts/methods 是否可能同时命中同一请求
+func overlaps(a, b *RouteRule) bool {
+    return setOverlap(lowerAll(a.Hosts), lowerAll(b.Hosts)) && setOverlap(upperAll(a.Methods), upperAll(b.Methods))
+}
+
+func setOverlap(a, b []string) bool {
+    if len(a) == 0 || len(b) == 0 { return true }
+    for _, x := range a {
+        for _, y := range b {
+            if x == y || strings.HasPrefix(x, "*") || strings.HasPrefix(y, "*") || strings.HasSuffix(x, "*") || strings.HasSuffix(y, "*") { return true }
+        }
+    }
+    return false
+}
+
+func matchHost(hosts []string, host string) bool {
+    if len(hosts) == 0 { return true }
+    host = strings.ToLower(host)
+    if i := strings.LastIndex(host, ":"); i > 0 { host = host[:i] }
+    for _, h := range hosts {
+        h = strings.ToLower(h)
+        switch {
+        case h == host:
+            return true
+        case strings.HasPrefix(h, "*") && strings.HasSuffix(host, strings.TrimPrefix(h, "*")):
+            return true
+        case strings.HasSuffix(h, "*") && strings.HasPrefix(host, strings.TrimSuffix(h, "*")):
+            return true
+        }
+    }
+    return false
+}
+
+func matchMethod(methods []string, method string) bool {
+    if len(methods) == 0 { return true }
+    for _, m := range methods {
+        if strings.EqualFold(m, method) { return true }
+    }
+    return false
+}
+
+func matchHeaders(want map[string][]string, got map[string]string) bool {
+    for k, vs := range want {
+        v, ok := got[strings.ToLower(k)]
+        if !ok { return false }
+        hit := false
+        for _, x := range vs {
+            if strings.EqualFold(x, v) { hit = true; break }
+        }
+        if !hit { return false }
+    }
+    return true
+}
+
+func lowerAll(xs []string) []string {
+    out := make([]string, 0, len(xs))
+    for _, x := range xs { out = append(out, strings.ToLower(x)) }
+    return out
+}
+
+func upperAll(xs []string) []string {
+    out := make([]string, 0, len(xs))
+    for _, x := range xs { out = append(out, strings.ToUpper(x)) }
+    return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+    out := make([]string, 0, len(m))
+    for k := range m { out = append(out, k) }
+    sort.Strings(out)
+    return out
+}
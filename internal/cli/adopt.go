@@ -0,0 +1,117 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "sort"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// applyFromExport 为 apply --from-export：导出当前网关、为全部资源补加 managed-by 标签，
+// 并将导出结果写入 -f 指定的文件作为初始 spec，同时记录 last-applied
+var applyFromExport bool
+
+// adoptRemote 为导出范围内缺少 managed-by 标签的 upstream/target/service/route 补加标签；dryRun 时只统计
+func adoptRemote(ctx context.Context, client *kong.Client, rs *remoteState, dryRun bool) ([]string, error) {
+    tag := managedByTag()
+    var adopted []string
+    ups, err := client.ListUpstreams(ctx)
+    if err != nil { return nil, err }
+    for _, up := range ups {
+        if !rs.upNames[up.Name] { continue }
+        if !kong.HasTag(up.Tags, tag) {
+            adopted = append(adopted, "Upstream "+up.Name)
+            if !dryRun {
+                if _, err := client.PatchUpstream(ctx, up.Name, map[string]any{"tags": append(append([]string{}, up.Tags...), tag)}); err != nil { return adopted, err }
+            }
+        }
+        ts, err := client.ListTargets(ctx, up.Name)
+        if err != nil { return adopted, err }
+        for _, t := range ts {
+            if kong.HasTag(t.Tags, tag) { continue }
+            adopted = append(adopted, "Target "+up.Name+"/"+t.Target)
+            if !dryRun {
+                id := t.ID
+                if id == "" { id = t.Target }
+                if _, err := client.UpdateTargetTags(ctx, up.Name, id, append(append([]string{}, t.Tags...), tag)); err != nil { return adopted, err }
+            }
+        }
+    }
+    names := make([]string, 0, len(rs.svcByName))
+    for n := range rs.svcByName { names = append(names, n) }
+    sort.Strings(names)
+    for _, n := range names {
+        s := rs.svcByName[n]
+        if n == "" || kong.HasTag(s.Tags, tag) { continue }
+        adopted = append(adopted, "Service "+n)
+        if !dryRun {
+            if _, err := client.PatchService(ctx, n, map[string]any{"tags": append(append([]string{}, s.Tags...), tag)}); err != nil { return adopted, err }
+        }
+    }
+    names = names[:0]
+    for n := range rs.rtByName { names = append(names, n) }
+    sort.Strings(names)
+    for _, n := range names {
+        r := rs.rtByName[n]
+        if kong.HasTag(r.Tags, tag) { continue }
+        adopted = append(adopted, "Route "+n)
+        if !dryRun {
+            if _, err := client.PatchRoute(ctx, n, map[string]any{"tags": append(append([]string{}, r.Tags...), tag)}); err != nil { return adopted, err }
+        }
+    }
+    return adopted, nil
+}
+
+// runAdoption 执行 apply --from-export：一次完成从手工维护的网关到声明式管理的接入
+func runAdoption(cmd *cobra.Command) (err error) {
+    if applyFile == "" || applyFile == "-" {
+        return withCode("usage", "", fmt.Errorf("--from-export 需通过 -f 指定要写入的 spec 文件路径，例：-f kong.yaml"))
+    }
+    path := expandPath(applyFile)
+    if _, serr := os.Stat(path); serr == nil {
+        return withCode("usage", "", fmt.Errorf("%s 已存在：--from-export 用于生成初始 spec，不会覆盖已有文件", applyFile))
+    }
+    cfg, err := clientConfig(30 * time.Second)
+    if err != nil {
+        return err
+    }
+    cfg.MaxCalls = budgetMaxCalls
+    client := kong.NewClient(cfg)
+    ctx, cancel := budgetContext(cmd.Context(), cfg.Timeout)
+    defer cancel()
+    start := time.Now()
+    defer func() { err = finishBudget(cmd, ctx, client, start, err) }()
+
+    rs, err := exportRemote(ctx, client, true)
+    if err != nil {
+        return err
+    }
+    spec := rs.spec()
+    adopted, err := adoptRemote(ctx, client, rs, dryRun)
+    if dryRun {
+        if err != nil {
+            return err
+        }
+        for _, a := range adopted { cmd.Printf("  + %s（添加标签 %s）\n", a, managedByTag()) }
+        cmd.Printf("[dry-run] 将接管 %d 个资源（upstreams=%d services=%d routes=%d），并写入 %s（未实际变更）\n", len(adopted), len(spec.Upstreams), len(spec.Services), len(spec.Routes), applyFile)
+        return nil
+    }
+    if err != nil {
+        return fmt.Errorf("补加 %s 标签失败（已处理 %d 项，可重新执行）：%w", managedByTag(), len(adopted), err)
+    }
+    out, err := marshalExportYAML(spec, true)
+    if err != nil {
+        return err
+    }
+    if err := writeTextFile(path, out, 0644); err != nil {
+        return fmt.Errorf("写入文件失败：%w", err)
+    }
+    recordLastApplied(cmd, spec)
+    PrintSuccess(cmd, "已接管 %d 个资源（补加 %s 标签），初始 spec 已写入：%s（upstreams=%d services=%d routes=%d）", len(adopted), managedByTag(), applyFile, len(spec.Upstreams), len(spec.Services), len(spec.Routes))
+    PrintInfo(cmd, "提交该文件后即可声明式管理：kongctl sync -f %s --dry-run --diff", applyFile)
+    return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "io"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyAgainst 为 apply --dry-run --against：以 export 导出的快照文件代替网关的当前状态计算计划（离线评审）
+var applyAgainst string
+
+// againstClient 将快照按 apply 的方式写入进程内的 MemoryAdmin（新建资源同样带上下文标签与 managed-by 标签），
+// 返回在其上只读规划的客户端；规划过程不访问 Admin API
+func againstClient(cmd *cobra.Command, ctx context.Context, cfg kong.Config) (*kong.Client, error) {
+    content, err := readSpecFile(cmd, applyAgainst)
+    if err != nil {
+        return nil, err
+    }
+    snap, err := parseApplySpec(content)
+    if err != nil {
+        return nil, fmt.Errorf("解析快照 %s 失败：%w", applyAgainst, err)
+    }
+    mem := kong.NewMemoryAdmin()
+    cfg.AdminURL, cfg.Workspace, cfg.Token, cfg.TokenSource, cfg.Transport = "http://snapshot", "", "", nil, mem
+    seedCfg := cfg
+    seedCfg.MaxCalls, seedCfg.Tags, seedCfg.CreateTags = 0, defaultTags(), []string{managedByTag()}
+    if err := memoryApply(cmd, ctx, seedCfg, snap); err != nil {
+        return nil, fmt.Errorf("载入快照 %s 失败：%w", applyAgainst, err)
+    }
+    mem.SetReadOnly(true)
+    PrintInfo(cmd, "--against：基于快照 %s 规划（upstreams=%d services=%d routes=%d consumer_groups=%d consumers=%d certificates=%d vaults=%d key_sets=%d），不访问网关", applyAgainst, len(snap.Upstreams), len(snap.Services), len(snap.Routes), len(snap.ConsumerGroups), len(snap.Consumers), len(snap.Certificates), len(snap.Vaults), len(snap.KeySets))
+    return kong.NewClient(cfg), nil
+}
+
+// memoryApply 静默地按 apply 的方式（覆盖更新、不删除）将 spec 写入 cfg.Transport 指定的 MemoryAdmin
+func memoryApply(cmd *cobra.Command, ctx context.Context, cfg kong.Config, spec applySpec) error {
+    out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+    cmd.SetOut(io.Discard)
+    cmd.SetErr(io.Discard)
+    prevDry, prevOverwrite, prevPrune, prevPruneTargets, prevLast, prevCheckpoint := dryRun, applyOverwrite, applyPrune, applyPruneTargets, applyLastApplied, applyCheckpoint
+    dryRun, applyOverwrite, applyPrune, applyPruneTargets, applyLastApplied, applyCheckpoint = false, true, false, false, nil, nil
+    err := runApplyPhase(cmd, ctx, kong.NewClient(cfg), spec, &aplan.Plan{})
+    dryRun, applyOverwrite, applyPrune, applyPruneTargets, applyLastApplied, applyCheckpoint = prevDry, prevOverwrite, prevPrune, prevPruneTargets, prevLast, prevCheckpoint
+    cmd.SetOut(out)
+    cmd.SetErr(errOut)
+    return err
+}
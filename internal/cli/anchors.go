@@ -0,0 +1,89 @@
+package cli
+
+import (
+    "bytes"
+    "fmt"
+
+    "gopkg.in/yaml.v3"
+)
+
+// anchorKeys 为导出时尝试合并为锚点的字段：相同值第二次出现起改为别名引用
+var anchorKeys = map[string]bool{
+    "backend": true,
+    "tags":    true,
+    "targets": true,
+    "headers": true,
+}
+
+// marshalExportYAML 序列化导出结构；anchors 为 true 时将重复的 backend/tags 等结构提取为 YAML 锚点（&name / *name）
+func marshalExportYAML(v any, anchors bool) ([]byte, error) {
+    if !anchors {
+        return yaml.Marshal(v)
+    }
+    var doc yaml.Node
+    if err := doc.Encode(v); err != nil {
+        return nil, err
+    }
+    seen := map[string]*yaml.Node{}
+    counter := map[string]int{}
+    var walk func(n *yaml.Node)
+    walk = func(n *yaml.Node) {
+        if n.Kind == yaml.MappingNode {
+            for i := 0; i+1 < len(n.Content); i += 2 {
+                key, val := n.Content[i], n.Content[i+1]
+                if anchorKeys[key.Value] && !isEmptyNode(val) {
+                    sig := key.Value + "\x00" + nodeSignature(val)
+                    if first, ok := seen[sig]; ok {
+                        if first.Anchor == "" {
+                            counter[key.Value]++
+                            first.Anchor = fmt.Sprintf("%s-%d", key.Value, counter[key.Value])
+                        }
+                        n.Content[i+1] = &yaml.Node{Kind: yaml.AliasNode, Value: first.Anchor, Alias: first}
+                        continue
+                    }
+                    seen[sig] = val
+                }
+                walk(val)
+            }
+            return
+        }
+        for _, c := range n.Content {
+            walk(c)
+        }
+    }
+    walk(&doc)
+    var buf bytes.Buffer
+    enc := yaml.NewEncoder(&buf)
+    enc.SetIndent(4)
+    if err := enc.Encode(&doc); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// isEmptyNode 判断节点是否只包含零值（空集合、空字符串、0、null），此类节点不值得提取锚点
+func isEmptyNode(n *yaml.Node) bool {
+    switch n.Kind {
+    case yaml.ScalarNode:
+        return n.Value == "" || n.Value == "0" || n.Tag == "!!null"
+    case yaml.MappingNode:
+        for i := 1; i < len(n.Content); i += 2 {
+            if !isEmptyNode(n.Content[i]) {
+                return false
+            }
+        }
+        return true
+    case yaml.SequenceNode:
+        return len(n.Content) == 0
+    }
+    return false
+}
+
+// nodeSignature 以规范化序列化结果作为结构相等的判定依据
+func nodeSignature(n *yaml.Node) string {
+    out, err := yaml.Marshal(n)
+    if err != nil {
+        return ""
+    }
+    return string(out)
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// annotationTagPrefix 为注解在 Kong 标签中的前缀：annotations.owner: team-x 保存为标签 meta:owner=team-x
+const annotationTagPrefix = "meta:"
+
+// splitAnnotations 将标签拆分为普通标签与 meta: 注解
+func splitAnnotations(tags []string) ([]string, map[string]string) {
+    var rest []string
+    var ann map[string]string
+    for _, t := range tags {
+        kv, ok := strings.CutPrefix(t, annotationTagPrefix)
+        if !ok {
+            rest = append(rest, t)
+            continue
+        }
+        k, v, _ := strings.Cut(kv, "=")
+        if ann == nil { ann = map[string]string{} }
+        ann[k] = v
+    }
+    return rest, ann
+}
+
+// annotationTagList 按 key 排序返回注解对应的标签
+func annotationTagList(ann map[string]string) []string {
+    keys := make([]string, 0, len(ann))
+    for k := range ann { keys = append(keys, k) }
+    sort.Strings(keys)
+    out := make([]string, 0, len(keys))
+    for _, k := range keys { out = append(out, annotationTagPrefix+k+"="+ann[k]) }
+    return out
+}
+
+// withAnnotations 以 ann 替换 tags 中已有的 meta: 标签，其余标签保持不变
+func withAnnotations(tags []string, ann map[string]string) []string {
+    rest, _ := splitAnnotations(tags)
+    return append(append([]string{}, rest...), annotationTagList(ann)...)
+}
+
+// formatAnnotations 以 k=v 形式（按 key 排序）展示注解
+func formatAnnotations(ann map[string]string) string {
+    out := annotationTagList(ann)
+    for i := range out { out[i] = strings.TrimPrefix(out[i], annotationTagPrefix) }
+    return strings.Join(out, ", ")
+}
+
+// annotationDiff 返回远程注解与期望注解的差异行；一致时返回空
+func annotationDiff(curTags []string, ann map[string]string) string {
+    _, cur := splitAnnotations(curTags)
+    if formatAnnotations(cur) == formatAnnotations(ann) { return "" }
+    return fmt.Sprintf("annotations: {%s} -> {%s}\n", formatAnnotations(cur), formatAnnotations(ann))
+}
+
+// checkAnnotations 校验注解 key/value 能否保存为 Kong 标签（可打印 ASCII，不含空白、逗号与斜杠）
+func checkAnnotations(spec applySpec) error {
+    check := func(where string, ann map[string]string) error {
+        for k, v := range ann {
+            if k == "" { return fmt.Errorf("%s.annotations 的 key 不能为空", where) }
+            for _, s := range []string{k, v} {
+                for _, r := range s {
+                    if r <= ' ' || r > '~' || r == ',' || r == '/' {
+                        return fmt.Errorf("%s.annotations.%s 含有无法保存为 Kong 标签的字符 %q（仅支持可打印 ASCII，不含空白、逗号与斜杠）", where, k, r)
+                    }
+                }
+            }
+            if strings.Contains(k, "=") { return fmt.Errorf("%s.annotations 的 key 不能包含 '='：%s", where, k) }
+        }
+        return nil
+    }
+    for _, s := range spec.Services {
+        if err := check("services["+s.Name+"]", s.Annotations); err != nil { return err }
+    }
+    for _, r := range spec.Routes {
+        name := r.Name
+        if name == "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        if err := check("routes["+name+"]", r.Annotations); err != nil { return err }
+    }
+    return nil
+}
+
+// syncServiceAnnotations 将 services[].annotations 写入 Service 标签；已存在的 Service 需 --overwrite 才更新
+func syncServiceAnnotations(cmd *cobra.Command, ctx context.Context, client *kong.Client, s applyService, created bool) error {
+    if s.Annotations == nil {
+        return nil
+    }
+    cur, ok, err := client.GetService(ctx, s.Name)
+    if err != nil || !ok {
+        return err
+    }
+    if annotationDiff(cur.Tags, s.Annotations) == "" {
+        return nil
+    }
+    if !created && !applyOverwrite {
+        PrintWarn(cmd, "检测到 Service 注解变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
+        return nil
+    }
+    if _, err := client.UpdateServiceTags(ctx, s.Name, withAnnotations(cur.Tags, s.Annotations)); err != nil {
+        return err
+    }
+    PrintSuccess(cmd, "已更新 Service 注解：%s（%s）", s.Name, formatAnnotations(s.Annotations))
+    return nil
+}
+
+// ownerSummary 统计 spec 中 routes/services 的 owner 注解，用于计划汇总
+func ownerSummary(spec applySpec) string {
+    owners := map[string]int{}
+    missing, annotated := 0, false
+    add := func(ann map[string]string) {
+        if ann != nil { annotated = true }
+        if o := ann["owner"]; o != "" { owners[o]++ } else { missing++ }
+    }
+    for _, s := range spec.Services { add(s.Annotations) }
+    for _, r := range spec.Routes { add(r.Annotations) }
+    if !annotated {
+        return ""
+    }
+    names := make([]string, 0, len(owners))
+    for o := range owners { names = append(names, o) }
+    sort.Strings(names)
+    parts := make([]string, 0, len(names)+1)
+    for _, o := range names { parts = append(parts, fmt.Sprintf("%s %d", o, owners[o])) }
+    if missing > 0 { parts = append(parts, fmt.Sprintf("未标注 %d", missing)) }
+    return strings.Join(parts, "，")
+}
@@ -2,10 +2,13 @@ package cli
 
 import (
     "context"
+    "bytes"
     "fmt"
+    "io"
     "os"
     "path/filepath"
     "strings"
+    "sync"
     "time"
 
     "github.com/spf13/cobra"
@@ -17,19 +20,62 @@ import (
 
 // applySpec 定义通过文件批量创建的资源结构
 type applySpec struct {
+    // KongctlFormat 为格式版本（见 spec.go）；旧文件可缺省，export 总是写入当前版本
+    KongctlFormat string      `yaml:"kongctl_format,omitempty" json:"kongctl_format,omitempty"`
+    // Include 为需合并的片段文件（支持通配，相对于当前文件所在目录），见 include.go
+    Include   []string        `yaml:"include,omitempty" json:"include,omitempty"`
+    // CommonTags 为本次 apply 创建/更新的 service/route/upstream 都附加的标签（与 --add-tags 合并）
+    CommonTags []string       `yaml:"common_tags,omitempty" json:"common_tags,omitempty"`
     Upstreams []applyUpstream `yaml:"upstreams" json:"upstreams"`
     Services  []applyService  `yaml:"services"  json:"services"`
     Routes    []applyRoute    `yaml:"routes"    json:"routes"`
+    ConsumerGroups []applyConsumerGroup `yaml:"consumer_groups,omitempty" json:"consumer_groups,omitempty"`
+    Consumers []applyConsumer `yaml:"consumers" json:"consumers"`
+    Certificates []applyCertificate `yaml:"certificates,omitempty" json:"certificates,omitempty"`
+    Vaults []applyVault `yaml:"vaults,omitempty" json:"vaults,omitempty"`
+    KeySets []applyKeySet `yaml:"key_sets,omitempty" json:"key_sets,omitempty"`
 }
 
 type applyUpstream struct {
     Name    string         `yaml:"name" json:"name"`
+    ClientCertificate string `yaml:"client_certificate,omitempty" json:"client_certificate,omitempty"` // 证书 ID：后端要求 mTLS 时 Kong 出示的客户端证书
+    HostHeader   string    `yaml:"host_header,omitempty" json:"host_header,omitempty"`              // 代理请求与健康检查探测使用的 Host 头
+    Healthchecks *applyHealthchecks `yaml:"healthchecks,omitempty" json:"healthchecks,omitempty"`
     Targets []applyTarget  `yaml:"targets" json:"targets"`
+    IgnoreFields []string  `yaml:"ignore_fields,omitempty" json:"ignore_fields,omitempty"` // 交由其他工具管理、apply 不比较也不覆盖的字段
+    source  string         // 来源文件（使用 include 时记录，用于冲突报告）
+}
+
+// applyHealthchecks 为 upstream 的健康检查配置；只管理声明的字段，未声明的沿用远程（或 Kong 默认）值
+type applyHealthchecks struct {
+    Active *applyActiveHealthcheck `yaml:"active,omitempty" json:"active,omitempty"`
+}
+
+type applyActiveHealthcheck struct {
+    Type                   string `yaml:"type,omitempty" json:"type,omitempty"` // http/https/tcp/grpc/grpcs
+    HTTPPath               string `yaml:"http_path,omitempty" json:"http_path,omitempty"`
+    HTTPSVerifyCertificate *bool  `yaml:"https_verify_certificate,omitempty" json:"https_verify_certificate,omitempty"`
+    HTTPSSni               string `yaml:"https_sni,omitempty" json:"https_sni,omitempty"`
 }
 
 type applyTarget struct {
-    Target string `yaml:"target" json:"target"` // host:port
-    Weight int    `yaml:"weight" json:"weight"`
+    Target string   `yaml:"target" json:"target"` // host:port
+    Weight int      `yaml:"weight" json:"weight"`
+    Zone   string   `yaml:"zone,omitempty" json:"zone,omitempty"` // 所在区域，写入标签 zone=<区域>，供 upstream rebalance 按区域分配权重
+    Tags   []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// tagList 返回写入 Kong 的 target 标签（含 zone=<区域>）
+func (t applyTarget) tagList() []string {
+    tags := append([]string{}, t.Tags...)
+    if t.Zone != "" && kong.TargetZone(tags) == "" { tags = append(tags, "zone="+t.Zone) }
+    return tags
+}
+
+// zoneMatches 判断远程 target 的 zone= 标签是否与声明一致（未声明区域时不比较）
+func (t applyTarget) zoneMatches(cur kong.Target) bool {
+    zone := kong.TargetZone(t.tagList())
+    return zone == "" || kong.TargetZone(cur.Tags) == zone
 }
 
 type applyService struct {
@@ -44,6 +90,9 @@ type applyService struct {
     ReadTimeout    int     `yaml:"read_timeout" json:"read_timeout"`
     WriteTimeout   int     `yaml:"write_timeout" json:"write_timeout"`
     Targets  []applyTarget `yaml:"targets" json:"targets"` // 可选：便捷在此 service 的 upstream 下创建 targets
+    Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"` // 运维元数据，保存为 meta:<key>=<value> 标签
+    IgnoreFields []string `yaml:"ignore_fields,omitempty" json:"ignore_fields,omitempty"`
+    source   string
 }
 
 type applyRoute struct {
@@ -63,10 +112,14 @@ type applyRoute struct {
     Headers map[string][]string     `yaml:"headers" json:"headers"`
     Snis    []string                `yaml:"snis" json:"snis"`
     Tags    []string                `yaml:"tags" json:"tags"`
+    Annotations map[string]string   `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+    IgnoreFields []string           `yaml:"ignore_fields,omitempty" json:"ignore_fields,omitempty"`
+    Tests   []routeTest             `yaml:"tests,omitempty" json:"tests,omitempty"` // apply --verify 在变更后经 proxy 发送的冒烟测试
     // 简写支持：仅给出 route 时，自动创建同名前缀的 service/upstream
     ServiceName  string        `yaml:"service_name" json:"service_name"`
     UpstreamName string        `yaml:"upstream_name" json:"upstream_name"`
     Backend      routeBackend  `yaml:"backend" json:"backend"`
+    source       string
 }
 
 type routeBackend struct {
@@ -84,6 +137,15 @@ type autoRouteInfo struct {
     Targets      []applyTarget
 }
 
+// autoBackendNames 返回 route 简写自动派生的 service/upstream 名称
+func autoBackendNames(r applyRoute, routeName string) (svcName, upName string) {
+    svcName = r.ServiceName
+    if svcName == "" { svcName = routeName + "-service" }
+    upName = r.UpstreamName
+    if upName == "" { upName = routeName + "-upstream" }
+    return svcName, upName
+}
+
 // sliceSetEqual 判断两个字符串切片（作为集合）是否相等
 func sliceSetEqual(a, b []string) bool {
     if len(a) != len(b) { return false }
@@ -98,39 +160,83 @@ func sliceSetEqual(a, b []string) bool {
 }
 
 // mapStringSliceEqual 比较 map[string][]string （值作为集合，不计顺序/重复）
+// 键与值均按 Kong headers 语义不区分大小写（规则同 kong.NormalizeHeaders）。
 func mapStringSliceEqual(a, b map[string][]string) bool {
+    a, b = kong.NormalizeHeaders(a), kong.NormalizeHeaders(b)
     if len(a) != len(b) { return false }
     for k, va := range a {
         vb, ok := b[k]
         if !ok { return false }
-        if !sliceSetEqual(va, vb) { return false }
+        if !sliceSetEqual(toLower(va), toLower(vb)) { return false }
     }
     return true
 }
 
-// diffMapStringSlice 生成差异文本（简单展示键级对比与新增/删除）
-func diffMapStringSlice(field string, cur, want map[string][]string) string {
-    var sb strings.Builder
-    sb.WriteString(field+":\n")
-    // 删除的键
-    for k := range cur {
-        if _, ok := want[k]; !ok {
-            sb.WriteString(colorWarn("- "+k)+"\n")
+// loadApplySpec 读取并解析 apply 文件，支持三种顶层结构：
+// 1) 对象：{upstreams/services/routes}
+// 2) 列表：[...] 视为 routes 简写
+// 3) 单对象：{name, paths, ...} 视为单个 route 简写
+// 仅负责解析；重复定义与匹配冲突由 apply/validate 各自调用 checkSpecConflicts 检测
+func loadApplySpec(cmd *cobra.Command, file string) (applySpec, error) {
+    content, err := readSpecFile(cmd, file)
+    if err != nil {
+        return applySpec{}, err
+    }
+    spec, err := parseApplySpec(content)
+    if err != nil {
+        return applySpec{}, err
+    }
+    if spec, err = resolveIncludes(spec, file); err != nil {
+        return applySpec{}, err
+    }
+    return spec, nil
+}
+
+// readSpecFile 读取 spec 内容；file 为 "-" 时从标准输入读取（便于接入 helm template、envsubst 等生成器）
+func readSpecFile(cmd *cobra.Command, file string) ([]byte, error) {
+    if file == "-" {
+        content, err := io.ReadAll(cmd.InOrStdin())
+        if err != nil {
+            return nil, fmt.Errorf("读取标准输入失败：%w", err)
+        }
+        if len(bytes.TrimSpace(content)) == 0 {
+            return nil, fmt.Errorf("标准输入为空：-f - 需要通过管道传入 YAML/JSON")
         }
+        return normalizeText(content), nil
     }
-    // 新增或变更
-    for k := range want {
-        vcur, ok := cur[k]
-        if !ok {
-            sb.WriteString(colorSuccess("+ "+k+": "+strings.Join(want[k], ", "))+"\n")
-            continue
+    content, err := os.ReadFile(expandPath(file))
+    if err != nil {
+        return nil, fmt.Errorf("读取文件失败：%w", err)
+    }
+    return normalizeText(content), nil
+}
+
+func parseApplySpec(content []byte) (applySpec, error) {
+    var spec applySpec
+    errTop := yaml.Unmarshal(content, &spec)
+    if errTop == nil {
+        if err := checkSpecFormat(spec.KongctlFormat); err != nil {
+            return applySpec{}, err
         }
-        if !sliceSetEqual(vcur, want[k]) {
-            sb.WriteString(colorWarn("- "+k+": "+strings.Join(vcur, ", "))+"\n")
-            sb.WriteString(colorSuccess("+ "+k+": "+strings.Join(want[k], ", "))+"\n")
+    }
+    if errTop != nil || (len(spec.Include) == 0 && len(spec.Upstreams) == 0 && len(spec.Services) == 0 && len(spec.Routes) == 0 && len(spec.ConsumerGroups) == 0 && len(spec.Consumers) == 0 && len(spec.Certificates) == 0 && len(spec.Vaults) == 0 && len(spec.KeySets) == 0) {
+        // 尝试以 routes 列表解析
+        var routes []applyRoute
+        if errList := yaml.Unmarshal(content, &routes); errList == nil && len(routes) > 0 {
+            spec.Routes = routes
+        } else {
+            // 尝试以单个 route 解析
+            var r applyRoute
+            if errOne := yaml.Unmarshal(content, &r); errOne == nil && (r.Name != "" || len(r.Paths) > 0 || len(r.Hosts) > 0 || len(r.Methods) > 0 || r.Service != "" || len(r.Backend.Targets) > 0 || r.Backend.Protocol != "" || r.Backend.Port != 0 || r.Backend.Path != "") {
+                spec.Routes = []applyRoute{r}
+            } else if errTop != nil {
+                return applySpec{}, fmt.Errorf("解析文件失败（支持 YAML/JSON）。可提供顶层对象 {routes: [...]}，或直接提供 route 列表/单个 route。原始错误：%w", errTop)
+            } else {
+                return applySpec{}, fmt.Errorf("配置为空或未识别到任何资源，请提供 routes/ services/ upstreams 或使用简写列表")
+            }
         }
     }
-    return sb.String()
+    return spec, nil
 }
 
 var (
@@ -139,6 +245,10 @@ var (
     applyASCII   bool
     applyCompact bool
     applyOverwrite bool
+    applyAccessLog string
+    applyDetailedExit bool
+    applyNoPrefetch bool
+    applyAddTags []string
 )
 
 var applyCmd = &cobra.Command{
@@ -151,309 +261,606 @@ kongctl apply -f examples/apply.yaml
 # 简写：顶层为 routes 列表，仅定义路由并自动创建 service/upstream
 kongctl apply -f examples/route-simple.yaml
 
+# 从标准输入读取（配合 envsubst / helm template 等生成器）
+envsubst < spec.tpl.yaml | kongctl apply -f - --dry-run
+
 # 预览计划（彩色、分层显示），并显示字段级差异
 kongctl apply -f examples/route-simple.yaml --dry-run --diff
 
+# 以资源 YAML 的统一 diff 展示更新（便于评审较大的变更）
+kongctl apply -f spec.yaml --dry-run --diff --diff-format unified
+
 # 使用 ASCII 与紧凑模式（隐藏无变化项）
-kongctl apply -f examples/route-simple.yaml --dry-run --ascii --compact`,
-    RunE: func(cmd *cobra.Command, args []string) error {
-        if applyFile == "" {
-            return fmt.Errorf("必须通过 -f/--file 指定配置文件")
+kongctl apply -f examples/route-simple.yaml --dry-run --ascii --compact
+
+# 声明式同步：更新差异并删除文件中已移除的受管资源（先 dry-run 预览）
+kongctl apply -f spec.yaml --overwrite --prune --dry-run
+
+# 以 Go 模板渲染 spec，按环境取值（values 文件可多次指定）
+kongctl apply -f routes.tpl.yaml --values values/common.yaml --values values/prod.yaml --dry-run
+
+# 只同步大文件中名称以 user- 开头的路由
+kongctl apply -f spec.yaml --select kind=route,name=user-* --dry-run
+
+# 两阶段执行：先保存计划供评审，再按计划执行（远程状态变化时拒绝）
+kongctl apply -f spec.yaml --overwrite --dry-run --out plan.json
+kongctl apply --plan plan.json
+
+# CI 中检测漂移：无变更退出码 0，存在待执行变更为 2，出错为 1
+kongctl apply -f spec.yaml --dry-run --detailed-exitcode
+
+# 按近 24 小时各路由的请求数（Prometheus）估算计划对流量去向的影响
+kongctl apply -f spec.yaml --dry-run --prometheus-url http://prometheus:9090 --prometheus-window 24h
+
+# 以 JSON 输出计划，供 CI 在合并请求中发布计划评论
+kongctl apply -f spec.yaml --dry-run --output json > plan.json
+
+# 在 GitHub Actions / GitLab 合并请求中以注解展示待执行变更
+kongctl apply -f spec.yaml --dry-run --output github
+kongctl apply -f spec.yaml --dry-run --output gitlab > gl-code-quality.json
+
+# 先展示计划，确认后再执行（CI 中可加 --yes 跳过）
+kongctl apply -f spec.yaml --overwrite --confirm
+
+# 限制生产环境的调用次数与整体时长
+kongctl apply -f spec.yaml --max-api-calls 300 --deadline 1m
+
+# 作为 GitOps 代理持续运行：每 30s 重新读取目录下的 spec 并调和漂移
+kongctl apply -f config/ --watch --interval 30s
+
+# 同一 spec 下发到多个集群，逐个报告结果
+kongctl apply -f spec.yaml --overwrite --context prod-dc1,prod-dc2`,
+    RunE: func(cmd *cobra.Command, args []string) (err error) {
+        if !fanoutActive {
+            contexts, err := targetContexts(cmd)
+            if err != nil {
+                return err
+            }
+            if len(contexts) > 0 {
+                return runFanout(cmd, args, contexts)
+            }
+        }
+        if applyFromExport {
+            if applyPlanFile != "" || len(applySelect) > 0 || renderEnabled() || applyPrune || len(applyOverlays) > 0 {
+                return withCode("usage", "", fmt.Errorf("--from-export 不能与 --plan/--select/--template/--prune/--overlay 同时使用（spec 由远程导出生成）"))
+            }
+            return runAdoption(cmd)
+        }
+        if applyWatch && !watchActive {
+            return runWatch(cmd, args)
+        }
+        var pf *planFile
+        var spec applySpec
+        if applyPlanFile != "" {
+            // 从计划文件执行：spec 与 --overwrite/--prune 均以规划时为准
+            if applyFile != "" || len(applySelect) > 0 || renderEnabled() {
+                return fmt.Errorf("--plan 不能与 -f/--select/--template 同时使用（计划文件已包含展开后的 spec）")
+            }
+            if applyPlanOut != "" {
+                return fmt.Errorf("--plan 不能与 --out 同时使用")
+            }
+            if pf, err = loadPlanFile(applyPlanFile); err != nil {
+                return err
+            }
+            spec, applyOverwrite, applyPrune, applyPruneTargets, applyIgnoreFields, applyThreeWay, applyAddTags = pf.Spec, pf.Overwrite, pf.Prune, pf.PruneTargets, pf.IgnoreFields, pf.ThreeWay, pf.AddTags
+            applyNamePrefix, applyNameSuffix = pf.NamePrefix, pf.NameSuffix
+        } else if spec, err = loadApplyInput(cmd); err != nil {
+            return err
+        }
+        if applyPlanOut != "" && !dryRun {
+            return fmt.Errorf("--out 需与 --dry-run 一起使用")
+        }
+        if applyResume && dryRun {
+            return withCode("usage", "", fmt.Errorf("--resume 不能与 --dry-run 同时使用"))
+        }
+        if applyAccessLog != "" && applyPrometheusURL != "" {
+            return withCode("usage", "", fmt.Errorf("--access-log 与 --prometheus-url 只能指定一个"))
+        }
+        if applyPrometheusQuery != "" && applyPrometheusURL == "" {
+            return withCode("usage", "", fmt.Errorf("--prometheus-query 需与 --prometheus-url 一起使用"))
+        }
+        if applyDetailedExit && !dryRun {
+            return withCode("usage", "", fmt.Errorf("--detailed-exitcode 需与 --dry-run 一起使用"))
+        }
+        if applyAgainst != "" && !dryRun {
+            return withCode("usage", "", fmt.Errorf("--against 需与 --dry-run 一起使用（计划基于离线快照，不能据此执行）"))
+        }
+        if applyAgainst != "" && applyServerValidate {
+            return withCode("usage", "", fmt.Errorf("--server-validate 需要访问网关，不能与 --against 同时使用"))
+        }
+        if applyUpdateOnly {
+            // 只更新已有资源：差异需覆盖才能生效
+            applyOverwrite = true
+        }
+        if applyWait && applyWaitTimeout <= 0 {
+            return withCode("usage", "", fmt.Errorf("--wait-timeout 必须大于 0"))
+        }
+        if err := checkIgnoreFlag(); err != nil {
+            return err
+        }
+        if err := checkNameAffixes(); err != nil {
+            return err
+        }
+        if err := checkPathPrefix(); err != nil {
+            return err
+        }
+        if err := checkDiffFormat(); err != nil {
+            return err
+        }
+        if format := planOutputFormat(); format != "" && dryRun {
+            // 计划以 JSON 或 CI 注解写入标准输出，规划过程中的提示信息不再输出
+            applyOutputPlan = aplan.Plan{}
+            planOut, restore := silencePlanOutput(cmd)
+            defer restore()
+            defer func() {
+                if err != nil { return }
+                switch format {
+                case "github":
+                    err = writePlanGitHub(planOut, applyOutputPlan)
+                case "gitlab":
+                    err = writePlanGitLab(planOut, applyOutputPlan)
+                default:
+                    err = writePlanJSON(planOut, applyOutputPlan)
+                }
+            }()
         }
 
-        content, err := os.ReadFile(applyFile)
+        cfg, err := clientConfig(15 * time.Second)
         if err != nil {
-            return fmt.Errorf("读取文件失败：%w", err)
+            return err
+        }
+        cfg.MaxCalls = budgetMaxCalls
+        cfg.Tags = applyTags(spec)
+        // 新建的资源均带上 managed-by 标签，--prune 与 sync 仅删除带此标签的资源
+        cfg.CreateTags = []string{managedByTag()}
+        if applyPrune && !syncScoped {
+            // --prune 同时接管文件中声明的已有资源（sync 只调和已带标签的资源，不补加）
+            cfg.Tags = append(cfg.Tags, managedByTag())
+        }
+        client := kong.NewClient(cfg)
+        confirm := applyConfirmEnabled() && !dryRun
+        if !confirm {
+            // 先于执行时限与 apply 锁确认，等待输入的时间不占用二者；--confirm 时在展示计划后确认
+            if err := confirmPrune(cmd, -1); err != nil {
+                return err
+            }
+        }
+        ctx, cancel := budgetContext(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        start := time.Now()
+        defer func() {
+            err = finishBudget(cmd, ctx, client, start, err)
+            watchWrites = len(client.Writes())
+        }()
+        if applyAgainst != "" {
+            if client, err = againstClient(cmd, ctx, cfg); err != nil {
+                return err
+            }
+        }
+        wsMissing := false
+        if applyCreateWorkspace && applyAgainst == "" {
+            // 先于 apply 锁：锁保存在 workspace 内
+            if wsMissing, err = ensureApplyWorkspace(cmd, ctx, cfg); err != nil {
+                return err
+            }
+        }
+        if !dryRun && !applyNoLock && !confirm {
+            // --confirm 时由 confirmAndApply 在确认后加锁，避免等待输入期间一直持有锁
+            release, err := acquireApplyLock(cmd, ctx, cfg)
+            if err != nil {
+                return err
+            }
+            defer release()
         }
 
-        // 支持三种顶层结构：
-        // 1) 对象：{upstreams/services/routes}
-        // 2) 列表：[...] 视为 routes 简写
-        // 3) 单对象：{name, paths, ...} 视为单个 route 简写
-        var spec applySpec
-        errTop := yaml.Unmarshal(content, &spec)
-        if errTop != nil || (len(spec.Upstreams) == 0 && len(spec.Services) == 0 && len(spec.Routes) == 0) {
-            // 尝试以 routes 列表解析
-            var routes []applyRoute
-            if errList := yaml.Unmarshal(content, &routes); errList == nil && len(routes) > 0 {
-                spec.Routes = routes
-            } else {
-                // 尝试以单个 route 解析
-                var r applyRoute
-                if errOne := yaml.Unmarshal(content, &r); errOne == nil && (r.Name != "" || len(r.Paths) > 0 || len(r.Hosts) > 0 || len(r.Methods) > 0 || r.Service != "" || len(r.Backend.Targets) > 0 || r.Backend.Protocol != "" || r.Backend.Port != 0 || r.Backend.Path != "") {
-                    spec.Routes = []applyRoute{r}
-                } else if errTop != nil {
-                    return fmt.Errorf("解析文件失败（支持 YAML/JSON）。可提供顶层对象 {routes: [...]}，或直接提供 route 列表/单个 route。原始错误：%w", errTop)
-                } else {
-                    return fmt.Errorf("配置为空或未识别到任何资源，请提供 routes/ services/ upstreams 或使用简写列表")
+        if !applyNoPrefetch && !wsMissing {
+            // 预取远程状态，避免按 spec 逐项查询（N+1 次调用）
+            if err := client.Prefetch(ctx); err != nil {
+                PrintWarn(cmd, "预取远程状态失败，改为逐项查询：%v", err)
+            }
+        }
+        if syncScoped {
+            if spec, err = scopeToManaged(cmd, ctx, client, spec); err != nil {
+                return err
+            }
+        }
+        if applyAgainst == "" {
+            // 离线快照没有网关 schema 可供预检
+            if err := preflightSchemas(cmd, ctx, client, spec); err != nil {
+                return err
+            }
+        }
+        if applyServerValidate {
+            if err := serverValidate(cmd, ctx, client, cfg, spec); err != nil {
+                return err
+            }
+        }
+        if pf != nil {
+            if err := verifyPlanFile(ctx, client, pf); err != nil {
+                return err
+            }
+            PrintInfo(cmd, "计划文件 %s 校验通过：远程状态与规划时一致", applyPlanFile)
+        }
+        if applyUpdateOnly && !dryRun {
+            plan, perr := silentPlan(cmd, ctx, client, spec)
+            if perr != nil {
+                return perr
+            }
+            if err := checkUpdateOnly(cmd, ctx, client, plan); err != nil {
+                return err
+            }
+        }
+        if !dryRun {
+            cp, resumed, cerr := openCheckpoint(cmd, spec, applyResume)
+            if cerr != nil {
+                return cerr
+            }
+            applyCheckpoint = cp
+            if resumed > 0 {
+                PrintInfo(cmd, "从检查点继续：跳过已完成的 %d 项资源", resumed)
+            } else if applyResume {
+                PrintInfo(cmd, "未找到该文件未完成的 apply 进度，将完整执行")
+            }
+            defer func() {
+                // applyCheckpoint 为 nil 表示变更已全部写入（--wait 阶段失败无需 --resume）
+                if err == nil || applyCheckpoint == nil {
+                    cp.remove()
+                    return
+                }
+                if applyFile != "" { PrintInfo(cmd, "已完成的资源已记录到检查点，修复问题后可使用 --resume 继续（跳过已完成部分）：kongctl apply -f %s --resume", applyFile) }
+            }()
+        }
+        if applyThreeWay {
+            st, found, lerr := loadLastApplied()
+            if lerr != nil {
+                return lerr
+            }
+            if !found { PrintInfo(cmd, "--three-way：尚无该网关的 last-applied 记录，本次按文件与远程两方比较（执行后开始记录）") }
+            applyLastApplied = st
+        }
+        if confirm {
+            return confirmAndApply(cmd, ctx, cfg, client, spec)
+        }
+        if !dryRun {
+            if err := snapshotBeforeApply(cmd, ctx, client, spec); err != nil {
+                return err
+            }
+        }
+        plan := &aplan.Plan{}
+        if err := runApplyPhase(cmd, ctx, client, spec, plan); err != nil {
+            return err
+        }
+        if !dryRun { recordLastApplied(cmd, spec) }
+        if applyWait && !dryRun {
+            if err := waitHealthyTargets(cmd, client, spec); err != nil {
+                return err
+            }
+        }
+        if applyVerify && !dryRun {
+            return verifyRouteTests(cmd, spec)
+        }
+        if dryRun && applyUpdateOnly {
+            if err := checkUpdateOnly(cmd, ctx, client, *plan); err != nil {
+                return err
+            }
+        }
+        if dryRun {
+            applyOutputPlan = *plan
+            if applyPlanOut != "" {
+                if err := writePlanFile(ctx, client, applyPlanOut, spec, *plan); err != nil {
+                    return err
                 }
+                PrintSuccess(cmd, "已保存计划到 %s（执行：kongctl apply --plan %s）", applyPlanOut, applyPlanOut)
+            }
+            cmd.Println("[dry-run] 以上为计划操作（未实际变更）" + emojiSuccess)
+            if n, _ := pendingChanges(*plan); applyDetailedExit && n > 0 {
+                exitStatus = exitChanges
             }
         }
+        return nil
+    },
+}
+
+// applyTags 返回 apply 为创建/更新的资源附加的标签：上下文 tags/select_tags、--add-tags 与 spec 的 common_tags
+func applyTags(spec applySpec) []string {
+    out := defaultTags()
+    for _, t := range append(append([]string(nil), applyAddTags...), spec.CommonTags...) {
+        if t = strings.TrimSpace(t); t != "" && !sliceContains(out, t) { out = append(out, t) }
+    }
+    return out
+}
 
-        cfg := kong.Config{
-            AdminURL:      viper.GetString("admin_url"),
-            Token:         viper.GetString("token"),
-            TLSSkipVerify: viper.GetBool("tls_skip_verify"),
-            Timeout:       15 * time.Second,
+// loadApplyInput 读取 -f 指定的 spec：模板渲染、解析、展开 include、冲突检测与 --select 过滤
+func loadApplyInput(cmd *cobra.Command) (applySpec, error) {
+    if applyFile == "" && applyInputSpec == nil {
+        return applySpec{}, fmt.Errorf("必须通过 -f/--file 指定配置文件")
+    }
+    var spec applySpec
+    var err error
+    if applyInputSpec != nil {
+        spec = *applyInputSpec
+    } else if isSpecDir(applyFile) {
+        // -f <目录>：目录下的各文件按 include 片段合并（片段各自渲染模板）
+        if spec, err = dirSpec(applyFile); err != nil {
+            return applySpec{}, err
         }
-        if cfg.AdminURL == "" {
-            return fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置")
+    } else {
+        content, err := readSpecFile(cmd, applyFile)
+        if err != nil {
+            return applySpec{}, err
         }
-        client := kong.NewClient(cfg)
-        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
-        defer cancel()
-        var plan aplan.Plan
+        if renderEnabled() {
+            if content, err = renderSpecTemplate(content, applyFile); err != nil {
+                return applySpec{}, err
+            }
+        }
+        if spec, err = parseApplySpec(content); err != nil {
+            return applySpec{}, err
+        }
+    }
+    if spec, err = resolveIncludes(spec, applyFile); err != nil {
+        return applySpec{}, err
+    }
+    resolveCertificatePaths(&spec, applyFile)
+    resolveKeySetPaths(&spec, applyFile)
+    if spec, err = resolveOverlays(spec, applyFile); err != nil {
+        return applySpec{}, err
+    }
+    if err := checkSpecConflicts(spec); err != nil {
+        return applySpec{}, err
+    }
+    if len(applySelect) > 0 {
+        if applyPrune {
+            return applySpec{}, fmt.Errorf("--select 不能与 --prune 同时使用（未选中的资源会被视为已从文件中移除）")
+        }
+        sels, err := parseSelectors("--select", applySelect)
+        if err != nil {
+            return applySpec{}, err
+        }
+        var n int
+        full := spec
+        spec, n = selectSpec(spec, sels)
+        // promote 的目标上下文中可能缺少被引用的 service/upstream，一并带上
+        if applyInputSpec != nil { spec = withDependencies(full, spec) }
+        if n == 0 {
+            return applySpec{}, fmt.Errorf("--select 未匹配到任何资源：%s", strings.Join(applySelect, " | "))
+        }
+        PrintInfo(cmd, "--select 已选中 %d 个资源（upstreams=%d services=%d routes=%d consumer_groups=%d consumers=%d certificates=%d vaults=%d key_sets=%d）", n, len(spec.Upstreams), len(spec.Services), len(spec.Routes), len(spec.ConsumerGroups), len(spec.Consumers), len(spec.Certificates), len(spec.Vaults), len(spec.KeySets))
+    }
+    // 前后缀与路径前缀在 --select 之后处理，选择器按文件中的原名称与路径匹配
+    return prefixSpecPaths(cmd, renameSpec(spec)), nil
+}
 
-        // 1) Upstreams + Targets
-        for _, up := range spec.Upstreams {
-            if up.Name == "" { return fmt.Errorf("upstreams[].name 不能为空") }
+// runApplyPhase 按 vaults -> upstreams -> services -> routes -> consumers -> certificates -> key_sets -> prune 的顺序处理 spec：
+// dryRun 时只读取远程状态并填充 plan、输出分层计划，否则执行变更
+func runApplyPhase(cmd *cobra.Command, ctx context.Context, client *kong.Client, spec applySpec, plan *aplan.Plan) error {
+    if err := checkAnnotations(spec); err != nil {
+        return err
+    }
+    spec, err := resolveIgnoreFields(ctx, client, spec)
+    if err != nil {
+        return err
+    }
+    if applyLastApplied != nil {
+        if spec, err = resolveLastApplied(ctx, client, spec, applyLastApplied); err != nil {
+            return err
+        }
+    }
+    if spec, err = resolveRouteProtocols(ctx, client, spec); err != nil {
+        return err
+    }
+    if dryRun { warnDuplicateTargets(cmd, ctx, client, spec) }
+    // 0) Vaults（先于其他资源，插件、凭证等配置中的 {vault://...} 引用依赖它们）
+    if err := applyVaults(cmd, ctx, client, spec.Vaults, plan); err != nil {
+        return err
+    }
+    // 1) Upstreams + Targets
+    upstreamKeys := func(i int) []string { return []string{"upstream:" + spec.Upstreams[i].Name} }
+    if err := runItems("upstreams", len(spec.Upstreams), upstreamKeys, plan, func(i int, plan *aplan.Plan) error {
+        up := spec.Upstreams[i]
+        if up.Name == "" { return fmt.Errorf("upstreams[].name 不能为空") }
+        if dryRun {
+            if cur, ok, err := client.GetUpstream(ctx, up.Name); err == nil {
+                act, diff := "create", ""
+                if ok {
+                    act = "none"
+                    if _, diff = upstreamChanges(up, cur); diff != "" { act = "update" }
+                }
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: up.Name, Action: act, Diff: diff})
+            } else {
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: up.Name, Action: "create"})
+            }
+        } else if showDiff {
+            PrintInfo(cmd, "确保 Upstream：%s", up.Name)
+        }
+        if !dryRun {
+            // 仅在不存在时创建；存在且 TLS/健康检查选项有差异时，需 --overwrite 才更新
+            if cur, ok, err := client.GetUpstream(ctx, up.Name); err != nil {
+                return err
+            } else if !ok {
+                if _, _, err := client.CreateOrUpdateUpstream(ctx, up.Name); err != nil { return err }
+                // 新建后若声明了可选字段，则补丁更新
+                if payload, _ := upstreamChanges(up, nil); len(payload) > 0 {
+                    if _, err := client.PatchUpstream(ctx, up.Name, payload); err != nil { return err }
+                }
+            } else if payload, _ := upstreamChanges(up, cur); len(payload) > 0 {
+                if applyOverwrite {
+                    if _, err := client.PatchUpstream(ctx, up.Name, payload); err != nil { return err }
+                    PrintSuccess(cmd, "已更新 Upstream：%s", up.Name)
+                } else {
+                    PrintWarn(cmd, "检测到 Upstream 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", up.Name)
+                }
+            } else if applyOverwrite {
+                // 仅补齐缺失的标签
+                if _, _, err := client.CreateOrUpdateUpstream(ctx, up.Name); err != nil { return err }
+            }
+        }
+        for _, t := range up.Targets {
+            w := t.Weight
+            if w == 0 { w = 100 }
             if dryRun {
-                if _, ok, err := client.GetUpstream(ctx, up.Name); err == nil {
+                if list, err := client.ListTargets(ctx, up.Name); err == nil {
+                    action := "create"
+                    for i := range list {
+                        if list[i].Target == t.Target && list[i].Weight == w && t.zoneMatches(list[i]) { action = "none"; break }
+                    }
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: up.Name+"/"+t.Target, Action: action})
+                } else {
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: up.Name+"/"+t.Target, Action: "create"})
+                }
+            } else if showDiff {
+                PrintInfo(cmd, "确保 Target：%s (weight=%d) -> %s", t.Target, w, up.Name)
+            }
+            if !dryRun {
+                // 若已存在且权重不同，视为覆盖更新：默认跳过，除非启用 --overwrite
+                list, err := client.ListTargets(ctx, up.Name)
+                if err != nil { return err }
+                exists := false
+                unchanged := false
+                for i := range list {
+                    if list[i].Target == t.Target {
+                        exists = true
+                        if (list[i].Weight == w || w == 0) && t.zoneMatches(list[i]) { unchanged = true }
+                        break
+                    }
+                }
+                if !exists {
+                    if _, err := client.EnsureTarget(ctx, up.Name, t.Target, w, t.tagList()...); err != nil { return err }
+                } else if unchanged {
+                    // no-op
+                } else if applyOverwrite {
+                    if _, err := client.EnsureTarget(ctx, up.Name, t.Target, w, t.tagList()...); err != nil { return err }
+                } else {
+                    PrintWarn(cmd, "已存在 Target：%s，检测到权重或区域变更（将跳过，启用 --overwrite 可覆盖）", t.Target)
+                }
+            }
+        }
+        return nil
+    }); err != nil {
+        return err
+    }
+
+    // 2) Services（可直接 URL，或通过 upstream+protocol/port/path）
+    serviceKeys := func(i int) []string {
+        if up := spec.Services[i].Upstream; up != "" { return []string{"upstream:" + up} }
+        return []string{"service:" + spec.Services[i].Name}
+    }
+    if err := runItems("services", len(spec.Services), serviceKeys, plan, func(i int, plan *aplan.Plan) error {
+        s := spec.Services[i]
+        if s.Name == "" { return fmt.Errorf("services[].name 不能为空") }
+        if s.Upstream != "" {
+            // 先确保 upstream
+            if dryRun {
+                if _, ok, err := client.GetUpstream(ctx, s.Upstream); err == nil {
                     act := "create"; if ok { act = "none" }
-                    plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: up.Name, Action: act})
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: s.Upstream, Action: act})
                 } else {
-                    plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: up.Name, Action: "create"})
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: s.Upstream, Action: "create"})
                 }
             } else if showDiff {
-                PrintInfo(cmd, "确保 Upstream：%s", up.Name)
+                PrintInfo(cmd, "确保 Upstream：%s（service=%s）", s.Upstream, s.Name)
             }
             if !dryRun {
-                // 仅在不存在时创建；存在则不覆盖配置
-                if _, ok, err := client.GetUpstream(ctx, up.Name); err != nil {
-                    return err
-                } else if !ok {
-                    if _, _, err := client.CreateOrUpdateUpstream(ctx, up.Name); err != nil { return err }
+                if _, ok, err := client.GetUpstream(ctx, s.Upstream); err != nil { return err } else if !ok {
+                    if _, _, err := client.CreateOrUpdateUpstream(ctx, s.Upstream); err != nil { return err }
                 } else if applyOverwrite {
-                    // 当前 Upstream 没有可变更字段，CreateOrUpdateUpstream 也不会修改现有可配置项；
-                    // 若未来扩展需要 PATCH，可在此处启用覆盖。
-                    if _, _, err := client.CreateOrUpdateUpstream(ctx, up.Name); err != nil { return err }
+                    if _, _, err := client.CreateOrUpdateUpstream(ctx, s.Upstream); err != nil { return err }
                 }
             }
-            for _, t := range up.Targets {
-                w := t.Weight
-                if w == 0 { w = 100 }
+            // 若 service 节点中包含 targets，则在该 upstream 下确保
+            for _, t := range s.Targets {
+                w := t.Weight; if w == 0 { w = 100 }
                 if dryRun {
-                    if list, err := client.ListTargets(ctx, up.Name); err == nil {
+                    if list, err := client.ListTargets(ctx, s.Upstream); err == nil {
                         action := "create"
-                        for i := range list {
-                            if list[i].Target == t.Target && (list[i].Weight == w) { action = "none"; break }
-                        }
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: up.Name+"/"+t.Target, Action: action})
+                        for i := range list { if list[i].Target == t.Target && list[i].Weight == w && t.zoneMatches(list[i]) { action = "none"; break } }
+                        plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: s.Upstream+"/"+t.Target, Action: action})
                     } else {
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: up.Name+"/"+t.Target, Action: "create"})
+                        plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: s.Upstream+"/"+t.Target, Action: "create"})
                     }
                 } else if showDiff {
-                    PrintInfo(cmd, "确保 Target：%s (weight=%d) -> %s", t.Target, w, up.Name)
+                    PrintInfo(cmd, "确保 Target：%s (weight=%d) -> %s", t.Target, w, s.Upstream)
                 }
                 if !dryRun {
-                    // 若已存在且权重不同，视为覆盖更新：默认跳过，除非启用 --overwrite
-                    list, err := client.ListTargets(ctx, up.Name)
+                    list, err := client.ListTargets(ctx, s.Upstream)
                     if err != nil { return err }
                     exists := false
-                    sameWeight := false
-                    for i := range list {
-                        if list[i].Target == t.Target {
-                            exists = true
-                            if list[i].Weight == w || w == 0 { sameWeight = true }
-                            break
-                        }
-                    }
+                    unchanged := false
+                    for i := range list { if list[i].Target == t.Target { exists = true; if (list[i].Weight == w || w == 0) && t.zoneMatches(list[i]) { unchanged = true }; break } }
                     if !exists {
-                        if _, err := client.EnsureTarget(ctx, up.Name, t.Target, w); err != nil { return err }
-                    } else if sameWeight {
+                        if _, err := client.EnsureTarget(ctx, s.Upstream, t.Target, w, t.tagList()...); err != nil { return err }
+                    } else if unchanged {
                         // no-op
                     } else if applyOverwrite {
-                        if _, err := client.EnsureTarget(ctx, up.Name, t.Target, w); err != nil { return err }
+                        if _, err := client.EnsureTarget(ctx, s.Upstream, t.Target, w, t.tagList()...); err != nil { return err }
                     } else {
-                        PrintWarn(cmd, "已存在 Target：%s，检测到权重变更（将跳过，启用 --overwrite 可覆盖）", t.Target)
+                        PrintWarn(cmd, "已存在 Target：%s，检测到权重或区域变更（将跳过，启用 --overwrite 可覆盖）", t.Target)
                     }
                 }
             }
-        }
-
-        // 2) Services（可直接 URL，或通过 upstream+protocol/port/path）
-        for _, s := range spec.Services {
-            if s.Name == "" { return fmt.Errorf("services[].name 不能为空") }
-            if s.Upstream != "" {
-                // 先确保 upstream
-                if dryRun {
-                    if _, ok, err := client.GetUpstream(ctx, s.Upstream); err == nil {
-                        act := "create"; if ok { act = "none" }
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: s.Upstream, Action: act})
-                    } else {
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: s.Upstream, Action: "create"})
-                    }
-                } else if showDiff {
-                    PrintInfo(cmd, "确保 Upstream：%s（service=%s）", s.Upstream, s.Name)
-                }
-                if !dryRun {
-                    if _, ok, err := client.GetUpstream(ctx, s.Upstream); err != nil { return err } else if !ok {
-                        if _, _, err := client.CreateOrUpdateUpstream(ctx, s.Upstream); err != nil { return err }
-                    } else if applyOverwrite {
-                        if _, _, err := client.CreateOrUpdateUpstream(ctx, s.Upstream); err != nil { return err }
-                    }
-                }
-                // 若 service 节点中包含 targets，则在该 upstream 下确保
-                for _, t := range s.Targets {
-                    w := t.Weight; if w == 0 { w = 100 }
-                    if dryRun {
-                        if list, err := client.ListTargets(ctx, s.Upstream); err == nil {
-                            action := "create"
-                            for i := range list { if list[i].Target == t.Target && list[i].Weight == w { action = "none"; break } }
-                            plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: s.Upstream+"/"+t.Target, Action: action})
-                        } else {
-                            plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: s.Upstream+"/"+t.Target, Action: "create"})
-                        }
-                    } else if showDiff {
-                        PrintInfo(cmd, "确保 Target：%s (weight=%d) -> %s", t.Target, w, s.Upstream)
-                    }
-                    if !dryRun {
-                        list, err := client.ListTargets(ctx, s.Upstream)
-                        if err != nil { return err }
-                        exists := false
-                        sameWeight := false
-                        for i := range list { if list[i].Target == t.Target { exists = true; if list[i].Weight == w || w == 0 { sameWeight = true }; break } }
-                        if !exists {
-                            if _, err := client.EnsureTarget(ctx, s.Upstream, t.Target, w); err != nil { return err }
-                        } else if sameWeight {
-                            // no-op
-                        } else if applyOverwrite {
-                            if _, err := client.EnsureTarget(ctx, s.Upstream, t.Target, w); err != nil { return err }
-                        } else {
-                            PrintWarn(cmd, "已存在 Target：%s，检测到权重变更（将跳过，启用 --overwrite 可覆盖）", t.Target)
-                        }
-                    }
-                }
-                // 应用 Service
-                proto := s.Protocol
-                if proto == "" { proto = "http" }
-                port := s.Port
-                if port == 0 {
-                    if proto == "https" { port = 443 } else { port = 80 }
-                }
-                if dryRun {
-                    if cur, ok, err := client.GetService(ctx, s.Name); err == nil {
-                        action := "create"
-                        if ok {
-                            action = "none"
-                            if cur.Host != s.Upstream || cur.Protocol != proto || cur.Port != port || (cur.Path != s.Path) {
-                                action = "update"
-                            }
-                            if s.Retries > 0 && cur.Retries != s.Retries { action = "update" }
-                            if s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout { action = "update" }
-                            if s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout { action = "update" }
-                            if s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout { action = "update" }
-                        }
-                        diff := ""
-                        if ok {
-                            if cur.Host != s.Upstream { diff += fmt.Sprintf("host: %s -> %s\n", cur.Host, s.Upstream) }
-                            if cur.Protocol != proto { diff += fmt.Sprintf("protocol: %s -> %s\n", cur.Protocol, proto) }
-                            if cur.Port != port { diff += fmt.Sprintf("port: %d -> %d\n", cur.Port, port) }
-                            if cur.Path != s.Path { diff += fmt.Sprintf("path: %s -> %s\n", cur.Path, s.Path) }
-                            if s.Retries > 0 && cur.Retries != s.Retries { diff += fmt.Sprintf("retries: %d -> %d\n", cur.Retries, s.Retries) }
-                            if s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout { diff += fmt.Sprintf("connect_timeout: %d -> %d\n", cur.ConnectTimeout, s.ConnectTimeout) }
-                            if s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout { diff += fmt.Sprintf("read_timeout: %d -> %d\n", cur.ReadTimeout, s.ReadTimeout) }
-                            if s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout { diff += fmt.Sprintf("write_timeout: %d -> %d\n", cur.WriteTimeout, s.WriteTimeout) }
-                        }
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: s.Name, Action: action, Diff: diff})
-                    } else {
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: s.Name, Action: "create"})
-                    }
-                } else if showDiff {
-                    PrintInfo(cmd, "同步 Service：%s -> upstream=%s (%s:%d path=%s)", s.Name, s.Upstream, proto, port, s.Path)
-                }
-                if !dryRun {
-                    // 仅在不存在时创建；若存在且有差异，需 --overwrite 才更新
-                    if cur, ok, err := client.GetService(ctx, s.Name); err != nil { return err } else if !ok {
-                        action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, s.Name, s.Upstream, proto, port, s.Path)
-                        if err != nil { return err }
-                        PrintSuccess(cmd, "已%sed Service：%s（upstream=%s）", actionCN(action), s.Name, s.Upstream)
-                        // 新建后若指定了扩展字段，则补丁更新
-                        if s.Retries > 0 || s.ConnectTimeout > 0 || s.ReadTimeout > 0 || s.WriteTimeout > 0 {
-                            if _, err := client.UpdateServiceExtras(ctx, s.Name, s.Retries, s.ConnectTimeout, s.ReadTimeout, s.WriteTimeout); err != nil { return err }
-                        }
-                    } else {
-                        changed := cur.Host != s.Upstream || cur.Protocol != proto || cur.Port != port || (cur.Path != s.Path)
-                        // 扩展字段差异
-                        extrasChanged := (s.Retries > 0 && cur.Retries != s.Retries) ||
-                            (s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout) ||
-                            (s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout) ||
-                            (s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout)
-                        if changed {
-                            if applyOverwrite {
-                                action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, s.Name, s.Upstream, proto, port, s.Path)
-                                if err != nil { return err }
-                                PrintSuccess(cmd, "已%sed Service：%s（upstream=%s）", actionCN(action), s.Name, s.Upstream)
-                            } else {
-                                PrintWarn(cmd, "检测到 Service 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
-                            }
-                        }
-                        if extrasChanged {
-                            if applyOverwrite {
-                                if _, err := client.UpdateServiceExtras(ctx, s.Name, s.Retries, s.ConnectTimeout, s.ReadTimeout, s.WriteTimeout); err != nil { return err }
-                                PrintSuccess(cmd, "已更新 Service 额外参数：%s", s.Name)
-                            } else {
-                                PrintWarn(cmd, "检测到 Service 额外参数变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
-                            }
-                        }
-                    }
-                }
-                continue
-            }
-            // 通过 URL
-            if s.URL == "" {
-                return fmt.Errorf("services[%s] 需要提供 url 或 upstream", s.Name)
+            // 应用 Service
+            proto := s.Protocol
+            if proto == "" { proto = "http" }
+            port := s.Port
+            if port == 0 {
+                if proto == "https" { port = 443 } else { port = 80 }
             }
             if dryRun {
                 if cur, ok, err := client.GetService(ctx, s.Name); err == nil {
                     action := "create"
-                    diff := ""
                     if ok {
                         action = "none"
-                        curURL := reconstructURL(cur)
-                        if curURL != s.URL { action = "update"; diff = fmt.Sprintf("url: %s -> %s\n", curURL, s.URL) }
-                        if s.Retries > 0 && cur.Retries != s.Retries { action = "update"; diff += fmt.Sprintf("retries: %d -> %d\n", cur.Retries, s.Retries) }
-                        if s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout { action = "update"; diff += fmt.Sprintf("connect_timeout: %d -> %d\n", cur.ConnectTimeout, s.ConnectTimeout) }
-                        if s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout { action = "update"; diff += fmt.Sprintf("read_timeout: %d -> %d\n", cur.ReadTimeout, s.ReadTimeout) }
-                        if s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout { action = "update"; diff += fmt.Sprintf("write_timeout: %d -> %d\n", cur.WriteTimeout, s.WriteTimeout) }
+                        if serviceTargetDiff(cur, s.Upstream, proto, port, s.Path) != "" {
+                            action = "update"
+                        }
+                        if s.Retries > 0 && cur.Retries != s.Retries { action = "update" }
+                        if s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout { action = "update" }
+                        if s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout { action = "update" }
+                        if s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout { action = "update" }
+                    }
+                    diff := ""
+                    if ok {
+                        diff += serviceTargetDiff(cur, s.Upstream, proto, port, s.Path)
+                        if s.Retries > 0 && cur.Retries != s.Retries { diff += fmt.Sprintf("retries: %d -> %d\n", cur.Retries, s.Retries) }
+                        if s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout { diff += fmt.Sprintf("connect_timeout: %d -> %d\n", cur.ConnectTimeout, s.ConnectTimeout) }
+                        if s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout { diff += fmt.Sprintf("read_timeout: %d -> %d\n", cur.ReadTimeout, s.ReadTimeout) }
+                        if s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout { diff += fmt.Sprintf("write_timeout: %d -> %d\n", cur.WriteTimeout, s.WriteTimeout) }
+                        if s.Annotations != nil {
+                            if d := annotationDiff(cur.Tags, s.Annotations); d != "" { action = "update"; diff += d }
+                        }
                     }
                     plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: s.Name, Action: action, Diff: diff})
                 } else {
                     plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: s.Name, Action: "create"})
                 }
             } else if showDiff {
-                PrintInfo(cmd, "同步 Service：name=%s url=%s", s.Name, s.URL)
+                PrintInfo(cmd, "同步 Service：%s -> upstream=%s (%s:%d path=%s)", s.Name, s.Upstream, proto, port, s.Path)
             }
             if !dryRun {
+                // 仅在不存在时创建；若存在且有差异，需 --overwrite 才更新
                 if cur, ok, err := client.GetService(ctx, s.Name); err != nil { return err } else if !ok {
-                    action, _, err := client.CreateOrUpdateService(ctx, s.Name, s.URL)
+                    action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, s.Name, s.Upstream, proto, port, s.Path)
                     if err != nil { return err }
-                    if action == "create" {
-                        PrintSuccess(cmd, "已创建 Service：name=%s", s.Name)
-                    } else {
-                        PrintSuccess(cmd, "已更新 Service：name=%s", s.Name)
-                    }
+                    PrintSuccess(cmd, "已%sed Service：%s（upstream=%s）", actionCN(action), s.Name, s.Upstream)
                     // 新建后若指定了扩展字段，则补丁更新
                     if s.Retries > 0 || s.ConnectTimeout > 0 || s.ReadTimeout > 0 || s.WriteTimeout > 0 {
                         if _, err := client.UpdateServiceExtras(ctx, s.Name, s.Retries, s.ConnectTimeout, s.ReadTimeout, s.WriteTimeout); err != nil { return err }
                     }
+                    if err := syncServiceAnnotations(cmd, ctx, client, s, true); err != nil { return err }
                 } else {
-                    curURL := reconstructURL(cur)
+                    changed := serviceTargetDiff(cur, s.Upstream, proto, port, s.Path) != ""
+                    // 扩展字段差异
                     extrasChanged := (s.Retries > 0 && cur.Retries != s.Retries) ||
                         (s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout) ||
                         (s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout) ||
                         (s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout)
-                    if curURL != s.URL {
+                    if changed {
                         if applyOverwrite {
-                            action, _, err := client.CreateOrUpdateService(ctx, s.Name, s.URL)
+                            action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, s.Name, s.Upstream, proto, port, s.Path)
                             if err != nil { return err }
-                            if action == "create" {
-                                PrintSuccess(cmd, "已创建 Service：name=%s", s.Name)
-                            } else {
-                                PrintSuccess(cmd, "已更新 Service：name=%s", s.Name)
-                            }
+                            if action != "none" { PrintSuccess(cmd, "已%sed Service：%s（upstream=%s）", actionCN(action), s.Name, s.Upstream) }
                         } else {
-                            PrintWarn(cmd, "检测到 Service URL 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
+                            PrintWarn(cmd, "检测到 Service 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
                         }
                     }
                     if extrasChanged {
@@ -464,295 +871,490 @@ kongctl apply -f examples/route-simple.yaml --dry-run --ascii --compact`,
                             PrintWarn(cmd, "检测到 Service 额外参数变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
                         }
                     }
+                    if err := syncServiceAnnotations(cmd, ctx, client, s, false); err != nil { return err }
                 }
             }
+            return nil
         }
-
-        // 记录由 route 简写自动生成的名字，用于层级展示时避免在顶层重复
-        autoSvcSet := map[string]bool{}
-        autoUpSet := map[string]bool{}
-
-        // 3) Routes（支持简写：缺省 service 时，自动创建 service/upstream）
-        var autoInfos []autoRouteInfo
-        for _, r := range spec.Routes {
-            // 计算最终的 route 名称
-            name := r.Name
-            // 若缺省 route 名称且提供了 service，则按原规则生成
-            if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
-
-            // 简写路径：未显式给出 service 时，自动创建 service/upstream
-            if r.Service == "" {
-                if name == "" {
-                    return fmt.Errorf("route 未提供 name，且缺少 service，无法推导")
-                }
-                svcName := r.ServiceName
-                if svcName == "" { svcName = name + "-service" }
-                upName := r.UpstreamName
-                if upName == "" { upName = name + "-upstream" }
-                autoSvcSet[svcName] = true
-                autoUpSet[upName] = true
-                autoInfos = append(autoInfos, autoRouteInfo{RouteName: name, ServiceName: svcName, UpstreamName: upName, Targets: r.Backend.Targets})
-
-                // 先确保 upstream 与 targets
-                if dryRun {
-                    if _, ok, err := client.GetUpstream(ctx, upName); err == nil {
-                        act := "create"; if ok { act = "none" }
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: upName, Action: act})
-                    } else {
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: upName, Action: "create"})
+        // 通过 URL
+        if s.URL == "" {
+            return fmt.Errorf("services[%s] 需要提供 url 或 upstream", s.Name)
+        }
+        if dryRun {
+            if cur, ok, err := client.GetService(ctx, s.Name); err == nil {
+                action := "create"
+                diff := ""
+                if ok {
+                    action = "none"
+                    curURL := reconstructURL(cur)
+                    if !sameURL(curURL, s.URL) { action = "update"; diff = fmt.Sprintf("url: %s -> %s\n", curURL, s.URL) }
+                    if s.Retries > 0 && cur.Retries != s.Retries { action = "update"; diff += fmt.Sprintf("retries: %d -> %d\n", cur.Retries, s.Retries) }
+                    if s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout { action = "update"; diff += fmt.Sprintf("connect_timeout: %d -> %d\n", cur.ConnectTimeout, s.ConnectTimeout) }
+                    if s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout { action = "update"; diff += fmt.Sprintf("read_timeout: %d -> %d\n", cur.ReadTimeout, s.ReadTimeout) }
+                    if s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout { action = "update"; diff += fmt.Sprintf("write_timeout: %d -> %d\n", cur.WriteTimeout, s.WriteTimeout) }
+                    if s.Annotations != nil {
+                        if d := annotationDiff(cur.Tags, s.Annotations); d != "" { action = "update"; diff += d }
                     }
-                } else if showDiff {
-                PrintInfo(cmd, "确保 Upstream：%s（route=%s 简写）", upName, name)
                 }
-                if !dryRun {
-                    if _, ok, err := client.GetUpstream(ctx, upName); err != nil { return err } else if !ok {
-                        if _, _, err := client.CreateOrUpdateUpstream(ctx, upName); err != nil { return err }
-                    } else if applyOverwrite {
-                        if _, _, err := client.CreateOrUpdateUpstream(ctx, upName); err != nil { return err }
-                    }
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: s.Name, Action: action, Diff: diff})
+            } else {
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: s.Name, Action: "create"})
+            }
+        } else if showDiff {
+            PrintInfo(cmd, "同步 Service：name=%s url=%s", s.Name, s.URL)
+        }
+        if !dryRun {
+            if cur, ok, err := client.GetService(ctx, s.Name); err != nil { return err } else if !ok {
+                action, _, err := client.CreateOrUpdateService(ctx, s.Name, s.URL)
+                if err != nil { return err }
+                if action == "create" {
+                    PrintSuccess(cmd, "已创建 Service：name=%s", s.Name)
+                } else {
+                    PrintSuccess(cmd, "已更新 Service：name=%s", s.Name)
                 }
-                for _, t := range r.Backend.Targets {
-                    w := t.Weight; if w == 0 { w = 100 }
-                    if dryRun {
-                        if list, err := client.ListTargets(ctx, upName); err == nil {
-                            action := "create"
-                            for i := range list { if list[i].Target == t.Target && list[i].Weight == w { action = "none"; break } }
-                            plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: upName+"/"+t.Target, Action: action})
-                        } else {
-                            plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: upName+"/"+t.Target, Action: "create"})
-                        }
-                    } else if showDiff {
-                        PrintInfo(cmd, "确保 Target：%s (weight=%d) -> %s", t.Target, w, upName)
-                    }
-                    if !dryRun {
-                        list, err := client.ListTargets(ctx, upName)
+                // 新建后若指定了扩展字段，则补丁更新
+                if s.Retries > 0 || s.ConnectTimeout > 0 || s.ReadTimeout > 0 || s.WriteTimeout > 0 {
+                    if _, err := client.UpdateServiceExtras(ctx, s.Name, s.Retries, s.ConnectTimeout, s.ReadTimeout, s.WriteTimeout); err != nil { return err }
+                }
+                if err := syncServiceAnnotations(cmd, ctx, client, s, true); err != nil { return err }
+            } else {
+                curURL := reconstructURL(cur)
+                extrasChanged := (s.Retries > 0 && cur.Retries != s.Retries) ||
+                    (s.ConnectTimeout > 0 && cur.ConnectTimeout != s.ConnectTimeout) ||
+                    (s.ReadTimeout > 0 && cur.ReadTimeout != s.ReadTimeout) ||
+                    (s.WriteTimeout > 0 && cur.WriteTimeout != s.WriteTimeout)
+                if !sameURL(curURL, s.URL) {
+                    if applyOverwrite {
+                        action, _, err := client.CreateOrUpdateService(ctx, s.Name, s.URL)
                         if err != nil { return err }
-                        exists := false
-                        sameWeight := false
-                        for i := range list { if list[i].Target == t.Target { exists = true; if list[i].Weight == w || w == 0 { sameWeight = true }; break } }
-                        if !exists {
-                            if _, err := client.EnsureTarget(ctx, upName, t.Target, w); err != nil { return err }
-                        } else if sameWeight {
-                            // no-op
-                        } else if applyOverwrite {
-                            if _, err := client.EnsureTarget(ctx, upName, t.Target, w); err != nil { return err }
-                        } else {
-                            PrintWarn(cmd, "已存在 Target：%s，检测到权重变更（将跳过，启用 --overwrite 可覆盖）", t.Target)
+                        if action == "create" {
+                            PrintSuccess(cmd, "已创建 Service：name=%s", s.Name)
+                        } else if action == "update" {
+                            PrintSuccess(cmd, "已更新 Service：name=%s", s.Name)
                         }
+                    } else {
+                        PrintWarn(cmd, "检测到 Service URL 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
                     }
                 }
+                if extrasChanged {
+                    if applyOverwrite {
+                        if _, err := client.UpdateServiceExtras(ctx, s.Name, s.Retries, s.ConnectTimeout, s.ReadTimeout, s.WriteTimeout); err != nil { return err }
+                        PrintSuccess(cmd, "已更新 Service 额外参数：%s", s.Name)
+                    } else {
+                        PrintWarn(cmd, "检测到 Service 额外参数变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", s.Name)
+                    }
+                }
+                if err := syncServiceAnnotations(cmd, ctx, client, s, false); err != nil { return err }
+            }
+        }
+        return nil
+    }); err != nil {
+        return err
+    }
+
+    // 记录由 route 简写自动生成的名字，用于层级展示时避免在顶层重复
+    autoSvcSet := map[string]bool{}
+    autoUpSet := map[string]bool{}
 
-                // 再创建/更新 service 指向该 upstream
-                proto := r.Backend.Protocol; if proto == "" { proto = "http" }
-                port := r.Backend.Port; if port == 0 { if proto == "https" { port = 443 } else { port = 80 } }
-                path := r.Backend.Path
+    // 3) Routes（支持简写：缺省 service 时，自动创建 service/upstream）
+    var autoInfos []autoRouteInfo
+    var plannedRoutes []kong.Route
+    var mu sync.Mutex
+    routeKeys := func(i int) []string {
+        r := spec.Routes[i]
+        name := r.Name
+        if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        if r.Service != "" { return []string{"service:" + r.Service} }
+        svcName, upName := autoBackendNames(r, name)
+        return []string{"service:" + svcName, "upstream:" + upName}
+    }
+    if err := runItems("routes", len(spec.Routes), routeKeys, plan, func(i int, plan *aplan.Plan) error {
+        r := spec.Routes[i]
+        // 计算最终的 route 名称
+        name := r.Name
+        // 若缺省 route 名称且提供了 service，则按原规则生成
+        if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+
+        // 简写路径：未显式给出 service 时，自动创建 service/upstream
+        if r.Service == "" {
+            if name == "" {
+                return fmt.Errorf("route 未提供 name，且缺少 service，无法推导")
+            }
+            svcName, upName := autoBackendNames(r, name)
+            mu.Lock()
+            autoSvcSet[svcName] = true
+            autoUpSet[upName] = true
+            autoInfos = append(autoInfos, autoRouteInfo{RouteName: name, ServiceName: svcName, UpstreamName: upName, Targets: r.Backend.Targets})
+            mu.Unlock()
 
+            // 先确保 upstream 与 targets
+            if dryRun {
+                if _, ok, err := client.GetUpstream(ctx, upName); err == nil {
+                    act := "create"; if ok { act = "none" }
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: upName, Action: act})
+                } else {
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Upstream", Name: upName, Action: "create"})
+                }
+            } else if showDiff {
+            PrintInfo(cmd, "确保 Upstream：%s（route=%s 简写）", upName, name)
+            }
+            if !dryRun {
+                if _, ok, err := client.GetUpstream(ctx, upName); err != nil { return err } else if !ok {
+                    if _, _, err := client.CreateOrUpdateUpstream(ctx, upName); err != nil { return err }
+                } else if applyOverwrite {
+                    if _, _, err := client.CreateOrUpdateUpstream(ctx, upName); err != nil { return err }
+                }
+            }
+            for _, t := range r.Backend.Targets {
+                w := t.Weight; if w == 0 { w = 100 }
                 if dryRun {
-                    if cur, ok, err := client.GetService(ctx, svcName); err == nil {
+                    if list, err := client.ListTargets(ctx, upName); err == nil {
                         action := "create"
-                        if ok {
-                            action = "none"
-                            if cur.Host != upName || cur.Protocol != proto || cur.Port != port || (cur.Path != path) {
-                                action = "update"
-                            }
-                        }
-                        diff := ""
-                        if ok {
-                            if cur.Host != upName { diff += fmt.Sprintf("host: %s -> %s\n", cur.Host, upName) }
-                            if cur.Protocol != proto { diff += fmt.Sprintf("protocol: %s -> %s\n", cur.Protocol, proto) }
-                            if cur.Port != port { diff += fmt.Sprintf("port: %d -> %d\n", cur.Port, port) }
-                            if cur.Path != path { diff += fmt.Sprintf("path: %s -> %s\n", cur.Path, path) }
-                        }
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: svcName, Action: action, Diff: diff})
+                        for i := range list { if list[i].Target == t.Target && list[i].Weight == w && t.zoneMatches(list[i]) { action = "none"; break } }
+                        plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: upName+"/"+t.Target, Action: action})
                     } else {
-                        plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: svcName, Action: "create"})
+                        plan.Items = append(plan.Items, aplan.Change{Kind: "Target", Name: upName+"/"+t.Target, Action: "create"})
                     }
                 } else if showDiff {
-                    PrintInfo(cmd, "同步 Service：%s -> upstream=%s (%s:%d path=%s)", svcName, upName, proto, port, path)
+                    PrintInfo(cmd, "确保 Target：%s (weight=%d) -> %s", t.Target, w, upName)
                 }
                 if !dryRun {
-                    if cur, ok, err := client.GetService(ctx, svcName); err != nil { return err } else if !ok {
-                        action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, svcName, upName, proto, port, path)
-                        if err != nil { return err }
-                        PrintSuccess(cmd, "已%sed Service：%s（auto, upstream=%s）", actionCN(action), svcName, upName)
+                    list, err := client.ListTargets(ctx, upName)
+                    if err != nil { return err }
+                    exists := false
+                    unchanged := false
+                    for i := range list { if list[i].Target == t.Target { exists = true; if (list[i].Weight == w || w == 0) && t.zoneMatches(list[i]) { unchanged = true }; break } }
+                    if !exists {
+                        if _, err := client.EnsureTarget(ctx, upName, t.Target, w, t.tagList()...); err != nil { return err }
+                    } else if unchanged {
+                        // no-op
+                    } else if applyOverwrite {
+                        if _, err := client.EnsureTarget(ctx, upName, t.Target, w, t.tagList()...); err != nil { return err }
                     } else {
-                        changed := cur.Host != upName || cur.Protocol != proto || cur.Port != port || (cur.Path != path)
-                        if changed {
-                            if applyOverwrite {
-                                action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, svcName, upName, proto, port, path)
-                                if err != nil { return err }
-                                PrintSuccess(cmd, "已%sed Service：%s（auto, upstream=%s）", actionCN(action), svcName, upName)
-                            } else {
-                                PrintWarn(cmd, "检测到 Service 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", svcName)
-                            }
-                        }
+                        PrintWarn(cmd, "已存在 Target：%s，检测到权重或区域变更（将跳过，启用 --overwrite 可覆盖）", t.Target)
                     }
                 }
-
-                // 最终 route 仍然需要 service 名称
-                r.Service = svcName
-            }
-
-            // 常规 route 同步
-            if name == "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
-            // 校验 path_handling（若提供）
-            ph := strings.ToLower(strings.TrimSpace(r.PathHandling))
-            if ph != "" && ph != "v0" && ph != "v1" {
-                return fmt.Errorf("routes[].path_handling 仅支持 v0 或 v1：%s", r.PathHandling)
             }
 
-            desired := kong.Route{
-                Name:    name,
-                Hosts:   r.Hosts,
-                Paths:   r.Paths,
-                Methods: toUpper(r.Methods),
-                PathHandling: ph,
-            }
-            if len(r.Protocols) > 0 { desired.Protocols = r.Protocols }
-            if r.PreserveHost != nil { desired.PreserveHost = r.PreserveHost }
-            if r.RegexPriority != 0 { desired.RegexPriority = r.RegexPriority }
-            if r.HTTPSRedirectStatusCode != 0 { desired.HTTPSRedirectStatusCode = r.HTTPSRedirectStatusCode }
-            if r.RequestBuffering != nil { desired.RequestBuffering = r.RequestBuffering }
-            if r.ResponseBuffering != nil { desired.ResponseBuffering = r.ResponseBuffering }
-            if len(r.Headers) > 0 { desired.Headers = r.Headers }
-            if len(r.Snis) > 0 { desired.Snis = r.Snis }
-            if len(r.Tags) > 0 { desired.Tags = r.Tags }
-            if r.StripPath != nil { desired.StripPath = r.StripPath } else { sp := true; desired.StripPath = &sp }
-            desired.Service.Name = r.Service
+            // 再创建/更新 service 指向该 upstream
+            proto := r.Backend.Protocol; if proto == "" { proto = "http" }
+            port := r.Backend.Port; if port == 0 { if proto == "https" { port = 443 } else { port = 80 } }
+            path := r.Backend.Path
 
             if dryRun {
-                if cur, ok, err := client.GetRoute(ctx, name); err == nil {
+                if cur, ok, err := client.GetService(ctx, svcName); err == nil {
                     action := "create"
-                    diff := ""
                     if ok {
                         action = "none"
-                        changed := false
-                        if !sliceSetEqual(cur.Hosts, desired.Hosts) { changed = true; diff += diffSlice("hosts", cur.Hosts, desired.Hosts) }
-                        if !sliceSetEqual(cur.Paths, desired.Paths) { changed = true; diff += diffSlice("paths", cur.Paths, desired.Paths) }
-                        if !sliceSetEqual(toUpper(cur.Methods), desired.Methods) { changed = true; diff += diffSlice("methods", cur.Methods, desired.Methods) }
-                        if len(r.Protocols) > 0 {
-                            if !sliceSetEqual(cur.Protocols, desired.Protocols) { changed = true; diff += diffSlice("protocols", cur.Protocols, desired.Protocols) }
-                        }
-                        curPH := strings.ToLower(cur.PathHandling)
-                        desPH := strings.ToLower(desired.PathHandling)
-                        if desPH != "" && curPH != desPH { changed = true; diff += fmt.Sprintf("path_handling: %s -> %s\n", curPH, desPH) }
-                        if r.PreserveHost != nil {
-                            curPHo := false; if cur.PreserveHost != nil { curPHo = *cur.PreserveHost }
-                            desPHo := false; if desired.PreserveHost != nil { desPHo = *desired.PreserveHost }
-                            if curPHo != desPHo { changed = true; diff += fmt.Sprintf("preserve_host: %v -> %v\n", curPHo, desPHo) }
+                        if serviceTargetDiff(cur, upName, proto, port, path) != "" {
+                            action = "update"
                         }
-                        if r.RegexPriority != 0 {
-                            if cur.RegexPriority != desired.RegexPriority { changed = true; diff += fmt.Sprintf("regex_priority: %d -> %d\n", cur.RegexPriority, desired.RegexPriority) }
-                        }
-                        if r.HTTPSRedirectStatusCode != 0 {
-                            if cur.HTTPSRedirectStatusCode != desired.HTTPSRedirectStatusCode { changed = true; diff += fmt.Sprintf("https_redirect_status_code: %d -> %d\n", cur.HTTPSRedirectStatusCode, desired.HTTPSRedirectStatusCode) }
-                        }
-                        if r.RequestBuffering != nil {
-                            curRB := false; if cur.RequestBuffering != nil { curRB = *cur.RequestBuffering }
-                            desRB := false; if desired.RequestBuffering != nil { desRB = *desired.RequestBuffering }
-                            if curRB != desRB { changed = true; diff += fmt.Sprintf("request_buffering: %v -> %v\n", curRB, desRB) }
-                        }
-                        if r.ResponseBuffering != nil {
-                            curRB := false; if cur.ResponseBuffering != nil { curRB = *cur.ResponseBuffering }
-                            desRB := false; if desired.ResponseBuffering != nil { desRB = *desired.ResponseBuffering }
-                            if curRB != desRB { changed = true; diff += fmt.Sprintf("response_buffering: %v -> %v\n", curRB, desRB) }
-                        }
-                        if len(r.Headers) > 0 {
-                            if !mapStringSliceEqual(cur.Headers, desired.Headers) { changed = true; diff += diffMapStringSlice("headers", cur.Headers, desired.Headers) }
-                        }
-                        if len(r.Snis) > 0 {
-                            if !sliceSetEqual(cur.Snis, desired.Snis) { changed = true; diff += diffSlice("snis", cur.Snis, desired.Snis) }
-                        }
-                        if len(r.Tags) > 0 {
-                            if !sliceSetEqual(cur.Tags, desired.Tags) { changed = true; diff += diffSlice("tags", cur.Tags, desired.Tags) }
-                        }
-                        curSP := false; if cur.StripPath != nil { curSP = *cur.StripPath }
-                        desSP := false; if desired.StripPath != nil { desSP = *desired.StripPath }
-                        if curSP != desSP { changed = true; diff += fmt.Sprintf("strip_path: %v -> %v\n", curSP, desSP) }
-                        if cur.Service.Name != desired.Service.Name && desired.Service.Name != "" { changed = true; diff += fmt.Sprintf("service: %s -> %s\n", cur.Service.Name, desired.Service.Name) }
-                        if changed { action = "update" }
                     }
-                    plan.Items = append(plan.Items, aplan.Change{Kind: "Route", Name: name, Action: action, Diff: diff})
+                    diff := ""
+                    if ok {
+                        diff += serviceTargetDiff(cur, upName, proto, port, path)
+                    }
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: svcName, Action: action, Diff: diff})
                 } else {
-                    plan.Items = append(plan.Items, aplan.Change{Kind: "Route", Name: name, Action: "create"})
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Service", Name: svcName, Action: "create"})
                 }
             } else if showDiff {
-                PrintInfo(cmd, "同步 Route：name=%s service=%s", name, r.Service)
+                PrintInfo(cmd, "同步 Service：%s -> upstream=%s (%s:%d path=%s)", svcName, upName, proto, port, path)
             }
             if !dryRun {
-                if cur, ok, err := client.GetRoute(ctx, name); err != nil { return err } else if !ok {
-                    action, _, err := client.CreateOrUpdateRoute(ctx, desired)
+                if cur, ok, err := client.GetService(ctx, svcName); err != nil { return err } else if !ok {
+                    action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, svcName, upName, proto, port, path)
                     if err != nil { return err }
-                    PrintSuccess(cmd, "已%sed Route：name=%s service=%s", actionCN(action), name, r.Service)
+                    PrintSuccess(cmd, "已%sed Service：%s（auto, upstream=%s）", actionCN(action), svcName, upName)
                 } else {
-                    // 计算是否变更
+                    changed := serviceTargetDiff(cur, upName, proto, port, path) != ""
+                    if changed {
+                        if applyOverwrite {
+                            action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, svcName, upName, proto, port, path)
+                            if err != nil { return err }
+                            if action != "none" { PrintSuccess(cmd, "已%sed Service：%s（auto, upstream=%s）", actionCN(action), svcName, upName) }
+                        } else {
+                            PrintWarn(cmd, "检测到 Service 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", svcName)
+                        }
+                    }
+                }
+            }
+
+            // 最终 route 仍然需要 service 名称
+            r.Service = svcName
+        }
+
+        // 常规 route 同步
+        if name == "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        // 校验 path_handling（若提供）
+        ph := strings.ToLower(strings.TrimSpace(r.PathHandling))
+        if ph != "" && ph != "v0" && ph != "v1" {
+            return fmt.Errorf("routes[].path_handling 仅支持 v0 或 v1：%s", r.PathHandling)
+        }
+
+        desired := kong.Route{
+            Name:    name,
+            Hosts:   r.Hosts,
+            Paths:   r.Paths,
+            Methods: toUpper(r.Methods),
+            PathHandling: ph,
+        }
+        if len(r.Protocols) > 0 { desired.Protocols = r.Protocols }
+        if r.PreserveHost != nil { desired.PreserveHost = r.PreserveHost }
+        if r.RegexPriority != 0 { desired.RegexPriority = r.RegexPriority }
+        if r.HTTPSRedirectStatusCode != 0 { desired.HTTPSRedirectStatusCode = r.HTTPSRedirectStatusCode }
+        if r.RequestBuffering != nil { desired.RequestBuffering = r.RequestBuffering }
+        if r.ResponseBuffering != nil { desired.ResponseBuffering = r.ResponseBuffering }
+        if len(r.Headers) > 0 { desired.Headers = kong.NormalizeHeaders(r.Headers) }
+        if len(r.Snis) > 0 { desired.Snis = r.Snis }
+        if len(r.Tags) > 0 {
+            // 附加标签（common_tags、--add-tags 等）由客户端写入，比较时一并计入，避免每次都显示差异
+            desired.Tags = append([]string{}, r.Tags...)
+            for _, t := range client.ConfigTags() {
+                if !kong.HasTag(desired.Tags, t) { desired.Tags = append(desired.Tags, t) }
+            }
+            if applyPrune && !kong.HasTag(desired.Tags, managedByTag()) { desired.Tags = append(desired.Tags, managedByTag()) }
+        }
+        if r.Annotations != nil { desired.Tags = withAnnotations(desired.Tags, r.Annotations) }
+        if r.StripPath != nil { desired.StripPath = r.StripPath } else { sp := true; desired.StripPath = &sp }
+        desired.Service.Name = r.Service
+
+        if dryRun {
+            if cur, ok, err := client.GetRoute(ctx, name); err == nil {
+                action := "create"
+                diff := ""
+                if ok {
+                    action = "none"
                     changed := false
-                    if !sliceSetEqual(cur.Hosts, desired.Hosts) { changed = true }
-                    if !sliceSetEqual(cur.Paths, desired.Paths) { changed = true }
-                    if !sliceSetEqual(toUpper(cur.Methods), desired.Methods) { changed = true }
-                    if len(r.Protocols) > 0 && !sliceSetEqual(cur.Protocols, desired.Protocols) { changed = true }
+                    if !sliceSetEqual(toLower(cur.Hosts), toLower(desired.Hosts)) { changed = true; diff += diffSlice("hosts", toLower(cur.Hosts), toLower(desired.Hosts)) }
+                    if !sliceSetEqual(normalizePaths(cur.Paths), normalizePaths(desired.Paths)) { changed = true; diff += diffSlice("paths", cur.Paths, desired.Paths) }
+                    if !sliceSetEqual(toUpper(cur.Methods), desired.Methods) { changed = true; diff += diffSlice("methods", toUpper(cur.Methods), desired.Methods) }
+                    if len(r.Protocols) > 0 {
+                        if !sliceSetEqual(toLower(cur.Protocols), toLower(desired.Protocols)) { changed = true; diff += diffSlice("protocols", toLower(cur.Protocols), toLower(desired.Protocols)) }
+                    }
                     curPH := strings.ToLower(cur.PathHandling)
                     desPH := strings.ToLower(desired.PathHandling)
-                    if desPH != "" && curPH != desPH { changed = true }
+                    if desPH != "" && curPH != desPH { changed = true; diff += fmt.Sprintf("path_handling: %s -> %s\n", curPH, desPH) }
                     if r.PreserveHost != nil {
                         curPHo := false; if cur.PreserveHost != nil { curPHo = *cur.PreserveHost }
                         desPHo := false; if desired.PreserveHost != nil { desPHo = *desired.PreserveHost }
-                        if curPHo != desPHo { changed = true }
+                        if curPHo != desPHo { changed = true; diff += fmt.Sprintf("preserve_host: %v -> %v\n", curPHo, desPHo) }
+                    }
+                    if r.RegexPriority != 0 {
+                        if cur.RegexPriority != desired.RegexPriority { changed = true; diff += fmt.Sprintf("regex_priority: %d -> %d\n", cur.RegexPriority, desired.RegexPriority) }
+                    }
+                    if r.HTTPSRedirectStatusCode != 0 {
+                        if cur.HTTPSRedirectStatusCode != desired.HTTPSRedirectStatusCode { changed = true; diff += fmt.Sprintf("https_redirect_status_code: %d -> %d\n", cur.HTTPSRedirectStatusCode, desired.HTTPSRedirectStatusCode) }
                     }
-                    if r.RegexPriority != 0 && cur.RegexPriority != desired.RegexPriority { changed = true }
-                    if r.HTTPSRedirectStatusCode != 0 && cur.HTTPSRedirectStatusCode != desired.HTTPSRedirectStatusCode { changed = true }
                     if r.RequestBuffering != nil {
                         curRB := false; if cur.RequestBuffering != nil { curRB = *cur.RequestBuffering }
                         desRB := false; if desired.RequestBuffering != nil { desRB = *desired.RequestBuffering }
-                        if curRB != desRB { changed = true }
+                        if curRB != desRB { changed = true; diff += fmt.Sprintf("request_buffering: %v -> %v\n", curRB, desRB) }
                     }
                     if r.ResponseBuffering != nil {
                         curRB := false; if cur.ResponseBuffering != nil { curRB = *cur.ResponseBuffering }
                         desRB := false; if desired.ResponseBuffering != nil { desRB = *desired.ResponseBuffering }
-                        if curRB != desRB { changed = true }
+                        if curRB != desRB { changed = true; diff += fmt.Sprintf("response_buffering: %v -> %v\n", curRB, desRB) }
+                    }
+                    if len(r.Headers) > 0 {
+                        if !mapStringSliceEqual(cur.Headers, desired.Headers) { changed = true; diff += diffNested("headers", kong.NormalizeHeaders(cur.Headers), kong.NormalizeHeaders(desired.Headers)) }
+                    }
+                    if len(r.Snis) > 0 {
+                        if !sliceSetEqual(cur.Snis, desired.Snis) { changed = true; diff += diffSlice("snis", cur.Snis, desired.Snis) }
+                    }
+                    if len(r.Tags) > 0 {
+                        if !sliceSetEqual(cur.Tags, desired.Tags) { changed = true; diff += diffSlice("tags", cur.Tags, desired.Tags) }
+                    } else if r.Annotations != nil {
+                        if d := annotationDiff(cur.Tags, r.Annotations); d != "" { changed = true; diff += d }
                     }
-                    if len(r.Headers) > 0 && !mapStringSliceEqual(cur.Headers, desired.Headers) { changed = true }
-                    if len(r.Snis) > 0 && !sliceSetEqual(cur.Snis, desired.Snis) { changed = true }
-                    if len(r.Tags) > 0 && !sliceSetEqual(cur.Tags, desired.Tags) { changed = true }
                     curSP := false; if cur.StripPath != nil { curSP = *cur.StripPath }
                     desSP := false; if desired.StripPath != nil { desSP = *desired.StripPath }
-                    if curSP != desSP { changed = true }
-                    if cur.Service.Name != desired.Service.Name && desired.Service.Name != "" { changed = true }
-                    if changed {
-                        if applyOverwrite {
-                            action, _, err := client.CreateOrUpdateRoute(ctx, desired)
-                            if err != nil { return err }
-                            PrintSuccess(cmd, "已%sed Route：name=%s service=%s", actionCN(action), name, r.Service)
-                        } else {
-                            PrintWarn(cmd, "检测到 Route 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", name)
-                        }
+                    if curSP != desSP { changed = true; diff += fmt.Sprintf("strip_path: %v -> %v\n", curSP, desSP) }
+                    if cur.Service.Name != desired.Service.Name && desired.Service.Name != "" { changed = true; diff += fmt.Sprintf("service: %s -> %s\n", cur.Service.Name, desired.Service.Name) }
+                    if changed { action = "update" }
+                }
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Route", Name: name, Action: action, Diff: diff})
+            } else {
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Route", Name: name, Action: "create"})
+            }
+            mu.Lock()
+            plannedRoutes = append(plannedRoutes, desired)
+            mu.Unlock()
+        } else if showDiff {
+            PrintInfo(cmd, "同步 Route：name=%s service=%s", name, r.Service)
+        }
+        if !dryRun {
+            if cur, ok, err := client.GetRoute(ctx, name); err != nil { return err } else if !ok {
+                action, _, err := client.CreateOrUpdateRoute(ctx, desired)
+                if err != nil { return err }
+                PrintSuccess(cmd, "已%sed Route：name=%s service=%s", actionCN(action), name, r.Service)
+            } else {
+                // 计算是否变更
+                changed := false
+                if !sliceSetEqual(toLower(cur.Hosts), toLower(desired.Hosts)) { changed = true }
+                if !sliceSetEqual(normalizePaths(cur.Paths), normalizePaths(desired.Paths)) { changed = true }
+                if !sliceSetEqual(toUpper(cur.Methods), desired.Methods) { changed = true }
+                if len(r.Protocols) > 0 && !sliceSetEqual(toLower(cur.Protocols), toLower(desired.Protocols)) { changed = true }
+                curPH := strings.ToLower(cur.PathHandling)
+                desPH := strings.ToLower(desired.PathHandling)
+                if desPH != "" && curPH != desPH { changed = true }
+                if r.PreserveHost != nil {
+                    curPHo := false; if cur.PreserveHost != nil { curPHo = *cur.PreserveHost }
+                    desPHo := false; if desired.PreserveHost != nil { desPHo = *desired.PreserveHost }
+                    if curPHo != desPHo { changed = true }
+                }
+                if r.RegexPriority != 0 && cur.RegexPriority != desired.RegexPriority { changed = true }
+                if r.HTTPSRedirectStatusCode != 0 && cur.HTTPSRedirectStatusCode != desired.HTTPSRedirectStatusCode { changed = true }
+                if r.RequestBuffering != nil {
+                    curRB := false; if cur.RequestBuffering != nil { curRB = *cur.RequestBuffering }
+                    desRB := false; if desired.RequestBuffering != nil { desRB = *desired.RequestBuffering }
+                    if curRB != desRB { changed = true }
+                }
+                if r.ResponseBuffering != nil {
+                    curRB := false; if cur.ResponseBuffering != nil { curRB = *cur.ResponseBuffering }
+                    desRB := false; if desired.ResponseBuffering != nil { desRB = *desired.ResponseBuffering }
+                    if curRB != desRB { changed = true }
+                }
+                if len(r.Headers) > 0 && !mapStringSliceEqual(cur.Headers, desired.Headers) { changed = true }
+                if len(r.Snis) > 0 && !sliceSetEqual(cur.Snis, desired.Snis) { changed = true }
+                if len(r.Tags) > 0 && !sliceSetEqual(cur.Tags, desired.Tags) { changed = true }
+                if len(r.Tags) == 0 && r.Annotations != nil {
+                    // 未声明 tags 时保留远程已有的普通标签，仅替换 meta: 注解
+                    desired.Tags = withAnnotations(cur.Tags, r.Annotations)
+                    if annotationDiff(cur.Tags, r.Annotations) != "" { changed = true }
+                }
+                curSP := false; if cur.StripPath != nil { curSP = *cur.StripPath }
+                desSP := false; if desired.StripPath != nil { desSP = *desired.StripPath }
+                if curSP != desSP { changed = true }
+                if cur.Service.Name != desired.Service.Name && desired.Service.Name != "" { changed = true }
+                if changed {
+                    if applyOverwrite {
+                        action, _, err := client.CreateOrUpdateRoute(ctx, desired)
+                        if err != nil { return err }
+                        if action != "none" { PrintSuccess(cmd, "已%sed Route：name=%s service=%s", actionCN(action), name, r.Service) }
+                    } else {
+                        PrintWarn(cmd, "检测到 Route 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", name)
                     }
                 }
             }
         }
+        return nil
+    }); err != nil {
+        return err
+    }
+
+    // 4) Consumer Groups（先于 consumers，成员关系引用已存在的分组）、Consumers 及其凭证
+    if err := applyConsumerGroups(cmd, ctx, client, spec.ConsumerGroups, plan); err != nil {
+        return err
+    }
+    if err := applyConsumers(cmd, ctx, client, spec.Consumers, plan); err != nil {
+        return err
+    }
+
+    // 5) Certificates 及其 SNI
+    if err := applyCertificates(cmd, ctx, client, spec.Certificates, plan); err != nil {
+        return err
+    }
+
+    // 6) Key Sets 及其密钥
+    if err := applyKeySets(cmd, ctx, client, spec.KeySets, plan); err != nil {
+        return err
+    }
 
+    // 7) Prune：删除带 managed-by 标签但未在文件中声明的资源；--prune-targets 删除已声明 upstream 下多余的 targets
+    if applyPrune || applyPruneTargets {
+        var pruned []aplan.Change
+        if applyPrune {
+            var err error
+            if pruned, err = planPrune(ctx, client, spec); err != nil { return err }
+        }
+        if applyPruneTargets {
+            stale, err := planPruneTargets(ctx, client, spec)
+            if err != nil { return err }
+            pruned = mergePruned(pruned, stale)
+        }
         if dryRun {
-            printHierPlan(cmd, plan, spec, autoInfos, autoSvcSet, autoUpSet, showDiff)
-            if !applyOverwrite {
-                PrintInfo(cmd, "提示：当前未启用覆盖更新（--overwrite）。执行时仅创建缺失资源，不修改已存在的远程配置。")
+            plan.Items = append(plan.Items, pruned...)
+        } else if err := executePrune(cmd, ctx, client, pruned); err != nil {
+            return err
+        }
+    }
+
+    if dryRun {
+        if len(plannedRoutes) > 0 {
+            if remote, err := client.ListRoutes(ctx); err != nil {
+                PrintWarn(cmd, "无法计算路由匹配优先级：%v", err)
+            } else {
+                annotateRoutePrecedence(cmd, plan, remote, plannedRoutes)
+                samples, source, err := trafficSamples(cmd, ctx, remote)
+                if err != nil {
+                    return err
+                }
+                annotateTrafficImpact(cmd, plan, remote, plannedRoutes, samples, source)
             }
-            cmd.Println("[dry-run] 以上为计划操作（未实际变更）✅")
         }
-        return nil
-    },
+        applyUnifiedDiffs = nil
+        if showDiff && applyDiffFormat == "unified" { applyUnifiedDiffs = unifiedPlanDiffs(ctx, client, *plan) }
+        printHierPlan(cmd, *plan, spec, autoInfos, autoSvcSet, autoUpSet, showDiff)
+        if !applyOverwrite {
+            PrintInfo(cmd, "提示：当前未启用覆盖更新（--overwrite）。执行时仅创建缺失资源，不修改已存在的远程配置。")
+        }
+    }
+    return nil
 }
 
 func init() {
     rootCmd.AddCommand(applyCmd)
     // 子命令：生成示例 YAML
     applyCmd.AddCommand(applyExampleCmd)
-    applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "配置文件路径（YAML/JSON），例：-f examples/apply.yaml")
+    applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "配置文件路径（YAML/JSON，- 表示标准输入；目录表示合并其下的 *.yaml/*.yml/*.json），例：-f examples/apply.yaml")
     applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更（例：--dry-run --diff）")
     applyCmd.Flags().BoolVar(&showDiff, "diff", false, "显示操作摘要与字段差异（配合 --dry-run）")
     applyCmd.Flags().BoolVar(&applyNoColor, "no-color", false, "禁用彩色输出")
     applyCmd.Flags().BoolVar(&applyASCII, "ascii", false, "使用 ASCII 输出（避免 Unicode 图形字符）")
     applyCmd.Flags().BoolVar(&applyCompact, "compact", false, "紧凑模式：隐藏无变化项（none）")
+    applyCmd.Flags().StringVar(&applyDiffFormat, "diff-format", "text", "--diff 的展示格式：text（逐字段差异）或 unified（资源 YAML 的统一 diff，含上下文行）")
     applyCmd.Flags().BoolVar(&applyOverwrite, "overwrite", false, "允许覆盖远程已有配置（默认只创建，不更新）")
+    applyCmd.Flags().BoolVar(&applyPrune, "prune", false, "删除带 managed-by:kongctl 标签但未在文件中声明的 routes/services/upstreams/targets（并为本次写入的资源打上该标签）")
+    applyCmd.Flags().BoolVar(&applyPruneTargets, "prune-targets", false, "删除文件中声明了 targets 的 upstream 下未声明的 targets（不要求 managed-by 标签；未声明 targets 的 upstream 不处理）")
+    applyCmd.Flags().StringArrayVar(&applySelect, "select", nil, "仅处理匹配的资源：kind=upstream|service|route|consumer、name=<通配>、tag=<标签>，逗号分隔表示同时满足，可重复指定（任一匹配），例：--select kind=route,name=user-*")
+    applyCmd.Flags().BoolVar(&applyConfirm, "confirm", false, "先展示计划并交互确认后再执行（也可在配置中设置 confirm: true）")
+    applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "跳过 --confirm / confirm: true 的交互确认")
+    applyCmd.Flags().BoolVar(&applyNoPrefetch, "no-prefetch", false, "不预取远程状态，按 spec 逐项查询 upstream/service/route/targets（默认先一次性列出并建立索引）")
+    applyCmd.Flags().BoolVar(&applyResume, "resume", false, "从上次中断的进度继续：跳过检查点中已完成的资源（需与中断时的文件及上下文一致）")
+    applyCmd.Flags().StringSliceVar(&applyAddTags, "add-tags", nil, "为创建/更新的 service/route/upstream/target 附加标签（逗号分隔，与 spec 的 common_tags 合并），例：--add-tags env:prod,team:platform")
+    applyCmd.Flags().BoolVar(&applyFromExport, "from-export", false, "接入已有网关：导出当前配置写入 -f 指定的新文件，为全部资源补加 managed-by 标签并记录 last-applied")
+    applyCmd.Flags().StringSliceVar(&applyOverlays, "overlay", nil, "合并环境补丁（spec 所在目录下 overlays/<name>.yaml，或文件路径），可重复，按顺序生效，例：--overlay prod")
+    applyCmd.Flags().StringVar(&applyNamePrefix, "name-prefix", "", "为所有 upstream/service/route 名称加前缀（同步改写相互引用），同一 spec 可多次部署到同一 Kong，例：--name-prefix staging-")
+    applyCmd.Flags().StringVar(&applyNameSuffix, "name-suffix", "", "为所有 upstream/service/route 名称加后缀，例：--name-suffix -v2")
+    applyCmd.Flags().StringVar(&applyPathPrefix, "path-prefix", "", "为所有 route 的 paths 加上统一前缀（strip_path 为 true 时上游收到的路径不变），例：--path-prefix /team-a")
+    applyCmd.Flags().BoolVar(&applyThreeWay, "three-way", false, "三方比较：结合上次 apply 的记录，集合字段（hosts/paths/methods/tags 等）、headers 与注解中由其他工具添加的项予以保留，只移除 kongctl 曾写入、已从文件删除的项")
+    applyCmd.Flags().BoolVar(&applyUpdateOnly, "update-only", false, "只更新远程已存在的资源（隐含 --overwrite）；spec 中有需新建的资源时拒绝执行且不做任何变更")
+    applyCmd.Flags().StringSliceVar(&applyIgnoreFields, "ignore-fields", nil, "对所有资源忽略的字段（逗号分隔，由其他工具管理，不比较也不覆盖），例：--ignore-fields tags,retries；也可在资源上设置 ignore_fields")
+    applyCmd.Flags().BoolVar(&applyWait, "wait", false, "执行后轮询 /upstreams/{name}/health，直到 spec 中声明的 targets 全部 HEALTHY（超时则以非零状态退出）")
+    applyCmd.Flags().DurationVar(&applyWaitTimeout, "wait-timeout", 5*time.Minute, "--wait 的最长等待时间，例：--wait-timeout 2m")
+    applyCmd.Flags().BoolVar(&applyVerify, "verify", false, "执行后经 Kong 代理发送 routes[].tests 声明的冒烟测试，任一未达预期时以非零状态退出")
+    applyCmd.Flags().StringVar(&applyVerifyProxy, "proxy-url", "", "--verify 使用的 Kong 代理地址（默认取配置项 proxy_url，否则 http://localhost:8000）")
+    applyCmd.Flags().DurationVar(&applyVerifyTimeout, "verify-timeout", 30*time.Second, "--verify 等待配置生效的最长时间（未达预期时每秒重试）")
+    applyCmd.Flags().BoolVar(&applyNoSnapshot, "no-snapshot", false, "执行前不保存快照（默认保存到 ~/.kongctl/snapshots，可用 kongctl rollback 恢复）")
+    applyCmd.Flags().IntVar(&applyParallel, "parallel", 1, "同一阶段内并发处理的资源数（按 upstream -> target -> service -> route 分阶段执行，共享依赖的资源仍按顺序处理），例：--parallel 8")
+    applyCmd.Flags().BoolVar(&applyCreateWorkspace, "create-workspace", false, "--workspace 指定的 workspace 不存在时先创建（企业版），便于一次调用初始化 workspace 及其资源")
+    applyCmd.Flags().BoolVar(&applyNoLock, "no-lock", false, "不获取网关上的 apply 锁（默认执行变更前获取，另一个 kongctl 正在执行时立即失败）")
+    applyCmd.Flags().BoolVar(&applyForceUnlock, "force-unlock", false, "移除他人持有的 apply 锁后再执行（确认对方已异常退出时使用）")
+    applyCmd.Flags().BoolVar(&applyServerValidate, "server-validate", false, "执行或输出计划前将待创建/更新的资源提交到 Kong 的 /schemas/<entity>/validate 校验，未通过时不做任何变更")
+    applyCmd.Flags().StringVar(&applyAgainst, "against", "", "配合 --dry-run：以 kongctl export 导出的快照文件代替网关的当前状态计算计划，无需访问网关，例：--against snapshot.yaml")
+    applyCmd.Flags().BoolVar(&applyDetailedExit, "detailed-exitcode", false, "配合 --dry-run 使用退出码表示结果：0 无变更，1 出错，2 存在待执行变更（便于 CI 判断）")
+    applyCmd.Flags().StringVar(&applyPlanOut, "out", "", "配合 --dry-run 将计划（含远程状态指纹）保存到文件，例：--dry-run --out plan.json")
+    applyCmd.Flags().StringVar(&applyPlanFile, "plan", "", "执行 --out 保存的计划；规划后远程资源有变化时拒绝执行，例：--plan plan.json")
+    applyCmd.Flags().BoolVar(&applyAllContexts, "all-contexts", false, "对配置文件中的全部上下文（集群）依次规划并执行，汇总各集群结果；也可用 --context a,b 指定多个")
+    applyCmd.Flags().BoolVar(&applyWatch, "watch", false, "持续运行：按 --interval 周期重新读取 spec 并调和漂移（隐含 --overwrite，不保存快照），单轮失败在下一轮重试，可作为 GitOps 代理")
+    applyCmd.Flags().DurationVar(&applyWatchInterval, "interval", 30*time.Second, "--watch 的调和间隔，例：--interval 1m")
+    addBudgetFlags(applyCmd)
+    addRenderFlags(applyCmd)
+    applyCmd.Flags().StringVar(&applyAccessLog, "access-log", "", "访问日志样本（combined 格式或 Kong file-log JSON 行），dry-run 时估算各路由流量去向变化，例：--access-log access.log")
+    applyCmd.Flags().StringVar(&applyPrometheusURL, "prometheus-url", "", "Prometheus 地址（可含 user:pass@），dry-run 时按各 route 的请求数估算流量去向变化（与 --access-log 二选一），例：--prometheus-url http://prometheus:9090")
+    applyCmd.Flags().DurationVar(&applyPrometheusWindow, "prometheus-window", time.Hour, "统计请求数的时间窗口（配合 --prometheus-url）")
+    applyCmd.Flags().StringVar(&applyPrometheusQuery, "prometheus-query", "", "自定义 PromQL（结果需为带 route 标签的 vector，指定后忽略 --prometheus-window），默认 "+fmt.Sprintf(prometheusDefaultQuery, "<window>"))
 }
 
 // ----- apply example 子命令 -----
@@ -777,6 +1379,9 @@ kongctl apply example --type routes-simple -o examples/route-simple.yaml --force
 # 生成最简路由（引用已存在 service）且不包含注释
 kongctl apply example --type route-basic --no-comments
 
+# 生成英文注释的模板（便于分享给非中文团队成员）
+kongctl apply example --type full --lang en
+
 # 生成仓库中 examples/route-simple.yaml 风格的多路由模板
 kongctl apply example --type route-simple -o my-routes.yaml`,
     RunE: func(cmd *cobra.Command, args []string) error {
@@ -795,6 +1400,10 @@ kongctl apply example --type route-simple -o my-routes.yaml`,
         default:
             return fmt.Errorf("不支持的 --type：%s（可选：full、routes-simple、route-basic）", exampleType)
         }
+        content, err := localizeExample(content, exampleLang)
+        if err != nil {
+            return err
+        }
         if exampleNoComments {
             // 过滤注释行（保留 shebang 风格为空）
             var sb strings.Builder
@@ -819,7 +1428,7 @@ kongctl apply example --type route-simple -o my-routes.yaml`,
                 return fmt.Errorf("目标文件已存在：%s（使用 --force 覆盖）", exampleOutput)
             }
         }
-        if err := os.WriteFile(exampleOutput, []byte(content), 0o644); err != nil {
+        if err := writeTextFile(exampleOutput, []byte(content), 0o644); err != nil {
             return fmt.Errorf("写入示例失败：%w", err)
         }
         PrintSuccess(cmd, "示例已生成：%s（type=%s）", exampleOutput, t)
@@ -832,12 +1441,15 @@ func init() {
     applyExampleCmd.Flags().StringVarP(&exampleOutput, "output", "o", "", "输出文件路径（留空输出到控制台）")
     applyExampleCmd.Flags().BoolVar(&exampleNoComments, "no-comments", false, "移除注释，仅输出纯 YAML")
     applyExampleCmd.Flags().BoolVar(&exampleForce, "force", false, "覆盖已存在文件")
+    applyExampleCmd.Flags().StringVar(&exampleLang, "lang", "zh", "注释语言：zh 或 en，例：--lang en")
 }
 
 func exampleYAMLFull() string {
     return `# 通过 kongctl apply -f <file> 应用
 # 完整示例：包含 upstreams / services / routes 三类资源
 
+kongctl_format: v1                # 文件格式版本（旧文件可缺省，可用 kongctl spec upgrade 补齐）
+
 upstreams:
   - name: user-service-upstream   # 上游命名；与 Service 通过 host 关联
     targets:                      # 将后端实例注册为 target（host:port）
@@ -1025,27 +1637,31 @@ func exampleYAMLRouteSimpleRepo() string {
 // ----- 层级化 Dry-Run 展示 -----
 
 func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfos []autoRouteInfo, autoSvcSet, autoUpSet map[string]bool, withDiff bool) {
-    useColor := !(applyNoColor || viper.GetBool("no_color"))
-    ascii := applyASCII
+    useColor := !(applyNoColor || viper.GetBool("no_color") || plainTerminal)
+    ascii := applyASCII || minimalStyle()
     compact := applyCompact
     p := func(indent int, format string, args ...any) {
         cmd.Printf("%s%s\n", strings.Repeat("  ", indent), fmt.Sprintf(format, args...))
     }
     // color helpers
-    c := func(s, code string) string { if !useColor { return s }; return code + s + "\033[0m" }
-    header := func(s string) string { return c(s, "\033[36;1m") }      // bold cyan
-    accent := func(s string) string { return c(s, "\033[35;1m") }      // bold magenta
-    subtle := func(s string) string { return c(s, "\033[90m") }        // gray
+    pal := activePalette
+    c := func(s, code string) string { if !useColor { return s }; return sgr(code) + s + "\033[0m" }
+    header := func(s string) string { return c(s, pal.Header) }
+    accent := func(s string) string { return c(s, pal.Accent) }
+    subtle := func(s string) string { return c(s, pal.Subtle) }
     actColor := func(a string) string {
         switch a {
         case "create":
-            if ascii { return c("创建", "\033[32m") }
-            return c("创建 ✨", "\033[32m") // green
+            if ascii { return c("创建", pal.Create) }
+            return c("创建 ✨", pal.Create)
         case "update":
-            if ascii { return c("更新", "\033[33m") }
-            return c("更新 ♻️", "\033[33m") // yellow
+            if ascii { return c("更新", pal.Update) }
+            return c("更新 ♻️", pal.Update)
+        case "delete":
+            if ascii { return c("删除", pal.Removed) }
+            return c("删除 🗑️", pal.Removed)
         case "none":
-            return c("无变化", "\033[90m") // gray
+            return c("无变化", pal.Subtle)
         default:
             return a
         }
@@ -1054,13 +1670,38 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
         if !useColor { return line }
         s := strings.TrimSpace(line)
         if strings.HasPrefix(s, "+ ") || strings.HasPrefix(s, "+") {
-            return "\033[32m" + line + "\033[0m"
+            return c(line, pal.Added)
         }
         if strings.HasPrefix(s, "- ") || strings.HasPrefix(s, "-") {
-            return "\033[31m" + line + "\033[0m"
+            return c(line, pal.Removed)
         }
         return line
     }
+    // printDiff 输出计划项的差异：有统一 diff 时原样输出（@@ 行使用强调色），否则逐行加 bullet 前缀
+    printDiff := func(indent int, ch aplan.Change, bullet string) {
+        if u, ok := applyUnifiedDiffs[ch.Kind+"/"+ch.Name]; ok {
+            for _, line := range strings.Split(strings.TrimRight(u, "\n"), "\n") {
+                switch {
+                case strings.HasPrefix(line, "@@"):
+                    p(indent, "%s", accent(line))
+                case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+                    p(indent, "%s", subtle(line))
+                case strings.HasPrefix(line, "+"):
+                    p(indent, "%s", c(line, pal.Added))
+                case strings.HasPrefix(line, "-"):
+                    p(indent, "%s", c(line, pal.Removed))
+                default:
+                    p(indent, "%s", line)
+                }
+            }
+            return
+        }
+        if strings.TrimSpace(ch.Diff) == "" { return }
+        for _, line := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
+            if strings.TrimSpace(line) == "" { continue }
+            p(indent, "%s", diffColor(bullet+line))
+        }
+    }
     kindIcon := func(kind string) string {
         if ascii {
             switch kind {
@@ -1068,6 +1709,13 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
             case "Target": return "[T]"
             case "Service": return "[S]"
             case "Route": return "[R]"
+            case "Consumer": return "[C]"
+            case "Credential": return "[K]"
+            case "ConsumerGroup": return "[G]"
+            case "Certificate": return "[X]"
+            case "Vault": return "[V]"
+            case "KeySet": return "[Y]"
+            case "Key": return "[J]"
             default: return "[*]"
             }
         }
@@ -1076,11 +1724,18 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
         case "Target": return "🎯"
         case "Service": return "🧩"
         case "Route": return "🛣️"
+        case "Consumer": return "👤"
+        case "Credential": return "🔑"
+        case "ConsumerGroup": return "👥"
+        case "Certificate": return "🔒"
+        case "Vault": return "🗝️"
+        case "KeySet": return "🔐"
+        case "Key": return "🔏"
         default: return "•"
         }
     }
     sep := func() {
-        if ascii { p(0, strings.Repeat("=", 40)) } else { p(0, strings.Repeat("─", 40)) }
+        if ascii { p(0, "%s", strings.Repeat("=", 40)) } else { p(0, "%s", strings.Repeat("─", 40)) }
     }
     find := func(kind, name string) *aplan.Change {
         for i := range plan.Items {
@@ -1101,23 +1756,30 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
     }
 
     // summary header
-    p(0, header("变更计划："))
+    p(0, "%s", header(contextBanner()+"变更计划："))
     sep()
     // 汇总计数
-    type cnt struct{ c, u, n int }
+    type cnt struct{ c, u, n, d int }
     var cntUp, cntSvc, cntRt, cntTgt cnt
 
+    if len(spec.Vaults) > 0 {
+        p(1, "%s", header("Vaults:"))
+        printVaultPlan(p, spec.Vaults, find, kindIcon, actColor, diffColor, compact, withDiff)
+        sep()
+    }
+
     // 顶层 Upstreams（排除由简写自动生成的）
     if len(spec.Upstreams) > 0 {
-        p(1, header("Upstreams:"))
+        p(1, "%s", header("Upstreams:"))
         for _, up := range spec.Upstreams {
             ch := find("Upstream", up.Name)
             action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
             switch action { case "create": cntUp.c++; case "update": cntUp.u++; default: cntUp.n++ }
             if compact && action == "none" && len(up.Targets) == 0 { continue }
             p(2, "%s %s (%s)", kindIcon("Upstream"), up.Name, actColor(action))
+            if withDiff && ch != nil { printDiff(3, *ch, "- ") }
             // targets from spec
-            if len(up.Targets) > 0 { p(3, subtle("Targets:")) }
+            if len(up.Targets) > 0 { p(3, "%s", subtle("Targets:")) }
             for _, t := range up.Targets {
                 // find plan result for this target
                 tname := up.Name + "/" + t.Target
@@ -1132,22 +1794,18 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
 
     // 顶层 Services（排除由简写自动生成的）
     if len(spec.Services) > 0 {
-        p(1, header("Services:"))
+        p(1, "%s", header("Services:"))
         for _, s := range spec.Services {
             ch := find("Service", s.Name)
             action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
             switch action { case "create": cntSvc.c++; case "update": cntSvc.u++; default: cntSvc.n++ }
             if compact && action == "none" && (ch == nil || strings.TrimSpace(ch.Diff) == "") && len(s.Targets) == 0 { continue }
             p(2, "%s %s (%s)", kindIcon("Service"), s.Name, actColor(action))
-            if withDiff && ch != nil && strings.TrimSpace(ch.Diff) != "" {
-                for _, line := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
-                    if strings.TrimSpace(line) == "" { continue }
-                    p(3, "%s", diffColor("- "+line))
-                }
-            }
+            if len(s.Annotations) > 0 { p(3, "%s", subtle("注解："+formatAnnotations(s.Annotations))) }
+            if withDiff && ch != nil { printDiff(3, *ch, "- ") }
             // If service carries targets in spec, show them under its upstream (if provided)
             if s.Upstream != "" && len(s.Targets) > 0 {
-                p(3, subtle(fmt.Sprintf("Targets (Upstream %s):", s.Upstream)))
+                p(3, "%s", subtle(fmt.Sprintf("Targets (Upstream %s):", s.Upstream)))
                 for _, t := range s.Targets {
                     tname := s.Upstream + "/" + t.Target
                     taction := "none"
@@ -1162,7 +1820,7 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
 
     // Routes（包含简写的嵌套展示）
     if len(spec.Routes) > 0 {
-        p(1, header("Routes:"))
+        p(1, "%s", header("Routes:"))
         routePrinted := false
         for _, r := range spec.Routes {
             name := r.Name
@@ -1174,14 +1832,13 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
             if compact && action == "none" && (ch == nil || strings.TrimSpace(ch.Diff) == "") && len(r.Backend.Targets) == 0 { continue }
             // route-level separator between different routes (accent color)
             if routePrinted {
-                if ascii { p(2, accent(strings.Repeat("=", 40))) } else { p(2, accent(strings.Repeat("━", 40))) }
+                if ascii { p(2, "%s", accent(strings.Repeat("=", 40))) } else { p(2, "%s", accent(strings.Repeat("━", 40))) }
             }
             p(2, "%s %s (%s)", kindIcon("Route"), name, actColor(action))
-            if withDiff && ch != nil && ch.Diff != "" {
-                for _, line := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
-                    if strings.TrimSpace(line) == "" { continue }
-                    p(3, "%s", diffColor(line))
-                }
+            if len(r.Annotations) > 0 { p(3, "%s", subtle("注解："+formatAnnotations(r.Annotations))) }
+            if withDiff && ch != nil { printDiff(3, *ch, "") }
+            if ch != nil {
+                for _, n := range ch.Notes { p(3, "%s", subtle(n)) }
             }
             // 若为简写，嵌套其 service 和 upstream
             if r.Service == "" {
@@ -1193,12 +1850,7 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
                 if svcName != "" {
                     if sch := find("Service", svcName); sch != nil {
                         p(3, "%s Service: %s (%s)", kindIcon("Service"), svcName, actColor(sch.Action))
-                        if withDiff && strings.TrimSpace(sch.Diff) != "" {
-                            for _, line := range strings.Split(strings.TrimSpace(sch.Diff), "\n") {
-                                if strings.TrimSpace(line) == "" { continue }
-                                p(4, "%s", diffColor(line))
-                            }
-                        }
+                        if withDiff { printDiff(4, *sch, "") }
                     } else {
                         p(3, "%s Service: %s (%s)", kindIcon("Service"), svcName, actColor("none"))
                     }
@@ -1210,7 +1862,7 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
                         p(3, "%s Upstream: %s (%s)", kindIcon("Upstream"), upName, actColor("none"))
                     }
                     // targets from spec backend
-                    if len(r.Backend.Targets) > 0 { p(4, subtle("Targets:")) }
+                    if len(r.Backend.Targets) > 0 { p(4, "%s", subtle("Targets:")) }
                     for _, t := range r.Backend.Targets {
                         tname := upName + "/" + t.Target
                         taction := "none"
@@ -1224,39 +1876,110 @@ func printHierPlan(cmd *cobra.Command, plan aplan.Plan, spec applySpec, autoInfo
         sep()
     }
 
+    if applyPrune || applyPruneTargets {
+        p(1, "%s", header("Prune（未在文件中声明的受管资源）:"))
+        pruned := 0
+        for _, it := range plan.Items {
+            if it.Action != "delete" { continue }
+            p(2, "%s %s (%s)", kindIcon(it.Kind), it.Name, actColor(it.Action))
+            pruned++
+        }
+        if pruned == 0 { p(2, "%s", subtle("无")) }
+        sep()
+    }
+
+    if len(spec.ConsumerGroups) > 0 {
+        p(1, "%s", header("Consumer Groups:"))
+        printConsumerGroupPlan(p, spec.ConsumerGroups, find, kindIcon, actColor, diffColor, compact, withDiff)
+        sep()
+    }
+
+    if len(spec.Consumers) > 0 {
+        p(1, "%s", header("Consumers:"))
+        printConsumerPlan(p, spec.Consumers, find, kindIcon, actColor, diffColor, subtle, compact, withDiff)
+        sep()
+    }
+
+    if len(spec.Certificates) > 0 {
+        p(1, "%s", header("Certificates:"))
+        printCertificatePlan(p, spec.Certificates, find, kindIcon, actColor, diffColor, compact, withDiff)
+        sep()
+    }
+    if len(spec.KeySets) > 0 {
+        p(1, "%s", header("Key Sets:"))
+        printKeySetPlan(p, spec.KeySets, find, kindIcon, actColor, diffColor, compact, withDiff)
+        sep()
+    }
+
     // 汇总（基于 plan 重新准确统计，包含简写自动生成项）
+    var cntGrp, cntCs, cntCred, cntCert, cntVault, cntKS, cntKey cnt
     cntUp, cntSvc, cntRt, cntTgt = cnt{}, cnt{}, cnt{}, cnt{}
+    count := func(x *cnt, action string) {
+        switch action { case "create": x.c++; case "update": x.u++; case "delete": x.d++; default: x.n++ }
+    }
     for _, it := range plan.Items {
-        action := it.Action
         switch it.Kind {
-        case "Upstream":
-            if action == "create" { cntUp.c++ } else if action == "update" { cntUp.u++ } else { cntUp.n++ }
-        case "Service":
-            if action == "create" { cntSvc.c++ } else if action == "update" { cntSvc.u++ } else { cntSvc.n++ }
-        case "Route":
-            if action == "create" { cntRt.c++ } else if action == "update" { cntRt.u++ } else { cntRt.n++ }
-        case "Target":
-            if action == "create" { cntTgt.c++ } else if action == "update" { cntTgt.u++ } else { cntTgt.n++ }
+        case "Upstream": count(&cntUp, it.Action)
+        case "Service": count(&cntSvc, it.Action)
+        case "Route": count(&cntRt, it.Action)
+        case "Target": count(&cntTgt, it.Action)
+        case "ConsumerGroup": count(&cntGrp, it.Action)
+        case "Consumer": count(&cntCs, it.Action)
+        case "Credential": count(&cntCred, it.Action)
+        case "Certificate": count(&cntCert, it.Action)
+        case "Vault": count(&cntVault, it.Action)
+        case "KeySet": count(&cntKS, it.Action)
+        case "Key": count(&cntKey, it.Action)
         }
     }
     colNum := func(n int, a string) string {
         s := fmt.Sprintf("%d", n)
         if !useColor { return s }
         switch a {
-        case "create": return c(s, "\033[32;1m") // bold green
-        case "update": return c(s, "\033[33;1m") // bold yellow
-        case "none":   return c(s, "\033[90m")   // gray
+        case "create": return c(s, "1;"+pal.Create)
+        case "update": return c(s, "1;"+pal.Update)
+        case "delete": return c(s, "1;"+pal.Removed)
+        case "none":   return c(s, pal.Subtle)
         }
         return s
     }
-    p(0, header("汇总："))
-    p(1, "Upstreams: 创建 %s，更新 %s，无变化 %s", colNum(cntUp.c, "create"), colNum(cntUp.u, "update"), colNum(cntUp.n, "none"))
-    p(1, "Services: 创建 %s，更新 %s，无变化 %s", colNum(cntSvc.c, "create"), colNum(cntSvc.u, "update"), colNum(cntSvc.n, "none"))
-    p(1, "Routes:   创建 %s，更新 %s，无变化 %s", colNum(cntRt.c, "create"), colNum(cntRt.u, "update"), colNum(cntRt.n, "none"))
-    p(1, "Targets:  创建 %s，更新 %s，无变化 %s", colNum(cntTgt.c, "create"), colNum(cntTgt.u, "update"), colNum(cntTgt.n, "none"))
+    // 启用 --prune 时在各行末尾追加删除计数
+    del := func(x cnt) string {
+        if !applyPrune && !applyPruneTargets { return "" }
+        return "，删除 " + colNum(x.d, "delete")
+    }
+    p(0, "%s", header(contextBanner()+"汇总："))
+    p(1, "Upstreams: 创建 %s，更新 %s，无变化 %s%s", colNum(cntUp.c, "create"), colNum(cntUp.u, "update"), colNum(cntUp.n, "none"), del(cntUp))
+    p(1, "Services: 创建 %s，更新 %s，无变化 %s%s", colNum(cntSvc.c, "create"), colNum(cntSvc.u, "update"), colNum(cntSvc.n, "none"), del(cntSvc))
+    p(1, "Routes:   创建 %s，更新 %s，无变化 %s%s", colNum(cntRt.c, "create"), colNum(cntRt.u, "update"), colNum(cntRt.n, "none"), del(cntRt))
+    p(1, "Targets:  创建 %s，更新 %s，无变化 %s%s", colNum(cntTgt.c, "create"), colNum(cntTgt.u, "update"), colNum(cntTgt.n, "none"), del(cntTgt))
+    if len(spec.ConsumerGroups) > 0 {
+        p(1, "Consumer Groups: 创建 %s，更新 %s，无变化 %s", colNum(cntGrp.c, "create"), colNum(cntGrp.u, "update"), colNum(cntGrp.n, "none"))
+    }
+    if len(spec.Consumers) > 0 {
+        p(1, "Consumers: 创建 %s，更新 %s，无变化 %s", colNum(cntCs.c, "create"), colNum(cntCs.u, "update"), colNum(cntCs.n, "none"))
+        p(1, "Credentials: 创建 %s，更新 %s，无变化 %s", colNum(cntCred.c, "create"), colNum(cntCred.u, "update"), colNum(cntCred.n, "none"))
+    }
+    if len(spec.Vaults) > 0 {
+        p(1, "Vaults: 创建 %s，更新 %s，无变化 %s", colNum(cntVault.c, "create"), colNum(cntVault.u, "update"), colNum(cntVault.n, "none"))
+    }
+    if len(spec.Certificates) > 0 {
+        p(1, "Certificates: 创建 %s，更新 %s，无变化 %s", colNum(cntCert.c, "create"), colNum(cntCert.u, "update"), colNum(cntCert.n, "none"))
+    }
+    if len(spec.KeySets) > 0 {
+        p(1, "Key Sets: 创建 %s，更新 %s，无变化 %s", colNum(cntKS.c, "create"), colNum(cntKS.u, "update"), colNum(cntKS.n, "none"))
+        p(1, "Keys: 创建 %s，更新 %s，无变化 %s", colNum(cntKey.c, "create"), colNum(cntKey.u, "update"), colNum(cntKey.n, "none"))
+    }
+    if owners := ownerSummary(spec); owners != "" {
+        p(1, "Owners:   %s", owners)
+    }
+    // minimal 风格不输出装饰性提示
+    if minimalStyle() {
+        return
+    }
     if !ascii {
-        p(0, subtle("提示：可使用 --no-color 关闭颜色，--ascii 使用 ASCII，--compact 隐藏无变化项"))
+        p(0, "%s", subtle("提示：可使用 --no-color 关闭颜色，--ascii 使用 ASCII，--compact 隐藏无变化项"))
     } else {
-        p(0, "提示：可使用 --no-color 关闭颜色，--ascii 使用 ASCII，--compact 隐藏无变化项")
+        p(0, "%s", "提示：可使用 --no-color 关闭颜色，--ascii 使用 ASCII，--compact 隐藏无变化项")
     }
 }
@@ -0,0 +1,152 @@
+package cli
+
+import (
+    "context"
+    "crypto/x509"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyCertificate 为 spec 中的 TLS 证书及其 SNI：cert_file/key_file 为 PEM 文件，相对路径以声明它的 spec 文件所在目录为基准；
+// 私钥也可用 key_env 从环境变量读取，避免与 spec 一同提交。证书按 snis 已绑定的证书匹配，snis[0] 作为其在计划中的名称，例如：
+//
+//	certificates:
+//	  - cert_file: certs/api.crt
+//	    key_env: API_TLS_KEY
+//	    snis: [api.example.com, www.example.com]
+type applyCertificate struct {
+    CertFile string   `yaml:"cert_file" json:"cert_file"`
+    KeyFile  string   `yaml:"key_file,omitempty" json:"key_file,omitempty"`
+    KeyEnv   string   `yaml:"key_env,omitempty" json:"key_env,omitempty"`
+    Snis     []string `yaml:"snis" json:"snis"`
+    Tags     []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+    source   string
+}
+
+// key 返回证书在计划、选择器与冲突检查中的名称（第一个 SNI）
+func (c applyCertificate) key() string {
+    if len(c.Snis) > 0 { return c.Snis[0] }
+    return c.CertFile
+}
+
+// resolveCertificatePaths 将 certificates[].cert_file/key_file 的相对路径转换为绝对路径（以声明它的文件所在目录为基准），
+// 使 --plan 保存的 spec 在其他目录下执行时仍能读取到证书
+func resolveCertificatePaths(spec *applySpec, file string) {
+    for i := range spec.Certificates {
+        c := &spec.Certificates[i]
+        resolveSpecPaths(c.source, file, &c.CertFile, &c.KeyFile)
+    }
+}
+
+// resolveSpecPaths 将 paths 中的相对路径转换为绝对路径：以 source（资源所在文件，为空时为 file）所在目录为基准
+func resolveSpecPaths(source, file string, paths ...*string) {
+    base := source
+    if base == "" { base = file }
+    dir := "."
+    if base != "" && base != "-" { dir = filepath.Dir(expandPath(base)) }
+    for _, p := range paths {
+        if *p == "" { continue }
+        *p = expandPath(*p)
+        if !filepath.IsAbs(*p) {
+            if abs, err := filepath.Abs(filepath.Join(dir, *p)); err == nil { *p = abs }
+        }
+    }
+}
+
+// loadSpecCertificate 读取证书与私钥并校验配对与有效期
+func loadSpecCertificate(c applyCertificate) (certPEM, keyPEM string, leaf *x509.Certificate, err error) {
+    if c.CertFile == "" {
+        return "", "", nil, fmt.Errorf("缺少 cert_file")
+    }
+    certBytes, err := os.ReadFile(c.CertFile)
+    if err != nil {
+        return "", "", nil, fmt.Errorf("读取证书失败：%w", err)
+    }
+    var keyBytes []byte
+    switch {
+    case c.KeyFile != "" && c.KeyEnv != "":
+        return "", "", nil, fmt.Errorf("key_file 与 key_env 只能指定一个")
+    case c.KeyFile != "":
+        if keyBytes, err = os.ReadFile(c.KeyFile); err != nil {
+            return "", "", nil, fmt.Errorf("读取私钥失败：%w", err)
+        }
+    case c.KeyEnv != "":
+        val, set := os.LookupEnv(c.KeyEnv)
+        if !set { return "", "", nil, fmt.Errorf("key_env 引用的环境变量 %s 未设置", c.KeyEnv) }
+        keyBytes = []byte(val)
+    default:
+        return "", "", nil, fmt.Errorf("缺少 key_file 或 key_env")
+    }
+    if leaf, err = checkCertPair(certBytes, keyBytes, c.CertFile); err != nil {
+        return "", "", nil, err
+    }
+    return string(certBytes), string(keyBytes), leaf, nil
+}
+
+// applyCertificates 上传 spec 中的证书并绑定 SNI；dry-run 时仅写入计划。
+// 与其他资源一致：新证书直接上传，已有证书的替换（续期）或 SNI、标签变更需 --overwrite
+func applyCertificates(cmd *cobra.Command, ctx context.Context, client *kong.Client, certs []applyCertificate, plan *aplan.Plan) error {
+    keys := func(i int) []string {
+        out := make([]string, len(certs[i].Snis))
+        for j, s := range certs[i].Snis { out[j] = "sni:" + s }
+        return out
+    }
+    return runItems("certificates", len(certs), keys, plan, func(i int, plan *aplan.Plan) error {
+        c := certs[i]
+        if len(c.Snis) == 0 { return fmt.Errorf("certificates[%d] 缺少 snis", i) }
+        certPEM, keyPEM, leaf, err := loadSpecCertificate(c)
+        if err != nil { return fmt.Errorf("certificates[%s]：%w", c.key(), err) }
+        cur, err := findCertificateForSnis(ctx, client, c.Snis)
+        if err != nil { return fmt.Errorf("certificates[%s]：%w", c.key(), err) }
+        action, diff := "create", "+ cert: "+certSummary(leaf)+"\n"
+        var want []string
+        if cur != nil {
+            if len(c.Tags) > 0 { want = desiredTags(client, cur.Tags, c.Tags) }
+            diff = certificateDiff(cur, leaf, c.Snis, want)
+            action = "none"
+            if diff != "" { action = "update" }
+        }
+        if dryRun {
+            plan.Items = append(plan.Items, aplan.Change{Kind: "Certificate", Name: c.key(), Action: action, Diff: diff})
+            return nil
+        }
+        if showDiff { PrintInfo(cmd, "同步证书：%s", c.key()) }
+        desired := kong.Certificate{Cert: certPEM, Key: keyPEM, Snis: c.Snis, Tags: c.Tags}
+        switch {
+        case cur == nil:
+            warnSniMismatch(cmd, leaf, c.Snis)
+            if _, err := client.CreateCertificate(ctx, desired); err != nil { return err }
+            PrintSuccess(cmd, "已上传证书：%s（%s）", c.key(), certSummary(leaf))
+        case action == "update" && applyOverwrite:
+            warnSniMismatch(cmd, leaf, c.Snis)
+            desired.Tags = want
+            if _, err := client.UpdateCertificate(ctx, cur.ID, desired); err != nil { return err }
+            PrintSuccess(cmd, "已更新证书：%s（%s）", c.key(), certSummary(leaf))
+        case action == "update":
+            PrintWarn(cmd, "检测到证书变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", c.key())
+        }
+        return nil
+    })
+}
+
+// printCertificatePlan 在层级计划中展示证书及其 SNI 的差异
+func printCertificatePlan(p func(int, string, ...any), certs []applyCertificate, find func(kind, name string) *aplan.Change,
+    icon, actColor, diffColor func(string) string, compact, withDiff bool) {
+    for _, c := range certs {
+        ch := find("Certificate", c.key())
+        action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
+        if compact && action == "none" { continue }
+        p(2, "%s %s (%s)  snis: %s", icon("Certificate"), c.key(), actColor(action), strings.Join(c.Snis, ", "))
+        if withDiff && ch != nil {
+            for _, l := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
+                if strings.TrimSpace(l) != "" { p(3, "%s", diffColor(l)) }
+            }
+        }
+    }
+}
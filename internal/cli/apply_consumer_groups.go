@@ -0,0 +1,162 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyConsumerGroup 为 spec 中的 consumer group（Kong Enterprise / 3.4+）；plugins 为挂在组上的插件，
+// 键为插件名、值为 config（只比较给出的字段），配合 consumers[].groups 实现分级限流，例如：
+//
+//	consumer_groups:
+//	  - name: gold
+//	    plugins:
+//	      rate-limiting: {minute: 1000, policy: local}
+//	consumers:
+//	  - username: partner-a
+//	    groups: [gold]
+type applyConsumerGroup struct {
+    Name    string                    `yaml:"name" json:"name"`
+    Tags    []string                  `yaml:"tags,omitempty" json:"tags,omitempty"`
+    Plugins map[string]map[string]any `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+    source  string
+}
+
+func (g applyConsumerGroup) pluginNames() []string {
+    names := make([]string, 0, len(g.Plugins))
+    for n := range g.Plugins { names = append(names, n) }
+    sort.Strings(names)
+    return names
+}
+
+// desiredTags 返回 consumer group、证书等资源的期望标签：spec 中的 tags 加上客户端附加的标签，并保留已有的 managed-by 标签
+func desiredTags(client *kong.Client, cur, tags []string) []string {
+    out := append([]string{}, tags...)
+    for _, t := range client.ConfigTags() {
+        if !kong.HasTag(out, t) { out = append(out, t) }
+    }
+    if kong.HasTag(cur, managedByTag()) && !kong.HasTag(out, managedByTag()) { out = append(out, managedByTag()) }
+    return out
+}
+
+// consumerGroupDiff 比较组的 tags 与插件配置；返回差异以及需要新建、需要更新的插件
+func consumerGroupDiff(ctx context.Context, client *kong.Client, cur *kong.ConsumerGroup, g applyConsumerGroup) (diff string, missing, changed []string, err error) {
+    if want := desiredTags(client, cur.Tags, g.Tags); len(g.Tags) > 0 && !sliceSetEqual(cur.Tags, want) { diff += diffSlice("tags", cur.Tags, want) }
+    for _, name := range g.pluginNames() {
+        p, ok, err := client.GetConsumerGroupPlugin(ctx, cur.ID, name)
+        if err != nil {
+            return "", nil, nil, err
+        }
+        if !ok {
+            missing = append(missing, name)
+            diff += "+ plugins." + name + "\n"
+            continue
+        }
+        if d := pluginConfigDiff("plugins."+name+".config", p.Config, g.Plugins[name]); d != "" {
+            changed = append(changed, name)
+            diff += d
+        }
+    }
+    return diff, missing, changed, nil
+}
+
+// applyConsumerGroups 同步 consumer groups 及组上的插件；dry-run 时仅写入计划。
+// 与凭证一致：缺失的插件直接创建，已存在且配置不同的插件需 --overwrite 才更新
+func applyConsumerGroups(cmd *cobra.Command, ctx context.Context, client *kong.Client, groups []applyConsumerGroup, plan *aplan.Plan) error {
+    keys := func(i int) []string { return []string{"consumer_group:" + groups[i].Name} }
+    return runItems("consumer_groups", len(groups), keys, plan, func(i int, plan *aplan.Plan) error {
+        g := groups[i]
+        if g.Name == "" { return fmt.Errorf("consumer_groups[%d] 缺少 name", i) }
+        cur, ok, err := client.GetConsumerGroup(ctx, g.Name)
+        if err != nil && !dryRun { return err }
+        action, diff := "create", ""
+        missing, changed := g.pluginNames(), []string(nil)
+        if ok {
+            if diff, missing, changed, err = consumerGroupDiff(ctx, client, cur, g); err != nil && !dryRun { return err }
+            action = "none"
+            if diff != "" { action = "update" }
+        } else {
+            for _, name := range missing { diff += "+ plugins." + name + "\n" }
+        }
+        if dryRun {
+            plan.Items = append(plan.Items, aplan.Change{Kind: "ConsumerGroup", Name: g.Name, Action: action, Diff: diff})
+            return nil
+        }
+        if showDiff { PrintInfo(cmd, "同步 Consumer Group：%s", g.Name) }
+        tagsChanged := ok && len(g.Tags) > 0 && !sliceSetEqual(cur.Tags, desiredTags(client, cur.Tags, g.Tags))
+        switch {
+        case !ok:
+            if _, _, err := client.CreateOrUpdateConsumerGroup(ctx, kong.ConsumerGroup{Name: g.Name, Tags: g.Tags}); err != nil { return err }
+            PrintSuccess(cmd, "已创建 Consumer Group：%s", g.Name)
+        case tagsChanged && applyOverwrite:
+            if _, _, err := client.CreateOrUpdateConsumerGroup(ctx, kong.ConsumerGroup{Name: g.Name, Tags: desiredTags(client, cur.Tags, g.Tags)}); err != nil { return err }
+            PrintSuccess(cmd, "已更新 Consumer Group：%s", g.Name)
+        case tagsChanged:
+            PrintWarn(cmd, "检测到 Consumer Group 标签变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", g.Name)
+        }
+        for _, name := range missing {
+            if _, _, err := client.CreateOrUpdateConsumerGroupPlugin(ctx, g.Name, kong.Plugin{Name: name, Config: g.Plugins[name]}); err != nil { return err }
+            PrintSuccess(cmd, "已创建插件：%s（consumer_group=%s）", name, g.Name)
+        }
+        for _, name := range changed {
+            if !applyOverwrite {
+                PrintWarn(cmd, "检测到插件配置变更但未启用覆盖：%s（consumer_group=%s，跳过，使用 --overwrite 应用变更）", name, g.Name)
+                continue
+            }
+            if _, _, err := client.CreateOrUpdateConsumerGroupPlugin(ctx, g.Name, kong.Plugin{Name: name, Config: g.Plugins[name]}); err != nil { return err }
+            PrintSuccess(cmd, "已更新插件：%s（consumer_group=%s）", name, g.Name)
+        }
+        return nil
+    })
+}
+
+// syncConsumerGroups 按 want 调和 consumer 的分组成员关系：加入缺少的分组、移出多余的分组
+func syncConsumerGroups(ctx context.Context, client *kong.Client, consumer string, cur, want []string) error {
+    for _, g := range want {
+        if !sliceContains(cur, g) {
+            if err := client.AddConsumerGroupMember(ctx, g, consumer); err != nil { return fmt.Errorf("将 Consumer %s 加入分组 %s 失败：%w", consumer, g, err) }
+        }
+    }
+    for _, g := range cur {
+        if !sliceContains(want, g) {
+            if err := client.RemoveConsumerGroupMember(ctx, g, consumer); err != nil { return fmt.Errorf("将 Consumer %s 移出分组 %s 失败：%w", consumer, g, err) }
+        }
+    }
+    return nil
+}
+
+// consumerGroupNames 返回 consumer 当前所属分组的名称
+func consumerGroupNames(ctx context.Context, client *kong.Client, consumer string) ([]string, error) {
+    groups, err := client.ListConsumerGroupsOf(ctx, consumer)
+    if err != nil {
+        return nil, err
+    }
+    names := make([]string, len(groups))
+    for i, g := range groups { names[i] = g.Name }
+    sort.Strings(names)
+    return names, nil
+}
+
+// printConsumerGroupPlan 在层级计划中展示 consumer groups 及组上插件的差异
+func printConsumerGroupPlan(p func(int, string, ...any), groups []applyConsumerGroup, find func(kind, name string) *aplan.Change,
+    icon, actColor, diffColor func(string) string, compact, withDiff bool) {
+    for _, g := range groups {
+        ch := find("ConsumerGroup", g.Name)
+        action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
+        if compact && action == "none" { continue }
+        line := fmt.Sprintf("%s %s (%s)", icon("ConsumerGroup"), g.Name, actColor(action))
+        if names := g.pluginNames(); len(names) > 0 { line += "  plugins: " + strings.Join(names, ", ") }
+        p(2, "%s", line)
+        if withDiff && ch != nil {
+            for _, l := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
+                if strings.TrimSpace(l) != "" { p(3, "%s", diffColor(l)) }
+            }
+        }
+    }
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyConsumer 为 spec 中的 consumer 定义；credentials 按凭证类型分组，例如：
+//
+//	credentials:
+//	  key-auth:   [{key: abc123}]
+//	  basic-auth: [{username: alice, password: s3cret}]
+//	  hmac-auth:  [{username: alice-hmac, secret: s3cret}]
+//	  oauth2:     [{name: portal, client_id: portal-app, client_secret_env: PORTAL_SECRET, redirect_uris: [https://portal/cb]}]
+//	  mtls-auth:  [{subject_name: alice.internal.example.com, ca_certificate: <CA 证书 ID>}]
+//	  acls:       [{group: admins}]
+//
+// groups 为所属的 consumer group（见 apply_consumer_groups.go）；未声明时不管理成员关系，声明 [] 表示移出全部分组
+type applyConsumer struct {
+    Username    string                      `yaml:"username" json:"username"`
+    CustomID    string                      `yaml:"custom_id" json:"custom_id"`
+    Tags        []string                    `yaml:"tags" json:"tags"`
+    Groups      []string                    `yaml:"groups,omitempty" json:"groups"`
+    Credentials map[string][]map[string]any `yaml:"credentials" json:"credentials"`
+    source      string
+}
+
+func (c applyConsumer) key() string {
+    if c.Username != "" { return c.Username }
+    return c.CustomID
+}
+
+// secretCredentialFields 为不会原样回显（或不应显示）的字段：不参与差异比较，展示时打码
+var secretCredentialFields = map[string]bool{"password": true, "secret": true, "client_secret": true}
+
+// resolveCredentialEnv 将密码类字段的 <field>_env（如 client_secret_env: PORTAL_SECRET）替换为对应环境变量的值，
+// 使密钥不必写入 spec 文件；环境变量未设置时报错
+func resolveCredentialEnv(cred map[string]any) (map[string]any, error) {
+    out := make(map[string]any, len(cred))
+    for k, v := range cred { out[k] = v }
+    for field := range secretCredentialFields {
+        ref, ok := cred[field+"_env"]
+        if !ok { continue }
+        name, _ := ref.(string)
+        if name == "" { return nil, fmt.Errorf("%s_env 需为环境变量名", field) }
+        if _, dup := cred[field]; dup { return nil, fmt.Errorf("%s 与 %s_env 只能指定一个", field, field) }
+        val, set := os.LookupEnv(name)
+        if !set { return nil, fmt.Errorf("%s_env 引用的环境变量 %s 未设置", field, name) }
+        delete(out, field+"_env")
+        out[field] = val
+    }
+    return out, nil
+}
+
+// credentialRefFields 为凭证中引用其他实体的字段：spec 中可直接写 ID，写入前展开为 {id: ...}，与 Kong 的返回值一致
+var credentialRefFields = []string{"ca_certificate"}
+
+func expandCredentialRefs(cred map[string]any) {
+    for _, f := range credentialRefFields {
+        if id, ok := cred[f].(string); ok && id != "" { cred[f] = map[string]any{"id": id} }
+    }
+}
+
+// credentialLabel 返回计划中展示的凭证名称（key-auth 的 key 本身即密钥，需打码）
+func credentialLabel(kind, ident string) string {
+    if kind == "key-auth" && len(ident) > 4 {
+        ident = ident[:4] + "***"
+    }
+    return kind + ":" + ident
+}
+
+// credentialDiff 比较期望凭证与远程凭证的非密钥字段，返回差异说明
+func credentialDiff(desired map[string]any, cur kong.Credential) string {
+    keys := make([]string, 0, len(desired))
+    for k := range desired { keys = append(keys, k) }
+    sort.Strings(keys)
+    diff := ""
+    for _, k := range keys {
+        if secretCredentialFields[k] { continue }
+        diff += diffNested(k, cur[k], desired[k])
+    }
+    return diff
+}
+
+func consumerDiff(cur *kong.Consumer, c applyConsumer) string {
+    diff := ""
+    if c.CustomID != "" && cur.CustomID != c.CustomID { diff += fmt.Sprintf("custom_id: %s -> %s\n", cur.CustomID, c.CustomID) }
+    if len(c.Tags) > 0 && !sliceSetEqual(cur.Tags, c.Tags) { diff += diffSlice("tags", cur.Tags, c.Tags) }
+    return diff
+}
+
+// applyConsumers 同步 consumers 及其凭证；dry-run 时仅写入计划。
+// 与其他资源一致：默认只创建缺失项，已存在且有差异时需 --overwrite 才更新。
+func applyConsumers(cmd *cobra.Command, ctx context.Context, client *kong.Client, consumers []applyConsumer, plan *aplan.Plan) error {
+    keys := func(i int) []string { return []string{"consumer:" + consumers[i].key()} }
+    return runItems("consumers", len(consumers), keys, plan, func(i int, plan *aplan.Plan) error {
+        c := consumers[i]
+        name := c.key()
+        if name == "" { return fmt.Errorf("consumers[] 需要提供 username 或 custom_id") }
+        cur, ok, err := client.GetConsumer(ctx, name)
+        if err != nil && !dryRun { return err }
+        diff := ""
+        action := "create"
+        var curGroups []string
+        if ok {
+            diff = consumerDiff(cur, c)
+            if c.Groups != nil {
+                curGroups, err = consumerGroupNames(ctx, client, name)
+                if err != nil && !dryRun { return err }
+                if !sliceSetEqual(curGroups, c.Groups) { diff += diffSlice("groups", curGroups, c.Groups) }
+            }
+            action = "none"
+            if diff != "" { action = "update" }
+        } else if len(c.Groups) > 0 {
+            diff = diffSlice("groups", nil, c.Groups)
+        }
+        if dryRun {
+            plan.Items = append(plan.Items, aplan.Change{Kind: "Consumer", Name: name, Action: action, Diff: diff})
+        } else if showDiff {
+            PrintInfo(cmd, "同步 Consumer：%s", name)
+        }
+        if !dryRun {
+            desired := kong.Consumer{Username: c.Username, CustomID: c.CustomID, Tags: c.Tags}
+            switch {
+            case action == "create":
+                if _, _, err := client.CreateOrUpdateConsumer(ctx, desired); err != nil { return err }
+                if err := syncConsumerGroups(ctx, client, name, nil, c.Groups); err != nil { return err }
+                PrintSuccess(cmd, "已创建 Consumer：%s", name)
+            case action == "update" && applyOverwrite:
+                if _, _, err := client.CreateOrUpdateConsumer(ctx, desired); err != nil { return err }
+                if c.Groups != nil {
+                    if err := syncConsumerGroups(ctx, client, name, curGroups, c.Groups); err != nil { return err }
+                }
+                PrintSuccess(cmd, "已更新 Consumer：%s", name)
+            case action == "update":
+                PrintWarn(cmd, "检测到 Consumer 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", name)
+            }
+        }
+
+        for _, kind := range sortedCredentialKinds(c) {
+            idField := kong.CredentialIdentity(kind)
+            var existing []kong.Credential
+            if ok {
+                existing, err = client.ListCredentials(ctx, name, kind)
+                if err != nil && !dryRun { return err }
+            }
+            for i, cred := range c.Credentials[kind] {
+                cred, err := resolveCredentialEnv(cred)
+                if err != nil { return fmt.Errorf("consumers[%s].credentials.%s[%d]：%w", name, kind, i, err) }
+                expandCredentialRefs(cred)
+                ident := fmt.Sprint(cred[idField])
+                if cred[idField] == nil || ident == "" {
+                    return fmt.Errorf("consumers[%s].credentials.%s[%d] 缺少 %s", name, kind, i, idField)
+                }
+                var match kong.Credential
+                for _, e := range existing {
+                    if fmt.Sprint(e[idField]) == ident { match = e; break }
+                }
+                caction, cdiff := "create", ""
+                if match != nil {
+                    cdiff = credentialDiff(cred, match)
+                    caction = "none"
+                    if cdiff != "" { caction = "update" }
+                }
+                label := credentialLabel(kind, ident)
+                if dryRun {
+                    plan.Items = append(plan.Items, aplan.Change{Kind: "Credential", Name: name + "/" + label, Action: caction, Diff: cdiff})
+                    continue
+                }
+                switch {
+                case caction == "create":
+                    if _, err := client.CreateCredential(ctx, name, kind, kong.Credential(cred)); err != nil { return err }
+                    PrintSuccess(cmd, "已创建凭证：%s（consumer=%s）", label, name)
+                case caction == "update" && applyOverwrite:
+                    if _, err := client.UpdateCredential(ctx, name, kind, fmt.Sprint(match["id"]), kong.Credential(cred)); err != nil { return err }
+                    PrintSuccess(cmd, "已更新凭证：%s（consumer=%s）", label, name)
+                case caction == "update":
+                    PrintWarn(cmd, "检测到凭证变更但未启用覆盖：%s（consumer=%s，跳过，使用 --overwrite 应用变更）", label, name)
+                }
+            }
+        }
+        return nil
+    })
+}
+
+// printConsumerPlan 在层级计划中展示 consumers 及其凭证
+func printConsumerPlan(p func(int, string, ...any), consumers []applyConsumer, find func(kind, name string) *aplan.Change,
+    icon, actColor, diffColor, subtle func(string) string, compact, withDiff bool) {
+    for _, c := range consumers {
+        name := c.key()
+        ch := find("Consumer", name)
+        action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
+        var creds []aplan.Change
+        for _, kind := range sortedCredentialKinds(c) {
+            idField := kong.CredentialIdentity(kind)
+            for _, cred := range c.Credentials[kind] {
+                if cc := find("Credential", name+"/"+credentialLabel(kind, fmt.Sprint(cred[idField]))); cc != nil {
+                    creds = append(creds, *cc)
+                }
+            }
+        }
+        pending := false
+        for _, cc := range creds { if cc.Action != "none" { pending = true } }
+        if compact && action == "none" && !pending { continue }
+        p(2, "%s %s (%s)", icon("Consumer"), name, actColor(action))
+        if withDiff && ch != nil {
+            for _, line := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
+                if strings.TrimSpace(line) != "" { p(3, "%s", diffColor(line)) }
+            }
+        }
+        if len(creds) > 0 && !(compact && !pending) { p(3, "%s", subtle("Credentials:")) }
+        for _, cc := range creds {
+            if compact && cc.Action == "none" { continue }
+            p(4, "%s %s (%s)", icon("Credential"), strings.TrimPrefix(cc.Name, name+"/"), actColor(cc.Action))
+            if withDiff {
+                for _, line := range strings.Split(strings.TrimSpace(cc.Diff), "\n") {
+                    if strings.TrimSpace(line) != "" { p(5, "%s", diffColor(line)) }
+                }
+            }
+        }
+    }
+}
+
+func sortedCredentialKinds(c applyConsumer) []string {
+    kinds := make([]string, 0, len(c.Credentials))
+    for k := range c.Credentials { kinds = append(kinds, k) }
+    sort.Strings(kinds)
+    return kinds
+}
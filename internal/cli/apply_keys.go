@@ -0,0 +1,209 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyKeySet 为 spec 中的 key set 及其密钥：密钥按 kid 匹配，内容为 JWK（jwk_file/jwk_env）或 PEM（pem）二选一，
+// 文件的相对路径以声明它的 spec 文件所在目录为基准；私钥可从环境变量读取，且不会出现在计划中，例如：
+//
+//	key_sets:
+//	  - name: jwt-signing
+//	    keys:
+//	      - kid: 2024-01
+//	        jwk_env: SIGNING_JWK
+//	      - kid: legacy
+//	        pem: {public_key_file: keys/legacy.pub, private_key_env: LEGACY_KEY}
+type applyKeySet struct {
+    Name   string     `yaml:"name" json:"name"`
+    Tags   []string   `yaml:"tags,omitempty" json:"tags,omitempty"`
+    Keys   []applyKey `yaml:"keys,omitempty" json:"keys,omitempty"`
+    source string
+}
+
+type applyKey struct {
+    Kid     string       `yaml:"kid" json:"kid"`
+    Name    string       `yaml:"name,omitempty" json:"name,omitempty"`
+    JWKFile string       `yaml:"jwk_file,omitempty" json:"jwk_file,omitempty"`
+    JWKEnv  string       `yaml:"jwk_env,omitempty" json:"jwk_env,omitempty"`
+    PEM     *applyKeyPEM `yaml:"pem,omitempty" json:"pem,omitempty"`
+    Tags    []string     `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+type applyKeyPEM struct {
+    PublicKeyFile  string `yaml:"public_key_file,omitempty" json:"public_key_file,omitempty"`
+    PrivateKeyFile string `yaml:"private_key_file,omitempty" json:"private_key_file,omitempty"`
+    PrivateKeyEnv  string `yaml:"private_key_env,omitempty" json:"private_key_env,omitempty"`
+}
+
+// resolveKeySetPaths 将 key_sets[].keys[] 中文件的相对路径转换为绝对路径（以声明它的文件所在目录为基准）
+func resolveKeySetPaths(spec *applySpec, file string) {
+    for i := range spec.KeySets {
+        ks := &spec.KeySets[i]
+        for j := range ks.Keys {
+            k := &ks.Keys[j]
+            resolveSpecPaths(ks.source, file, &k.JWKFile)
+            if k.PEM != nil { resolveSpecPaths(ks.source, file, &k.PEM.PublicKeyFile, &k.PEM.PrivateKeyFile) }
+        }
+    }
+}
+
+// readSpecSecret 读取文件或环境变量（至多指定一个），label 为报错中的字段名
+func readSpecSecret(label, file, env string) ([]byte, error) {
+    switch {
+    case file != "" && env != "":
+        return nil, fmt.Errorf("%s_file 与 %s_env 只能指定一个", label, label)
+    case file != "":
+        b, err := os.ReadFile(file)
+        if err != nil { return nil, fmt.Errorf("读取 %s_file 失败：%w", label, err) }
+        return b, nil
+    case env != "":
+        val, set := os.LookupEnv(env)
+        if !set { return nil, fmt.Errorf("%s_env 引用的环境变量 %s 未设置", label, env) }
+        return []byte(val), nil
+    }
+    return nil, nil
+}
+
+// loadSpecKey 读取并校验 spec 中密钥的内容
+func loadSpecKey(k applyKey) (kong.Key, error) {
+    if k.Kid == "" {
+        return kong.Key{}, fmt.Errorf("缺少 kid")
+    }
+    jwk, err := readSpecSecret("jwk", k.JWKFile, k.JWKEnv)
+    if err != nil {
+        return kong.Key{}, err
+    }
+    var pub, priv []byte
+    if k.PEM != nil {
+        if pub, err = readSpecSecret("public_key", k.PEM.PublicKeyFile, ""); err != nil {
+            return kong.Key{}, err
+        }
+        if priv, err = readSpecSecret("private_key", k.PEM.PrivateKeyFile, k.PEM.PrivateKeyEnv); err != nil {
+            return kong.Key{}, err
+        }
+    }
+    jwkStr, keyPEM, err := loadKeyMaterial(k.Kid, strings.TrimSpace(string(jwk)), pub, priv)
+    if err != nil {
+        return kong.Key{}, err
+    }
+    return kong.Key{Name: k.Name, Kid: k.Kid, JWK: jwkStr, PEM: keyPEM, Tags: k.Tags}, nil
+}
+
+// applyKeySets 同步 spec 中的 key set 及其密钥；dry-run 时仅写入计划。
+// 已有 key set 的标签变更与已有密钥的替换（轮换）需 --overwrite，未在 spec 中列出的密钥保留
+func applyKeySets(cmd *cobra.Command, ctx context.Context, client *kong.Client, sets []applyKeySet, plan *aplan.Plan) error {
+    keys := func(i int) []string { return []string{"key_set:" + sets[i].Name} }
+    return runItems("key_sets", len(sets), keys, plan, func(i int, plan *aplan.Plan) error {
+        ks := sets[i]
+        if ks.Name == "" { return fmt.Errorf("key_sets[%d] 缺少 name", i) }
+        want := make([]kong.Key, len(ks.Keys))
+        for j, k := range ks.Keys {
+            var err error
+            if want[j], err = loadSpecKey(k); err != nil { return fmt.Errorf("key_sets[%s].keys[%d]：%w", ks.Name, j, err) }
+        }
+        cur, ok, err := client.GetKeySet(ctx, ks.Name)
+        if err != nil && !dryRun { return err }
+        action, diff := "create", ""
+        var tags []string
+        if ok {
+            if len(ks.Tags) > 0 { tags = desiredTags(client, cur.Tags, ks.Tags) }
+            if len(tags) > 0 && !sliceSetEqual(cur.Tags, tags) { diff = diffSlice("tags", cur.Tags, tags) }
+            action = "none"
+            if diff != "" { action = "update" }
+        }
+        // 已有密钥按 kid 匹配；key set 尚不存在时其中的密钥均为新建
+        existing := map[string]*kong.Key{}
+        if ok {
+            list, err := client.ListKeys(ctx, cur.ID)
+            if err != nil && !dryRun { return err }
+            for j := range list { existing[list[j].Kid] = &list[j] }
+        }
+        keyActions := make([]string, len(want))
+        keyDiffs := make([]string, len(want))
+        for j, k := range want {
+            keyActions[j] = "create"
+            if old := existing[k.Kid]; old != nil {
+                var kt []string
+                if len(k.Tags) > 0 { kt = desiredTags(client, old.Tags, k.Tags) }
+                keyDiffs[j] = keyDiff(old, k, kt)
+                keyActions[j] = "none"
+                if keyDiffs[j] != "" { keyActions[j] = "update" }
+            }
+        }
+        if dryRun {
+            plan.Items = append(plan.Items, aplan.Change{Kind: "KeySet", Name: ks.Name, Action: action, Diff: diff})
+            for j, k := range want {
+                d := keyDiffs[j]
+                if keyActions[j] == "create" { d = "+ key: " + keySummary(k) + "\n" }
+                plan.Items = append(plan.Items, aplan.Change{Kind: "Key", Name: ks.Name + "/" + k.Kid, Action: keyActions[j], Diff: d})
+            }
+            return nil
+        }
+        if showDiff { PrintInfo(cmd, "同步 Key Set：%s", ks.Name) }
+        switch {
+        case !ok, action == "update" && applyOverwrite:
+            var out kong.KeySet
+            if _, out, err = client.CreateOrUpdateKeySet(ctx, kong.KeySet{Name: ks.Name, Tags: ks.Tags}); err != nil { return err }
+            if !ok { cur = &out }
+            PrintSuccess(cmd, "已%s Key Set：%s", actionCN(action), ks.Name)
+        case action == "update":
+            PrintWarn(cmd, "检测到 Key Set 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", ks.Name)
+        }
+        for j, k := range want {
+            label := ks.Name + "/" + k.Kid
+            k.Set = &kong.EntityRef{ID: cur.ID}
+            switch {
+            case keyActions[j] == "create":
+                if _, err := client.CreateKey(ctx, k); err != nil { return fmt.Errorf("创建 Key %s 失败：%w", label, err) }
+                PrintSuccess(cmd, "已创建 Key：%s（%s）", label, keySummary(k))
+            case keyActions[j] == "update" && applyOverwrite:
+                if len(k.Tags) > 0 { k.Tags = desiredTags(client, existing[k.Kid].Tags, k.Tags) }
+                if _, err := client.UpdateKey(ctx, existing[k.Kid].ID, k); err != nil { return fmt.Errorf("更新 Key %s 失败：%w", label, err) }
+                PrintSuccess(cmd, "已更新 Key：%s（%s）", label, keySummary(k))
+            case keyActions[j] == "update":
+                PrintWarn(cmd, "检测到 Key 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", label)
+            }
+        }
+        return nil
+    })
+}
+
+// printKeySetPlan 在层级计划中展示 key set 及其密钥的差异
+func printKeySetPlan(p func(int, string, ...any), sets []applyKeySet, find func(kind, name string) *aplan.Change,
+    icon, actColor, diffColor func(string) string, compact, withDiff bool) {
+    diffLines := func(level int, ch *aplan.Change) {
+        if !withDiff || ch == nil { return }
+        for _, l := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
+            if strings.TrimSpace(l) != "" { p(level, "%s", diffColor(l)) }
+        }
+    }
+    for _, ks := range sets {
+        ch := find("KeySet", ks.Name)
+        action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
+        var keyChanges []*aplan.Change
+        changed := action != "none"
+        for _, k := range ks.Keys {
+            kc := find("Key", ks.Name+"/"+k.Kid)
+            keyChanges = append(keyChanges, kc)
+            if kc != nil && kc.Action != "none" { changed = true }
+        }
+        if compact && !changed { continue }
+        p(2, "%s %s (%s)  keys: %d", icon("KeySet"), ks.Name, actColor(action), len(ks.Keys))
+        diffLines(3, ch)
+        for j, k := range ks.Keys {
+            kc := keyChanges[j]
+            ka := "none"; if kc != nil && kc.Action != "" { ka = kc.Action }
+            if compact && ka == "none" { continue }
+            p(3, "%s %s (%s)", icon("Key"), k.Kid, actColor(ka))
+            diffLines(4, kc)
+        }
+    }
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyVault 为 spec 中的 vault（Kong 3.x secret 后端）：name 为后端类型，prefix 唯一，其他资源以 {vault://<prefix>/<key>} 引用；
+// config 只比较给出的字段，以 _env 结尾的字段从对应环境变量读取（如 token_env: VAULT_TOKEN），计划中新旧值均打码，例如：
+//
+//	vaults:
+//	  - prefix: app-env
+//	    name: env
+//	    config: {prefix: MY_APP_}
+//	  - prefix: hcv-prod
+//	    name: hcv
+//	    config: {host: vault.internal, mount: secret, kv: v2, token_env: VAULT_TOKEN}
+type applyVault struct {
+    Prefix      string         `yaml:"prefix" json:"prefix"`
+    Name        string         `yaml:"name" json:"name"`
+    Description string         `yaml:"description,omitempty" json:"description,omitempty"`
+    Config      map[string]any `yaml:"config,omitempty" json:"config,omitempty"`
+    Tags        []string       `yaml:"tags,omitempty" json:"tags,omitempty"`
+    source      string
+}
+
+// resolveVaultConfig 将 config 中的 <field>_env 替换为环境变量的值，返回替换后的配置与这些字段名；环境变量未设置时报错
+func resolveVaultConfig(conf map[string]any) (map[string]any, []string, error) {
+    out := make(map[string]any, len(conf))
+    var secrets []string
+    keys := make([]string, 0, len(conf))
+    for k := range conf { keys = append(keys, k) }
+    sort.Strings(keys)
+    for _, k := range keys {
+        field, ok := strings.CutSuffix(k, "_env")
+        if !ok || field == "" { out[k] = conf[k]; continue }
+        name, _ := conf[k].(string)
+        if name == "" { return nil, nil, fmt.Errorf("config.%s 需为环境变量名", k) }
+        if _, dup := conf[field]; dup { return nil, nil, fmt.Errorf("config.%s 与 config.%s 只能指定一个", field, k) }
+        val, set := os.LookupEnv(name)
+        if !set { return nil, nil, fmt.Errorf("config.%s 引用的环境变量 %s 未设置", k, name) }
+        out[field] = val
+        secrets = append(secrets, field)
+    }
+    return out, secrets, nil
+}
+
+// vaultSecretDiff 与 vaultDiff 相同，但 secrets 中的字段单独比较，差异中不显示其新旧值
+func vaultSecretDiff(cur *kong.Vault, want kong.Vault, secrets []string) string {
+    plain := want
+    plain.Config = make(map[string]any, len(want.Config))
+    for k, v := range want.Config {
+        if !sliceContains(secrets, k) { plain.Config[k] = v }
+    }
+    diff := vaultDiff(cur, plain)
+    for _, f := range secrets {
+        if fmt.Sprint(cur.Config[f]) != fmt.Sprint(want.Config[f]) { diff += fmt.Sprintf("config.%s: ****** -> ******（已变更）\n", f) }
+    }
+    return diff
+}
+
+// applyVaults 同步 spec 中的 vaults；dry-run 时仅写入计划。已存在且配置不同的 vault 需 --overwrite 才更新
+func applyVaults(cmd *cobra.Command, ctx context.Context, client *kong.Client, vaults []applyVault, plan *aplan.Plan) error {
+    keys := func(i int) []string { return []string{"vault:" + vaults[i].Prefix} }
+    return runItems("vaults", len(vaults), keys, plan, func(i int, plan *aplan.Plan) error {
+        v := vaults[i]
+        if err := checkVault(cmd, v.Prefix, v.Name); err != nil { return fmt.Errorf("vaults[%d]：%w", i, err) }
+        if v.Name == "" { return fmt.Errorf("vaults[%s] 缺少 name（后端类型：%s）", v.Prefix, strings.Join(vaultBackends, "、")) }
+        conf, secrets, err := resolveVaultConfig(v.Config)
+        if err != nil { return fmt.Errorf("vaults[%s]：%w", v.Prefix, err) }
+        want := kong.Vault{Name: v.Name, Prefix: v.Prefix, Description: v.Description, Config: conf, Tags: v.Tags}
+        cur, ok, err := client.GetVault(ctx, v.Prefix)
+        if err != nil && !dryRun { return err }
+        action, diff := "create", ""
+        if ok {
+            if len(v.Tags) > 0 { want.Tags = desiredTags(client, cur.Tags, v.Tags) }
+            diff = vaultSecretDiff(cur, want, secrets)
+            action = "none"
+            if diff != "" { action = "update" }
+        }
+        if dryRun {
+            plan.Items = append(plan.Items, aplan.Change{Kind: "Vault", Name: v.Prefix, Action: action, Diff: diff})
+            return nil
+        }
+        if showDiff { PrintInfo(cmd, "同步 Vault：%s", v.Prefix) }
+        switch {
+        case !ok, action == "update" && applyOverwrite:
+            if _, _, err := client.CreateOrUpdateVault(ctx, want); err != nil { return err }
+            PrintSuccess(cmd, "已%s Vault：%s", actionCN(action), v.Prefix)
+        case action == "update":
+            PrintWarn(cmd, "检测到 Vault 变更但未启用覆盖：%s（跳过，使用 --overwrite 应用变更）", v.Prefix)
+        }
+        return nil
+    })
+}
+
+// printVaultPlan 在层级计划中展示 vaults 的差异
+func printVaultPlan(p func(int, string, ...any), vaults []applyVault, find func(kind, name string) *aplan.Change,
+    icon, actColor, diffColor func(string) string, compact, withDiff bool) {
+    for _, v := range vaults {
+        ch := find("Vault", v.Prefix)
+        action := "none"; if ch != nil && ch.Action != "" { action = ch.Action }
+        if compact && action == "none" { continue }
+        p(2, "%s %s (%s)  backend: %s", icon("Vault"), v.Prefix, actColor(action), v.Name)
+        if withDiff && ch != nil {
+            for _, l := range strings.Split(strings.TrimSpace(ch.Diff), "\n") {
+                if strings.TrimSpace(l) != "" { p(3, "%s", diffColor(l)) }
+            }
+        }
+    }
+}
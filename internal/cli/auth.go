@@ -0,0 +1,114 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// authCheckTag 标记权限自检创建的临时资源，便于识别与手工清理
+const authCheckTag = "kongctl-auth-check"
+
+var authCheckWrite bool
+
+var authCmd = &cobra.Command{
+    Use:   "auth",
+    Short: "认证与权限相关工具",
+}
+
+// authProbe 为一次权限探测的结果
+type authProbe struct {
+    Op     string
+    Method string
+    Path   string
+    Status int
+    Err    error
+}
+
+func (p authProbe) allowed() bool { return p.Err == nil && p.Status/100 == 2 }
+
+func (p authProbe) denied() bool { return p.Status == http.StatusUnauthorized || p.Status == http.StatusForbidden }
+
+var authCheckCmd = &cobra.Command{
+    Use:   "check",
+    Short: "检查当前 token/workspace 的读写权限（长时间 apply 前排查 RBAC 限制）",
+    Long: `依次探测各类资源的读取权限；指定 --write 时，额外以临时 upstream（带 kongctl-auth-check 标签）
+验证创建、更新与删除权限，结束后自动删除。存在被拒绝的操作时以非零状态退出。`,
+    Example: `# 仅检查读取权限
+kongctl auth check
+
+# 同时检查写权限（创建并删除一个临时 upstream）
+kongctl auth check --write --context prod`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        var probes []authProbe
+        probe := func(op, method, path string, body, out any) authProbe {
+            st, err := client.Probe(ctx, method, path, body, out)
+            pr := authProbe{Op: op, Method: method, Path: path, Status: st, Err: err}
+            probes = append(probes, pr)
+            return pr
+        }
+        for _, coll := range []string{"services", "routes", "upstreams", "consumers", "plugins", "certificates"} {
+            probe("读取 "+coll, http.MethodGet, "/"+coll+"?size=1", nil, nil)
+        }
+        if authCheckWrite {
+            name := fmt.Sprintf("%s-%d", authCheckTag, time.Now().Unix())
+            var created kong.Upstream
+            body := map[string]any{"name": name, "tags": []string{authCheckTag}}
+            if pr := probe("创建 upstream", http.MethodPost, "/upstreams", body, &created); pr.allowed() {
+                key := created.ID
+                if key == "" { key = name }
+                probe("更新 upstream", http.MethodPatch, "/upstreams/"+key, map[string]any{"tags": []string{authCheckTag, "patched"}}, nil)
+                if pr := probe("删除 upstream", http.MethodDelete, "/upstreams/"+key, nil, nil); !pr.allowed() {
+                    PrintWarn(cmd, "临时 upstream 未能删除，请手工清理：%s（标签 %s）", name, authCheckTag)
+                }
+            }
+        }
+
+        denied, failed := 0, 0
+        for _, pr := range probes {
+            target := pr.Method + " " + pr.Path
+            switch {
+            case pr.allowed():
+                cmd.Printf("%s %-16s %s\n", colorSuccess(glyph("✔", "[ALLOW]")), pr.Op, target)
+            case pr.denied():
+                denied++
+                cmd.Printf("%s %-16s %s（HTTP %d）\n", colorError(glyph("✘", "[DENY]")), pr.Op, target, pr.Status)
+            default:
+                failed++
+                reason := fmt.Sprintf("HTTP %d", pr.Status)
+                if pr.Err != nil { reason = pr.Err.Error() }
+                cmd.Printf("%s %-16s %s（%s）\n", colorWarn(glyph("?", "[ERROR]")), pr.Op, target, reason)
+            }
+        }
+        if !authCheckWrite {
+            PrintInfo(cmd, "未检查写权限；使用 --write 以临时 upstream 验证创建/更新/删除")
+        }
+        if denied > 0 || failed > 0 {
+            var parts []string
+            if denied > 0 { parts = append(parts, fmt.Sprintf("%d 项被拒绝", denied)) }
+            if failed > 0 { parts = append(parts, fmt.Sprintf("%d 项出错", failed)) }
+            return fmt.Errorf("权限检查未通过：%s", strings.Join(parts, "，"))
+        }
+        PrintSuccess(cmd, "权限检查通过：%d 项操作均被允许", len(probes))
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(authCmd)
+    authCmd.AddCommand(authCheckCmd)
+    authCheckCmd.Flags().BoolVar(&authCheckWrite, "write", false, "同时检查写权限（创建/更新/删除一个带 kongctl-auth-check 标签的临时 upstream）")
+}
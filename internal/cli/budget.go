@@ -0,0 +1,59 @@
+package cli
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// 单次命令的安全预算（apply/export），防止异常 spec 对生产 Admin API 造成过量请求
+var (
+    budgetMaxCalls int
+    budgetDeadline time.Duration
+)
+
+func addBudgetFlags(cmd *cobra.Command) {
+    cmd.Flags().IntVar(&budgetMaxCalls, "max-api-calls", 0, "Admin API 调用次数上限（0 不限制），超出后中止并输出已执行摘要，例：--max-api-calls 500")
+    cmd.Flags().DurationVar(&budgetDeadline, "deadline", 0, "整体执行时限（覆盖默认超时），超时后中止并输出已执行摘要，例：--deadline 2m")
+}
+
+// budgetEnabled 表示用户显式设置了预算
+func budgetEnabled() bool { return budgetMaxCalls > 0 || budgetDeadline > 0 }
+
+// budgetContext 返回命令整体使用的 context：设置 --deadline 时以其为准，否则使用默认超时
+func budgetContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+    if budgetDeadline > 0 {
+        return context.WithTimeout(parent, budgetDeadline)
+    }
+    return context.WithTimeout(parent, timeout)
+}
+
+// finishBudget 在命令结束时调用：预算耗尽导致的失败会附带部分执行摘要；
+// 正常结束且设置了预算时输出调用次数与耗时。
+func finishBudget(cmd *cobra.Command, ctx context.Context, client *kong.Client, start time.Time, err error) error {
+    elapsed := time.Since(start).Round(time.Millisecond)
+    exceeded := errors.Is(err, kong.ErrBudgetExceeded)
+    timedOut := err != nil && budgetDeadline > 0 && (errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded))
+    if !exceeded && !timedOut {
+        if err == nil && budgetEnabled() {
+            PrintInfo(cmd, "共发出 %d 次 API 调用，耗时 %s", client.Calls(), elapsed)
+        }
+        return err
+    }
+    writes := client.Writes()
+    PrintWarn(cmd, "执行已中止：已发出 %d 次 API 调用，耗时 %s，其中写操作 %d 次", client.Calls(), elapsed, len(writes))
+    for _, w := range writes {
+        cmd.Println("  " + w)
+    }
+    if len(writes) > 0 {
+        PrintInfo(cmd, "以上变更已生效；apply 为幂等操作，调整预算后重新执行即可继续剩余部分")
+    }
+    if timedOut && !exceeded {
+        return fmt.Errorf("超出执行时限 --deadline=%s：%w", budgetDeadline, err)
+    }
+    return err
+}
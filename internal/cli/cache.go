@@ -0,0 +1,208 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// proxyCachePlugin 为 Kong 内置的响应缓存插件
+const proxyCachePlugin = "proxy-cache"
+
+var (
+    cacheService      string
+    cacheRoute        string
+    cacheTTL          int
+    cacheStrategy     string
+    cacheContentTypes []string
+    cacheMethods      []string
+    cacheKey          string
+)
+
+var cacheCmd = &cobra.Command{
+    Use:   "cache",
+    Short: "管理 proxy-cache 响应缓存（启用/关闭/清除）",
+}
+
+// cacheScope 校验 --service/--route 二选一，返回资源类型与名称
+func cacheScope() (string, string, error) {
+    switch {
+    case cacheService != "" && cacheRoute != "":
+        return "", "", fmt.Errorf("--service 与 --route 只能指定一个")
+    case cacheService != "":
+        return "Service", cacheService, nil
+    case cacheRoute != "":
+        return "Route", cacheRoute, nil
+    }
+    return "", "", fmt.Errorf("必须通过 --service 或 --route 指定缓存作用的资源")
+}
+
+// cachePlugin 查找资源上的 proxy-cache 插件
+func cachePlugin(ctx context.Context, client *kong.Client, kind, name string) (*kong.Plugin, bool, error) {
+    if kind == "Route" {
+        return client.GetRoutePlugin(ctx, name, proxyCachePlugin)
+    }
+    return client.GetServicePlugin(ctx, name, proxyCachePlugin)
+}
+
+var cacheEnableCmd = &cobra.Command{
+    Use:   "enable",
+    Short: "在 Service/Route 上启用或更新 proxy-cache（幂等）",
+    Example: `# 为 catalog 服务缓存 60 秒
+kongctl cache enable --service catalog --ttl 60
+
+# 仅缓存 JSON 的 GET/HEAD 请求，作用于单个路由
+kongctl cache enable --route catalog-list --ttl 30 --content-types application/json --methods GET,HEAD`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := cacheScope()
+        if err != nil {
+            return err
+        }
+        if cacheTTL <= 0 {
+            return fmt.Errorf("--ttl 必须为正整数（秒）：%d", cacheTTL)
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        conf := map[string]any{"cache_ttl": cacheTTL, "strategy": cacheStrategy}
+        if len(cacheContentTypes) > 0 { conf["content_type"] = cacheContentTypes }
+        if len(cacheMethods) > 0 { conf["request_method"] = toUpper(cacheMethods) }
+        enabled := true
+        desired := kong.Plugin{Name: proxyCachePlugin, Config: conf, Enabled: &enabled}
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将在 %s %s 上启用 %s：ttl=%ds strategy=%s", kind, name, proxyCachePlugin, cacheTTL, cacheStrategy)
+            return nil
+        }
+        var action string
+        if kind == "Route" {
+            action, _, err = client.CreateOrUpdateRoutePlugin(ctx, name, desired)
+        } else {
+            action, _, err = client.CreateOrUpdateServicePlugin(ctx, name, desired)
+        }
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s %s：%s %s（ttl=%ds strategy=%s）", actionCN(action), proxyCachePlugin, kind, name, cacheTTL, cacheStrategy)
+        return nil
+    },
+}
+
+var cacheDisableCmd = &cobra.Command{
+    Use:   "disable",
+    Short: "移除 Service/Route 上的 proxy-cache",
+    Example: `kongctl cache disable --service catalog`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := cacheScope()
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        p, ok, err := cachePlugin(ctx, client, kind, name)
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "%s %s 未启用 %s，无需移除", kind, name, proxyCachePlugin)
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将移除 %s %s 的 %s", kind, name, proxyCachePlugin)
+            return nil
+        }
+        if err := client.DeletePlugin(ctx, p.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已移除 %s %s 的 %s", kind, name, proxyCachePlugin)
+        return nil
+    },
+}
+
+var cachePurgeCmd = &cobra.Command{
+    Use:   "purge",
+    Short: "清除 proxy-cache 缓存（Admin API /proxy-cache）",
+    Long: `清除指定 Service/Route 所用 proxy-cache 的缓存。
+指定 --key 时仅清除该插件下的单个缓存项；否则调用 DELETE /proxy-cache 清除节点上全部 proxy-cache 缓存
+（Kong 不支持按插件清空，因此会影响其他资源的缓存；生产上下文需确认）。
+仅对 memory 策略有效；多节点部署需对每个节点的 Admin API 分别执行。`,
+    Example: `# 清除全部缓存（需 catalog 已启用 proxy-cache）
+kongctl cache purge --service catalog
+
+# 清除单个缓存项
+kongctl cache purge --service catalog --key 2a1e8c4e0b`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := cacheScope()
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        p, ok, err := cachePlugin(ctx, client, kind, name)
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "先使用 kongctl cache enable 启用缓存", fmt.Errorf("%s %s 未启用 %s", kind, name, proxyCachePlugin))
+        }
+        if s, _ := p.Config["strategy"].(string); s != "" && s != "memory" {
+            PrintWarn(cmd, "%s 的缓存策略为 %s，Admin API 清除仅对 memory 策略生效", proxyCachePlugin, s)
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将清除 %s %s 的 %s 缓存", kind, name, proxyCachePlugin)
+            return nil
+        }
+        if cacheKey == "" {
+            if err := confirmDestructive(cmd, "cache purge（清除节点上全部 proxy-cache 缓存）"); err != nil {
+                return err
+            }
+        }
+        found, err := client.PurgeProxyCache(ctx, p.ID, cacheKey)
+        if err != nil {
+            return err
+        }
+        switch {
+        case cacheKey == "":
+            PrintSuccess(cmd, "已清除全部 proxy-cache 缓存（%s %s）", kind, name)
+        case found:
+            PrintSuccess(cmd, "已清除缓存项 %s（%s %s）", cacheKey, kind, name)
+        default:
+            PrintInfo(cmd, "缓存项不存在或已过期：%s", cacheKey)
+        }
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(cacheCmd)
+    cacheCmd.AddCommand(cacheEnableCmd, cacheDisableCmd, cachePurgeCmd)
+    for _, c := range []*cobra.Command{cacheEnableCmd, cacheDisableCmd, cachePurgeCmd} {
+        c.Flags().StringVar(&cacheService, "service", "", "Service 名称，例：--service catalog")
+        c.Flags().StringVar(&cacheRoute, "route", "", "Route 名称（与 --service 二选一）")
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+    cacheEnableCmd.Flags().IntVar(&cacheTTL, "ttl", 300, "缓存时长（秒），例：--ttl 60")
+    cacheEnableCmd.Flags().StringVar(&cacheStrategy, "strategy", "memory", "缓存后端：memory 或 redis（企业版）")
+    cacheEnableCmd.Flags().StringSliceVar(&cacheContentTypes, "content-types", nil, "可缓存的响应 Content-Type（默认沿用插件默认值）")
+    cacheEnableCmd.Flags().StringSliceVar(&cacheMethods, "methods", nil, "可缓存的请求方法（默认 GET,HEAD）")
+    cachePurgeCmd.Flags().StringVar(&cacheKey, "key", "", "仅清除指定缓存键（响应头 X-Cache-Key）")
+}
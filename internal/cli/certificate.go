@@ -0,0 +1,358 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/json"
+    "encoding/pem"
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    certCertFile string
+    certKeyFile  string
+    certSnis     []string
+    certID       string
+    certTags     []string
+)
+
+// certExpiryWarnDays 为 list 中标记“即将过期”的剩余天数
+const certExpiryWarnDays = 30
+
+var certificateCmd = &cobra.Command{
+    Use:   "certificate",
+    Short: "管理 TLS 证书（上传/续期、列出到期时间、删除）",
+    Long: `上传证书与私钥（PEM），并通过 --sni 绑定服务器名称。sync 按 --id、--sni 已绑定的证书、内容相同的证书的顺序查找已有证书：
+找到时替换其 cert/key（续期），否则新建。私钥只上传到 Kong，不会在任何输出中显示。`,
+}
+
+// parseLeafCert 解析 PEM 中的第一张证书（证书链的叶子证书）
+func parseLeafCert(data string) (*x509.Certificate, error) {
+    block, _ := pem.Decode([]byte(data))
+    if block == nil || block.Type != "CERTIFICATE" {
+        return nil, fmt.Errorf("不是 PEM 格式的证书")
+    }
+    return x509.ParseCertificate(block.Bytes)
+}
+
+// certSummary 返回证书的简要说明，例：CN=api.example.com，有效期至 2025-01-01
+func certSummary(c *x509.Certificate) string {
+    return fmt.Sprintf("CN=%s，有效期至 %s", c.Subject.CommonName, c.NotAfter.Local().Format("2006-01-02"))
+}
+
+// certDaysLeft 返回距到期的天数（已过期为负数）
+func certDaysLeft(c *x509.Certificate) int {
+    return int(time.Until(c.NotAfter).Hours() / 24)
+}
+
+// readCertPair 读取 --cert-file/--key-file，校验私钥与证书匹配、证书未过期，返回 PEM 内容与叶子证书
+func readCertPair() (string, string, *x509.Certificate, error) {
+    if certCertFile == "" || certKeyFile == "" {
+        return "", "", nil, fmt.Errorf("必须提供 --cert-file 与 --key-file")
+    }
+    certPEM, err := os.ReadFile(expandPath(certCertFile))
+    if err != nil {
+        return "", "", nil, fmt.Errorf("读取证书失败：%w", err)
+    }
+    keyPEM, err := os.ReadFile(expandPath(certKeyFile))
+    if err != nil {
+        return "", "", nil, fmt.Errorf("读取私钥失败：%w", err)
+    }
+    leaf, err := checkCertPair(certPEM, keyPEM, certCertFile)
+    if err != nil {
+        return "", "", nil, err
+    }
+    return string(certPEM), string(keyPEM), leaf, nil
+}
+
+// checkCertPair 校验私钥与证书匹配且证书未过期，返回叶子证书；label 为报错中的证书来源
+func checkCertPair(certPEM, keyPEM []byte, label string) (*x509.Certificate, error) {
+    if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+        return nil, fmt.Errorf("证书与私钥无效或不匹配：%w", err)
+    }
+    leaf, err := parseLeafCert(string(certPEM))
+    if err != nil {
+        return nil, fmt.Errorf("%s：%w", label, err)
+    }
+    if time.Now().After(leaf.NotAfter) {
+        return nil, fmt.Errorf("证书已于 %s 过期：%s", leaf.NotAfter.Local().Format("2006-01-02"), label)
+    }
+    return leaf, nil
+}
+
+// warnSniMismatch 对证书 CN/SAN 不覆盖的 SNI 给出提示
+func warnSniMismatch(cmd *cobra.Command, leaf *x509.Certificate, snis []string) {
+    for _, sni := range snis {
+        host := sni
+        if strings.HasPrefix(host, "*.") { host = "wildcard" + host[1:] }
+        if len(leaf.DNSNames) == 0 && strings.EqualFold(leaf.Subject.CommonName, sni) { continue }
+        if leaf.VerifyHostname(host) != nil { PrintWarn(cmd, "证书（%s）不包含 %s，使用该 SNI 的客户端将校验失败", certSummary(leaf), sni) }
+    }
+}
+
+// resolveCertificate 按 ID 或其绑定的 SNI 查找证书
+func resolveCertificate(ctx context.Context, client *kong.Client, ref string) (*kong.Certificate, bool, error) {
+    cur, ok, err := client.GetCertificate(ctx, ref)
+    if err == nil && !ok { cur, ok, err = client.FindCertificateBySNI(ctx, ref) }
+    return cur, ok, err
+}
+
+// findCertificateForSnis 返回 snis 已绑定的证书（均未绑定时为 nil）；snis 分别绑定在不同证书上时报错
+func findCertificateForSnis(ctx context.Context, client *kong.Client, snis []string) (*kong.Certificate, error) {
+    if len(snis) == 0 {
+        return nil, nil
+    }
+    list, err := client.ListCertificates(ctx)
+    if err != nil {
+        return nil, err
+    }
+    var match *kong.Certificate
+    for _, sni := range snis {
+        for i := range list {
+            if !kong.HasTag(list[i].Snis, sni) { continue }
+            if match != nil && match.ID != list[i].ID {
+                return nil, fmt.Errorf("SNI %s 分别绑定在证书 %s 与 %s 上", strings.Join(snis, ","), match.ID, list[i].ID)
+            }
+            match = &list[i]
+        }
+    }
+    return match, nil
+}
+
+// certificateDiff 比较已有证书与期望的叶子证书、snis 与 tags（snis/tags 为空时不比较）
+func certificateDiff(cur *kong.Certificate, leaf *x509.Certificate, snis, tags []string) string {
+    diff := ""
+    old, err := parseLeafCert(cur.Cert)
+    switch {
+    case err != nil:
+        diff += fmt.Sprintf("cert: （无法解析） -> %s\n", certSummary(leaf))
+    case !bytes.Equal(old.Raw, leaf.Raw):
+        diff += fmt.Sprintf("cert: %s -> %s\n", certSummary(old), certSummary(leaf))
+    }
+    if len(snis) > 0 && !sliceSetEqual(cur.Snis, snis) { diff += diffSlice("snis", cur.Snis, snis) }
+    if len(tags) > 0 && !sliceSetEqual(cur.Tags, tags) { diff += diffSlice("tags", cur.Tags, tags) }
+    return diff
+}
+
+// findSyncTarget 按 --id、--sni、证书内容的顺序查找 sync 要更新的已有证书
+func findSyncTarget(ctx context.Context, client *kong.Client, leaf *x509.Certificate) (*kong.Certificate, error) {
+    if certID != "" {
+        cur, ok, err := client.GetCertificate(ctx, certID)
+        if err != nil {
+            return nil, err
+        } else if !ok {
+            return nil, withCode("not_found", "使用 kongctl certificate list 查看已有证书", fmt.Errorf("证书不存在：%s", certID))
+        }
+        return cur, nil
+    }
+    match, err := findCertificateForSnis(ctx, client, certSnis)
+    if err != nil {
+        return nil, fmt.Errorf("%w，请使用 --id 指定要更新的证书", err)
+    }
+    if match != nil {
+        return match, nil
+    }
+    list, err := client.ListCertificates(ctx)
+    if err != nil {
+        return nil, err
+    }
+    for i := range list {
+        if c, err := parseLeafCert(list[i].Cert); err == nil && bytes.Equal(c.Raw, leaf.Raw) { return &list[i], nil }
+    }
+    return nil, nil
+}
+
+var certificateSyncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "上传证书或为已有证书续期，并绑定 SNI（幂等）",
+    Example: `kongctl certificate sync --cert-file api.crt --key-file api.key --sni api.example.com,www.example.com
+
+# 续期：--sni 已绑定的证书被替换为新证书
+kongctl certificate sync --cert-file api-2025.crt --key-file api-2025.key --sni api.example.com --dry-run`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        certPEM, keyPEM, leaf, err := readCertPair()
+        if err != nil {
+            return err
+        }
+        warnSniMismatch(cmd, leaf, certSnis)
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        cur, err := findSyncTarget(ctx, client, leaf)
+        if err != nil {
+            return err
+        }
+        diff := ""
+        if cur != nil {
+            if diff = certificateDiff(cur, leaf, certSnis, certTags); diff == "" {
+                PrintInfo(cmd, "证书无变更，未写入：%s（%s）", cur.ID, certSummary(leaf))
+                return nil
+            }
+        }
+        if showDiff || dryRun {
+            if cur == nil {
+                PrintInfo(cmd, "%sDiff: 新证书", emojiDiff)
+                cmd.Printf("%s\n", colorInfo("+ cert: "+certSummary(leaf)))
+                if len(certSnis) > 0 { cmd.Printf("%s\n", colorInfo("+ snis: "+strings.Join(certSnis, ","))) }
+            } else {
+                PrintInfo(cmd, "%sDiff: 证书 %s", emojiDiff, cur.ID)
+                for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") { cmd.Printf("  %s\n", l) }
+            }
+        }
+        if dryRun {
+            if cur == nil {
+                PrintInfo(cmd, "[dry-run] 将上传证书：%s", certSummary(leaf))
+            } else {
+                PrintInfo(cmd, "[dry-run] 将更新证书：%s", cur.ID)
+            }
+            return nil
+        }
+        desired := kong.Certificate{Cert: certPEM, Key: keyPEM, Snis: certSnis, Tags: certTags}
+        if cur == nil {
+            out, err := client.CreateCertificate(ctx, desired)
+            if err != nil {
+                return err
+            }
+            PrintSuccess(cmd, "已上传证书：%s（%s）", out.ID, certSummary(leaf))
+            return nil
+        }
+        if _, err := client.UpdateCertificate(ctx, cur.ID, desired); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已更新证书：%s（%s）", cur.ID, certSummary(leaf))
+        return nil
+    },
+}
+
+// certificateRow 为 list 的一行（不含 cert/key 内容）
+type certificateRow struct {
+    ID       string   `json:"id"`
+    Subject  string   `json:"subject"`
+    NotAfter string   `json:"not_after"`
+    DaysLeft int      `json:"days_left"`
+    Snis     []string `json:"snis"`
+    Tags     []string `json:"tags,omitempty"`
+}
+
+var certificateListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出证书及其到期时间与 SNI（按到期时间排序）",
+    Example: `kongctl certificate list
+kongctl certificate list --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListCertificates(ctx)
+        if err != nil {
+            return err
+        }
+        rows := make([]certificateRow, 0, len(list))
+        for _, c := range list {
+            row := certificateRow{ID: c.ID, Snis: c.Snis, Tags: c.Tags}
+            if row.Snis == nil { row.Snis = []string{} }
+            if leaf, err := parseLeafCert(c.Cert); err == nil {
+                row.Subject, row.NotAfter, row.DaysLeft = leaf.Subject.CommonName, leaf.NotAfter.UTC().Format(time.RFC3339), certDaysLeft(leaf)
+            }
+            rows = append(rows, row)
+        }
+        sort.SliceStable(rows, func(i, j int) bool { return rows[i].NotAfter < rows[j].NotAfter })
+        if outputJSON() {
+            b, _ := json.MarshalIndent(rows, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(rows) == 0 {
+            PrintInfo(cmd, "未找到证书")
+            return nil
+        }
+        cmd.Printf("%-36s %-28s %-12s %-10s %s\n", "ID", "SUBJECT", "NOT_AFTER", "DAYS_LEFT", "SNIS")
+        for _, r := range rows {
+            left := fmt.Sprintf("%-10d", r.DaysLeft)
+            switch {
+            case r.NotAfter == "":
+                left = fmt.Sprintf("%-10s", "-")
+            case r.DaysLeft < 0:
+                left = colorWarn(fmt.Sprintf("%-10s", "已过期"))
+            case r.DaysLeft < certExpiryWarnDays:
+                left = colorWarn(left)
+            }
+            notAfter := "-"
+            if len(r.NotAfter) >= 10 { notAfter = r.NotAfter[:10] }
+            cmd.Printf("%-36s %-28s %-12s %s %s\n", r.ID, r.Subject, notAfter, left, strings.Join(r.Snis, ","))
+        }
+        return nil
+    },
+}
+
+var certificateDeleteCmd = &cobra.Command{
+    Use:   "delete <id|sni>",
+    Short: "删除证书（按 ID 或其绑定的 SNI 查找，SNI 一并删除）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl certificate delete api.example.com`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        cur, ok, err := resolveCertificate(ctx, client, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "证书不存在，无需删除：%s", args[0])
+            return nil
+        }
+        label := cur.ID
+        if len(cur.Snis) > 0 { label += "（snis=" + strings.Join(cur.Snis, ",") + "）" }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除证书：%s", label)
+            return nil
+        }
+        if err := confirmDestructive(cmd, "certificate delete "+label); err != nil {
+            return err
+        }
+        if err := client.DeleteCertificate(ctx, cur.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除证书：%s", label)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(certificateCmd)
+    certificateCmd.AddCommand(certificateSyncCmd, certificateListCmd, certificateDeleteCmd)
+    certificateSyncCmd.Flags().StringVar(&certCertFile, "cert-file", "", "证书（PEM，可包含中间证书链）")
+    certificateSyncCmd.Flags().StringVar(&certKeyFile, "key-file", "", "私钥（PEM）")
+    certificateSyncCmd.Flags().StringSliceVar(&certSnis, "sni", nil, "绑定的服务器名称（可重复或逗号分隔，覆盖现有 SNI），例：--sni api.example.com")
+    certificateSyncCmd.Flags().StringVar(&certID, "id", "", "要更新的证书 ID（默认按 --sni 或证书内容查找）")
+    certificateSyncCmd.Flags().StringSliceVar(&certTags, "tags", nil, "证书标签")
+    certificateSyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    for _, c := range []*cobra.Command{certificateSyncCmd, certificateDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "sync"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+)
+
+// applyResume 为 apply --resume：存在同一 spec 的检查点时跳过已完成的资源
+var applyResume bool
+
+// applyCheckpoint 为当前 apply 的进度记录；为 nil 时不记录（dry-run、schedule、rollback 等）
+var applyCheckpoint *checkpoint
+
+// checkpoint 记录 apply 已完成的资源，保存在 ~/.kongctl/checkpoints/<key>.json；
+// key 由上下文、admin_url 与展开后的 spec 计算，spec 变化后旧进度不会被误用
+type checkpoint struct {
+    File      string          `json:"file"`
+    Context   string          `json:"context,omitempty"`
+    AdminURL  string          `json:"admin_url"`
+    StartedAt time.Time       `json:"started_at"`
+    UpdatedAt time.Time       `json:"updated_at"`
+    Done      map[string]bool `json:"done"`
+
+    mu   sync.Mutex
+    path string
+}
+
+func checkpointDir() (string, error) {
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "checkpoints"), nil
+}
+
+// openCheckpoint 返回 spec 对应的检查点；resume 为 true 且存在未完成的进度时沿用已完成项，否则从头记录
+func openCheckpoint(cmd *cobra.Command, spec applySpec, resume bool) (cp *checkpoint, resumed int, err error) {
+    b, err := json.Marshal(spec)
+    if err != nil {
+        return nil, 0, err
+    }
+    adminURL := viper.GetString("admin_url")
+    sum := sha256.Sum256([]byte(activeContext + "\x00" + adminURL + "\x00" + string(b)))
+    dir, err := checkpointDir()
+    if err != nil {
+        return nil, 0, err
+    }
+    path := filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+    now := time.Now().UTC()
+    cp = &checkpoint{File: applyFile, Context: activeContext, AdminURL: adminURL, StartedAt: now, UpdatedAt: now, Done: map[string]bool{}, path: path}
+    if data, rerr := os.ReadFile(path); rerr == nil {
+        var prev checkpoint
+        if err := json.Unmarshal(data, &prev); err == nil && len(prev.Done) > 0 {
+            if !resume {
+                PrintWarn(cmd, "检测到该文件未完成的 apply 进度（已完成 %d 项，%s），本次将完整执行；使用 --resume 可跳过已完成的资源", len(prev.Done), prev.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+            } else {
+                cp.StartedAt, cp.Done = prev.StartedAt, prev.Done
+                resumed = len(prev.Done)
+            }
+        }
+    }
+    if err := os.MkdirAll(dir, 0o700); err != nil {
+        return nil, 0, fmt.Errorf("创建目录失败：%w", err)
+    }
+    return cp, resumed, cp.save()
+}
+
+func checkpointID(phase string, i int) string { return phase + "[" + strconv.Itoa(i) + "]" }
+
+// done 判断资源是否已在之前的执行中完成
+func (cp *checkpoint) done(phase string, i int) bool {
+    if cp == nil { return false }
+    cp.mu.Lock()
+    defer cp.mu.Unlock()
+    return cp.Done[checkpointID(phase, i)]
+}
+
+// mark 记录资源已完成并立即落盘，进程被中断时进度不丢失
+func (cp *checkpoint) mark(phase string, i int) error {
+    if cp == nil { return nil }
+    cp.mu.Lock()
+    defer cp.mu.Unlock()
+    cp.Done[checkpointID(phase, i)] = true
+    cp.UpdatedAt = time.Now().UTC()
+    return cp.saveLocked()
+}
+
+func (cp *checkpoint) save() error {
+    cp.mu.Lock()
+    defer cp.mu.Unlock()
+    return cp.saveLocked()
+}
+
+func (cp *checkpoint) saveLocked() error {
+    b, err := json.MarshalIndent(cp, "", "  ")
+    if err != nil {
+        return err
+    }
+    if err := writeTextFile(cp.path, append(b, '\n'), 0o600); err != nil {
+        return fmt.Errorf("保存 apply 进度失败：%w", err)
+    }
+    return nil
+}
+
+// remove 在 apply 全部完成后删除检查点
+func (cp *checkpoint) remove() {
+    if cp == nil { return }
+    _ = os.Remove(cp.path)
+}
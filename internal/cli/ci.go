@@ -0,0 +1,112 @@
+package cli
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "strings"
+
+    aplan "kongctl/internal/apply"
+)
+
+// planActionText 为 CI 注解中各动作的描述
+var planActionText = map[string]string{"create": "将被创建", "update": "将被更新", "delete": "将被删除"}
+
+// planAnnotationFile 返回注解关联的文件：-f 为单个文件时即该文件，否则为空
+func planAnnotationFile() string {
+    if applyFile == "" || applyFile == "-" || isSpecDir(applyFile) { return "" }
+    return applyFile
+}
+
+// pendingItems 返回计划中有变更的项；未启用 --overwrite 时更新项也列出，并在 skipped 中标记
+func pendingItems(plan aplan.Plan) (items []aplan.Change, skipped map[int]bool) {
+    skipped = map[int]bool{}
+    for _, it := range plan.Items {
+        if it.Action == "none" { continue }
+        if it.Action == "update" && !applyOverwrite { skipped[len(items)] = true }
+        items = append(items, it)
+    }
+    return items, skipped
+}
+
+func planItemMessage(it aplan.Change, skipped bool) string {
+    act := planActionText[it.Action]
+    if act == "" { act = it.Action }
+    msg := fmt.Sprintf("%s %s %s", it.Kind, it.Name, act)
+    if skipped { msg += "（未启用 --overwrite，本次将跳过）" }
+    return msg
+}
+
+// githubEscape 按 GitHub Actions workflow command 的规则转义消息与属性值
+func githubEscape(s string, property bool) string {
+    r := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+    s = r.Replace(s)
+    if property { s = strings.NewReplacer(":", "%3A", ",", "%2C").Replace(s) }
+    return s
+}
+
+// writePlanGitHub 以 GitHub Actions 注解输出计划：创建为 notice，更新与删除为 warning，字段差异随注解展开显示
+func writePlanGitHub(w io.Writer, plan aplan.Plan) error {
+    items, skipped := pendingItems(plan)
+    props := "title=" + githubEscape("kongctl plan", true)
+    if f := planAnnotationFile(); f != "" { props = "file=" + githubEscape(f, true) + "," + props }
+    for i, it := range items {
+        level := "warning"
+        if it.Action == "create" { level = "notice" }
+        msg := planItemMessage(it, skipped[i])
+        if it.Diff != "" { msg += "\n" + strings.TrimRight(it.Diff, "\n") }
+        if _, err := fmt.Fprintf(w, "::%s %s::%s\n", level, props, githubEscape(msg, false)); err != nil {
+            return err
+        }
+    }
+    n, _ := pendingChanges(plan)
+    summary := contextBanner() + "无变更"
+    if n > 0 { summary = fmt.Sprintf("%s共 %d 项待执行变更", contextBanner(), n) }
+    if len(skipped) > 0 { summary += fmt.Sprintf("（另有 %d 项更新需 --overwrite）", len(skipped)) }
+    _, err := fmt.Fprintf(w, "::notice %s::%s\n", props, githubEscape(summary, false))
+    return err
+}
+
+// gitlabIssue 为 GitLab Code Quality 报告中的一项，合并请求页面会逐项展示
+type gitlabIssue struct {
+    Description string         `json:"description"`
+    CheckName   string         `json:"check_name"`
+    Fingerprint string         `json:"fingerprint"`
+    Severity    string         `json:"severity"`
+    Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+    Path  string         `json:"path"`
+    Lines map[string]int `json:"lines"`
+}
+
+// gitlabSeverity 为各动作在 Code Quality 报告中的严重程度
+var gitlabSeverity = map[string]string{"create": "info", "update": "minor", "delete": "major"}
+
+// writePlanGitLab 以 GitLab Code Quality 报告（JSON 数组）输出计划，作为 artifacts:reports:codequality 上传后在合并请求中展示
+func writePlanGitLab(w io.Writer, plan aplan.Plan) error {
+    items, skipped := pendingItems(plan)
+    path := planAnnotationFile()
+    if path == "" { path = applyFile }
+    out := make([]gitlabIssue, 0, len(items))
+    for i, it := range items {
+        desc := planItemMessage(it, skipped[i])
+        if d := strings.TrimRight(it.Diff, "\n"); d != "" { desc += "：" + strings.NewReplacer(":\n", ": ", "\n", "; ").Replace(d) }
+        sev := gitlabSeverity[it.Action]
+        if sev == "" { sev = "info" }
+        out = append(out, gitlabIssue{
+            Description: desc,
+            CheckName:   "kongctl-plan-" + it.Action,
+            Fingerprint: fingerprint([]string{activeContext, it.Kind, it.Name, it.Action, it.Diff}),
+            Severity:    sev,
+            Location:    gitlabLocation{Path: path, Lines: map[string]int{"begin": 1}},
+        })
+    }
+    b, err := json.MarshalIndent(out, "", "  ")
+    if err != nil {
+        return err
+    }
+    _, err = fmt.Fprintln(w, string(b))
+    return err
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "net/http"
+    "os"
+    "os/exec"
+    "runtime"
+    "strings"
+    "time"
+
+    "github.com/spf13/viper"
+    "kongctl/internal/kong"
+)
+
+// clientConfig 汇总 flag/env/配置文件中的 Admin API 连接参数，供各子命令创建客户端
+func clientConfig(timeout time.Duration) (kong.Config, error) {
+    cfg := kong.Config{
+        AdminURL:      viper.GetString("admin_url"),
+        Token:         viper.GetString("token"),
+        Workspace:     viper.GetString("workspace"),
+        TLSSkipVerify: viper.GetBool("tls_skip_verify"),
+        Timeout:       timeout,
+    }
+    if cfg.AdminURL == "" {
+        return cfg, withCode("config", "运行 kongctl init --admin-url <url> 或设置 KONGCTL_ADMIN_URL", fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置"))
+    }
+    if command := strings.TrimSpace(viper.GetString("token_command")); command != "" {
+        cfg.TokenSource = tokenCommandSource(command)
+    }
+    headers, err := requestHeaders()
+    if err != nil {
+        return cfg, err
+    }
+    cfg.UserAgent, cfg.Headers, cfg.TimeoutHeader = userAgent(), headers, strings.TrimSpace(viper.GetString("timeout_header"))
+    if viper.GetBool("debug_http") { cfg.Debug = os.Stderr }
+    return cfg, nil
+}
+
+// userAgent 返回 Admin API 请求的 User-Agent
+func userAgent() string {
+    return "kongctl/" + version
+}
+
+// requestHeaders 合并配置（或上下文）中的 headers 与 --header 指定的请求头，后者优先
+func requestHeaders() (map[string]string, error) {
+    out := map[string]string{}
+    // viper 会将配置中的键转为小写，统一为规范形式后再合并
+    for k, v := range viper.GetStringMapString("headers") { out[http.CanonicalHeaderKey(k)] = v }
+    for _, h := range viper.GetStringSlice("header") {
+        k, v, ok := strings.Cut(h, ":")
+        if !ok || strings.TrimSpace(k) == "" {
+            return nil, withCode("usage", "", fmt.Errorf("--header 格式应为 名称:值，例：--header 'X-Request-Source: ci'"))
+        }
+        out[http.CanonicalHeaderKey(strings.TrimSpace(k))] = strings.TrimSpace(v)
+    }
+    return out, nil
+}
+
+// tokenCommandSource 通过执行 token_command 获取 token（取标准输出并去除首尾空白）
+func tokenCommandSource(command string) func(ctx context.Context) (string, error) {
+    return func(ctx context.Context) (string, error) {
+        ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+        defer cancel()
+        var c *exec.Cmd
+        if runtime.GOOS == "windows" {
+            c = exec.CommandContext(ctx, "cmd", "/C", command)
+        } else {
+            c = exec.CommandContext(ctx, "sh", "-c", command)
+        }
+        var stderr bytes.Buffer
+        c.Stderr = &stderr
+        out, err := c.Output()
+        if err != nil {
+            msg := strings.TrimSpace(stderr.String())
+            if msg != "" {
+                return "", fmt.Errorf("执行 token_command 失败：%v：%s", err, msg)
+            }
+            return "", fmt.Errorf("执行 token_command 失败：%v", err)
+        }
+        token := strings.TrimSpace(string(out))
+        if token == "" {
+            return "", fmt.Errorf("token_command 未输出 token")
+        }
+        return token, nil
+    }
+}
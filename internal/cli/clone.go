@@ -0,0 +1,377 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    cloneAs          string
+    cloneWithRoutes  bool
+    cloneWithPlugins bool
+    clonePathPrefix  string
+    cloneService     string
+)
+
+// cloneRoute 为待复制的 route：body 为新 route 的内容，plugins 为其上待复制的插件
+type cloneRoute struct {
+    from    string
+    body    map[string]any
+    plugins []map[string]any
+}
+
+// cloneFetch 读取单个实体或列表为 map，数值保留为 json.Number（原样写回，不转为浮点）
+func cloneFetch(ctx context.Context, client *kong.Client, path string) (map[string]any, bool, error) {
+    raw, ok, err := client.GetRaw(ctx, path)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    var obj map[string]any
+    dec := json.NewDecoder(bytes.NewReader(raw))
+    dec.UseNumber()
+    if err := dec.Decode(&obj); err != nil {
+        return nil, false, fmt.Errorf("解析 %s 失败：%w", path, err)
+    }
+    return obj, true, nil
+}
+
+// cloneEntity 复制实体并去掉 id、created_at、updated_at 等只读字段
+func cloneEntity(obj map[string]any) map[string]any {
+    out := make(map[string]any, len(obj))
+    for k, v := range obj {
+        if !sliceContains(editReadOnlyFields, k) { out[k] = v }
+    }
+    return out
+}
+
+// clonePlugins 列出 scope（如 /services/<id>）下的插件并复制；instance_name 需唯一，由 Kong 重新生成
+func clonePlugins(ctx context.Context, client *kong.Client, scope string) ([]map[string]any, error) {
+    lst, _, err := cloneFetch(ctx, client, scope+"/plugins?size=1000")
+    if err != nil {
+        return nil, fmt.Errorf("列出 %s 的插件失败：%w", scope, err)
+    }
+    data, _ := lst["data"].([]any)
+    out := make([]map[string]any, 0, len(data))
+    for _, d := range data {
+        p, ok := d.(map[string]any)
+        if !ok { continue }
+        p = cloneEntity(p)
+        delete(p, "instance_name")
+        out = append(out, p)
+    }
+    return out, nil
+}
+
+// cloneRouteName 推导复制后的 route 名称：名称中含源 Service 名称时替换为新名称，否则追加 -<新名称>；未命名的 route 保持未命名
+func cloneRouteName(name, from, to string) string {
+    if name == "" { return "" }
+    if from != "" && strings.Contains(name, from) { return strings.Replace(name, from, to, 1) }
+    return name + "-" + to
+}
+
+// clonePrefixPaths 为 route 的 paths 加上前缀；正则路径（~ 开头）保持不变并计入返回的个数
+func clonePrefixPaths(body map[string]any, prefix string) int {
+    prefix = "/" + strings.Trim(prefix, "/")
+    paths, _ := body["paths"].([]any)
+    skipped := 0
+    for i, p := range paths {
+        s, ok := p.(string)
+        if !ok || strings.HasPrefix(s, "~") { skipped++; continue }
+        if s == "/" { s = "" }
+        paths[i] = prefix + s
+    }
+    return skipped
+}
+
+// cloneRefID 返回实体引用字段（如 route.service）中的 id
+func cloneRefID(body map[string]any, field string) string {
+    ref, _ := body[field].(map[string]any)
+    id, _ := ref["id"].(string)
+    return id
+}
+
+// cloneRouteLabel 返回用于提示的 route 名称与 paths
+func cloneRouteLabel(body map[string]any) string {
+    name, _ := body["name"].(string)
+    if name == "" { name = "（未命名）" }
+    var paths []string
+    if ps, ok := body["paths"].([]any); ok {
+        for _, p := range ps { paths = append(paths, fmt.Sprint(p)) }
+    }
+    if len(paths) == 0 { return name }
+    return fmt.Sprintf("%s paths=%s", name, strings.Join(paths, ","))
+}
+
+// cloneLoadRoute 读取待复制的 route 及（--with-plugins 时）其上的插件
+func cloneLoadRoute(ctx context.Context, client *kong.Client, nameOrID, newName string) (cloneRoute, bool, error) {
+    src, ok, err := cloneFetch(ctx, client, "/routes/"+url.PathEscape(nameOrID))
+    if err != nil || !ok {
+        return cloneRoute{}, ok, err
+    }
+    from, _ := src["name"].(string)
+    if from == "" { from, _ = src["id"].(string) }
+    body := cloneEntity(src)
+    if newName != "" {
+        body["name"] = newName
+    } else {
+        delete(body, "name")
+    }
+    r := cloneRoute{from: from, body: body}
+    if cloneWithPlugins {
+        id, _ := src["id"].(string)
+        if r.plugins, err = clonePlugins(ctx, client, "/routes/"+id); err != nil {
+            return cloneRoute{}, false, err
+        }
+    }
+    return r, true, nil
+}
+
+// cloneCheckRoutes 检查新 route 名称未被占用，并处理 --path-prefix 与匹配相同请求的提示
+func cloneCheckRoutes(cmd *cobra.Command, ctx context.Context, client *kong.Client, rts []cloneRoute) error {
+    for _, r := range rts {
+        name, _ := r.body["name"].(string)
+        if name == "" { continue }
+        _, exists, err := client.GetRaw(ctx, "/routes/"+url.PathEscape(name))
+        if err != nil {
+            return err
+        }
+        if exists {
+            return withCode("conflict", "", fmt.Errorf("Route 已存在：%s（复制自 %s）", name, r.from))
+        }
+    }
+    if len(rts) == 0 {
+        return nil
+    }
+    if clonePathPrefix == "" {
+        PrintWarn(cmd, "复制的 route 与原 route 的 hosts/paths/methods 相同，请求可能由任一 route 处理；可使用 --path-prefix 区分，或复制后用 kongctl edit route <name> 修改")
+        return nil
+    }
+    skipped := 0
+    for _, r := range rts { skipped += clonePrefixPaths(r.body, clonePathPrefix) }
+    if skipped > 0 { PrintWarn(cmd, "%d 个正则路径（~ 开头）未加 --path-prefix，复制后请按需修改", skipped) }
+    return nil
+}
+
+// cloneCreatePlugins 创建插件：service/route 引用替换为新实体的 id（为空时保持原引用）
+func cloneCreatePlugins(cmd *cobra.Command, ctx context.Context, client *kong.Client, plugins []map[string]any, svcID, routeID, owner string) error {
+    for _, p := range plugins {
+        if svcID != "" && (p["service"] != nil || routeID == "") { p["service"] = map[string]any{"id": svcID} }
+        if routeID != "" { p["route"] = map[string]any{"id": routeID} }
+        if _, err := client.CreateRaw(ctx, "/plugins", p); err != nil {
+            return fmt.Errorf("创建插件 %v（%s）失败：%w", p["name"], owner, err)
+        }
+        PrintSuccess(cmd, "已创建插件：%v（%s）", p["name"], owner)
+    }
+    return nil
+}
+
+// cloneCreateRoutes 依次创建 route 及其插件；svcID 非空时将 route 挂到该 Service 下
+func cloneCreateRoutes(cmd *cobra.Command, ctx context.Context, client *kong.Client, rts []cloneRoute, svcID string) error {
+    for _, r := range rts {
+        if svcID != "" { r.body["service"] = map[string]any{"id": svcID} }
+        raw, err := client.CreateRaw(ctx, "/routes", r.body)
+        if err != nil {
+            return fmt.Errorf("创建 Route %s 失败：%w", cloneRouteLabel(r.body), err)
+        }
+        var created struct{ ID, Name string }
+        if err := json.Unmarshal(raw, &created); err != nil {
+            return fmt.Errorf("解析新建 Route 失败：%w", err)
+        }
+        label := created.Name
+        if label == "" { label = created.ID }
+        PrintSuccess(cmd, "已创建 Route：%s（复制自 %s）", cloneRouteLabel(r.body), r.from)
+        if err := cloneCreatePlugins(cmd, ctx, client, r.plugins, svcID, created.ID, "Route "+label); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// clonePrintPlan 输出 --dry-run 的复制计划
+func clonePrintPlan(cmd *cobra.Command, rts []cloneRoute, plugins []map[string]any, owner string) {
+    for _, p := range plugins { PrintInfo(cmd, "[dry-run] 将创建插件：%v（%s）", p["name"], owner) }
+    for _, r := range rts {
+        PrintInfo(cmd, "[dry-run] 将创建 Route：%s（复制自 %s）", cloneRouteLabel(r.body), r.from)
+        name, _ := r.body["name"].(string)
+        for _, p := range r.plugins { PrintInfo(cmd, "[dry-run] 将创建插件：%v（Route %s）", p["name"], name) }
+    }
+}
+
+var cloneCmd = &cobra.Command{
+    Use:   "clone",
+    Short: "以新名称复制 Service（可含 routes 与插件）或 Route",
+    Long: `读取远程已有的 Service 或 Route，以新名称创建一份副本（除 id、created_at、updated_at 外的字段原样复制），
+适合在现有 API 的基础上创建 v2 等新版本。复制后的资源与原资源互不影响，可用 kongctl edit 或 apply 继续修改。`,
+}
+
+var cloneServiceCmd = &cobra.Command{
+    Use:   "service <name|id> --as <new-name>",
+    Short: "复制 Service；--with-routes 一并复制其 routes，--with-plugins 一并复制插件",
+    Long: `以 --as 为名称复制 Service。--with-routes 时复制其下全部 routes 并挂到新 Service 下：
+route 名称中含原 Service 名称时替换为新名称（user-service-list → user-service-v2-list），否则追加 -<新名称>；
+--path-prefix 为复制的 routes 的 paths 加上前缀，避免与原 routes 匹配相同的请求。
+--with-plugins 复制 Service（及复制的 routes）上的插件。创建中途失败时已创建的资源不回滚，可使用 kongctl service delete <new-name> --cascade 清理。`,
+    Args: cobra.ExactArgs(1),
+    Example: `# 预览：基于 user-service 创建 v2（含 routes 与插件，路径加 /v2 前缀）
+kongctl clone service user-service --as user-service-v2 --with-routes --with-plugins --path-prefix /v2 --dry-run
+
+# 只复制 Service 本身
+kongctl clone service user-service --as user-service-canary`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if cloneAs == "" {
+            return withCode("usage", "", fmt.Errorf("必须通过 --as 指定新 Service 的名称"))
+        }
+        if clonePathPrefix != "" && !cloneWithRoutes {
+            return withCode("usage", "", fmt.Errorf("--path-prefix 需与 --with-routes 一起使用"))
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        svc, ok, err := client.GetService(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "使用 kongctl service list 查看已有 Service", fmt.Errorf("Service 不存在：%s", args[0]))
+        }
+        if _, exists, err := client.GetService(ctx, cloneAs); err != nil {
+            return err
+        } else if exists {
+            return withCode("conflict", "", fmt.Errorf("Service 已存在：%s", cloneAs))
+        }
+        src, _, err := cloneFetch(ctx, client, "/services/"+svc.ID)
+        if err != nil {
+            return err
+        }
+        body := cloneEntity(src)
+        body["name"] = cloneAs
+        // 通过 /services/<id>/plugins 列出的插件中，同时绑定 route 的随 route 复制
+        var plugins []map[string]any
+        if cloneWithPlugins {
+            all, err := clonePlugins(ctx, client, "/services/"+svc.ID)
+            if err != nil {
+                return err
+            }
+            for _, p := range all {
+                if p["route"] == nil { plugins = append(plugins, p) }
+            }
+        }
+        var rts []cloneRoute
+        if cloneWithRoutes {
+            list, err := serviceRoutes(ctx, client, svc)
+            if err != nil {
+                return err
+            }
+            for _, r := range list {
+                cr, _, err := cloneLoadRoute(ctx, client, r.ID, cloneRouteName(r.Name, svc.Name, cloneAs))
+                if err != nil {
+                    return err
+                }
+                rts = append(rts, cr)
+            }
+            if err := cloneCheckRoutes(cmd, ctx, client, rts); err != nil {
+                return err
+            }
+        }
+
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将创建 Service：%s（复制自 %s）", cloneAs, svc.Name)
+            clonePrintPlan(cmd, rts, plugins, "Service "+cloneAs)
+            return nil
+        }
+        raw, err := client.CreateRaw(ctx, "/services", body)
+        if err != nil {
+            return fmt.Errorf("创建 Service %s 失败：%w", cloneAs, err)
+        }
+        var created struct{ ID string }
+        if err := json.Unmarshal(raw, &created); err != nil {
+            return fmt.Errorf("解析新建 Service 失败：%w", err)
+        }
+        PrintSuccess(cmd, "已创建 Service：%s（复制自 %s）", cloneAs, svc.Name)
+        err = cloneCreatePlugins(cmd, ctx, client, plugins, created.ID, "", "Service "+cloneAs)
+        if err == nil { err = cloneCreateRoutes(cmd, ctx, client, rts, created.ID) }
+        if err != nil {
+            return fmt.Errorf("%w（已创建的资源未回滚，可使用 kongctl service delete %s --cascade 清理）", err, cloneAs)
+        }
+        return nil
+    },
+}
+
+var cloneRouteCmd = &cobra.Command{
+    Use:   "route <name|id> --as <new-name>",
+    Short: "复制 Route（默认挂在同一 Service 下，--service 指定其他 Service）",
+    Long: `以 --as 为名称复制 Route。默认挂在原 Service 下，--service 挂到其他 Service；
+--path-prefix 为 paths 加上前缀，--with-plugins 一并复制 Route 上的插件。`,
+    Args: cobra.ExactArgs(1),
+    Example: `kongctl clone route user-list --as user-list-v2 --path-prefix /v2 --dry-run
+kongctl clone route user-list --as user-list-canary --service user-service-canary --with-plugins`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if cloneAs == "" {
+            return withCode("usage", "", fmt.Errorf("必须通过 --as 指定新 Route 的名称"))
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        r, ok, err := cloneLoadRoute(ctx, client, args[0], cloneAs)
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "使用 kongctl route list 查看已有 Route", fmt.Errorf("Route 不存在：%s", args[0]))
+        }
+        svcID, owner := "", "新 Route 挂在原 Service 下"
+        if cloneService != "" {
+            svc, ok, err := client.GetService(ctx, cloneService)
+            if err != nil {
+                return err
+            }
+            if !ok {
+                return withCode("not_found", "使用 kongctl service list 查看已有 Service", fmt.Errorf("Service 不存在：%s", cloneService))
+            }
+            svcID, owner = svc.ID, "新 Route 挂在 Service "+svc.Name+" 下"
+        } else if cloneRefID(r.body, "service") == "" {
+            owner = "新 Route 不关联 Service"
+        }
+        rts := []cloneRoute{r}
+        if err := cloneCheckRoutes(cmd, ctx, client, rts); err != nil {
+            return err
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] %s", owner)
+            clonePrintPlan(cmd, rts, nil, "")
+            return nil
+        }
+        return cloneCreateRoutes(cmd, ctx, client, rts, svcID)
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(cloneCmd)
+    cloneCmd.AddCommand(cloneServiceCmd, cloneRouteCmd)
+    for _, c := range []*cobra.Command{cloneServiceCmd, cloneRouteCmd} {
+        c.Flags().StringVar(&cloneAs, "as", "", "新资源的名称（必填）")
+        c.Flags().BoolVar(&cloneWithPlugins, "with-plugins", false, "一并复制插件")
+        c.Flags().StringVar(&clonePathPrefix, "path-prefix", "", "为复制的 route 的 paths 加上前缀，例：--path-prefix /v2")
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+    cloneServiceCmd.Flags().BoolVar(&cloneWithRoutes, "with-routes", false, "一并复制 Service 下的全部 routes")
+    cloneRouteCmd.Flags().StringVar(&cloneService, "service", "", "将新 Route 挂到该 Service 下（默认与原 Route 相同）")
+}
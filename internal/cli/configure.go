@@ -80,15 +80,14 @@ kongctl init`,
             PrintInfo(cmd, "已自动检测 Admin API 地址：%s", detected)
         }
         flagAdminURL = detected
-        home, err := os.UserHomeDir()
+        dir, err := userConfigDir()
         if err != nil {
             return err
         }
-        dir := filepath.Join(home, ".kongctl")
         _ = os.MkdirAll(dir, 0o755)
         file := filepath.Join(dir, "config.yaml")
         content := fmt.Sprintf("admin_url: %s\ntoken: %s\nworkspace: %s\n", flagAdminURL, flagToken, flagWorkspace)
-        if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+        if err := writeTextFile(file, []byte(content), 0o600); err != nil {
             return err
         }
         PrintSuccess(cmd, "已写入配置：%s", file)
@@ -118,6 +117,7 @@ func probeAdminURL(ctx context.Context, baseURL, token string) bool {
     paths := []string{"/status", "/"}
     for _, p := range paths {
         req, _ := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(u, "/")+p, nil)
+        req.Header.Set("User-Agent", userAgent())
         if token != "" {
             req.Header.Set("Kong-Admin-Token", token)
             req.Header.Set("Authorization", "Bearer "+token)
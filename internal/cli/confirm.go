@@ -0,0 +1,185 @@
+package cli
+
+import (
+    "bufio"
+    "context"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// contextIsProduction 判断当前上下文是否标记为 production: true
+func contextIsProduction() bool {
+    if activeContext == "" { return false }
+    return viper.GetBool("contexts." + activeContext + ".production")
+}
+
+// confirmDestructive 在 production 上下文执行删除/回滚等破坏性操作前，
+// 要求用户输入上下文名称确认（类似 terraform destroy）；--force 可跳过。
+func confirmDestructive(cmd *cobra.Command, op string) error {
+    if !contextIsProduction() {
+        return nil
+    }
+    if viper.GetBool("force") {
+        PrintWarn(cmd, "已通过 --force 跳过生产上下文确认：%s（context=%s）", op, activeContext)
+        return nil
+    }
+    PrintWarn(cmd, "即将在生产上下文 %s 上执行破坏性操作：%s", activeContext, op)
+    cmd.Printf("请输入上下文名称 %q 以确认（或使用 --force 跳过）：", activeContext)
+    line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+    if err != nil && strings.TrimSpace(line) == "" {
+        return fmt.Errorf("未读取到确认输入，已取消：%s（非交互环境请使用 --force）", op)
+    }
+    if strings.TrimSpace(line) != activeContext {
+        return fmt.Errorf("确认失败（输入与上下文名称不一致），已取消：%s", op)
+    }
+    return nil
+}
+
+// confirmDelete 在 service/route/upstream/target delete 执行前提示 [y/N] 确认，--yes 跳过；
+// 标准输入不是终端（管道、CI）且未加 --yes 时直接拒绝。生产上下文另需 confirmDestructive 输入上下文名称确认。
+// 返回 false 表示用户取消（已提示，不视为错误）
+func confirmDelete(cmd *cobra.Command, op string) (bool, error) {
+    if !deleteYes {
+        if f, ok := cmd.InOrStdin().(*os.File); ok {
+            if st, err := f.Stat(); err != nil || st.Mode()&os.ModeCharDevice == 0 {
+                return false, withCode("usage", "非交互环境请加 --yes 确认删除", fmt.Errorf("标准输入不是终端，无法确认，已取消：%s", op))
+            }
+        }
+        target := ""
+        if activeContext != "" { target = "（上下文 " + activeContext + "）" }
+        cmd.Printf("确认执行 %s%s？[y/N] ", op, target)
+        line, rerr := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+        if rerr != nil && strings.TrimSpace(line) == "" {
+            return false, fmt.Errorf("未读取到确认输入，已取消（非交互环境请使用 --yes）")
+        }
+        switch strings.ToLower(strings.TrimSpace(line)) {
+        case "y", "yes":
+        default:
+            PrintInfo(cmd, "已取消，未做任何变更")
+            return false, nil
+        }
+    }
+    return true, confirmDestructive(cmd, op)
+}
+
+var (
+    applyConfirm bool
+    applyYes     bool
+    // deleteYes 为各 delete 命令的 --yes，与 apply 的 --yes 分开
+    deleteYes bool
+)
+
+// applyConfirmEnabled 判断 apply 是否需要先展示计划并交互确认（--confirm 或配置 confirm: true；--yes 跳过）
+func applyConfirmEnabled() bool {
+    if applyYes { return false }
+    return applyConfirm || viper.GetBool("confirm")
+}
+
+// pendingChanges 统计计划中实际会执行的变更数；未启用 --overwrite 时更新项会被跳过，不计入
+func pendingChanges(plan aplan.Plan) (n, skipped int) {
+    for _, it := range plan.Items {
+        switch it.Action {
+        case "none":
+        case "update":
+            if applyOverwrite { n++ } else { skipped++ }
+        default:
+            n++
+        }
+    }
+    return n, skipped
+}
+
+// confirmAndApply 先以 dry-run 方式计算并展示计划，确认后再执行。
+// 确认后的阶段使用新的执行时限 context 并在此时获取 apply 锁，等待输入的时间不计入时限、也不持有锁
+func confirmAndApply(cmd *cobra.Command, ctx context.Context, cfg kong.Config, client *kong.Client, spec applySpec) error {
+    if applyFile == "-" {
+        return fmt.Errorf("从标准输入读取 spec 时无法交互确认，请改用文件或加 --yes")
+    }
+    plan := &aplan.Plan{}
+    dryRun = true
+    err := runApplyPhase(cmd, ctx, client, spec, plan)
+    dryRun = false
+    if err != nil {
+        return err
+    }
+    n, skipped := pendingChanges(*plan)
+    if skipped > 0 {
+        PrintInfo(cmd, "%d 项更新因未启用 --overwrite 将被跳过", skipped)
+    }
+    if n == 0 {
+        PrintInfo(cmd, "没有需要执行的变更")
+        return nil
+    }
+    // 记录规划时的远程指纹，确认后校验，避免覆盖等待确认期间其他人对同一资源的修改
+    fps, err := collectFingerprints(ctx, client, *plan)
+    if err != nil {
+        return err
+    }
+    target := ""
+    if activeContext != "" { target = "到上下文 " + activeContext }
+    cmd.Printf("是否应用以上 %d 项变更%s？[y/N] ", n, target)
+    line, rerr := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+    if rerr != nil && strings.TrimSpace(line) == "" {
+        return fmt.Errorf("未读取到确认输入，已取消（非交互环境请使用 --yes）")
+    }
+    switch strings.ToLower(strings.TrimSpace(line)) {
+    case "y", "yes":
+    default:
+        PrintInfo(cmd, "已取消，未做任何变更")
+        return nil
+    }
+    deletes := 0
+    for _, it := range plan.Items {
+        if it.Action == "delete" { deletes++ }
+    }
+    if err := confirmPrune(cmd, deletes); err != nil {
+        return err
+    }
+    ctx, cancel := budgetContext(cmd.Context(), cfg.Timeout)
+    defer cancel()
+    if !applyNoLock {
+        release, err := acquireApplyLock(cmd, ctx, cfg)
+        if err != nil {
+            return err
+        }
+        defer release()
+    }
+    if !applyNoPrefetch {
+        // 重新预取，确保按最新的远程状态校验
+        if err := client.Prefetch(ctx); err != nil {
+            return fmt.Errorf("重新读取远程状态失败，未做任何变更：%w", err)
+        }
+    }
+    changed, err := changedSincePlan(ctx, client, *plan, fps)
+    if err != nil {
+        return err
+    }
+    if len(changed) > 0 {
+        // 未写入任何资源，无需 --resume
+        applyCheckpoint.remove()
+        applyCheckpoint = nil
+        return withCode("remote_changed", "", fmt.Errorf("展示计划后以下远程资源已被修改（可能有其他人同时变更），已中止以免覆盖，未做任何变更：\n  - %s", strings.Join(changed, "\n  - ")))
+    }
+    if err := snapshotBeforeApply(cmd, ctx, client, spec); err != nil {
+        return err
+    }
+    if err := runApplyPhase(cmd, ctx, client, spec, &aplan.Plan{}); err != nil {
+        return err
+    }
+    recordLastApplied(cmd, spec)
+    if applyWait {
+        if err := waitHealthyTargets(cmd, client, spec); err != nil {
+            return err
+        }
+    }
+    if applyVerify {
+        return verifyRouteTests(cmd, spec)
+    }
+    return nil
+}
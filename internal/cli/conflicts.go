@@ -0,0 +1,155 @@
+package cli
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// specConflict 描述 spec 中的一处重复定义或匹配冲突
+type specConflict struct {
+    Kind    string
+    Message string
+}
+
+// withSource 在使用 include 时附加资源来源文件
+func withSource(label, source string) string {
+    if source == "" { return label }
+    return label + "（" + source + "）"
+}
+
+// duplicateTracker 记录同类资源名称的首次出现位置
+type duplicateTracker struct {
+    kind  string
+    first map[string]string
+    out   *[]specConflict
+}
+
+func (d duplicateTracker) add(name, where string) {
+    if name == "" { return }
+    if prev, ok := d.first[name]; ok {
+        *d.out = append(*d.out, specConflict{Kind: d.kind, Message: fmt.Sprintf("%s 名称重复：%s（%s 与 %s）", d.kind, name, prev, where)})
+        return
+    }
+    d.first[name] = where
+}
+
+// routeMatchKey 为 route 的匹配条件（hosts/paths/methods/headers/regex_priority）
+func routeMatchKey(r applyRoute) (hosts, paths, methods []string, extra string) {
+    hosts = lowerAll(r.Hosts)
+    methods = toUpper(r.Methods)
+    var hk []string
+    for k, vs := range r.Headers {
+        v := append([]string(nil), vs...)
+        sort.Strings(v)
+        hk = append(hk, strings.ToLower(k)+"="+strings.Join(v, "|"))
+    }
+    sort.Strings(hk)
+    return hosts, r.Paths, methods, fmt.Sprintf("%s;%d", strings.Join(hk, ","), r.RegexPriority)
+}
+
+func lowerAll(xs []string) []string {
+    out := make([]string, 0, len(xs))
+    for _, x := range xs { out = append(out, strings.ToLower(x)) }
+    return out
+}
+
+// matchOverlap 判断某一匹配维度是否冲突：均未设置，或均设置且存在交集
+func matchOverlap(a, b []string) ([]string, bool) {
+    if len(a) == 0 && len(b) == 0 { return nil, true }
+    if len(a) == 0 || len(b) == 0 { return nil, false }
+    set := map[string]bool{}
+    for _, x := range a { set[x] = true }
+    var common []string
+    for _, x := range b {
+        if set[x] { common = append(common, x); delete(set, x) }
+    }
+    return common, len(common) > 0
+}
+
+func describeMatch(dim string, common []string) string {
+    if len(common) == 0 { return dim + "=任意" }
+    return dim + "=" + strings.Join(common, ",")
+}
+
+// checkSpecConflicts 检测 spec 内的重复名称与匹配条件完全重叠的 routes，避免后定义的资源静默覆盖前者
+func checkSpecConflicts(spec applySpec) error {
+    var out []specConflict
+    ups := duplicateTracker{kind: "upstream", first: map[string]string{}, out: &out}
+    for i, up := range spec.Upstreams {
+        ups.add(up.Name, withSource(fmt.Sprintf("upstreams[%d]", i), up.source))
+    }
+    svcs := duplicateTracker{kind: "service", first: map[string]string{}, out: &out}
+    for i, s := range spec.Services {
+        svcs.add(s.Name, withSource(fmt.Sprintf("services[%d]", i), s.source))
+    }
+    rts := duplicateTracker{kind: "route", first: map[string]string{}, out: &out}
+    names := make([]string, len(spec.Routes))
+    derived := map[string]int{}
+    for i, r := range spec.Routes {
+        name := r.Name
+        if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        names[i] = name
+        where := withSource(fmt.Sprintf("routes[%d]", i), r.source)
+        rts.add(name, where)
+        if r.Service == "" && name != "" {
+            // 简写派生的 service 不应与显式声明的 service 同名，否则会被 backend 覆盖
+            svcName, _ := autoBackendNames(r, name)
+            if prev, ok := svcs.first[svcName]; ok {
+                out = append(out, specConflict{Kind: "service", Message: fmt.Sprintf("route %s 的简写派生 service %s 与 %s 重名", name, svcName, prev)})
+            } else if k, ok := derived[svcName]; ok && !reflect.DeepEqual(spec.Routes[k].Backend, r.Backend) {
+                out = append(out, specConflict{Kind: "service", Message: fmt.Sprintf("routes %s 与 %s 派生同名 service %s，但 backend 不一致", names[k], name, svcName)})
+            } else if !ok {
+                derived[svcName] = i
+            }
+        }
+    }
+    groups := duplicateTracker{kind: "consumer_group", first: map[string]string{}, out: &out}
+    for i, g := range spec.ConsumerGroups {
+        groups.add(g.Name, withSource(fmt.Sprintf("consumer_groups[%d]", i), g.source))
+    }
+    cons := duplicateTracker{kind: "consumer", first: map[string]string{}, out: &out}
+    for i, c := range spec.Consumers {
+        cons.add(c.key(), withSource(fmt.Sprintf("consumers[%d]", i), c.source))
+    }
+    vaults := duplicateTracker{kind: "vault", first: map[string]string{}, out: &out}
+    for i, v := range spec.Vaults {
+        vaults.add(v.Prefix, withSource(fmt.Sprintf("vaults[%d]", i), v.source))
+    }
+    keySets := duplicateTracker{kind: "key_set", first: map[string]string{}, out: &out}
+    for i, ks := range spec.KeySets {
+        keySets.add(ks.Name, withSource(fmt.Sprintf("key_sets[%d]", i), ks.source))
+        // kid 在同一 key set 内唯一
+        kids := duplicateTracker{kind: "key", first: map[string]string{}, out: &out}
+        for j, k := range ks.Keys {
+            kids.add(ks.Name+"/"+k.Kid, withSource(fmt.Sprintf("key_sets[%d].keys[%d]", i, j), ks.source))
+        }
+    }
+    // SNI 全局唯一，同一 SNI 出现在两个证书上时后者的绑定会失败
+    snis := duplicateTracker{kind: "sni", first: map[string]string{}, out: &out}
+    for i, c := range spec.Certificates {
+        for _, sni := range c.Snis { snis.add(sni, withSource(fmt.Sprintf("certificates[%d]", i), c.source)) }
+    }
+    for i := 0; i < len(spec.Routes); i++ {
+        hi, pi, mi, xi := routeMatchKey(spec.Routes[i])
+        for j := i + 1; j < len(spec.Routes); j++ {
+            if names[i] != "" && names[i] == names[j] { continue }
+            hj, pj, mj, xj := routeMatchKey(spec.Routes[j])
+            if xi != xj { continue }
+            hc, ok1 := matchOverlap(hi, hj)
+            pc, ok2 := matchOverlap(pi, pj)
+            mc, ok3 := matchOverlap(mi, mj)
+            if !ok1 || !ok2 || !ok3 { continue }
+            a := withSource(names[i], spec.Routes[i].source)
+            b := withSource(names[j], spec.Routes[j].source)
+            out = append(out, specConflict{Kind: "route", Message: fmt.Sprintf("route %s 与 %s 的匹配条件冲突：%s %s %s", a, b, describeMatch("hosts", hc), describeMatch("paths", pc), describeMatch("methods", mc))})
+        }
+    }
+    if len(out) == 0 {
+        return nil
+    }
+    lines := make([]string, 0, len(out))
+    for _, c := range out { lines = append(lines, "  - "+c.Message) }
+    return withCode("usage", "重命名或合并重复的定义；匹配条件相同的 routes 请通过 hosts/methods/headers 区分", fmt.Errorf("spec 存在 %d 处重复或冲突：\n%s", len(out), strings.Join(lines, "\n")))
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+var (
+    consumerUsername string
+    consumerCustomID string
+    consumerTags     []string
+)
+
+var consumerCmd = &cobra.Command{
+    Use:   "consumer",
+    Short: "管理 Consumer 资源（同步/列出/查看/删除）",
+}
+
+// consumerSyncDiff 比较 Consumer 当前值与 --custom-id/--tags（未指定的字段不比较）
+func consumerSyncDiff(cur *kong.Consumer) string {
+    diff := ""
+    if consumerCustomID != "" && cur.CustomID != consumerCustomID { diff += fmt.Sprintf("custom_id: %s -> %s\n", cur.CustomID, consumerCustomID) }
+    if len(consumerTags) > 0 && !sliceSetEqual(cur.Tags, consumerTags) { diff += diffSlice("tags", cur.Tags, consumerTags) }
+    return diff
+}
+
+var consumerSyncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "按 username（或 custom_id）创建或更新 Consumer（幂等）",
+    Example: `kongctl consumer sync --username app1 --custom-id 10001 --tags team-a
+
+# 仅预览差异
+kongctl consumer sync --username app1 --tags team-a,mobile --dry-run --diff`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if consumerUsername == "" && consumerCustomID == "" {
+            return fmt.Errorf("必须提供 --username 或 --custom-id")
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        key := consumerUsername
+        if key == "" { key = consumerCustomID }
+        cur, exists, err := client.GetConsumer(ctx, key)
+        if err != nil {
+            return err
+        }
+        diff := ""
+        if exists {
+            if diff = consumerSyncDiff(cur); diff == "" {
+                PrintInfo(cmd, "Consumer 无变更，未写入：%s", key)
+                return nil
+            }
+        }
+        if showDiff || dryRun {
+            PrintInfo(cmd, "%sDiff: Consumer %s", emojiDiff, key)
+            if !exists {
+                if consumerUsername != "" { cmd.Printf("%s\n", colorInfo("+ username: "+consumerUsername)) }
+                if consumerCustomID != "" { cmd.Printf("%s\n", colorInfo("+ custom_id: "+consumerCustomID)) }
+                if len(consumerTags) > 0 { cmd.Printf("%s\n", colorInfo("+ tags: "+strings.Join(consumerTags, ","))) }
+            } else {
+                for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") { cmd.Printf("  %s\n", l) }
+            }
+        }
+        if dryRun {
+            action := "create"
+            if exists { action = "update" }
+            PrintInfo(cmd, "[dry-run] 将%s Consumer：%s", actionCN(action), key)
+            return nil
+        }
+        action, _, err := client.CreateOrUpdateConsumer(ctx, kong.Consumer{Username: consumerUsername, CustomID: consumerCustomID, Tags: consumerTags})
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s Consumer：%s", actionCN(action), key)
+        return nil
+    },
+}
+
+var consumerListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Consumer（--tags 时只列出同时带有这些标签的）",
+    Example: `kongctl consumer list
+kongctl consumer list --tags team-a --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        var list []kong.Consumer
+        if len(consumerTags) > 0 {
+            list, err = client.ListConsumersByTags(ctx, consumerTags)
+        } else {
+            list, err = client.ListConsumers(ctx)
+        }
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Username < list[j].Username })
+        if outputJSON() {
+            if list == nil { list = []kong.Consumer{} }
+            b, _ := json.MarshalIndent(list, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Consumer")
+            return nil
+        }
+        cmd.Printf("%-24s %-20s %-36s %s\n", "USERNAME", "CUSTOM_ID", "ID", "TAGS")
+        for _, c := range list {
+            cmd.Printf("%-24s %-20s %-36s %s\n", c.Username, c.CustomID, c.ID, strings.Join(c.Tags, ","))
+        }
+        return nil
+    },
+}
+
+var consumerGetCmd = &cobra.Command{
+    Use:   "get <username|id>",
+    Short: "查看 Consumer 及其插件、所属分组（YAML，--output json 时为 JSON）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl consumer get app1`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        c, ok, err := client.GetConsumer(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "使用 kongctl consumer list 查看已有 Consumer", fmt.Errorf("Consumer 不存在：%s", args[0]))
+        }
+        plugins, err := client.ListConsumerPlugins(ctx, c.ID)
+        if err != nil {
+            return err
+        }
+        names := make([]string, len(plugins))
+        for i, p := range plugins { names[i] = p.Name }
+        sort.Strings(names)
+        var obj map[string]any
+        b, _ := json.Marshal(c)
+        _ = json.Unmarshal(b, &obj)
+        groups, err := consumerGroupNames(ctx, client, c.ID)
+        if err != nil {
+            return err
+        }
+        if len(names) > 0 { obj["plugins"] = names }
+        if len(groups) > 0 { obj["groups"] = groups }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(obj, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        out, err := yaml.Marshal(obj)
+        if err != nil {
+            return err
+        }
+        cmd.Print(string(out))
+        return nil
+    },
+}
+
+var consumerDeleteCmd = &cobra.Command{
+    Use:   "delete <username|id>",
+    Short: "删除 Consumer（其下的凭证与插件一并删除）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl consumer delete app1`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        c, ok, err := client.GetConsumer(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Consumer 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Consumer：%s（含其凭证与插件）", args[0])
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("consumer delete %s（含其凭证与插件）", args[0])); err != nil {
+            return err
+        }
+        if err := client.DeleteConsumer(ctx, c.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Consumer：%s", args[0])
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(consumerCmd)
+    consumerCmd.AddCommand(consumerSyncCmd, consumerListCmd, consumerGetCmd, consumerDeleteCmd)
+    consumerSyncCmd.Flags().StringVar(&consumerUsername, "username", "", "Consumer 用户名，例：--username app1")
+    consumerSyncCmd.Flags().StringVar(&consumerCustomID, "custom-id", "", "Consumer 的 custom_id（未提供 --username 时按 custom_id 匹配）")
+    consumerSyncCmd.Flags().StringSliceVar(&consumerTags, "tags", nil, "Consumer 标签（覆盖现有标签），例：--tags team-a,mobile")
+    consumerSyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    consumerListCmd.Flags().StringSliceVar(&consumerTags, "tags", nil, "只列出同时带有这些标签的 Consumer，例：--tags team-a")
+    for _, c := range []*cobra.Command{consumerSyncCmd, consumerDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    groupName      string
+    groupTags      []string
+    groupConsumers []string
+)
+
+var consumerGroupCmd = &cobra.Command{
+    Use:   "consumer-group",
+    Short: "管理 Consumer Group 及其成员（Kong Enterprise / 3.4+）",
+    Long: `Consumer Group 将多个 Consumer 归为一组，组上的插件（如 rate-limiting）作用于组内全部成员，
+常用于按套餐分级限流。组上的插件通过 kongctl plugin sync <plugin> --consumer-group <组名> 管理；
+声明式管理请在 apply spec 中使用 consumer_groups 与 consumers[].groups。`,
+}
+
+// groupSetup 校验 --group 并确认分组存在，返回客户端与上下文
+func groupSetup(cmd *cobra.Command) (*kong.Client, context.Context, context.CancelFunc, *kong.ConsumerGroup, error) {
+    if groupName == "" {
+        return nil, nil, nil, nil, fmt.Errorf("必须提供 --group")
+    }
+    if len(groupConsumers) == 0 {
+        return nil, nil, nil, nil, fmt.Errorf("必须提供 --consumer")
+    }
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return nil, nil, nil, nil, err
+    }
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    g, ok, err := client.GetConsumerGroup(ctx, groupName)
+    if err != nil {
+        cancel()
+        return nil, nil, nil, nil, err
+    } else if !ok {
+        cancel()
+        return nil, nil, nil, nil, withCode("not_found", "先使用 kongctl consumer-group sync 创建", fmt.Errorf("Consumer Group 不存在：%s", groupName))
+    }
+    return client, ctx, cancel, g, nil
+}
+
+// groupMemberNames 返回分组成员的 username（无 username 时为 id）
+func groupMemberNames(ctx context.Context, client *kong.Client, group string) ([]string, error) {
+    members, err := client.ListConsumerGroupMembers(ctx, group)
+    if err != nil {
+        return nil, err
+    }
+    names := make([]string, len(members))
+    for i, c := range members {
+        names[i] = c.Username
+        if names[i] == "" { names[i] = c.ID }
+    }
+    sort.Strings(names)
+    return names, nil
+}
+
+var consumerGroupSyncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "按名称创建或更新 Consumer Group（幂等）",
+    Example: `kongctl consumer-group sync --name gold --tags tier
+kongctl plugin sync rate-limiting --consumer-group gold --config '{"minute": 1000, "policy": "local"}'`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if groupName == "" {
+            return fmt.Errorf("必须提供 --name")
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        cur, exists, err := client.GetConsumerGroup(ctx, groupName)
+        if err != nil {
+            return err
+        }
+        diff := ""
+        if exists {
+            if len(groupTags) > 0 && !sliceSetEqual(cur.Tags, groupTags) { diff = diffSlice("tags", cur.Tags, groupTags) }
+            if diff == "" {
+                PrintInfo(cmd, "Consumer Group 无变更，未写入：%s", groupName)
+                return nil
+            }
+        }
+        if showDiff || dryRun {
+            PrintInfo(cmd, "%sDiff: Consumer Group %s", emojiDiff, groupName)
+            if !exists {
+                cmd.Printf("%s\n", colorInfo("+ name: "+groupName))
+                if len(groupTags) > 0 { cmd.Printf("%s\n", colorInfo("+ tags: "+strings.Join(groupTags, ","))) }
+            } else {
+                for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") { cmd.Printf("  %s\n", l) }
+            }
+        }
+        if dryRun {
+            action := "create"
+            if exists { action = "update" }
+            PrintInfo(cmd, "[dry-run] 将%s Consumer Group：%s", actionCN(action), groupName)
+            return nil
+        }
+        action, _, err := client.CreateOrUpdateConsumerGroup(ctx, kong.ConsumerGroup{Name: groupName, Tags: groupTags})
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s Consumer Group：%s", actionCN(action), groupName)
+        return nil
+    },
+}
+
+var consumerGroupListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Consumer Group 及其成员",
+    Example: `kongctl consumer-group list
+kongctl consumer-group list --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        groups, err := client.ListConsumerGroups(ctx)
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+        type row struct {
+            kong.ConsumerGroup
+            Members []string `json:"members"`
+        }
+        rows := make([]row, 0, len(groups))
+        for _, g := range groups {
+            members, err := groupMemberNames(ctx, client, g.ID)
+            if err != nil {
+                return err
+            }
+            rows = append(rows, row{ConsumerGroup: g, Members: members})
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(rows, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(rows) == 0 {
+            PrintInfo(cmd, "未找到 Consumer Group（Kong Enterprise 或 OSS 3.4+ 才支持）")
+            return nil
+        }
+        cmd.Printf("%-20s %-36s %-16s %s\n", "NAME", "ID", "TAGS", "MEMBERS")
+        for _, r := range rows {
+            cmd.Printf("%-20s %-36s %-16s %s\n", r.Name, r.ID, strings.Join(r.Tags, ","), strings.Join(r.Members, ","))
+        }
+        return nil
+    },
+}
+
+var consumerGroupAddMemberCmd = &cobra.Command{
+    Use:   "add-member",
+    Short: "将 Consumer 加入 Consumer Group（已是成员时跳过）",
+    Example: `kongctl consumer-group add-member --group gold --consumer partner-a,partner-b`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, g, err := groupSetup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        members, err := groupMemberNames(ctx, client, g.ID)
+        if err != nil {
+            return err
+        }
+        for _, c := range groupConsumers {
+            cur, ok, err := client.GetConsumer(ctx, c)
+            if err != nil {
+                return err
+            } else if !ok {
+                return withCode("not_found", "先使用 kongctl consumer sync 创建", fmt.Errorf("Consumer 不存在：%s", c))
+            }
+            if sliceContains(members, cur.Username) || sliceContains(members, cur.ID) {
+                PrintInfo(cmd, "Consumer %s 已在分组 %s 中", c, groupName)
+                continue
+            }
+            if dryRun {
+                PrintInfo(cmd, "[dry-run] 将把 Consumer %s 加入分组 %s", c, groupName)
+                continue
+            }
+            if err := client.AddConsumerGroupMember(ctx, g.ID, cur.ID); err != nil {
+                return err
+            }
+            PrintSuccess(cmd, "已将 Consumer %s 加入分组 %s", c, groupName)
+        }
+        return nil
+    },
+}
+
+var consumerGroupRemoveMemberCmd = &cobra.Command{
+    Use:   "remove-member",
+    Short: "将 Consumer 移出 Consumer Group（不在组内时跳过）",
+    Example: `kongctl consumer-group remove-member --group gold --consumer partner-a`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, g, err := groupSetup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        members, err := groupMemberNames(ctx, client, g.ID)
+        if err != nil {
+            return err
+        }
+        for _, c := range groupConsumers {
+            cur, ok, err := client.GetConsumer(ctx, c)
+            if err != nil {
+                return err
+            }
+            if !ok || !(sliceContains(members, cur.Username) || sliceContains(members, cur.ID)) {
+                PrintInfo(cmd, "Consumer %s 不在分组 %s 中，无需移出", c, groupName)
+                continue
+            }
+            if dryRun {
+                PrintInfo(cmd, "[dry-run] 将把 Consumer %s 移出分组 %s", c, groupName)
+                continue
+            }
+            if err := client.RemoveConsumerGroupMember(ctx, g.ID, cur.ID); err != nil {
+                return err
+            }
+            PrintSuccess(cmd, "已将 Consumer %s 移出分组 %s", c, groupName)
+        }
+        return nil
+    },
+}
+
+var consumerGroupDeleteCmd = &cobra.Command{
+    Use:   "delete <name>",
+    Short: "删除 Consumer Group（组上的插件与成员关系一并删除，Consumer 保留）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl consumer-group delete gold`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        g, ok, err := client.GetConsumerGroup(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Consumer Group 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Consumer Group：%s（含组上的插件与成员关系）", args[0])
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("consumer-group delete %s（含组上的插件与成员关系）", args[0])); err != nil {
+            return err
+        }
+        if err := client.DeleteConsumerGroup(ctx, g.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Consumer Group：%s", args[0])
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(consumerGroupCmd)
+    consumerGroupCmd.AddCommand(consumerGroupSyncCmd, consumerGroupListCmd, consumerGroupAddMemberCmd, consumerGroupRemoveMemberCmd, consumerGroupDeleteCmd)
+    consumerGroupSyncCmd.Flags().StringVar(&groupName, "name", "", "Consumer Group 名称，例：--name gold")
+    consumerGroupSyncCmd.Flags().StringSliceVar(&groupTags, "tags", nil, "分组标签（覆盖现有标签）")
+    consumerGroupSyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    for _, c := range []*cobra.Command{consumerGroupAddMemberCmd, consumerGroupRemoveMemberCmd} {
+        c.Flags().StringVar(&groupName, "group", "", "Consumer Group 名称，例：--group gold")
+        c.Flags().StringSliceVar(&groupConsumers, "consumer", nil, "Consumer 用户名或 ID（可重复或逗号分隔）")
+    }
+    for _, c := range []*cobra.Command{consumerGroupSyncCmd, consumerGroupAddMemberCmd, consumerGroupRemoveMemberCmd, consumerGroupDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
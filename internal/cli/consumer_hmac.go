@@ -0,0 +1,285 @@
+package cli
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// hmacCredential 为 Kong hmac-auth 凭证的类型名
+const hmacCredential = "hmac-auth"
+
+var (
+    hmacConsumer  string
+    hmacUsername  string
+    hmacSecret    string
+    hmacSecretOut string
+)
+
+var consumerHMACCmd = &cobra.Command{
+    Use:   "hmac",
+    Short: "管理 Consumer 的 hmac-auth 凭证（添加/列出/轮换/删除）",
+    Long: `hmac-auth 凭证以 username 标识（请求 Authorization 头中的 username），secret 为签名密钥。
+未提供 --secret 时在本地生成 32 字节的随机密钥：指定 --secret-out 时写入该文件（权限 0600），否则只在终端显示一次。
+声明式管理请在 apply spec 的 consumers[].credentials.hmac-auth 中声明。`,
+}
+
+// randomSecret 生成 32 字节的随机密钥（base64url 编码）
+func randomSecret() (string, error) {
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
+        return "", fmt.Errorf("生成密钥失败：%w", err)
+    }
+    return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hmacSecretValue 返回 --secret，未提供时生成随机密钥（generated=true）
+func hmacSecretValue() (secret string, generated bool, err error) {
+    if hmacSecret != "" { return hmacSecret, false, nil }
+    secret, err = randomSecret()
+    return secret, err == nil, err
+}
+
+// deliverHMACSecret 将本地生成的密钥写入 --secret-out，未指定时在终端显示一次
+func deliverHMACSecret(cmd *cobra.Command, secret string) {
+    if hmacSecretOut == "" {
+        PrintWarn(cmd, "hmac secret（仅显示这一次，请妥善保存）：%s", secret)
+        return
+    }
+    PrintInfo(cmd, "hmac secret 已写入 %s", hmacSecretOut)
+}
+
+// writeSecretFile 以 0600 创建密钥文件（已存在时拒绝覆盖）；在写入 Kong 前调用，确保密钥不会丢失
+func writeSecretFile(file, secret string) error {
+    f, err := os.OpenFile(expandPath(file), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+    if errors.Is(err, os.ErrExist) {
+        return fmt.Errorf("密钥文件已存在，拒绝覆盖：%s", file)
+    } else if err != nil {
+        return fmt.Errorf("写入密钥失败：%w", err)
+    }
+    if _, err := f.WriteString(secret + "\n"); err != nil {
+        f.Close()
+        return fmt.Errorf("写入密钥失败：%w", err)
+    }
+    return f.Close()
+}
+
+// findHMACCredential 按 username 查找 Consumer 下的 hmac-auth 凭证
+func findHMACCredential(ctx context.Context, client *kong.Client, consumer, username string) (kong.Credential, error) {
+    creds, err := client.ListCredentials(ctx, consumer, hmacCredential)
+    if err != nil {
+        return nil, err
+    }
+    for _, c := range creds {
+        if c["username"] == username { return c, nil }
+    }
+    return nil, nil
+}
+
+// hmacCommandSetup 校验 --consumer 并确认其存在，返回客户端与上下文
+func hmacCommandSetup(cmd *cobra.Command, needUsername bool) (*kong.Client, context.Context, context.CancelFunc, error) {
+    if hmacConsumer == "" {
+        return nil, nil, nil, fmt.Errorf("必须提供 --consumer")
+    }
+    if needUsername && hmacUsername == "" {
+        return nil, nil, nil, fmt.Errorf("必须提供 --username")
+    }
+    if hmacSecret != "" && hmacSecretOut != "" {
+        return nil, nil, nil, fmt.Errorf("--secret-out 仅用于保存本地生成的密钥，不能与 --secret 同时使用")
+    }
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return nil, nil, nil, err
+    }
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    if _, ok, err := client.GetConsumer(ctx, hmacConsumer); err != nil {
+        cancel()
+        return nil, nil, nil, err
+    } else if !ok {
+        cancel()
+        return nil, nil, nil, withCode("not_found", "先使用 kongctl consumer sync 创建", fmt.Errorf("Consumer 不存在：%s", hmacConsumer))
+    }
+    return client, ctx, cancel, nil
+}
+
+var consumerHMACAddCmd = &cobra.Command{
+    Use:   "add",
+    Short: "为 Consumer 添加 hmac-auth 凭证",
+    Example: `# 生成随机密钥并写入文件
+kongctl consumer hmac add --consumer app1 --username app1-hmac --secret-out app1-hmac.secret
+
+# 使用已有密钥
+kongctl consumer hmac add --consumer app1 --username app1-hmac --secret "$HMAC_SECRET"`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := hmacCommandSetup(cmd, true)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        cur, err := findHMACCredential(ctx, client, hmacConsumer, hmacUsername)
+        if err != nil {
+            return err
+        }
+        if cur != nil {
+            return fmt.Errorf("Consumer %s 已有 username=%s 的 hmac-auth 凭证（更换密钥请使用 kongctl consumer hmac rotate）", hmacConsumer, hmacUsername)
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将为 Consumer %s 添加 hmac-auth 凭证：username=%s", hmacConsumer, hmacUsername)
+            return nil
+        }
+        secret, generated, err := hmacSecretValue()
+        if err != nil {
+            return err
+        }
+        if generated && hmacSecretOut != "" {
+            if err := writeSecretFile(hmacSecretOut, secret); err != nil {
+                return err
+            }
+        }
+        if _, err := client.CreateCredential(ctx, hmacConsumer, hmacCredential, kong.Credential{"username": hmacUsername, "secret": secret}); err != nil {
+            if generated && hmacSecretOut != "" { _ = os.Remove(expandPath(hmacSecretOut)) }
+            return err
+        }
+        PrintSuccess(cmd, "已为 Consumer %s 添加 hmac-auth 凭证：username=%s", hmacConsumer, hmacUsername)
+        if generated { deliverHMACSecret(cmd, secret) }
+        return nil
+    },
+}
+
+var consumerHMACListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Consumer 的 hmac-auth 凭证（不显示 secret）",
+    Example: `kongctl consumer hmac list --consumer app1`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := hmacCommandSetup(cmd, false)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        creds, err := client.ListCredentials(ctx, hmacConsumer, hmacCredential)
+        if err != nil {
+            return err
+        }
+        for _, c := range creds { delete(c, "secret") }
+        if outputJSON() {
+            if creds == nil { creds = []kong.Credential{} }
+            b, _ := json.MarshalIndent(creds, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(creds) == 0 {
+            PrintInfo(cmd, "Consumer %s 没有 hmac-auth 凭证", hmacConsumer)
+            return nil
+        }
+        cmd.Printf("%-28s %-36s %s\n", "USERNAME", "ID", "CREATED")
+        for _, c := range creds {
+            created := ""
+            if ts, ok := c["created_at"].(float64); ok { created = time.Unix(int64(ts), 0).Local().Format("2006-01-02 15:04:05") }
+            cmd.Printf("%-28v %-36v %s\n", c["username"], c["id"], created)
+        }
+        return nil
+    },
+}
+
+var consumerHMACRotateCmd = &cobra.Command{
+    Use:   "rotate",
+    Short: "更换 hmac-auth 凭证的 secret",
+    Long: `更新指定 username 的 hmac-auth 凭证的 secret（--secret 或本地生成的随机密钥），旧密钥立即失效。
+需要不停机轮换时，可先以新的 username 执行 add，客户端切换后再 delete 旧凭证。`,
+    Example: `kongctl consumer hmac rotate --consumer app1 --username app1-hmac --secret-out app1-hmac.secret`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := hmacCommandSetup(cmd, true)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        cur, err := findHMACCredential(ctx, client, hmacConsumer, hmacUsername)
+        if err != nil {
+            return err
+        }
+        if cur == nil {
+            return withCode("not_found", "使用 kongctl consumer hmac list 查看已有凭证", fmt.Errorf("Consumer %s 没有 username=%s 的 hmac-auth 凭证", hmacConsumer, hmacUsername))
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将更换 Consumer %s 的 hmac-auth 凭证 %s 的 secret", hmacConsumer, hmacUsername)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("consumer hmac rotate %s（旧 secret 立即失效）", hmacUsername)); err != nil {
+            return err
+        }
+        secret, generated, err := hmacSecretValue()
+        if err != nil {
+            return err
+        }
+        if generated && hmacSecretOut != "" {
+            if err := writeSecretFile(hmacSecretOut, secret); err != nil {
+                return err
+            }
+        }
+        if _, err := client.UpdateCredential(ctx, hmacConsumer, hmacCredential, fmt.Sprint(cur["id"]), kong.Credential{"secret": secret}); err != nil {
+            if generated && hmacSecretOut != "" { _ = os.Remove(expandPath(hmacSecretOut)) }
+            return err
+        }
+        PrintSuccess(cmd, "已更换 hmac-auth 凭证 %s 的 secret（consumer=%s）", hmacUsername, hmacConsumer)
+        if generated { deliverHMACSecret(cmd, secret) }
+        return nil
+    },
+}
+
+var consumerHMACDeleteCmd = &cobra.Command{
+    Use:   "delete",
+    Short: "删除 Consumer 的 hmac-auth 凭证",
+    Example: `kongctl consumer hmac delete --consumer app1 --username app1-hmac`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := hmacCommandSetup(cmd, true)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        cur, err := findHMACCredential(ctx, client, hmacConsumer, hmacUsername)
+        if err != nil {
+            return err
+        }
+        if cur == nil {
+            PrintInfo(cmd, "Consumer %s 没有 username=%s 的 hmac-auth 凭证，无需删除", hmacConsumer, hmacUsername)
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Consumer %s 的 hmac-auth 凭证：%s", hmacConsumer, hmacUsername)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("consumer hmac delete %s", hmacUsername)); err != nil {
+            return err
+        }
+        if err := client.DeleteCredential(ctx, hmacConsumer, hmacCredential, fmt.Sprint(cur["id"])); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Consumer %s 的 hmac-auth 凭证：%s", hmacConsumer, hmacUsername)
+        return nil
+    },
+}
+
+func init() {
+    consumerCmd.AddCommand(consumerHMACCmd)
+    consumerHMACCmd.AddCommand(consumerHMACAddCmd, consumerHMACListCmd, consumerHMACRotateCmd, consumerHMACDeleteCmd)
+    for _, c := range []*cobra.Command{consumerHMACAddCmd, consumerHMACListCmd, consumerHMACRotateCmd, consumerHMACDeleteCmd} {
+        c.Flags().StringVar(&hmacConsumer, "consumer", "", "Consumer 用户名或 ID，例：--consumer app1")
+    }
+    for _, c := range []*cobra.Command{consumerHMACAddCmd, consumerHMACRotateCmd, consumerHMACDeleteCmd} {
+        c.Flags().StringVar(&hmacUsername, "username", "", "凭证的 username（签名请求中的 username）")
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+    for _, c := range []*cobra.Command{consumerHMACAddCmd, consumerHMACRotateCmd} {
+        c.Flags().StringVar(&hmacSecret, "secret", "", "签名密钥，默认在本地生成随机密钥")
+        c.Flags().StringVar(&hmacSecretOut, "secret-out", "", "将本地生成的密钥写入该文件（0600），未指定时在终端显示一次")
+    }
+}
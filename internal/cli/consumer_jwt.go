@@ -0,0 +1,194 @@
+package cli
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/x509"
+    "encoding/pem"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    jwtConsumer        string
+    jwtAlgorithm       string
+    jwtKey             string
+    jwtSecret          string
+    jwtPublicKey       string
+    jwtGenerateKeypair bool
+    jwtPrivateKeyOut   string
+    jwtKeyBits         int
+)
+
+// jwtAlgorithms 为 Kong jwt 插件支持、kongctl 可登记的签名算法
+var jwtAlgorithms = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512"}
+
+var consumerJWTCmd = &cobra.Command{
+    Use:   "jwt",
+    Short: "管理 Consumer 的 JWT 凭证",
+}
+
+var consumerJWTAddCmd = &cobra.Command{
+    Use:   "add",
+    Short: "为 Consumer 登记 JWT 凭证（RS* 可生成 RSA 密钥对）",
+    Long: `在 Consumer 下创建 jwt 凭证。--key 为 JWT 中 iss（或插件 key_claim_name 指定的声明）的取值，未提供时由 Kong 生成。
+HS256/384/512 使用共享密钥 --secret（未提供时由 Kong 生成，可在 Kong Manager 中查看）。
+RS256/384/512 登记 RSA 公钥：--public-key 指定已有的 PEM 文件，或 --generate-keypair 在本地生成密钥对，
+仅将公钥写入 Kong，私钥（PKCS#8 PEM，权限 0600）写入 --private-key-out 交给客户端团队签发令牌；私钥不会上传，丢失后需重新登记。`,
+    Example: `# 生成 RSA 密钥对并登记公钥，私钥写入 app1-jwt.pem
+kongctl consumer jwt add --consumer app1 --algorithm RS256 --generate-keypair --key app1-issuer
+
+# 登记已有公钥
+kongctl consumer jwt add --consumer app1 --algorithm RS256 --public-key ./app1.pub.pem
+
+# HS256 共享密钥
+kongctl consumer jwt add --consumer app1 --key app1 --secret "$JWT_SECRET"`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if jwtConsumer == "" {
+            return fmt.Errorf("必须提供 --consumer")
+        }
+        alg := strings.ToUpper(jwtAlgorithm)
+        if !sliceContains(jwtAlgorithms, alg) {
+            return fmt.Errorf("--algorithm 不支持 %s（可选：%s）", jwtAlgorithm, strings.Join(jwtAlgorithms, "/"))
+        }
+        rsaAlg := strings.HasPrefix(alg, "RS")
+        switch {
+        case !rsaAlg && (jwtGenerateKeypair || jwtPublicKey != ""):
+            return fmt.Errorf("--generate-keypair/--public-key 仅适用于 RS256/RS384/RS512，%s 请使用 --secret", alg)
+        case rsaAlg && jwtSecret != "":
+            return fmt.Errorf("%s 使用 RSA 公钥验签，不需要 --secret", alg)
+        case rsaAlg && jwtGenerateKeypair == (jwtPublicKey != ""):
+            return fmt.Errorf("%s 需要 --public-key 或 --generate-keypair（二选一）", alg)
+        case jwtPrivateKeyOut != "" && !jwtGenerateKeypair:
+            return fmt.Errorf("--private-key-out 需配合 --generate-keypair 使用")
+        case jwtGenerateKeypair && jwtKeyBits < 2048:
+            return fmt.Errorf("--bits 不能小于 2048：%d", jwtKeyBits)
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        if _, ok, err := client.GetConsumer(ctx, jwtConsumer); err != nil {
+            return err
+        } else if !ok {
+            return withCode("not_found", "先使用 kongctl consumer sync 创建", fmt.Errorf("Consumer 不存在：%s", jwtConsumer))
+        }
+        if jwtKey != "" {
+            creds, err := client.ListCredentials(ctx, jwtConsumer, "jwt")
+            if err != nil {
+                return err
+            }
+            for _, c := range creds {
+                if c["key"] == jwtKey {
+                    return fmt.Errorf("Consumer %s 已有 key=%s 的 JWT 凭证（如需轮换请换用新的 --key）", jwtConsumer, jwtKey)
+                }
+            }
+        }
+        out := jwtPrivateKeyOut
+        if jwtGenerateKeypair && out == "" { out = jwtConsumer + "-jwt.pem" }
+        if dryRun {
+            msg := fmt.Sprintf("[dry-run] 将为 Consumer %s 登记 %s JWT 凭证", jwtConsumer, alg)
+            if jwtKey != "" { msg += "（key=" + jwtKey + "）" }
+            if jwtGenerateKeypair { msg += fmt.Sprintf("，生成 %d 位 RSA 密钥对，私钥写入 %s", jwtKeyBits, out) }
+            PrintInfo(cmd, "%s", msg)
+            return nil
+        }
+
+        cred := kong.Credential{"algorithm": alg}
+        if jwtKey != "" { cred["key"] = jwtKey }
+        if jwtSecret != "" { cred["secret"] = jwtSecret }
+        switch {
+        case jwtPublicKey != "":
+            pub, err := readJWTPublicKey(jwtPublicKey)
+            if err != nil {
+                return err
+            }
+            cred["rsa_public_key"] = pub
+        case jwtGenerateKeypair:
+            pub, err := generateJWTKeypair(out, jwtKeyBits)
+            if err != nil {
+                return err
+            }
+            cred["rsa_public_key"] = pub
+        }
+        created, err := client.CreateCredential(ctx, jwtConsumer, "jwt", cred)
+        if err != nil {
+            // 公钥未登记时私钥没有用处，删除以免被误交付
+            if jwtGenerateKeypair { _ = os.Remove(expandPath(out)) }
+            return err
+        }
+        PrintSuccess(cmd, "已为 Consumer %s 登记 %s JWT 凭证：key=%v", jwtConsumer, alg, created["key"])
+        if jwtGenerateKeypair {
+            PrintInfo(cmd, "私钥已写入 %s（仅保存在本地，请通过安全渠道交给客户端团队；令牌的 iss 需为 %v）", out, created["key"])
+        }
+        return nil
+    },
+}
+
+// readJWTPublicKey 读取 PEM 格式的 RSA 公钥（PUBLIC KEY 或 RSA PUBLIC KEY）
+func readJWTPublicKey(file string) (string, error) {
+    data, err := os.ReadFile(expandPath(file))
+    if err != nil {
+        return "", fmt.Errorf("读取公钥失败：%w", err)
+    }
+    block, _ := pem.Decode(data)
+    if block == nil || !strings.HasSuffix(block.Type, "PUBLIC KEY") {
+        return "", fmt.Errorf("%s 不是 PEM 格式的公钥", file)
+    }
+    return string(pem.EncodeToMemory(block)), nil
+}
+
+// generateJWTKeypair 生成 RSA 密钥对：私钥以 PKCS#8 PEM（0600）写入 file（已存在时拒绝覆盖），返回 PEM 格式的公钥
+func generateJWTKeypair(file string, bits int) (string, error) {
+    key, err := rsa.GenerateKey(rand.Reader, bits)
+    if err != nil {
+        return "", fmt.Errorf("生成 RSA 密钥失败：%w", err)
+    }
+    priv, err := x509.MarshalPKCS8PrivateKey(key)
+    if err != nil {
+        return "", err
+    }
+    pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+    if err != nil {
+        return "", err
+    }
+    f, err := os.OpenFile(expandPath(file), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+    if errors.Is(err, os.ErrExist) {
+        return "", fmt.Errorf("私钥文件已存在，拒绝覆盖：%s（使用 --private-key-out 指定其他路径）", file)
+    } else if err != nil {
+        return "", fmt.Errorf("写入私钥失败：%w", err)
+    }
+    if err := pem.Encode(f, &pem.Block{Type: "PRIVATE KEY", Bytes: priv}); err != nil {
+        f.Close()
+        return "", fmt.Errorf("写入私钥失败：%w", err)
+    }
+    if err := f.Close(); err != nil {
+        return "", fmt.Errorf("写入私钥失败：%w", err)
+    }
+    return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})), nil
+}
+
+func init() {
+    consumerCmd.AddCommand(consumerJWTCmd)
+    consumerJWTCmd.AddCommand(consumerJWTAddCmd)
+    consumerJWTAddCmd.Flags().StringVar(&jwtConsumer, "consumer", "", "Consumer 用户名或 ID，例：--consumer app1")
+    consumerJWTAddCmd.Flags().StringVar(&jwtAlgorithm, "algorithm", "HS256", "签名算法："+strings.Join(jwtAlgorithms, "/"))
+    consumerJWTAddCmd.Flags().StringVar(&jwtKey, "key", "", "凭证的 key（令牌 iss 声明的取值），默认由 Kong 生成")
+    consumerJWTAddCmd.Flags().StringVar(&jwtSecret, "secret", "", "HS* 的共享密钥，默认由 Kong 生成")
+    consumerJWTAddCmd.Flags().StringVar(&jwtPublicKey, "public-key", "", "RS* 使用的已有 RSA 公钥（PEM 文件）")
+    consumerJWTAddCmd.Flags().BoolVar(&jwtGenerateKeypair, "generate-keypair", false, "在本地生成 RSA 密钥对，仅登记公钥")
+    consumerJWTAddCmd.Flags().StringVar(&jwtPrivateKeyOut, "private-key-out", "", "生成的私钥写入的文件（默认 <consumer>-jwt.pem）")
+    consumerJWTAddCmd.Flags().IntVar(&jwtKeyBits, "bits", 2048, "生成的 RSA 密钥长度")
+    consumerJWTAddCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+}
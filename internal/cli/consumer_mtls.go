@@ -0,0 +1,233 @@
+package cli
+
+import (
+    "context"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/hex"
+    "encoding/json"
+    "encoding/pem"
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// mtlsCredential 为 Kong mtls-auth 凭证的类型名
+const mtlsCredential = "mtls-auth"
+
+var (
+    mtlsConsumer string
+    mtlsSubject  string
+    mtlsCertFile string
+    mtlsCACert   string
+)
+
+var consumerMTLSCmd = &cobra.Command{
+    Use:   "mtls",
+    Short: "管理 Consumer 的 mtls-auth 凭证（客户端证书到 Consumer 的映射）",
+    Long: `mtls-auth 凭证以 subject_name 标识：客户端证书的 CN 或 SAN（DNS/邮箱/URI）与之相同时，请求被识别为该 Consumer。
+--cert 从客户端证书（PEM）中读取 CN（无 CN 时取第一个 SAN），并显示证书的 SHA-256 指纹便于核对；
+--ca-certificate 限定只接受该 CA 证书（/ca_certificates 中的 ID）签发的证书。
+声明式管理请在 apply spec 的 consumers[].credentials.mtls-auth 中声明 subject_name（以及可选的 ca_certificate）。`,
+}
+
+// certSubjectName 读取 PEM 格式的客户端证书，返回 mtls-auth 匹配用的名称（CN，无 CN 时为第一个 SAN）与 SHA-256 指纹
+func certSubjectName(file string) (name, fingerprint string, err error) {
+    data, err := os.ReadFile(expandPath(file))
+    if err != nil {
+        return "", "", fmt.Errorf("读取证书失败：%w", err)
+    }
+    block, _ := pem.Decode(data)
+    if block == nil || block.Type != "CERTIFICATE" {
+        return "", "", fmt.Errorf("%s 不是 PEM 格式的证书", file)
+    }
+    cert, err := x509.ParseCertificate(block.Bytes)
+    if err != nil {
+        return "", "", fmt.Errorf("解析证书失败：%w", err)
+    }
+    sum := sha256.Sum256(cert.Raw)
+    fp := strings.ToUpper(hex.EncodeToString(sum[:]))
+    var pairs []string
+    for i := 0; i < len(fp); i += 2 { pairs = append(pairs, fp[i:i+2]) }
+    fingerprint = strings.Join(pairs, ":")
+    name = cert.Subject.CommonName
+    switch {
+    case name != "":
+    case len(cert.DNSNames) > 0:
+        name = cert.DNSNames[0]
+    case len(cert.EmailAddresses) > 0:
+        name = cert.EmailAddresses[0]
+    case len(cert.URIs) > 0:
+        name = cert.URIs[0].String()
+    default:
+        return "", "", fmt.Errorf("证书 %s 没有 CN 或 SAN，无法用于 mtls-auth", file)
+    }
+    return name, fingerprint, nil
+}
+
+// findMTLSCredential 按 subject_name 查找 Consumer 下的 mtls-auth 凭证
+func findMTLSCredential(ctx context.Context, client *kong.Client, consumer, subject string) (kong.Credential, error) {
+    creds, err := client.ListCredentials(ctx, consumer, mtlsCredential)
+    if err != nil {
+        return nil, err
+    }
+    for _, c := range creds {
+        if c["subject_name"] == subject { return c, nil }
+    }
+    return nil, nil
+}
+
+// mtlsSetup 确定 subject_name（--subject-name 或 --cert）、校验 --consumer 并确认其存在，返回客户端与上下文
+func mtlsSetup(cmd *cobra.Command, needSubject bool) (*kong.Client, context.Context, context.CancelFunc, error) {
+    if mtlsConsumer == "" {
+        return nil, nil, nil, fmt.Errorf("必须提供 --consumer")
+    }
+    if mtlsSubject != "" && mtlsCertFile != "" {
+        return nil, nil, nil, fmt.Errorf("--subject-name 与 --cert 只能指定一个")
+    }
+    if mtlsCertFile != "" {
+        name, fp, err := certSubjectName(mtlsCertFile)
+        if err != nil {
+            return nil, nil, nil, err
+        }
+        mtlsSubject = name
+        PrintInfo(cmd, "证书 %s：subject_name=%s，SHA-256 指纹 %s", mtlsCertFile, name, fp)
+    }
+    if needSubject && mtlsSubject == "" {
+        return nil, nil, nil, fmt.Errorf("必须提供 --subject-name 或 --cert")
+    }
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return nil, nil, nil, err
+    }
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    if _, ok, err := client.GetConsumer(ctx, mtlsConsumer); err != nil {
+        cancel()
+        return nil, nil, nil, err
+    } else if !ok {
+        cancel()
+        return nil, nil, nil, withCode("not_found", "先使用 kongctl consumer sync 创建", fmt.Errorf("Consumer 不存在：%s", mtlsConsumer))
+    }
+    return client, ctx, cancel, nil
+}
+
+var consumerMTLSAddCmd = &cobra.Command{
+    Use:   "add",
+    Short: "将客户端证书的 subject name 映射到 Consumer",
+    Example: `# 从客户端证书读取 CN
+kongctl consumer mtls add --consumer app1 --cert ./app1-client.pem
+
+# 直接指定，并限定签发的 CA
+kongctl consumer mtls add --consumer app1 --subject-name app1.internal.example.com --ca-certificate 4e3ad2e4-0bc4-4638-8e34-c84a417ba39b`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := mtlsSetup(cmd, true)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        cur, err := findMTLSCredential(ctx, client, mtlsConsumer, mtlsSubject)
+        if err != nil {
+            return err
+        }
+        if cur != nil {
+            return fmt.Errorf("Consumer %s 已有 subject_name=%s 的 mtls-auth 凭证", mtlsConsumer, mtlsSubject)
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将为 Consumer %s 添加 mtls-auth 凭证：subject_name=%s", mtlsConsumer, mtlsSubject)
+            return nil
+        }
+        cred := kong.Credential{"subject_name": mtlsSubject}
+        if mtlsCACert != "" { cred["ca_certificate"] = map[string]any{"id": mtlsCACert} }
+        if _, err := client.CreateCredential(ctx, mtlsConsumer, mtlsCredential, cred); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已为 Consumer %s 添加 mtls-auth 凭证：subject_name=%s", mtlsConsumer, mtlsSubject)
+        return nil
+    },
+}
+
+var consumerMTLSListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Consumer 的 mtls-auth 凭证",
+    Example: `kongctl consumer mtls list --consumer app1`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := mtlsSetup(cmd, false)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        creds, err := client.ListCredentials(ctx, mtlsConsumer, mtlsCredential)
+        if err != nil {
+            return err
+        }
+        if outputJSON() {
+            if creds == nil { creds = []kong.Credential{} }
+            b, _ := json.MarshalIndent(creds, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(creds) == 0 {
+            PrintInfo(cmd, "Consumer %s 没有 mtls-auth 凭证", mtlsConsumer)
+            return nil
+        }
+        cmd.Printf("%-40s %-36s %s\n", "SUBJECT_NAME", "CA_CERTIFICATE", "ID")
+        for _, c := range creds {
+            ca := "-"
+            if ref, ok := c["ca_certificate"].(map[string]any); ok && ref["id"] != nil { ca = fmt.Sprint(ref["id"]) }
+            cmd.Printf("%-40v %-36s %v\n", c["subject_name"], ca, c["id"])
+        }
+        return nil
+    },
+}
+
+var consumerMTLSDeleteCmd = &cobra.Command{
+    Use:   "delete",
+    Short: "删除 Consumer 的 mtls-auth 凭证",
+    Example: `kongctl consumer mtls delete --consumer app1 --subject-name app1.internal.example.com`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := mtlsSetup(cmd, true)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        cur, err := findMTLSCredential(ctx, client, mtlsConsumer, mtlsSubject)
+        if err != nil {
+            return err
+        }
+        if cur == nil {
+            PrintInfo(cmd, "Consumer %s 没有 subject_name=%s 的 mtls-auth 凭证，无需删除", mtlsConsumer, mtlsSubject)
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Consumer %s 的 mtls-auth 凭证：%s", mtlsConsumer, mtlsSubject)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("consumer mtls delete %s", mtlsSubject)); err != nil {
+            return err
+        }
+        if err := client.DeleteCredential(ctx, mtlsConsumer, mtlsCredential, fmt.Sprint(cur["id"])); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Consumer %s 的 mtls-auth 凭证：%s", mtlsConsumer, mtlsSubject)
+        return nil
+    },
+}
+
+func init() {
+    consumerCmd.AddCommand(consumerMTLSCmd)
+    consumerMTLSCmd.AddCommand(consumerMTLSAddCmd, consumerMTLSListCmd, consumerMTLSDeleteCmd)
+    for _, c := range []*cobra.Command{consumerMTLSAddCmd, consumerMTLSListCmd, consumerMTLSDeleteCmd} {
+        c.Flags().StringVar(&mtlsConsumer, "consumer", "", "Consumer 用户名或 ID，例：--consumer app1")
+    }
+    for _, c := range []*cobra.Command{consumerMTLSAddCmd, consumerMTLSDeleteCmd} {
+        c.Flags().StringVar(&mtlsSubject, "subject-name", "", "客户端证书的 CN 或 SAN")
+        c.Flags().StringVar(&mtlsCertFile, "cert", "", "从该客户端证书（PEM）读取 subject name")
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+    consumerMTLSAddCmd.Flags().StringVar(&mtlsCACert, "ca-certificate", "", "只接受该 CA 证书（/ca_certificates 中的 ID）签发的客户端证书")
+}
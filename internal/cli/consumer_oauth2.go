@@ -0,0 +1,238 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/url"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// oauth2Credential 为 Kong oauth2 应用凭证的类型名
+const oauth2Credential = "oauth2"
+
+var (
+    oauthConsumer     string
+    oauthName         string
+    oauthClientID     string
+    oauthSecret       string
+    oauthSecretEnv    string
+    oauthSecretOut    string
+    oauthRedirectURIs []string
+    oauthHashSecret   bool
+)
+
+var consumerOAuth2Cmd = &cobra.Command{
+    Use:   "oauth2",
+    Short: "管理 Consumer 的 oauth2 应用凭证（添加/列出/删除）",
+    Long: `oauth2 凭证以 client_id 标识，对应在 Kong 上登记的一个 OAuth 2.0 客户端应用。
+client_secret 可通过 --client-secret-env 从环境变量读取，避免出现在命令历史中；都未提供时在本地生成随机密钥，
+写入 --secret-out（0600）或只在终端显示一次。声明式管理请在 apply spec 的 consumers[].credentials.oauth2 中声明
+（client_secret_env 同样从环境变量读取）。`,
+}
+
+// oauth2Secret 按 --client-secret、--client-secret-env 的顺序确定 client_secret，都未提供时在本地生成（generated=true）
+func oauth2Secret() (secret string, generated bool, err error) {
+    switch {
+    case oauthSecret != "":
+        return oauthSecret, false, nil
+    case oauthSecretEnv != "":
+        v, ok := os.LookupEnv(oauthSecretEnv)
+        if !ok || v == "" { return "", false, fmt.Errorf("--client-secret-env 引用的环境变量 %s 未设置", oauthSecretEnv) }
+        return v, false, nil
+    }
+    secret, err = randomSecret()
+    return secret, err == nil, err
+}
+
+// oauth2Setup 校验 --consumer 并确认其存在，返回客户端与上下文
+func oauth2Setup(cmd *cobra.Command) (*kong.Client, context.Context, context.CancelFunc, error) {
+    if oauthConsumer == "" {
+        return nil, nil, nil, fmt.Errorf("必须提供 --consumer")
+    }
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return nil, nil, nil, err
+    }
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    if _, ok, err := client.GetConsumer(ctx, oauthConsumer); err != nil {
+        cancel()
+        return nil, nil, nil, err
+    } else if !ok {
+        cancel()
+        return nil, nil, nil, withCode("not_found", "先使用 kongctl consumer sync 创建", fmt.Errorf("Consumer 不存在：%s", oauthConsumer))
+    }
+    return client, ctx, cancel, nil
+}
+
+var consumerOAuth2AddCmd = &cobra.Command{
+    Use:   "add",
+    Short: "为 Consumer 登记 oauth2 应用凭证",
+    Example: `# client_secret 从环境变量读取
+kongctl consumer oauth2 add --consumer portal --name portal-web --client-id portal-web \
+  --client-secret-env PORTAL_SECRET --redirect-uri https://portal.example.com/callback
+
+# 由 kongctl 生成 client_secret 并写入文件
+kongctl consumer oauth2 add --consumer portal --name portal-cli --secret-out portal-cli.secret`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if oauthName == "" {
+            return fmt.Errorf("必须提供 --name（应用名称）")
+        }
+        if oauthSecret != "" && oauthSecretEnv != "" {
+            return fmt.Errorf("--client-secret 与 --client-secret-env 只能指定一个")
+        }
+        if oauthSecretOut != "" && (oauthSecret != "" || oauthSecretEnv != "") {
+            return fmt.Errorf("--secret-out 仅用于保存本地生成的密钥，不能与 --client-secret/--client-secret-env 同时使用")
+        }
+        for _, u := range oauthRedirectURIs {
+            if p, err := url.Parse(u); err != nil || p.Scheme == "" || p.Host == "" {
+                return fmt.Errorf("无效的 --redirect-uri（需为完整的 URL）：%s", u)
+            }
+        }
+        client, ctx, cancel, err := oauth2Setup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        if oauthClientID != "" {
+            creds, err := client.ListCredentials(ctx, oauthConsumer, oauth2Credential)
+            if err != nil {
+                return err
+            }
+            for _, c := range creds {
+                if c["client_id"] == oauthClientID {
+                    return fmt.Errorf("Consumer %s 已有 client_id=%s 的 oauth2 凭证", oauthConsumer, oauthClientID)
+                }
+            }
+        }
+        secret, generated, err := oauth2Secret()
+        if err != nil {
+            return err
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将为 Consumer %s 登记 oauth2 应用 %s（redirect_uris=%s）", oauthConsumer, oauthName, strings.Join(oauthRedirectURIs, ","))
+            return nil
+        }
+        if generated && oauthSecretOut != "" {
+            if err := writeSecretFile(oauthSecretOut, secret); err != nil {
+                return err
+            }
+        }
+        cred := kong.Credential{"name": oauthName, "client_secret": secret}
+        if oauthClientID != "" { cred["client_id"] = oauthClientID }
+        if len(oauthRedirectURIs) > 0 { cred["redirect_uris"] = oauthRedirectURIs }
+        if oauthHashSecret { cred["hash_secret"] = true }
+        created, err := client.CreateCredential(ctx, oauthConsumer, oauth2Credential, cred)
+        if err != nil {
+            if generated && oauthSecretOut != "" { _ = os.Remove(expandPath(oauthSecretOut)) }
+            return err
+        }
+        PrintSuccess(cmd, "已为 Consumer %s 登记 oauth2 应用 %s：client_id=%v", oauthConsumer, oauthName, created["client_id"])
+        if generated {
+            if oauthSecretOut == "" {
+                PrintWarn(cmd, "client_secret（仅显示这一次，请妥善保存）：%s", secret)
+            } else {
+                PrintInfo(cmd, "client_secret 已写入 %s", oauthSecretOut)
+            }
+        }
+        return nil
+    },
+}
+
+var consumerOAuth2ListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Consumer 的 oauth2 应用凭证（不显示 client_secret）",
+    Example: `kongctl consumer oauth2 list --consumer portal`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, err := oauth2Setup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        creds, err := client.ListCredentials(ctx, oauthConsumer, oauth2Credential)
+        if err != nil {
+            return err
+        }
+        for _, c := range creds { delete(c, "client_secret") }
+        if outputJSON() {
+            if creds == nil { creds = []kong.Credential{} }
+            b, _ := json.MarshalIndent(creds, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(creds) == 0 {
+            PrintInfo(cmd, "Consumer %s 没有 oauth2 应用凭证", oauthConsumer)
+            return nil
+        }
+        cmd.Printf("%-24s %-36s %s\n", "NAME", "CLIENT_ID", "REDIRECT_URIS")
+        for _, c := range creds {
+            cmd.Printf("%-24v %-36v %s\n", c["name"], c["client_id"], flowValue(c["redirect_uris"]))
+        }
+        return nil
+    },
+}
+
+var consumerOAuth2DeleteCmd = &cobra.Command{
+    Use:   "delete",
+    Short: "删除 Consumer 的 oauth2 应用凭证（其签发的令牌随之失效）",
+    Example: `kongctl consumer oauth2 delete --consumer portal --client-id portal-web`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if oauthClientID == "" {
+            return fmt.Errorf("必须提供 --client-id")
+        }
+        client, ctx, cancel, err := oauth2Setup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        creds, err := client.ListCredentials(ctx, oauthConsumer, oauth2Credential)
+        if err != nil {
+            return err
+        }
+        var cur kong.Credential
+        for _, c := range creds {
+            if c["client_id"] == oauthClientID { cur = c; break }
+        }
+        if cur == nil {
+            PrintInfo(cmd, "Consumer %s 没有 client_id=%s 的 oauth2 凭证，无需删除", oauthConsumer, oauthClientID)
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Consumer %s 的 oauth2 应用 %v（client_id=%s）", oauthConsumer, cur["name"], oauthClientID)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("consumer oauth2 delete %s", oauthClientID)); err != nil {
+            return err
+        }
+        if err := client.DeleteCredential(ctx, oauthConsumer, oauth2Credential, fmt.Sprint(cur["id"])); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Consumer %s 的 oauth2 应用 %v（client_id=%s）", oauthConsumer, cur["name"], oauthClientID)
+        return nil
+    },
+}
+
+func init() {
+    consumerCmd.AddCommand(consumerOAuth2Cmd)
+    consumerOAuth2Cmd.AddCommand(consumerOAuth2AddCmd, consumerOAuth2ListCmd, consumerOAuth2DeleteCmd)
+    for _, c := range []*cobra.Command{consumerOAuth2AddCmd, consumerOAuth2ListCmd, consumerOAuth2DeleteCmd} {
+        c.Flags().StringVar(&oauthConsumer, "consumer", "", "Consumer 用户名或 ID，例：--consumer portal")
+    }
+    for _, c := range []*cobra.Command{consumerOAuth2AddCmd, consumerOAuth2DeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+    consumerOAuth2AddCmd.Flags().StringVar(&oauthClientID, "client-id", "", "client_id，默认由 Kong 生成")
+    consumerOAuth2DeleteCmd.Flags().StringVar(&oauthClientID, "client-id", "", "要删除的应用的 client_id")
+    consumerOAuth2AddCmd.Flags().StringVar(&oauthName, "name", "", "应用名称，例：--name portal-web")
+    consumerOAuth2AddCmd.Flags().StringVar(&oauthSecret, "client-secret", "", "client_secret（建议改用 --client-secret-env）")
+    consumerOAuth2AddCmd.Flags().StringVar(&oauthSecretEnv, "client-secret-env", "", "从该环境变量读取 client_secret，例：--client-secret-env PORTAL_SECRET")
+    consumerOAuth2AddCmd.Flags().StringVar(&oauthSecretOut, "secret-out", "", "将本地生成的 client_secret 写入该文件（0600），未指定时在终端显示一次")
+    consumerOAuth2AddCmd.Flags().StringSliceVar(&oauthRedirectURIs, "redirect-uri", nil, "授权回调地址（可重复或逗号分隔）")
+    consumerOAuth2AddCmd.Flags().BoolVar(&oauthHashSecret, "hash-secret", false, "Kong 仅保存 client_secret 的哈希（Kong 3.x）")
+}
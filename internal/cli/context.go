@@ -0,0 +1,312 @@
+package cli
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    "gopkg.in/yaml.v3"
+)
+
+// 当前调用生效的上下文名称（--context > KONGCTL_CONTEXT > 配置文件 current_context）
+var (
+    activeContext string
+    contextErr    error
+    // fanoutContexts 为 --context a,b 指定的多个上下文（仅 apply/sync 支持，逐个集群执行）
+    fanoutContexts []string
+    // contextBase 为合并任何上下文之前的配置值，切换上下文时据此恢复
+    contextBase map[string]any
+)
+
+// contextKeys 为上下文可覆盖的配置项（viper key -> 全局 flag 名，空表示仅配置文件/环境变量）
+var contextKeys = map[string]string{
+    "admin_url":       "admin-url",
+    "token":           "token",
+    "workspace":       "workspace",
+    "tls_skip_verify": "tls-skip-verify",
+    "token_command":   "",
+    "tags":            "",
+    "select_tags":     "",
+    "proxy_url":       "",
+    "managed_by_tag":  "",
+    "headers":         "",
+    "timeout_header":  "",
+}
+
+// configTags 读取列表型配置（YAML 列表，或环境变量中逗号/空白分隔的字符串）
+func configTags(key string) []string {
+    var out []string
+    for _, v := range viper.GetStringSlice(key) {
+        for _, t := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+            if !sliceContains(out, t) { out = append(out, t) }
+        }
+    }
+    return out
+}
+
+// defaultTags 返回 apply 为创建/更新的资源附加的标签：上下文 tags 与 select_tags 的并集
+func defaultTags() []string {
+    out := configTags("tags")
+    for _, t := range configTags("select_tags") {
+        if !sliceContains(out, t) { out = append(out, t) }
+    }
+    return out
+}
+
+// inTagScope 判断远程资源是否属于当前上下文的 select_tags 范围（需带全部标签；未配置时均属于）
+func inTagScope(tags []string) bool {
+    for _, t := range configTags("select_tags") {
+        if !sliceContains(tags, t) { return false }
+    }
+    return true
+}
+
+// activateContext 将选中上下文的配置合并进 viper。
+// 优先级：flag > env > context > 配置文件顶层字段；仅在 flag 未显式设置且无对应环境变量时覆盖。
+func activateContext() {
+    name := viper.GetString("context")
+    if name == "" {
+        name = viper.GetString("current_context")
+    }
+    activeContext = ""
+    contextErr = nil
+    if contextBase == nil {
+        contextBase = map[string]any{}
+        for key := range contextKeys { contextBase[key] = viper.Get(key) }
+    }
+    if name == "" {
+        return
+    }
+    if strings.Contains(name, ",") {
+        fanoutContexts = nil
+        for _, n := range strings.Split(name, ",") {
+            if n = strings.TrimSpace(n); n != "" && !sliceContains(fanoutContexts, n) { fanoutContexts = append(fanoutContexts, n) }
+        }
+        for _, n := range fanoutContexts {
+            if viper.Sub("contexts."+n) == nil {
+                contextErr = fmt.Errorf("未找到上下文：%s（可用：%s）", n, strings.Join(contextNames(), ", "))
+                return
+            }
+        }
+        return
+    }
+    sub := viper.Sub("contexts." + name)
+    if sub == nil {
+        contextErr = fmt.Errorf("未找到上下文：%s（可用：%s）", name, strings.Join(contextNames(), ", "))
+        return
+    }
+    for key, flag := range contextKeys {
+        if !sub.IsSet(key) {
+            continue
+        }
+        if f := rootCmd.PersistentFlags().Lookup(flag); f != nil && f.Changed {
+            continue
+        }
+        if _, ok := os.LookupEnv("KONGCTL_" + strings.ToUpper(key)); ok {
+            continue
+        }
+        viper.Set(key, sub.Get(key))
+    }
+    activeContext = name
+}
+
+// switchContext 在一次调用中切换到另一个上下文：先恢复未合并上下文时的配置，再合并 name 的配置
+func switchContext(name string) error {
+    for key, v := range contextBase { viper.Set(key, v) }
+    viper.Set("context", name)
+    activateContext()
+    return contextErr
+}
+
+func contextNames() []string {
+    var names []string
+    for k := range viper.GetStringMap("contexts") {
+        names = append(names, k)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// configFilePath 返回当前使用的配置文件路径（未加载时回落到默认路径）
+func configFilePath() (string, error) {
+    if f := viper.ConfigFileUsed(); f != "" {
+        return f, nil
+    }
+    if cfgFile != "" {
+        return cfgFile, nil
+    }
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "config.yaml"), nil
+}
+
+var contextCmd = &cobra.Command{
+    Use:   "context",
+    Short: "管理配置上下文（多集群/多环境切换）",
+    Long: `在配置文件中通过 contexts 定义多个 Kong 环境，并用 current_context 指定默认环境：
+
+current_context: dev
+contexts:
+  dev:
+    admin_url: http://localhost:8001
+  prod:
+    admin_url: https://kong-admin.prod:8444
+    token: <TOKEN>
+    production: true   # 破坏性操作前需输入上下文名称确认
+    workspace: team-a  # 企业版 workspace，请求路径自动加 /team-a 前缀
+    tags: [env:prod]   # apply 创建/更新的资源自动附加的标签
+    select_tags: [team:platform]  # 资源范围：apply 附加这些标签，export 与 --prune 仅处理带全部标签的资源
+
+单次调用可通过全局 --context 临时选择上下文（不修改 current_context）。`,
+    Example: `# 列出上下文
+kongctl context list
+
+# 切换默认上下文
+kongctl context use prod
+
+# 仅本次调用使用 prod
+kongctl apply -f spec.yaml --dry-run --context prod`,
+}
+
+// contextInfo 为 --template 渲染上下文时的数据
+type contextInfo struct {
+    Name       string
+    AdminURL   string
+    Workspace  string
+    Tags       []string
+    SelectTags []string
+    Production bool
+    Current    bool
+}
+
+func describeContext(name string) contextInfo {
+    prefix := "contexts." + name + "."
+    return contextInfo{
+        Name:       name,
+        AdminURL:   viper.GetString(prefix + "admin_url"),
+        Workspace:  viper.GetString(prefix + "workspace"),
+        Tags:       viper.GetStringSlice(prefix + "tags"),
+        SelectTags: viper.GetStringSlice(prefix + "select_tags"),
+        Production: viper.GetBool(prefix + "production"),
+        Current:    name == activeContext,
+    }
+}
+
+var contextListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出配置中的上下文",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        names := contextNames()
+        if outputTemplate != "" {
+            infos := make([]contextInfo, 0, len(names))
+            for _, n := range names { infos = append(infos, describeContext(n)) }
+            return printTemplate(cmd, infos)
+        }
+        if len(names) == 0 {
+            PrintInfo(cmd, "配置中未定义任何上下文（contexts）")
+            return nil
+        }
+        for _, n := range names {
+            mark := " "
+            if n == activeContext { mark = "*" }
+            cmd.Printf("%s %s\t%s\n", mark, n, viper.GetString("contexts."+n+".admin_url"))
+        }
+        return nil
+    },
+}
+
+var contextCurrentCmd = &cobra.Command{
+    Use:   "current",
+    Short: "显示当前生效的上下文",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if outputTemplate != "" {
+            if activeContext == "" { return fmt.Errorf("未启用上下文") }
+            return printTemplate(cmd, describeContext(activeContext))
+        }
+        if activeContext == "" {
+            PrintInfo(cmd, "未启用上下文，使用配置文件顶层字段")
+            return nil
+        }
+        cmd.Println(activeContext)
+        return nil
+    },
+}
+
+var contextUseCmd = &cobra.Command{
+    Use:   "use <name>",
+    Short: "切换默认上下文（写入 current_context）",
+    Args:  cobra.ExactArgs(1),
+    ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return contextNames(), cobra.ShellCompDirectiveNoFileComp
+    },
+    RunE: func(cmd *cobra.Command, args []string) error {
+        name := args[0]
+        if viper.Sub("contexts."+name) == nil {
+            return fmt.Errorf("未找到上下文：%s（可用：%s）", name, strings.Join(contextNames(), ", "))
+        }
+        file, err := configFilePath()
+        if err != nil {
+            return err
+        }
+        if err := setConfigScalar(file, "current_context", name); err != nil {
+            return err
+        }
+        activeContext = name
+        PrintSuccess(cmd, "已切换默认上下文：%s（%s）", name, file)
+        return nil
+    },
+}
+
+// setConfigScalar 在保留注释与其余字段的前提下，设置配置文件顶层的标量字段
+func setConfigScalar(file, key, value string) error {
+    var doc yaml.Node
+    if data, err := os.ReadFile(file); err == nil {
+        if err := yaml.Unmarshal(normalizeText(data), &doc); err != nil {
+            return fmt.Errorf("解析配置文件失败：%w", err)
+        }
+    }
+    if doc.Kind == 0 {
+        doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+    }
+    root := doc.Content[0]
+    found := false
+    for i := 0; i+1 < len(root.Content); i += 2 {
+        if root.Content[i].Value == key {
+            root.Content[i+1].SetString(value)
+            found = true
+            break
+        }
+    }
+    if !found {
+        k := &yaml.Node{}
+        k.SetString(key)
+        v := &yaml.Node{}
+        v.SetString(value)
+        root.Content = append(root.Content, k, v)
+    }
+    var buf bytes.Buffer
+    enc := yaml.NewEncoder(&buf)
+    enc.SetIndent(2)
+    if err := enc.Encode(&doc); err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+        return err
+    }
+    return writeTextFile(file, buf.Bytes(), 0o600)
+}
+
+func init() {
+    contextCmd.AddCommand(contextListCmd)
+    contextCmd.AddCommand(contextCurrentCmd)
+    contextCmd.AddCommand(contextUseCmd)
+    addTemplateFlag(contextListCmd)
+    addTemplateFlag(contextCurrentCmd)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var dataplanesStale time.Duration
+
+// dataplaneRow 为 dataplanes list 的一行：Synced 表示上报的配置哈希与控制面一致，Stale 表示超过 --stale 未上报
+type dataplaneRow struct {
+    kong.DataPlane
+    Synced bool `json:"synced"`
+    Stale  bool `json:"stale"`
+}
+
+var dataplanesCmd = &cobra.Command{
+    Use:   "dataplanes",
+    Short: "查看混合模式下控制面登记的 data plane",
+}
+
+var dataplanesListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 data plane 的版本、最近上报时间与配置同步状态",
+    Long: `读取控制面 /clustering/data-planes，显示各 data plane 的版本、最近上报时间、sync_status 与配置哈希，
+并与控制面 /status 的配置哈希比较（SYNCED）。超过 --stale 未上报的节点标记为 STALE（data plane 默认每 30 秒上报一次）。
+等待变更下发完成请使用 kongctl propagation check --wait。`,
+    Example: `kongctl dataplanes list
+kongctl dataplanes list --stale 2m --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        dps, hybrid, err := client.ListDataPlanes(ctx)
+        if err != nil {
+            return fmt.Errorf("读取 /clustering/data-planes 失败：%w", err)
+        }
+        st, err := client.GetStatus(ctx)
+        if err != nil {
+            return fmt.Errorf("读取控制面 /status 失败：%w", err)
+        }
+        sort.SliceStable(dps, func(i, j int) bool { return dps[i].Hostname < dps[j].Hostname })
+        rows := make([]dataplaneRow, len(dps))
+        for i, dp := range dps {
+            rows[i] = dataplaneRow{DataPlane: dp, Synced: st.ConfigurationHash != "" && dp.ConfigHash == st.ConfigurationHash}
+            rows[i].Stale = dataplanesStale > 0 && dp.LastSeen > 0 && time.Since(time.Unix(dp.LastSeen, 0)) > dataplanesStale
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(rows, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if !hybrid {
+            PrintInfo(cmd, "未发现 data plane（非混合模式）：配置写入后即生效")
+            return nil
+        }
+        if len(rows) == 0 {
+            PrintWarn(cmd, "控制面未登记任何 data plane")
+            return nil
+        }
+        exp := st.ConfigurationHash
+        if exp == "" { exp = "-" }
+        cmd.Printf("控制面配置哈希：%s\n", exp)
+        cmd.Printf("%-28s %-15s %-10s %-12s %-26s %-34s %s\n", "HOSTNAME", "IP", "VERSION", "LAST_SEEN", "SYNC_STATUS", "CONFIG_HASH", "SYNCED")
+        stale, pending := 0, 0
+        for _, r := range rows {
+            seen := "-"
+            if r.LastSeen > 0 { seen = time.Since(time.Unix(r.LastSeen, 0)).Round(time.Second).String() + " ago" }
+            // 先补齐宽度再着色，避免颜色控制符打乱列对齐
+            seen = fmt.Sprintf("%-12s", seen)
+            if r.Stale { seen = colorWarn(seen) + " STALE"; stale++ }
+            ip, status, version, hash := r.IP, r.SyncStatus, r.Version, r.ConfigHash
+            if ip == "" { ip = "-" }
+            if status == "" { status = "-" }
+            if version == "" { version = "-" }
+            if hash == "" { hash = "-" }
+            mark := colorSuccess(glyph("✔", "[OK]"))
+            switch {
+            case st.ConfigurationHash == "":
+                mark = "-"
+            case !r.Synced:
+                mark = colorWarn(glyph("✘", "[PENDING]"))
+                pending++
+            }
+            cmd.Printf("%-28s %-15s %-10s %s %-26s %-34s %s\n", r.Hostname, ip, version, seen, status, hash, mark)
+        }
+        if pending > 0 { PrintWarn(cmd, "%d/%d 个 data plane 的配置哈希与控制面不一致（可使用 kongctl propagation check --wait 等待同步）", pending, len(rows)) }
+        if stale > 0 { PrintWarn(cmd, "%d 个 data plane 超过 %s 未上报，可能已离线", stale, dataplanesStale) }
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(dataplanesCmd)
+    dataplanesCmd.AddCommand(dataplanesListCmd)
+    dataplanesListCmd.Flags().DurationVar(&dataplanesStale, "stale", time.Minute, "超过该时长未上报的节点标记为 STALE（0 表示不检查）")
+}
@@ -0,0 +1,227 @@
+package cli
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+)
+
+var depsFile string
+
+// depNode 为依赖树中的一个资源节点
+type depNode struct {
+    Kind     string
+    Name     string
+    Note     string
+    Children []*depNode
+}
+
+// depGraph 汇总 spec 中的资源及其引用关系（与 apply 的推导规则一致）
+type depGraph struct {
+    upstreams map[string][]applyTarget // 名称 -> 声明的 targets（合并所有来源）
+    services  map[string]string        // 名称 -> upstream 名称（URL 形式为空）
+    svcURL    map[string]string
+    routes    []*depNode
+    owners    map[string][]string // "Kind/Name" -> 声明/派生该资源的来源
+    annotations map[string]string // "Kind/Name" -> 注解（k=v 形式）
+    warnings  []string
+}
+
+func (g *depGraph) own(kind, name, by string) {
+    key := kind + "/" + name
+    g.owners[key] = append(g.owners[key], by)
+}
+
+// routeRef 记录 route 关联的 service（简写时为自动派生的名称）
+type routeRef struct {
+    name, paths, service string
+    auto                 bool
+}
+
+func buildDepGraph(spec applySpec) *depGraph {
+    g := &depGraph{
+        upstreams: map[string][]applyTarget{},
+        services:  map[string]string{},
+        svcURL:    map[string]string{},
+        owners:    map[string][]string{},
+        annotations: map[string]string{},
+    }
+    for _, up := range spec.Upstreams {
+        g.upstreams[up.Name] = append(g.upstreams[up.Name], up.Targets...)
+        g.own("Upstream", up.Name, "upstreams[]")
+    }
+    for _, s := range spec.Services {
+        g.own("Service", s.Name, "services[]")
+        if len(s.Annotations) > 0 { g.annotations["Service/"+s.Name] = formatAnnotations(s.Annotations) }
+        if s.Upstream != "" {
+            g.services[s.Name] = s.Upstream
+            g.upstreams[s.Upstream] = append(g.upstreams[s.Upstream], s.Targets...)
+            if _, declared := g.owners["Upstream/"+s.Upstream]; !declared {
+                g.own("Upstream", s.Upstream, "services["+s.Name+"]")
+            }
+        } else {
+            g.services[s.Name] = ""
+            g.svcURL[s.Name] = s.URL
+        }
+    }
+    var refs []routeRef
+    for _, r := range spec.Routes {
+        name := r.Name
+        if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        if name == "" {
+            g.warnings = append(g.warnings, "存在未命名且缺少 service 的 route，apply 将报错")
+            continue
+        }
+        g.own("Route", name, "routes[]")
+        if len(r.Annotations) > 0 { g.annotations["Route/"+name] = formatAnnotations(r.Annotations) }
+        ref := routeRef{name: name, paths: strings.Join(r.Paths, ","), service: r.Service}
+        if r.Service == "" {
+            svcName, upName := autoBackendNames(r, name)
+            g.own("Service", svcName, "route "+name+"（简写）")
+            g.own("Upstream", upName, "route "+name+"（简写）")
+            g.services[svcName] = upName
+            g.upstreams[upName] = append(g.upstreams[upName], r.Backend.Targets...)
+            ref.service, ref.auto = svcName, true
+        }
+        refs = append(refs, ref)
+    }
+    // 全部来源收集完毕后再构建节点，确保共享 upstream 展示合并后的 targets
+    for _, ref := range refs {
+        rn := &depNode{Kind: "Route", Name: ref.name, Note: joinNotes(ref.paths, g.annotations["Route/"+ref.name])}
+        sn := g.serviceNode(ref.service)
+        if ref.auto { sn.Note = "auto" }
+        sn.Note = joinNotes(sn.Note, g.annotations["Service/"+ref.service])
+        rn.Children = append(rn.Children, sn)
+        g.routes = append(g.routes, rn)
+    }
+    // 多处声明/派生同一资源：简写间共享或与显式定义冲突
+    keys := make([]string, 0, len(g.owners))
+    for k := range g.owners { keys = append(keys, k) }
+    sort.Strings(keys)
+    for _, k := range keys {
+        if by := g.owners[k]; len(by) > 1 {
+            g.warnings = append(g.warnings, fmt.Sprintf("%s 被多处声明/派生：%s", k, strings.Join(by, "；")))
+        }
+    }
+    return g
+}
+
+func joinNotes(notes ...string) string {
+    var out []string
+    for _, n := range notes {
+        if n != "" { out = append(out, n) }
+    }
+    return strings.Join(out, "; ")
+}
+
+func (g *depGraph) serviceNode(name string) *depNode {
+    n := &depNode{Kind: "Service", Name: name}
+    up, ok := g.services[name]
+    switch {
+    case !ok:
+        n.Note = "文件中未定义，需远程已存在"
+    case up != "":
+        n.Children = append(n.Children, g.upstreamNode(up))
+    default:
+        n.Note = "url=" + g.svcURL[name]
+    }
+    return n
+}
+
+func (g *depGraph) upstreamNode(name string) *depNode {
+    n := &depNode{Kind: "Upstream", Name: name}
+    seen := map[string]bool{}
+    for _, t := range g.upstreams[name] {
+        if seen[t.Target] {
+            continue
+        }
+        seen[t.Target] = true
+        n.Children = append(n.Children, &depNode{Kind: "Target", Name: t.Target})
+    }
+    return n
+}
+
+func printDepTree(cmd *cobra.Command, n *depNode, prefix string, last, root bool) {
+    label := n.Kind + " " + n.Name
+    if n.Note != "" { label += " (" + n.Note + ")" }
+    if root {
+        cmd.Println(label)
+    } else {
+        branch := glyph("├─ ", "|- ")
+        if last { branch = glyph("└─ ", "`- ") }
+        cmd.Println(prefix + branch + label)
+        if last { prefix += "   " } else { prefix += glyph("│  ", "|  ") }
+    }
+    for i, c := range n.Children {
+        printDepTree(cmd, c, prefix, i == len(n.Children)-1, false)
+    }
+}
+
+var depsCmd = &cobra.Command{
+    Use:   "deps",
+    Short: "显示 spec 文件的资源依赖树（离线，不访问 Admin API）",
+    Example: `# 查看路由 -> service -> upstream -> targets 的依赖关系
+kongctl deps -f examples/apply.yaml`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if depsFile == "" {
+            return fmt.Errorf("必须通过 -f/--file 指定配置文件")
+        }
+        spec, err := loadApplySpec(cmd, depsFile)
+        if err != nil {
+            return err
+        }
+        // 冲突不影响查看依赖树，仅提示
+        if err := checkSpecConflicts(spec); err != nil {
+            PrintWarn(cmd, "%v", err)
+        }
+        g := buildDepGraph(spec)
+        usedSvc := map[string]bool{}
+        usedUp := map[string]bool{}
+        var mark func(n *depNode)
+        mark = func(n *depNode) {
+            switch n.Kind {
+            case "Service": usedSvc[n.Name] = true
+            case "Upstream": usedUp[n.Name] = true
+            }
+            for _, c := range n.Children { mark(c) }
+        }
+        for _, r := range g.routes {
+            printDepTree(cmd, r, "", true, true)
+            mark(r)
+        }
+        // 未被任何路由引用的资源
+        var orphans []*depNode
+        svcNames := make([]string, 0, len(g.services))
+        for n := range g.services { svcNames = append(svcNames, n) }
+        sort.Strings(svcNames)
+        for _, n := range svcNames {
+            if !usedSvc[n] {
+                sn := g.serviceNode(n)
+                mark(sn)
+                orphans = append(orphans, sn)
+            }
+        }
+        upNames := make([]string, 0, len(g.upstreams))
+        for n := range g.upstreams { upNames = append(upNames, n) }
+        sort.Strings(upNames)
+        for _, n := range upNames {
+            if !usedUp[n] { orphans = append(orphans, g.upstreamNode(n)) }
+        }
+        if len(orphans) > 0 {
+            cmd.Println()
+            PrintInfo(cmd, "未被路由引用的资源：")
+            for _, o := range orphans { printDepTree(cmd, o, "", true, true) }
+        }
+        for _, w := range g.warnings {
+            PrintWarn(cmd, "%s", w)
+        }
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(depsCmd)
+    depsCmd.Flags().StringVarP(&depsFile, "file", "f", "", "配置文件路径（YAML/JSON，- 表示标准输入），例：-f examples/apply.yaml")
+}
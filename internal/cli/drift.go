@@ -0,0 +1,153 @@
+package cli
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// driftEnforce 为 drift --enforce：发现漂移后按 spec 重新同步（等价 kongctl sync）
+var driftEnforce bool
+
+// driftKinds 为计划动作对应的漂移类型：spec 中声明但远程已不存在、远程配置被修改、远程多出未声明的受管资源
+var driftKinds = map[string]string{"create": "missing", "update": "modified", "delete": "extra"}
+
+func driftLabel(kind string) string {
+    switch kind {
+    case "missing":
+        return "已删除"
+    case "modified":
+        return "已修改"
+    }
+    return "未声明"
+}
+
+// driftItem 为 drift --output json 中的单项漂移
+type driftItem struct {
+    Kind   string            `json:"kind"`
+    Name   string            `json:"name"`
+    Drift  string            `json:"drift"` // missing/modified/extra
+    Diff   string            `json:"diff,omitempty"`
+    Fields []aplan.FieldDiff `json:"fields,omitempty"`
+}
+
+// driftItems 从 sync 语义的计划中取出有漂移的资源
+func driftItems(plan aplan.Plan) []driftItem {
+    var out []driftItem
+    for _, it := range plan.Items {
+        kind, ok := driftKinds[it.Action]
+        if !ok { continue }
+        out = append(out, driftItem{Kind: it.Kind, Name: it.Name, Drift: kind, Diff: it.Diff, Fields: it.Fields()})
+    }
+    return out
+}
+
+var driftCmd = &cobra.Command{
+    Use:   "drift",
+    Short: "检查远程配置相对 spec 的漂移（被修改、被删除或多出的受管资源），--enforce 自动恢复",
+    Long: `以 sync 的语义（只看带 managed-by 标签的资源）对比 spec 与网关的当前配置，报告漂移：
+  - 已修改：远程资源的字段与 spec 不一致（如在 Kong Manager 中手工修改）；
+  - 已删除：spec 中声明的资源在远程已不存在；
+  - 未声明：远程带 managed-by 标签、但 spec 中没有的资源。
+drift 只读取、不做变更；加 --enforce 时发现漂移后按 spec 重新同步（等价 kongctl sync），适合定时执行的漂移修复任务。`,
+    Example: `# 报告漂移，存在漂移时以退出码 2 结束
+kongctl drift -f kong.yaml --diff --detailed-exitcode
+
+# 定时任务：发现漂移即恢复为 spec 中的配置
+kongctl drift -f kong.yaml --enforce --force`,
+    RunE: func(cmd *cobra.Command, args []string) (err error) {
+        if driftEnforce && outputJSON() {
+            return withCode("usage", "", fmt.Errorf("--enforce 不能与 --output json 同时使用"))
+        }
+        if applyDetailedExit && driftEnforce {
+            return withCode("usage", "", fmt.Errorf("--detailed-exitcode 不能与 --enforce 同时使用（--enforce 会修复漂移）"))
+        }
+        applyOverwrite, applyPrune, syncScoped = true, true, true
+        spec, err := loadApplyInput(cmd)
+        if err != nil {
+            return err
+        }
+        jsonOut := cmd.OutOrStdout()
+        if outputJSON() {
+            out, restore := silencePlanOutput(cmd)
+            defer restore()
+            jsonOut = out
+        }
+        cfg, err := clientConfig(15 * time.Second)
+        if err != nil {
+            return err
+        }
+        cfg.MaxCalls = budgetMaxCalls
+        client := kong.NewClient(cfg)
+        ctx, cancel := budgetContext(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        start := time.Now()
+        defer func() { err = finishBudget(cmd, ctx, client, start, err) }()
+        if err := client.Prefetch(ctx); err != nil {
+            PrintWarn(cmd, "预取远程状态失败，改为逐项查询：%v", err)
+        }
+        if spec, err = scopeToManaged(cmd, ctx, client, spec); err != nil {
+            return err
+        }
+        // 差异文本不带颜色，便于按字段解析与重定向
+        noColor := viper.GetBool("no_color")
+        viper.Set("no_color", true)
+        plan, err := silentPlan(cmd, ctx, client, spec)
+        viper.Set("no_color", noColor)
+        if err != nil {
+            return err
+        }
+        items := driftItems(plan)
+        if outputJSON() {
+            if items == nil { items = []driftItem{} }
+            enc := json.NewEncoder(jsonOut)
+            enc.SetEscapeHTML(false)
+            enc.SetIndent("", "  ")
+            if err := enc.Encode(map[string]any{"context": activeContext, "admin_url": viper.GetString("admin_url"), "drifted": len(items), "items": items}); err != nil {
+                return err
+            }
+            if applyDetailedExit && len(items) > 0 { exitStatus = exitChanges }
+            return nil
+        }
+        if len(items) == 0 {
+            PrintSuccess(cmd, "未发现漂移：远程配置与 %s 一致", applyFile)
+            return nil
+        }
+        cmd.Println(colorWarn(fmt.Sprintf("%s%s发现 %d 处漂移：", emojiDiff, contextBanner(), len(items))))
+        for _, it := range items {
+            cmd.Printf("  [%s] %s %s\n", driftLabel(it.Drift), it.Kind, it.Name)
+            if !showDiff || it.Diff == "" { continue }
+            for _, line := range strings.Split(strings.TrimRight(it.Diff, "\n"), "\n") {
+                cmd.Printf("    %s\n", line)
+            }
+        }
+        if !driftEnforce {
+            PrintInfo(cmd, "可执行 kongctl drift -f %s --enforce（或 kongctl sync -f %s）恢复为 spec 中的配置", applyFile, applyFile)
+            if applyDetailedExit { exitStatus = exitChanges }
+            return nil
+        }
+        PrintInfo(cmd, "--enforce：按 spec 重新同步以修复漂移")
+        dryRun = false
+        if err := applyCmd.RunE(cmd, args); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已修复 %d 处漂移", len(items))
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(driftCmd)
+    driftCmd.Flags().StringVarP(&applyFile, "file", "f", "", "spec 文件路径（YAML/JSON，- 表示标准输入；目录表示合并其下的 spec 文件），例：-f kong.yaml")
+    driftCmd.Flags().BoolVar(&showDiff, "diff", false, "展示被修改资源的字段级差异")
+    driftCmd.Flags().BoolVar(&driftEnforce, "enforce", false, "发现漂移后按 spec 重新同步（等价 kongctl sync；production 上下文删除资源需确认或 --force）")
+    driftCmd.Flags().BoolVar(&applyDetailedExit, "detailed-exitcode", false, "存在漂移时以退出码 2 结束（0 无漂移，1 出错）")
+    driftCmd.Flags().StringSliceVar(&applyOverlays, "overlay", nil, "合并环境补丁（overlays/<name>.yaml 或文件路径），例：--overlay prod")
+    addRenderFlags(driftCmd)
+}
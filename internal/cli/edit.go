@@ -0,0 +1,263 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+var (
+    editSelectors    []string
+    editSetHost      string
+    editReplaceHosts []string
+    editAddTags      []string
+    editRemoveTags   []string
+)
+
+// bulkEdit 为一个待修改的资源：Diff 用于预览，Payload 为 PATCH 的字段
+type bulkEdit struct {
+    Kind    string
+    Name    string
+    Key     string // 名称或 id（未命名资源）
+    Diff    string
+    Payload map[string]any
+}
+
+// editTags 按 --add-tag/--remove-tag 计算新的标签列表；无变化时返回 false
+func editTags(cur []string) ([]string, bool) {
+    out := []string{}
+    changed := false
+    for _, t := range cur {
+        if sliceContains(editRemoveTags, t) { changed = true; continue }
+        out = append(out, t)
+    }
+    for _, t := range editAddTags {
+        if !sliceContains(out, t) { out = append(out, t); changed = true }
+    }
+    return out, changed
+}
+
+func sliceContains(list []string, v string) bool {
+    for _, x := range list {
+        if x == v { return true }
+    }
+    return false
+}
+
+// replaceHost 按 --set-host/--replace-host 计算新的 host
+func replaceHost(host string, pairs map[string]string) string {
+    if editSetHost != "" { return editSetHost }
+    if to, ok := pairs[host]; ok { return to }
+    return host
+}
+
+// planRouteEdit 计算单个 route 的修改；无变化时返回 nil
+func planRouteEdit(r kong.Route, pairs map[string]string) *bulkEdit {
+    e := &bulkEdit{Kind: "Route", Name: r.Name, Key: r.Name, Payload: map[string]any{}}
+    if e.Key == "" { e.Key, e.Name = r.ID, r.ID }
+    if editSetHost != "" || len(pairs) > 0 {
+        var hosts []string
+        seen := map[string]bool{}
+        if editSetHost != "" {
+            hosts = []string{editSetHost}
+        } else {
+            for _, h := range r.Hosts {
+                h = replaceHost(h, pairs)
+                if !seen[h] { seen[h] = true; hosts = append(hosts, h) }
+            }
+        }
+        if !sliceSetEqual(r.Hosts, hosts) {
+            e.Payload["hosts"] = hosts
+            e.Diff += diffSlice("hosts", r.Hosts, hosts)
+        }
+    }
+    if tags, changed := editTags(r.Tags); changed {
+        e.Payload["tags"] = tags
+        e.Diff += diffSlice("tags", r.Tags, tags)
+    }
+    if len(e.Payload) == 0 { return nil }
+    return e
+}
+
+// planServiceEdit 计算单个 service 的修改；无变化时返回 nil
+func planServiceEdit(s kong.Service, pairs map[string]string) *bulkEdit {
+    e := &bulkEdit{Kind: "Service", Name: s.Name, Key: s.Name, Payload: map[string]any{}}
+    if e.Key == "" { e.Key, e.Name = s.ID, s.ID }
+    if h := replaceHost(s.Host, pairs); h != s.Host {
+        e.Payload["host"] = h
+        e.Diff += fmt.Sprintf("host: %s -> %s\n", s.Host, h)
+    }
+    if tags, changed := editTags(s.Tags); changed {
+        e.Payload["tags"] = tags
+        e.Diff += diffSlice("tags", s.Tags, tags)
+    }
+    if len(e.Payload) == 0 { return nil }
+    return e
+}
+
+// planBulkEdit 匹配远程 routes/services 并计算修改；selector 未指定 kind 时仅匹配 routes
+func planBulkEdit(ctx context.Context, client *kong.Client, sels []specSelector, pairs map[string]string) (edits []bulkEdit, matched int, err error) {
+    wantKind := func(kind string) bool {
+        for _, s := range sels {
+            if s.Kind == kind || (s.Kind == "" && kind == "route") { return true }
+        }
+        return false
+    }
+    match := func(kind, name string, tags []string) bool {
+        for _, s := range sels {
+            if s.Kind == "" { s.Kind = "route" }
+            if s.matches(kind, name, tags) { return true }
+        }
+        return false
+    }
+    if wantKind("route") {
+        rts, err := client.ListRoutes(ctx)
+        if err != nil { return nil, 0, fmt.Errorf("列出 routes 失败：%w", err) }
+        sort.Slice(rts, func(i, j int) bool { return rts[i].Name < rts[j].Name })
+        for _, r := range rts {
+            if !match("route", r.Name, r.Tags) { continue }
+            matched++
+            if e := planRouteEdit(r, pairs); e != nil { edits = append(edits, *e) }
+        }
+    }
+    if wantKind("service") {
+        svcs, err := client.ListServices(ctx)
+        if err != nil { return nil, 0, fmt.Errorf("列出 services 失败：%w", err) }
+        sort.Slice(svcs, func(i, j int) bool { return svcs[i].Name < svcs[j].Name })
+        for _, s := range svcs {
+            if !match("service", s.Name, s.Tags) { continue }
+            matched++
+            if e := planServiceEdit(s, pairs); e != nil { edits = append(edits, *e) }
+        }
+    }
+    return edits, matched, nil
+}
+
+var editCmd = &cobra.Command{
+    Use:   "edit [<kind> <name|id>]",
+    Short: "在 $EDITOR 中编辑单个资源，或按选择器批量修改 routes/services 的 host 与标签",
+    Long: `kongctl edit <kind> <name|id> 读取单个资源（upstream、service、route、consumer、consumer_group、plugin），以 YAML 在 $VISUAL/$EDITOR 中打开；
+保存退出后由 Kong 的 /schemas/<entity>/validate 校验（有误时带着错误说明重新打开），展示差异并以 PATCH 只下发修改的字段。
+
+不指定资源时按 --selector 匹配远程资源（kind、name 通配、tag/tags 通配；同一条内逗号分隔表示同时满足，多条之间满足任一即可），
+批量修改 host 与标签。未指定 kind 时仅匹配 routes；kind=service 时修改 Service 的 host 字段。
+先以 --dry-run 预览计划；production 上下文执行前需确认。`,
+    Args: cobra.MaximumNArgs(2),
+    Example: `# 在编辑器中修改单个 Service（--dry-run 只展示差异）
+kongctl edit service user-service
+EDITOR="code --wait" kongctl edit route user-list --dry-run
+
+# 预览：将 env:staging 路由的域名统一改为 api.staging.internal
+kongctl edit --selector 'tags=env:staging' --set-host api.staging.internal --dry-run
+
+# 仅替换旧域名，保留路由上的其他 hosts
+kongctl edit --selector 'name=user-*' --replace-host api.old.com=api.new.com
+
+# 为匹配的 service 批量加/删标签
+kongctl edit --selector 'kind=service,tag=team-x' --add-tag owner:team-x --remove-tag legacy`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if len(args) > 0 {
+            return editResource(cmd, args)
+        }
+        if len(editSelectors) == 0 {
+            return withCode("usage", "", fmt.Errorf("必须通过 --selector 指定要修改的资源，例：--selector 'tags=env:staging'"))
+        }
+        if editSetHost == "" && len(editReplaceHosts) == 0 && len(editAddTags) == 0 && len(editRemoveTags) == 0 {
+            return withCode("usage", "", fmt.Errorf("未指定任何修改：可用 --set-host、--replace-host、--add-tag、--remove-tag"))
+        }
+        if editSetHost != "" && len(editReplaceHosts) > 0 {
+            return withCode("usage", "", fmt.Errorf("--set-host 与 --replace-host 不能同时使用"))
+        }
+        sels, err := parseSelectors("--selector", editSelectors)
+        if err != nil {
+            return err
+        }
+        for _, s := range sels {
+            if s.Kind != "" && s.Kind != "route" && s.Kind != "service" {
+                return withCode("usage", "", fmt.Errorf("edit 仅支持 kind=route 或 kind=service：%s", s.Kind))
+            }
+        }
+        pairs := map[string]string{}
+        for _, p := range editReplaceHosts {
+            from, to, ok := strings.Cut(p, "=")
+            if !ok || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+                return withCode("usage", "", fmt.Errorf("--replace-host 格式错误：%q（应为 旧域名=新域名）", p))
+            }
+            pairs[strings.TrimSpace(from)] = strings.TrimSpace(to)
+        }
+
+        cfg, err := clientConfig(30 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        edits, matched, err := planBulkEdit(ctx, client, sels, pairs)
+        if err != nil {
+            return err
+        }
+        if outputJSON() && dryRun {
+            items := make([]aplan.Change, 0, len(edits))
+            for _, e := range edits { items = append(items, aplan.Change{Kind: e.Kind, Name: e.Name, Action: "update", Diff: e.Diff}) }
+            b, _ := json.MarshalIndent(map[string]any{"matched": matched, "items": items}, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if matched == 0 {
+            PrintWarn(cmd, "--selector 未匹配到任何资源：%s", strings.Join(editSelectors, " | "))
+            return nil
+        }
+        cmd.Println(colorInfo(fmt.Sprintf("%s%s批量修改计划：匹配 %d 项，需修改 %d 项", emojiDiff, contextBanner(), matched, len(edits))))
+        for _, e := range edits {
+            cmd.Printf("  %s %s\n", e.Kind, e.Name)
+            for _, line := range strings.Split(strings.TrimRight(e.Diff, "\n"), "\n") {
+                cmd.Printf("    %s\n", line)
+            }
+        }
+        if len(edits) == 0 {
+            PrintInfo(cmd, "匹配的资源均已符合要求，无需修改")
+            return nil
+        }
+        if dryRun {
+            cmd.Println("[dry-run] 以上为计划操作（未实际变更）" + emojiSuccess)
+            if applyDetailedExit { exitStatus = exitChanges }
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("edit（批量修改 %d 项资源）", len(edits))); err != nil {
+            return err
+        }
+        for _, e := range edits {
+            var err error
+            if e.Kind == "Route" {
+                _, err = client.PatchRoute(ctx, e.Key, e.Payload)
+            } else {
+                _, err = client.PatchService(ctx, e.Key, e.Payload)
+            }
+            if err != nil {
+                return fmt.Errorf("修改 %s %s 失败：%w", e.Kind, e.Name, err)
+            }
+        }
+        PrintSuccess(cmd, "已批量修改 %d 项资源", len(edits))
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(editCmd)
+    editCmd.Flags().StringArrayVar(&editSelectors, "selector", nil, "资源选择器：kind=route|service、name=<通配>、tag(s)=<通配>，逗号分隔表示同时满足，可重复指定（任一匹配）")
+    editCmd.Flags().StringVar(&editSetHost, "set-host", "", "将匹配 route 的 hosts 设为该域名（kind=service 时设置 Service host）")
+    editCmd.Flags().StringArrayVar(&editReplaceHosts, "replace-host", nil, "替换域名：旧域名=新域名（可重复指定），仅修改命中的 host")
+    editCmd.Flags().StringArrayVar(&editAddTags, "add-tag", nil, "为匹配的资源追加标签（可重复指定）")
+    editCmd.Flags().StringArrayVar(&editRemoveTags, "remove-tag", nil, "从匹配的资源移除标签（可重复指定）")
+    editCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅预览修改计划（编辑单个资源时只展示差异），不实际变更")
+    editCmd.Flags().BoolVar(&applyDetailedExit, "detailed-exitcode", false, "配合 --dry-run：存在待修改资源时以退出码 2 结束")
+}
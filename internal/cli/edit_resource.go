@@ -0,0 +1,230 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+// editResourceKinds 为 kongctl edit <kind> <name> 支持的资源类型（含私钥等敏感内容的证书、密钥不支持）
+var editResourceKinds = map[string]bool{"upstream": true, "service": true, "route": true, "consumer": true, "consumer_group": true, "plugin": true}
+
+// editReadOnlyFields 为编辑时不允许修改的字段
+var editReadOnlyFields = []string{"id", "created_at", "updated_at"}
+
+// editorCommand 返回编辑器命令：$VISUAL、$EDITOR，均未设置时为 vi；取值可带参数（如 code --wait）
+func editorCommand() []string {
+    for _, env := range []string{"VISUAL", "EDITOR"} {
+        if f := strings.Fields(os.Getenv(env)); len(f) > 0 { return f }
+    }
+    return []string{"vi"}
+}
+
+// runEditor 在终端中打开编辑器编辑 file，退出后返回文件内容
+func runEditor(file string) ([]byte, error) {
+    argv := append(editorCommand(), file)
+    c := exec.Command(argv[0], argv[1:]...)
+    c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+    if err := c.Run(); err != nil {
+        return nil, fmt.Errorf("编辑器 %s 执行失败：%w（通过 $EDITOR 指定编辑器）", argv[0], err)
+    }
+    return os.ReadFile(file)
+}
+
+// stripEditComments 去掉以 # 开头的说明行
+func stripEditComments(b []byte) []byte {
+    var out bytes.Buffer
+    for _, l := range strings.SplitAfter(string(b), "\n") {
+        if strings.HasPrefix(strings.TrimSpace(l), "#") { continue }
+        out.WriteString(l)
+    }
+    return out.Bytes()
+}
+
+// editPayload 比较编辑前后的实体，返回需 PATCH 的顶层字段（删除的字段置为 null，由 Kong 恢复默认值）与差异说明
+func editPayload(cur, edited map[string]any) (map[string]any, string, error) {
+    for _, k := range editReadOnlyFields {
+        if v, ok := edited[k]; ok && diffNested(k, cur[k], v) != "" {
+            return nil, "", fmt.Errorf("%s 为只读字段，不能修改", k)
+        }
+    }
+    keys := make([]string, 0, len(cur)+len(edited))
+    for k := range cur { keys = append(keys, k) }
+    for k := range edited {
+        if _, ok := cur[k]; !ok { keys = append(keys, k) }
+    }
+    sort.Strings(keys)
+    payload := map[string]any{}
+    var diff strings.Builder
+    for _, k := range keys {
+        if sliceContains(editReadOnlyFields, k) { continue }
+        d := diffNested(k, cur[k], edited[k])
+        if d == "" { continue }
+        payload[k] = edited[k]
+        diff.WriteString(d)
+    }
+    return payload, diff.String(), nil
+}
+
+// editResource 为 kongctl edit <kind> <name>：读取资源并以 YAML 在 $EDITOR 中打开，保存后校验、展示差异并 PATCH 修改的字段；
+// 内容有误时带着错误说明重新打开编辑器，再次保存且未修改时放弃
+func editResource(cmd *cobra.Command, args []string) error {
+    if len(args) != 2 {
+        return withCode("usage", "", fmt.Errorf("用法：kongctl edit <kind> <name|id>，或使用 --selector 批量修改"))
+    }
+    if len(editSelectors) > 0 || editSetHost != "" || len(editReplaceHosts) > 0 || len(editAddTags) > 0 || len(editRemoveTags) > 0 {
+        return withCode("usage", "", fmt.Errorf("编辑单个资源时不能同时使用 --selector、--set-host、--replace-host、--add-tag、--remove-tag"))
+    }
+    kind, err := getKind(args[0])
+    if err != nil {
+        return err
+    }
+    if !editResourceKinds[kind] {
+        return withCode("usage", "", fmt.Errorf("edit 不支持 %s（可选：upstream、service、route、consumer、consumer_group、plugin）", kind))
+    }
+    name := args[1]
+    path, err := getPath(kind, name)
+    if err != nil {
+        return err
+    }
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return err
+    }
+    client := kong.NewClient(cfg)
+    ctx := cmd.Context()
+
+    // 编辑器打开期间不计入超时，每次请求单独设置超时
+    rctx, rcancel := context.WithTimeout(ctx, cfg.Timeout)
+    raw, ok, err := client.GetRaw(rctx, path)
+    rcancel()
+    if err != nil {
+        return err
+    }
+    if !ok {
+        return withCode("not_found", fmt.Sprintf("使用 kongctl %s list 查看已有资源", strings.ReplaceAll(kind, "_", "-")), fmt.Errorf("%s 不存在：%s", kind, name))
+    }
+    var cur map[string]any
+    dec := json.NewDecoder(bytes.NewReader(raw))
+    dec.UseNumber()
+    if err := dec.Decode(&cur); err != nil {
+        return fmt.Errorf("解析 %s %s 失败：%w", kind, name, err)
+    }
+    cur, _ = yamlNumbers(cur).(map[string]any)
+    body, err := yaml.Marshal(cur)
+    if err != nil {
+        return err
+    }
+    ws := cfg.Workspace
+    if ws == "" { ws = "default" }
+    header := fmt.Sprintf("# 正在编辑 %s %s（%sworkspace=%s）。保存并退出后将校验并以 PATCH 下发修改的字段。\n", kind, name, contextBanner(), ws) +
+        "# 以 # 开头的行会被忽略；不做修改或清空文件则取消。" + strings.Join(editReadOnlyFields, "、") + " 为只读字段，删除的字段恢复为 Kong 的默认值。\n"
+
+    f, err := os.CreateTemp("", "kongctl-edit-*.yaml")
+    if err != nil {
+        return fmt.Errorf("创建临时文件失败：%w", err)
+    }
+    tmp := f.Name()
+    f.Close()
+    keep := false
+    defer func() {
+        if !keep { os.Remove(tmp) }
+    }()
+
+    content := []byte(header + string(body))
+    var (
+        payload  map[string]any
+        diff     string
+        lastBody []byte
+        lastErr  error
+    )
+    for {
+        if err := os.WriteFile(tmp, content, 0600); err != nil {
+            return fmt.Errorf("写入临时文件失败：%w", err)
+        }
+        out, err := runEditor(tmp)
+        if err != nil {
+            return err
+        }
+        edited := stripEditComments(out)
+        if len(bytes.TrimSpace(edited)) == 0 {
+            PrintInfo(cmd, "文件已清空，已取消编辑")
+            return nil
+        }
+        if bytes.Equal(edited, body) {
+            PrintInfo(cmd, "未修改 %s %s，已取消", kind, name)
+            return nil
+        }
+        if lastErr != nil && bytes.Equal(edited, lastBody) {
+            return fmt.Errorf("未修正错误，已取消编辑：%w", lastErr)
+        }
+        var obj map[string]any
+        err = yaml.Unmarshal(edited, &obj)
+        if err == nil && obj == nil { err = fmt.Errorf("内容应为 YAML 映射") }
+        if err == nil {
+            payload, diff, err = editPayload(cur, obj)
+        }
+        if err == nil && len(payload) > 0 {
+            // 由网关按自身 schema 校验编辑后的完整实体；网关不提供校验接口时跳过
+            entity := getCollections[kind]
+            full := map[string]any{}
+            for k, v := range obj {
+                if !sliceContains(editReadOnlyFields, k) { full[k] = v }
+            }
+            vctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+            supported, verr := client.ValidateEntity(vctx, entity, full)
+            cancel()
+            var apiErr *kong.APIError
+            switch {
+            case errors.As(verr, &apiErr):
+                msg := apiErr.Message
+                if msg == "" { msg = apiErr.Body }
+                if d := apiErrorDetail("schema_violation", apiErr); d != "" { msg += "（" + d + "）" }
+                err = fmt.Errorf("未通过 Kong 的校验：%s", msg)
+            case verr != nil:
+                return verr
+            case !supported:
+                PrintWarn(cmd, "网关不提供 /schemas/%s/validate，跳过服务端校验", entity)
+            }
+        }
+        if err == nil { break }
+        // 带着错误说明重新打开编辑器
+        lastBody, lastErr = edited, err
+        var msg strings.Builder
+        for _, l := range strings.Split(err.Error(), "\n") { msg.WriteString("# 错误：" + l + "\n") }
+        content = []byte(header + msg.String() + string(edited))
+    }
+    if len(payload) == 0 {
+        PrintInfo(cmd, "%s %s 无变更，未写入", kind, name)
+        return nil
+    }
+    PrintInfo(cmd, "%sDiff: %s %s", emojiDiff, kind, name)
+    for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") { cmd.Printf("  %s\n", l) }
+    if dryRun {
+        PrintInfo(cmd, "[dry-run] 将修改 %s %s 的 %d 个字段（未实际变更）", kind, name, len(payload))
+        return nil
+    }
+    if err := confirmDestructive(cmd, fmt.Sprintf("edit %s %s", kind, name)); err != nil {
+        return err
+    }
+    pctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+    defer cancel()
+    if _, err := client.PatchRaw(pctx, path, payload); err != nil {
+        // 保留编辑结果，便于修正后重试
+        keep = true
+        return fmt.Errorf("修改 %s %s 失败（编辑内容已保留在 %s）：%w", kind, name, tmp, err)
+    }
+    PrintSuccess(cmd, "已修改 %s %s（%d 个字段）", kind, name, len(payload))
+    return nil
+}
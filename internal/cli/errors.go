@@ -0,0 +1,139 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/url"
+    "sort"
+    "strings"
+
+    "github.com/spf13/viper"
+    "kongctl/internal/kong"
+)
+
+// outputJSON 表示以 JSON 输出（--output json、KONGCTL_OUTPUT=json 或配置文件 output: json）
+func outputJSON() bool { return strings.EqualFold(viper.GetString("output"), "json") }
+
+// outputYAML 表示以 YAML 输出（仅 list/get 等在 Annotations 中声明 yamlOutputAnnotation 的命令支持）
+func outputYAML() bool { return strings.EqualFold(viper.GetString("output"), "yaml") }
+
+// planOutputFormat 返回 apply/sync --dry-run 的计划输出格式：json、github、gitlab；文本输出时返回空
+func planOutputFormat() string {
+    switch o := strings.ToLower(viper.GetString("output")); o {
+    case "json", "github", "gitlab":
+        return o
+    }
+    return ""
+}
+
+// codedError 为 CLI 自身产生、需要携带错误码的错误（如配置缺失）
+type codedError struct {
+    Code string
+    Hint string
+    Err  error
+}
+
+func (e *codedError) Error() string { return e.Err.Error() }
+func (e *codedError) Unwrap() error { return e.Err }
+
+func withCode(code, hint string, err error) error {
+    if err == nil { return nil }
+    return &codedError{Code: code, Hint: hint, Err: err}
+}
+
+// errorInfo 为错误的结构化描述，--output json 时写入 stderr
+type errorInfo struct {
+    Code       string `json:"code"`
+    Message    string `json:"message"`
+    Resource   string `json:"resource,omitempty"`
+    Hint       string `json:"hint,omitempty"`
+    HTTPStatus int    `json:"http_status,omitempty"`
+}
+
+// describeError 将错误归类为稳定的错误码，供包装工具按类型分支处理
+func describeError(err error) errorInfo {
+    info := errorInfo{Code: "error", Message: err.Error()}
+    var ce *codedError
+    var ae *kong.APIError
+    var ue *url.Error
+    var ne net.Error
+    switch {
+    case errors.As(err, &ce):
+        info.Code, info.Hint = ce.Code, ce.Hint
+    case errors.Is(err, kong.ErrBudgetExceeded):
+        info.Code = "budget_exceeded"
+    case errors.As(err, &ae):
+        info.HTTPStatus = ae.Status
+        info.Resource = apiResource(ae.Path)
+        info.Code = apiErrorCode(ae)
+        info.Hint = apiErrorDetail(info.Code, ae)
+    case errors.Is(err, context.DeadlineExceeded):
+        info.Code = "timeout"
+    case errors.As(err, &ue), errors.As(err, &ne):
+        info.Code = "connection_error"
+    }
+    if h, ok := errorCatalog[info.Code]; ok && h.Hint != "" {
+        if info.Hint != "" { info.Hint += "；" }
+        info.Hint += h.Hint
+    }
+    return info
+}
+
+// apiErrorCode 按 HTTP 状态与 Kong 错误名称归类
+func apiErrorCode(e *kong.APIError) string {
+    switch {
+    case e.Status == 401:
+        return "unauthorized"
+    case e.Status == 403:
+        return "forbidden"
+    case e.Status == 404:
+        return "not_found"
+    case e.Status == 409 || strings.Contains(e.Name, "unique"):
+        return "conflict"
+    case strings.Contains(e.Name, "foreign key"):
+        return "foreign_key_violation"
+    case strings.Contains(e.Name, "schema"):
+        return "schema_violation"
+    case e.Status >= 500:
+        return "server_error"
+    case e.Status == 400:
+        return "bad_request"
+    }
+    return "http_error"
+}
+
+// apiErrorDetail 从 Kong 错误体中提取与本次请求相关的具体说明（出错字段、冲突的名称等）
+func apiErrorDetail(code string, e *kong.APIError) string {
+    switch code {
+    case "schema_violation", "foreign_key_violation", "conflict", "bad_request":
+        if len(e.Fields) == 0 { return "" }
+        keys := make([]string, 0, len(e.Fields))
+        for k := range e.Fields { keys = append(keys, k) }
+        sort.Strings(keys)
+        parts := make([]string, 0, len(keys))
+        for _, k := range keys {
+            parts = append(parts, fmt.Sprintf("%s: %v", k, e.Fields[k]))
+        }
+        return "出错字段 " + strings.Join(parts, "，")
+    }
+    return ""
+}
+
+// apiResource 由请求路径得到资源标识，例如 /routes/user-list -> routes/user-list
+func apiResource(path string) string {
+    return strings.Trim(path, "/")
+}
+
+// writeJSONError 以单行 JSON 输出错误：{"error": {...}}
+func writeJSONError(w io.Writer, err error) {
+    b, merr := json.Marshal(map[string]errorInfo{"error": describeError(err)})
+    if merr != nil {
+        fmt.Fprintf(w, "{\"error\":{\"code\":\"error\",\"message\":%q}}\n", err.Error())
+        return
+    }
+    fmt.Fprintf(w, "%s\n", b)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+    "fmt"
+    "strings"
+)
+
+var exampleLang string
+
+// exampleCommentsEN 为 apply example 模板注释的英文对照（键为中文注释原文，即行内最后一个 "# " 之后的内容）
+var exampleCommentsEN = map[string]string{
+    "通过 kongctl apply -f <file> 应用":                 "Apply with: kongctl apply -f <file>",
+    "完整示例：包含 upstreams / services / routes 三类资源":     "Full example: upstreams / services / routes",
+    "文件格式版本（旧文件可缺省，可用 kongctl spec upgrade 补齐）":   "File format version (optional in older files; add it with kongctl spec upgrade)",
+    "上游命名；与 Service 通过 host 关联":                   "Upstream name; services reference it through host",
+    "将后端实例注册为 target（host:port）":                  "Backend instances registered as targets (host:port)",
+    "权重，0~1000（未设置默认 100）":                        "Weight, 0-1000 (defaults to 100)",
+    "Service 名称":                                  "Service name",
+    "关联 upstream 名（生成的 Service.host 即此值）":         "Upstream name (becomes Service.host)",
+    "上游协议（默认 http）":                               "Upstream protocol (default http)",
+    "上游端口（http 默认 80；https 默认 443）":               "Upstream port (http defaults to 80, https to 443)",
+    "上游基础路径，可为空":                                  "Upstream base path, may be empty",
+    "可选：重试次数":                                     "Optional: retries",
+    "可选：连接超时（毫秒）":                                 "Optional: connect timeout (ms)",
+    "可选：读取超时（毫秒）":                                 "Optional: read timeout (ms)",
+    "可选：写入超时（毫秒）":                                 "Optional: write timeout (ms)",
+    "Route 名称":                                    "Route name",
+    "绑定的 Service 名称":                              "Service this route is attached to",
+    "可选：按 Host 过滤":                                "Optional: match by Host",
+    "路径匹配（支持多个）":                                  "Path match (multiple allowed)",
+    "方法匹配（可选）":                                    "Method match (optional)",
+    "可选：限定协议":                                     "Optional: restrict protocols",
+    "v0/v1（Kong 3.x 等价 v1）":                        "v0/v1 (Kong 3.x behaves as v1)",
+    "是否在转发前去掉匹配前缀":                                "Strip the matched prefix before proxying",
+    "是否保留原始 Host 头":                               "Keep the client's original Host header",
+    "正则优先级（更高优先）":                                 "Regex priority (higher wins)",
+    "https 重定向状态码（如 426/301/302/307/308）":          "HTTPS redirect status code (e.g. 426/301/302/307/308)",
+    "请求缓冲":                                        "Request buffering",
+    "响应缓冲":                                        "Response buffering",
+    "可选：按请求头匹配（键到值列表）":                            "Optional: match by headers (name to list of values)",
+    "可选：给资源打标签":                                   "Optional: tags for the resource",
+    "顶层为 routes 列表（简写）：仅定义路由，自动生成 <name>-service 与 <name>-upstream": "Top level is a list of routes (shorthand): define routes only; <name>-service and <name>-upstream are generated",
+    "- 未显式提供 service 时：根据 backend 自动创建 service 与 upstream，并把 targets 挂到 upstream。": "- Without an explicit service, a service and upstream are created from backend and the targets are attached to the upstream.",
+    "- 可通过 service_name/upstream_name 自定义自动生成的名称。": "- Use service_name/upstream_name to customize the generated names.",
+    "路由名称；未提供 service 时将生成 demo-route-service / demo-route-upstream": "Route name; without a service, demo-route-service / demo-route-upstream are generated",
+    "可选：按 Host 过滤；省略表示不限制主机":                      "Optional: match by Host; omit to match any host",
+    "路径匹配；v1 仅匹配路径段边界，/demo 不会匹配 /demox":           "Path match; v1 matches on segment boundaries, so /demo does not match /demox",
+    "可选：HTTP 方法过滤；省略表示任意方法":                       "Optional: HTTP methods; omit to match any method",
+    "可选：限定协议；默认 http/https":                        "Optional: restrict protocols; defaults to http/https",
+    "路径处理版本（建议 v1）；v0 为前缀匹配，可能误匹配 /foobar":         "Path handling (v1 recommended); v0 is prefix based and may match /foobar",
+    "去除匹配前缀再转发给上游":                                "Strip the matched prefix before proxying upstream",
+    "将上游 Host 设为 service.host（false）；true 则保留客户端原始 Host": "false sends service.host upstream; true keeps the client's Host",
+    "可选：自定义自动创建的 service 名称":                       "Optional: name of the generated service",
+    "可选：自定义自动创建的 upstream 名称":                      "Optional: name of the generated upstream",
+    "描述上游（用于自动创建 service/upstream）":                 "Upstream description (used to create the service/upstream)",
+    "上游基础路径（会与 strip_path 后余下路径拼接）":                "Upstream base path (joined with the path left after strip_path)",
+    "后端实例列表（host:port）":                           "Backend instances (host:port)",
+    "权重（0~1000；未指定默认 100）":                        "Weight (0-1000; defaults to 100)",
+    "仅定义 Route，绑定到已存在的 Service":                    "Route only, attached to an existing Service",
+    "适合已有 Service 时，追加一条路径或主机匹配（不会创建 service/upstream）": "Useful for adding a path or host match to an existing Service (no service/upstream is created)",
+    "路由名称":                                        "Route name",
+    "必填：已存在的 Service 名称":                          "Required: name of an existing Service",
+    "可选：Host 过滤；省略则不限制主机":                         "Optional: Host match; omit to match any host",
+    "路径匹配；v1 仅匹配路径段边界":                            "Path match; v1 matches on segment boundaries",
+    "可选：方法过滤；省略表示任意方法":                            "Optional: methods; omit to match any method",
+    "v0/v1；推荐 v1（不误匹配 /foobar）":                    "v0/v1; v1 recommended (does not match /foobar)",
+    "是否去除匹配前缀；根路径通常保留为 false":                      "Strip the matched prefix; usually false for the root path",
+    "可选：是否保留原始 Host 头":                            "Optional: keep the original Host header",
+    "可选：按请求头匹配":                                   "Optional: match by headers",
+    "可选：打标签":                                      "Optional: tags",
+    "顶层为一个 routes 列表（简写）：仅定义路由，自动创建 <name>-service 与 <name>-upstream 并挂载 targets": "Top level is a list of routes (shorthand): <name>-service and <name>-upstream are created and the targets attached",
+    "- 未显式提供 service 时：根据 backend 创建 service/upstream，并写入协议/端口/基础路径。": "- Without an explicit service, the service/upstream are created from backend with its protocol/port/base path.",
+    "- 可用 path_handling 控制路径匹配边界：推荐 v1（按路径段匹配，不误匹配 /foobar）。": "- path_handling controls match boundaries: v1 is recommended (segment based, does not match /foobar).",
+    "- 可按需补充 hosts/protocols/preserve_host 等字段。":    "- Add hosts/protocols/preserve_host and other fields as needed.",
+    "--- 感知平台服务 ---":                              "--- Perceptual platform service ---",
+    "路径匹配前缀":                                      "Path prefix to match",
+    "方法过滤（省略则为任意）":                                "Methods (omit for any)",
+    "v0/v1；建议 v1":                                 "v0/v1; v1 recommended",
+    "去除匹配前缀再转发":                                   "Strip the matched prefix before proxying",
+    "可选：是否保留原始 Host":                              "Optional: keep the original Host",
+    "上游描述（用于自动创建 service/upstream）":                 "Upstream description (used to create the service/upstream)",
+    "上游协议":                                        "Upstream protocol",
+    "上游端口":                                        "Upstream port",
+    "上游基础路径（与余下路径拼接）":                             "Upstream base path (joined with the remaining path)",
+    "后端实例列表":                                      "Backend instances",
+    "--- 服务目录 ---":                                "--- Service catalog ---",
+    "--- 调度执行器 ---":                               "--- Scheduler executor ---",
+    "--- 故障预处理 ---":                               "--- Fault preprocessing ---",
+    "注意尾随斜杠；v1 下 /serv/fault-pre 与 /serv/fault-pre/ 行为不同": "Mind the trailing slash: under v1, /serv/fault-pre and /serv/fault-pre/ behave differently",
+    "--- 认证 ---":                                  "--- Auth ---",
+    "--- 调度 Web ---":                              "--- Scheduler web ---",
+}
+
+// localizeExample 按 --lang 翻译示例模板中的注释；zh 为原文
+func localizeExample(content, lang string) (string, error) {
+    switch strings.ToLower(strings.TrimSpace(lang)) {
+    case "", "zh", "zh-cn":
+        return content, nil
+    case "en":
+    default:
+        return "", fmt.Errorf("不支持的 --lang：%s（可选：zh、en）", lang)
+    }
+    lines := strings.Split(content, "\n")
+    for i, line := range lines {
+        idx := strings.LastIndex(line, "# ")
+        if idx < 0 { continue }
+        if en, ok := exampleCommentsEN[line[idx+2:]]; ok {
+            lines[i] = line[:idx+2] + en
+        }
+    }
+    return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+)
+
+// errorHint 为错误码的说明：Hint 为随错误输出的简短建议，Guide 为 kongctl explain 展示的排查步骤
+type errorHint struct {
+    Title string
+    Hint  string
+    Guide string
+}
+
+// errorCatalog 汇总常见错误码（与 --output json 输出的 code 字段一致）
+var errorCatalog = map[string]errorHint{
+    "unauthorized": {
+        Title: "认证失败（HTTP 401）",
+        Hint:  "检查 --token、KONGCTL_TOKEN 或 token_command",
+        Guide: `Admin API 未接受当前凭据。
+1. 确认 token 已设置：kongctl context current，并检查配置中的 token / token_command。
+2. 使用会过期的 token（Konnect PAT、OIDC）时配置 token_command，kongctl 会在 401 时自动重新获取一次。
+3. 企业版 RBAC 需请求头 Kong-Admin-Token；确认 token 属于目标 workspace 的 RBAC 用户。`,
+    },
+    "forbidden": {
+        Title: "权限不足（HTTP 403，RBAC 拒绝）",
+        Hint:  "运行 kongctl auth check --write 查看当前 token 允许的操作",
+        Guide: `token 有效，但其 RBAC 角色不允许该操作或该 workspace。
+1. kongctl auth check --write：逐项列出读/写/删除权限。
+2. 检查 --workspace 是否正确；跨 workspace 操作需要对应角色。
+3. 仅需预览时可改用 apply --dry-run（只读）。`,
+    },
+    "not_found": {
+        Title: "资源不存在（HTTP 404）",
+        Hint:  "确认名称与 --workspace；可用 kongctl export 查看远程现有资源",
+        Guide: `请求的资源在当前 workspace 中不存在。
+1. 检查名称拼写与大小写；名称引用（如 route.service）需与远程一致。
+2. 检查 --workspace / --context 是否指向了预期环境。
+3. 若路径本身不存在（旧版本 Kong 不支持该实体），请确认 Kong 版本。`,
+    },
+    "conflict": {
+        Title: "名称冲突（唯一约束）",
+        Hint:  "同名资源已存在；更换名称，或在 apply 中使用 --overwrite 更新已有资源",
+        Guide: `Kong 中 name（或 consumer username/custom_id、target 等）必须唯一。
+1. apply 默认只创建缺失资源；已存在的资源需 --overwrite 才会更新。
+2. 若冲突来自其他团队手工创建的资源，先用 kongctl export 确认其归属再决定改名或接管。
+3. route 未指定 name 时会按 service/paths/methods 推导，多个简写 route 可能推导出相同名称——请显式命名。`,
+    },
+    "schema_violation": {
+        Title: "字段校验失败（schema violation）",
+        Hint:  "按错误中的字段修正 spec，并确认所用字段受当前 Kong 版本支持",
+        Guide: `Kong 拒绝了请求中的字段值。错误的 fields 部分给出了具体字段与原因。
+常见原因：
+- paths 未以 / 开头，或正则路径未加 ~ 前缀（Kong 3.x）。
+- hosts/methods/protocols 取值不合法（如 methods 需大写）。
+- 同时缺少 paths/hosts/methods 等匹配条件。
+- 字段在当前 Kong 版本中不存在（如旧版本不支持 path_handling v1）。
+修正后可先 apply --dry-run --diff 预览。`,
+    },
+    "foreign_key_violation": {
+        Title: "关联资源无效（foreign key violation）",
+        Hint:  "引用的 service/consumer 等不存在，或删除的对象仍被其他资源引用",
+        Guide: `请求引用了不存在的关联对象，或删除的对象仍被引用。
+1. 创建 route 前确保其 service 已存在（apply 会按 upstream → service → route 顺序处理）。
+2. 删除 service 前需先删除其下全部 routes（apply --prune 会按 route → service 顺序删除；kongctl service delete --cascade 一并删除）。
+3. kongctl deps -f <spec> 可离线查看依赖关系。`,
+    },
+    "bad_request": {
+        Title: "请求无效（HTTP 400）",
+        Hint:  "检查错误信息中的字段；可用 apply --dry-run --diff 对比期望与远程配置",
+        Guide: `Admin API 拒绝了请求，但未归类为 schema/唯一/外键错误。
+检查错误信息原文；若是自定义插件配置，确认插件已在 Kong 中启用。`,
+    },
+    "server_error": {
+        Title: "Admin API 内部错误（HTTP 5xx）",
+        Hint:  "稍后重试；持续出现时查看 Kong 节点日志",
+        Guide: `Kong 节点在处理请求时出错。
+1. 查看 Kong error.log；常见原因为数据库不可用或迁移未完成（kong migrations）。
+2. apply 为幂等操作，恢复后重新执行即可继续。`,
+    },
+    "http_error": {
+        Title: "非预期 HTTP 状态",
+        Guide: `Admin API 返回了未分类的非 2xx 状态码。检查错误信息原文；若前面有代理/网关，确认其未改写响应。`,
+    },
+    "timeout": {
+        Title: "请求超时",
+        Hint:  "检查网络与 Admin API 负载，或使用 --deadline 放宽整体时限",
+        Guide: `请求在时限内未完成。
+1. kongctl ping 检查连通性与延迟。
+2. 大型 spec 可使用 --deadline 5m 放宽整体时限。
+3. 若 Admin API 前有负载均衡，确认其空闲超时不小于请求耗时。`,
+    },
+    "connection_error": {
+        Title: "无法连接 Admin API",
+        Hint:  "检查 --admin-url 与网络，或运行 kongctl ping",
+        Guide: `TCP/TLS 连接失败。
+1. 确认 --admin-url 的协议与端口（默认 HTTP 8001，HTTPS 8444），而非代理端口 8000/8443。
+2. 自签名证书可临时使用 --tls-skip-verify（仅限测试环境）。
+3. 确认当前上下文：kongctl context current。`,
+    },
+    "budget_exceeded": {
+        Title: "超出 API 调用预算（--max-api-calls）",
+        Hint:  "调大 --max-api-calls 后重新执行；apply 为幂等操作，会从未完成的部分继续",
+        Guide: `本次执行的 Admin API 调用数达到了 --max-api-calls 上限并已中止，输出中列出了已生效的写操作。
+调大上限，或用 --select 拆分为多次较小的 apply。`,
+    },
+    "remote_changed": {
+        Title: "远程资源在规划后已被修改",
+        Hint:  "重新执行 apply --dry-run --diff 查看最新计划后再执行",
+        Guide: `apply 在规划时记录了各资源的远程指纹（--plan 的计划文件、--confirm 展示的计划），执行前发现其中有资源已被修改，
+为避免静默覆盖其他人的并发修改而中止，未做任何变更。
+1. kongctl apply -f <spec> --dry-run --diff 查看对方改动与最新计划。
+2. 确认以文件为准后重新执行 apply（--plan 需重新生成计划文件）。`,
+    },
+    "locked": {
+        Title: "网关正被另一个 kongctl 变更（apply 锁）",
+        Hint:  "等待对方结束后重试；确认对方已异常退出时可加 --force-unlock",
+        Guide: `apply/sync 在实际变更前会在网关上创建名为 kongctl-apply-lock 的 upstream 作为锁，结束后删除；
+锁已存在说明另一个流水线或操作者正在对同一网关（workspace）执行变更，为避免交错写入而立即中止。
+1. 错误中给出了持有者（主机名:进程号）与开始时间；等待对方结束后重试。
+2. 持有者异常退出未释放时，锁在 1 小时后自动失效，也可加 --force-unlock 立即移除。
+3. 确需并发执行（如变更互不相交的 workspace 以外的资源）时可加 --no-lock。`,
+    },
+    "config": {
+        Title: "配置缺失或无效",
+        Hint:  "运行 kongctl init 或检查配置文件与 --context",
+        Guide: `缺少必需的连接配置，或指定的上下文不存在。
+1. kongctl init --admin-url <url> 写入默认配置。
+2. kongctl context list 查看可用上下文；--context 拼写需与配置一致。`,
+    },
+    "usage": {
+        Title: "参数无效",
+        Guide: `命令行参数取值不合法；使用 kongctl <命令> --help 查看可选值。`,
+    },
+}
+
+var explainCmd = &cobra.Command{
+    Use:   "explain [error-code]",
+    Short: "离线查看错误码的排查说明",
+    Long:  "显示错误码（即 --output json 输出中的 code 字段）对应的原因与排查步骤；不带参数时列出全部错误码。",
+    Example: `# 列出全部错误码
+kongctl explain
+
+# 查看 RBAC 拒绝的排查步骤
+kongctl explain forbidden`,
+    Args: cobra.MaximumNArgs(1),
+    ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return errorCodes(), cobra.ShellCompDirectiveNoFileComp
+    },
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if len(args) == 0 {
+            for _, code := range errorCodes() {
+                cmd.Printf("%-22s %s\n", code, errorCatalog[code].Title)
+            }
+            return nil
+        }
+        code := strings.ToLower(strings.TrimSpace(args[0]))
+        h, ok := errorCatalog[code]
+        if !ok {
+            return withCode("usage", "", fmt.Errorf("未知错误码：%s（可选：%s）", args[0], strings.Join(errorCodes(), ", ")))
+        }
+        cmd.Printf("%s — %s\n\n%s\n", code, h.Title, h.Guide)
+        return nil
+    },
+}
+
+func errorCodes() []string {
+    codes := make([]string, 0, len(errorCatalog))
+    for k := range errorCatalog { codes = append(codes, k) }
+    sort.Strings(codes)
+    return codes
+}
+
+func init() {
+    rootCmd.AddCommand(explainCmd)
+}
@@ -3,14 +3,11 @@ package cli
 import (
     "context"
     "fmt"
-    "os"
     "sort"
     "strings"
     "time"
 
     "github.com/spf13/cobra"
-    "github.com/spf13/viper"
-    "gopkg.in/yaml.v3"
     "kongctl/internal/kong"
 )
 
@@ -18,6 +15,7 @@ var (
     exportOutput string
     exportShorthand bool
     exportIncludeOrphans bool
+    exportNoAnchors bool
 )
 
 // exportCmd 导出远程 Kong 配置为本地 YAML，结构与 apply 兼容
@@ -30,116 +28,34 @@ kongctl export
 # 导出到文件
 kongctl export -o kong-export.yaml
 
-# 以 routes 简写导出（将 service/upstream 折叠到 backend）
-kongctl export --shorthand -o routes.yaml`,
-    RunE: func(cmd *cobra.Command, args []string) error {
-        cfg := kong.Config{
-            AdminURL:      viper.GetString("admin_url"),
-            Token:         viper.GetString("token"),
-            TLSSkipVerify: viper.GetBool("tls_skip_verify"),
-            Timeout:       20 * time.Second,
-        }
-        if cfg.AdminURL == "" {
-            return fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置")
-        }
-        client := kong.NewClient(cfg)
-        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
-        defer cancel()
+# 以 routes 简写导出（将 service/upstream 折叠到 backend，顶层为 {kongctl_format, routes}）
+kongctl export --shorthand -o routes.yaml
 
-        // 1) 列出 upstreams 与 targets
-        ups, err := client.ListUpstreams(ctx)
-        if err != nil { return err }
-        upNames := map[string]bool{}
-        specUps := make([]applyUpstream, 0, len(ups))
-        upTargets := make(map[string][]applyTarget, len(ups))
-        for _, up := range ups {
-            if strings.TrimSpace(up.Name) == "" { continue }
-            upNames[up.Name] = true
-            ats, err := client.ListTargets(ctx, up.Name)
-            if err != nil { return err }
-            targets := make([]applyTarget, 0, len(ats))
-            for _, t := range ats {
-                if strings.TrimSpace(t.Target) == "" { continue }
-                targets = append(targets, applyTarget{Target: t.Target, Weight: t.Weight})
-            }
-            specUps = append(specUps, applyUpstream{Name: up.Name, Targets: targets})
-            upTargets[up.Name] = targets
-        }
-        sort.Slice(specUps, func(i, j int) bool { return specUps[i].Name < specUps[j].Name })
+# 不使用 YAML 锚点/别名（供不支持别名的工具读取）
+kongctl export --no-anchors -o kong.yaml
 
-        // 2) 列出 services
-        svcs, err := client.ListServices(ctx)
-        if err != nil { return err }
-        specSvcs := make([]applyService, 0, len(svcs))
-        svcID2Name := map[string]string{}
-        svcByName := make(map[string]kong.Service, len(svcs))
-        svcByID := make(map[string]kong.Service, len(svcs))
-        for _, s := range svcs {
-            svcID2Name[s.ID] = s.Name
-            svcByName[s.Name] = s
-            if s.ID != "" { svcByID[s.ID] = s }
-            as := applyService{
-                Name:           s.Name,
-                Retries:        s.Retries,
-                ConnectTimeout: s.ConnectTimeout,
-                ReadTimeout:    s.ReadTimeout,
-                WriteTimeout:   s.WriteTimeout,
-            }
-            // 优先导出为 Upstream 形式（若 Host 刚好是某个 upstream 名称）
-            if s.Host != "" && upNames[s.Host] {
-                as.Upstream = s.Host
-                if s.Protocol != "" { as.Protocol = s.Protocol }
-                if s.Port != 0 { as.Port = s.Port }
-                if s.Path != "" { as.Path = s.Path }
-            } else if s.URL != "" {
-                as.URL = s.URL
-            } else {
-                // 回退为 URL 形式
-                url := reconstructURL(&kong.Service{
-                    Protocol: s.Protocol,
-                    Host:     s.Host,
-                    Port:     s.Port,
-                    Path:     s.Path,
-                })
-                if url != "" { as.URL = url }
-            }
-            specSvcs = append(specSvcs, as)
+# 用 Go 模板提取字段（无需 yq）
+kongctl export --template '{{range .Routes}}{{.Name}} {{join .Paths ","}}{{"\n"}}{{end}}'
+kongctl export --shorthand --template '{{.Name}} {{.Backend.Port}}'
+
+# 限制调用次数与整体时长
+kongctl export --max-api-calls 200 --deadline 30s -o kong.yaml`,
+    RunE: func(cmd *cobra.Command, args []string) (err error) {
+        cfg, err := clientConfig(20 * time.Second)
+        if err != nil {
+            return err
         }
-        sort.Slice(specSvcs, func(i, j int) bool { return specSvcs[i].Name < specSvcs[j].Name })
+        cfg.MaxCalls = budgetMaxCalls
+        client := kong.NewClient(cfg)
+        ctx, cancel := budgetContext(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        start := time.Now()
+        defer func() { err = finishBudget(cmd, ctx, client, start, err) }()
 
-        // 3) 列出 routes
-        rts, err := client.ListRoutes(ctx)
+        rs, err := exportRemote(ctx, client, true)
         if err != nil { return err }
-        specRts := make([]applyRoute, 0, len(rts))
-        rtByName := make(map[string]kong.Route, len(rts))
-        for _, r := range rts {
-            if r.Name != "" { rtByName[r.Name] = r }
-            ar := applyRoute{
-                Name:      r.Name,
-                Hosts:     r.Hosts,
-                Paths:     r.Paths,
-                Methods:   r.Methods,
-                PathHandling: strings.ToLower(strings.TrimSpace(r.PathHandling)),
-                Protocols: r.Protocols,
-                RegexPriority: r.RegexPriority,
-                HTTPSRedirectStatusCode: r.HTTPSRedirectStatusCode,
-                Headers: r.Headers,
-                Snis:    r.Snis,
-                Tags:    r.Tags,
-            }
-            if r.PreserveHost != nil { v := *r.PreserveHost; ar.PreserveHost = &v }
-            if r.RequestBuffering != nil { v := *r.RequestBuffering; ar.RequestBuffering = &v }
-            if r.ResponseBuffering != nil { v := *r.ResponseBuffering; ar.ResponseBuffering = &v }
-            if r.StripPath != nil { v := *r.StripPath; ar.StripPath = &v }
-            // 关联 service 名称优先
-            if r.Service.Name != "" {
-                ar.Service = r.Service.Name
-            } else if r.Service.ID != "" {
-                if name, ok := svcID2Name[r.Service.ID]; ok { ar.Service = name }
-            }
-            specRts = append(specRts, ar)
-        }
-        sort.Slice(specRts, func(i, j int) bool { return specRts[i].Name < specRts[j].Name })
+        specUps, specRts := rs.Upstreams, rs.Routes
+        upNames, upTargets, svcByName, svcByID, rtByName := rs.upNames, rs.upTargets, rs.svcByName, rs.svcByID, rs.rtByName
 
         // 若选择简写导出：将 service/upstream 折叠到 route.backend，输出顶层 routes 列表
         if exportShorthand {
@@ -166,9 +82,11 @@ kongctl export --shorthand -o routes.yaml`,
                 Headers map[string][]string     `yaml:"headers"`
                 Snis    []string                `yaml:"snis"`
                 Tags    []string                `yaml:"tags"`
+                Annotations map[string]string   `yaml:"annotations,omitempty"`
                 Backend  exportBackend          `yaml:"backend"`
             }
             type shorthandBundle struct {
+                Format    string         `yaml:"kongctl_format"`
                 Routes    []exportRoute  `yaml:"routes"`
                 Upstreams []applyUpstream `yaml:"upstreams,omitempty"`
             }
@@ -191,6 +109,7 @@ kongctl export --shorthand -o routes.yaml`,
                     Headers:         rt.Headers,
                     Snis:            rt.Snis,
                     Tags:            rt.Tags,
+                    Annotations:     rt.Annotations,
                 }
                 // 归一化空集合，避免输出 null；以 [] / {} 形式呈现
                 if er.Hosts == nil { er.Hosts = []string{} }
@@ -236,21 +155,23 @@ kongctl export --shorthand -o routes.yaml`,
                         orphans = append(orphans, up)
                     }
                 }
-                bundle := shorthandBundle{Routes: exp, Upstreams: orphans}
-                out, err := yaml.Marshal(bundle)
+                bundle := shorthandBundle{Format: currentSpecFormat, Routes: exp, Upstreams: orphans}
+                if outputTemplate != "" { return exportTemplate(cmd, bundle) }
+                out, err := marshalExportYAML(bundle, !exportNoAnchors)
                 if err != nil { return err }
                 if exportOutput == "" || exportOutput == "-" {
                     cmd.Println(string(out))
                     PrintSuccess(cmd, "已导出 routes 简写并附加未引用的 upstreams 到标准输出（--shorthand --include-orphans）")
                     return nil
                 }
-                if err := os.WriteFile(exportOutput, out, 0644); err != nil {
+                if err := writeTextFile(expandPath(exportOutput), out, 0644); err != nil {
                     return fmt.Errorf("写入文件失败：%w", err)
                 }
                 PrintSuccess(cmd, "已导出 routes 简写并附加未引用的 upstreams 到：%s", exportOutput)
                 return nil
             } else {
-                out, err := yaml.Marshal(exp)
+                if outputTemplate != "" { return exportTemplate(cmd, exp) }
+                out, err := marshalExportYAML(shorthandBundle{Format: currentSpecFormat, Routes: exp}, !exportNoAnchors)
                 if err != nil { return err }
                 if exportOutput == "" || exportOutput == "-" {
                     cmd.Println(string(out))
@@ -261,7 +182,7 @@ kongctl export --shorthand -o routes.yaml`,
                     }
                     return nil
                 }
-                if err := os.WriteFile(exportOutput, out, 0644); err != nil {
+                if err := writeTextFile(expandPath(exportOutput), out, 0644); err != nil {
                     return fmt.Errorf("写入文件失败：%w", err)
                 }
                 PrintSuccess(cmd, "已导出 routes 简写到：%s", exportOutput)
@@ -270,9 +191,10 @@ kongctl export --shorthand -o routes.yaml`,
         }
 
         // 组合为 apply 兼容结构（完整形式）
-        spec := applySpec{Upstreams: specUps, Services: specSvcs, Routes: specRts}
+        spec := rs.spec()
+        if outputTemplate != "" { return exportTemplate(cmd, spec) }
 
-        out, err := yaml.Marshal(spec)
+        out, err := marshalExportYAML(spec, !exportNoAnchors)
         if err != nil { return err }
 
         if exportOutput == "" || exportOutput == "-" {
@@ -280,7 +202,7 @@ kongctl export --shorthand -o routes.yaml`,
             PrintSuccess(cmd, "已导出配置到标准输出（可重定向保存）")
             return nil
         }
-        if err := os.WriteFile(exportOutput, out, 0644); err != nil {
+        if err := writeTextFile(expandPath(exportOutput), out, 0644); err != nil {
             return fmt.Errorf("写入文件失败：%w", err)
         }
         PrintSuccess(cmd, "已导出配置到：%s", exportOutput)
@@ -293,4 +215,156 @@ func init() {
     exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "输出文件路径（默认输出到标准输出），例：-o kong.yaml")
     exportCmd.Flags().BoolVar(&exportShorthand, "shorthand", false, "以 routes 简写导出（将 service/upstream 折叠到 backend）")
     exportCmd.Flags().BoolVar(&exportIncludeOrphans, "include-orphans", false, "在 --shorthand 模式下，附加未被路由引用的 upstreams（顶层 upstreams 列表）")
+    addBudgetFlags(exportCmd)
+    addTemplateFlag(exportCmd)
+    exportCmd.Flags().BoolVar(&exportNoAnchors, "no-anchors", false, "禁用 YAML 锚点：重复的 backend/tags/targets/headers 逐处完整输出")
+}
+
+// exportTemplate 以 --template 渲染导出结果，写入 -o 指定文件或标准输出（标准输出时不附加提示，便于脚本处理）
+func exportTemplate(cmd *cobra.Command, data any) error {
+    if exportOutput == "" || exportOutput == "-" {
+        return printTemplate(cmd, data)
+    }
+    out, err := renderTemplate(data)
+    if err != nil { return err }
+    if err := writeTextFile(expandPath(exportOutput), out, 0644); err != nil {
+        return fmt.Errorf("写入文件失败：%w", err)
+    }
+    PrintSuccess(cmd, "已按模板导出到：%s", exportOutput)
+    return nil
+}
+
+// remoteState 为导出的远程配置（apply 兼容结构）及简写导出所需的索引
+type remoteState struct {
+    Upstreams []applyUpstream
+    Services  []applyService
+    Routes    []applyRoute
+    upNames   map[string]bool
+    upTargets map[string][]applyTarget
+    svcByName map[string]kong.Service
+    svcByID   map[string]kong.Service
+    rtByName  map[string]kong.Route
+}
+
+// spec 返回完整写法的 apply spec
+func (r *remoteState) spec() applySpec {
+    return applySpec{KongctlFormat: currentSpecFormat, Upstreams: r.Upstreams, Services: r.Services, Routes: r.Routes}
+}
+
+// exportRemote 读取远程 upstreams/targets、services、routes 并转换为 apply 结构；scoped 为 true 时仅保留 select_tags 范围内的资源
+func exportRemote(ctx context.Context, client *kong.Client, scoped bool) (*remoteState, error) {
+    // 1) 列出 upstreams 与 targets
+    ups, err := client.ListUpstreams(ctx)
+    if err != nil { return nil, err }
+    upNames := map[string]bool{}
+    specUps := make([]applyUpstream, 0, len(ups))
+    upTargets := make(map[string][]applyTarget, len(ups))
+    for _, up := range ups {
+        // apply 锁（--no-lock 之外的 apply 执行期间存在）不属于网关配置
+        if strings.TrimSpace(up.Name) == "" || up.Name == applyLockName || (scoped && !inTagScope(up.Tags)) { continue }
+        upNames[up.Name] = true
+        ats, err := client.ListTargets(ctx, up.Name)
+        if err != nil { return nil, err }
+        targets := make([]applyTarget, 0, len(ats))
+        for _, t := range ats {
+            if strings.TrimSpace(t.Target) == "" { continue }
+            targets = append(targets, applyTarget{Target: t.Target, Weight: t.Weight, Zone: kong.TargetZone(t.Tags)})
+        }
+        eu := exportUpstreamOptions(up)
+        eu.Targets = targets
+        specUps = append(specUps, eu)
+        upTargets[up.Name] = targets
+    }
+    sort.Slice(specUps, func(i, j int) bool { return specUps[i].Name < specUps[j].Name })
+
+    // 2) 列出 services
+    svcs, err := client.ListServices(ctx)
+    if err != nil { return nil, err }
+    specSvcs := make([]applyService, 0, len(svcs))
+    svcID2Name := map[string]string{}
+    svcByName := make(map[string]kong.Service, len(svcs))
+    svcByID := make(map[string]kong.Service, len(svcs))
+    for _, s := range svcs {
+        if scoped && !inTagScope(s.Tags) { continue }
+        svcID2Name[s.ID] = s.Name
+        svcByName[s.Name] = s
+        if s.ID != "" { svcByID[s.ID] = s }
+        specSvcs = append(specSvcs, exportService(s, upNames[s.Host]))
+    }
+    sort.Slice(specSvcs, func(i, j int) bool { return specSvcs[i].Name < specSvcs[j].Name })
+
+    // 3) 列出 routes
+    rts, err := client.ListRoutes(ctx)
+    if err != nil { return nil, err }
+    specRts := make([]applyRoute, 0, len(rts))
+    rtByName := make(map[string]kong.Route, len(rts))
+    for _, r := range rts {
+        if scoped && !inTagScope(r.Tags) { continue }
+        if r.Name != "" { rtByName[r.Name] = r }
+        specRts = append(specRts, exportRoute(r, svcID2Name[r.Service.ID]))
+    }
+    sort.Slice(specRts, func(i, j int) bool { return specRts[i].Name < specRts[j].Name })
+    return &remoteState{
+        Upstreams: specUps, Services: specSvcs, Routes: specRts,
+        upNames: upNames, upTargets: upTargets, svcByName: svcByName, svcByID: svcByID, rtByName: rtByName,
+    }, nil
+}
+
+// exportService 将远程 Service 转换为 apply 结构；viaUpstream 表示其 host 为某个 upstream 的名称
+func exportService(s kong.Service, viaUpstream bool) applyService {
+    as := applyService{
+        Name:           s.Name,
+        Retries:        s.Retries,
+        ConnectTimeout: s.ConnectTimeout,
+        ReadTimeout:    s.ReadTimeout,
+        WriteTimeout:   s.WriteTimeout,
+    }
+    _, as.Annotations = splitAnnotations(s.Tags)
+    // 优先导出为 Upstream 形式（若 Host 刚好是某个 upstream 名称）
+    if s.Host != "" && viaUpstream {
+        as.Upstream = s.Host
+        if s.Protocol != "" { as.Protocol = s.Protocol }
+        if s.Port != 0 { as.Port = s.Port }
+        if s.Path != "" { as.Path = s.Path }
+    } else if s.URL != "" {
+        as.URL = s.URL
+    } else {
+        // 回退为 URL 形式
+        url := reconstructURL(&kong.Service{
+            Protocol: s.Protocol,
+            Host:     s.Host,
+            Port:     s.Port,
+            Path:     s.Path,
+        })
+        if url != "" { as.URL = url }
+    }
+    return as
+}
+
+// exportRoute 将远程 Route 转换为 apply 结构；svcName 为其所属 Service 的名称（未知时为空）
+func exportRoute(r kong.Route, svcName string) applyRoute {
+    ar := applyRoute{
+        Name:      r.Name,
+        Hosts:     r.Hosts,
+        Paths:     r.Paths,
+        Methods:   r.Methods,
+        PathHandling: strings.ToLower(strings.TrimSpace(r.PathHandling)),
+        Protocols: r.Protocols,
+        RegexPriority: r.RegexPriority,
+        HTTPSRedirectStatusCode: r.HTTPSRedirectStatusCode,
+        Headers: r.Headers,
+        Snis:    r.Snis,
+    }
+    ar.Tags, ar.Annotations = splitAnnotations(r.Tags)
+    if r.PreserveHost != nil { v := *r.PreserveHost; ar.PreserveHost = &v }
+    if r.RequestBuffering != nil { v := *r.RequestBuffering; ar.RequestBuffering = &v }
+    if r.ResponseBuffering != nil { v := *r.ResponseBuffering; ar.ResponseBuffering = &v }
+    if r.StripPath != nil { v := *r.StripPath; ar.StripPath = &v }
+    // 关联 service 名称优先
+    if r.Service.Name != "" {
+        ar.Service = r.Service.Name
+    } else {
+        ar.Service = svcName
+    }
+    return ar
 }
@@ -0,0 +1,83 @@
+package cli
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+)
+
+// applyAllContexts 为 apply/sync --all-contexts：对配置文件中的全部上下文执行
+var applyAllContexts bool
+
+// fanoutActive 为 true 时 apply 处于多集群执行中的单个上下文
+var fanoutActive bool
+
+type fanoutResult struct {
+    context  string
+    adminURL string
+    elapsed  time.Duration
+    err      error
+}
+
+// targetContexts 返回本次需要执行的上下文；未指定多个上下文时返回 nil
+func targetContexts(cmd *cobra.Command) ([]string, error) {
+    if applyAllContexts {
+        if cmd.Flags().Changed("context") {
+            return nil, withCode("usage", "", fmt.Errorf("--all-contexts 不能与 --context 同时使用"))
+        }
+        names := contextNames()
+        if len(names) == 0 {
+            return nil, withCode("usage", "", fmt.Errorf("--all-contexts：配置文件中没有上下文（可用 kongctl context add 添加）"))
+        }
+        return names, nil
+    }
+    return fanoutContexts, nil
+}
+
+// runFanout 将同一 spec 依次规划并执行到多个上下文（集群），单个集群失败不影响其余集群，结束后汇总各集群结果；
+// 任一集群失败时以非零状态退出
+func runFanout(cmd *cobra.Command, args []string, contexts []string) error {
+    if applyPlanFile != "" || applyPlanOut != "" || applyResume || applyWatch || applyFromExport || applyFile == "-" {
+        return withCode("usage", "", fmt.Errorf("多上下文执行不能与 --plan/--out/--resume/--watch/--from-export 或 -f - 同时使用"))
+    }
+    if dryRun && planOutputFormat() != "" {
+        return withCode("usage", "", fmt.Errorf("多上下文执行不支持 --dry-run --output %s（计划按上下文分别输出）", planOutputFormat()))
+    }
+    fanoutActive = true
+    defer func() { fanoutActive = false }()
+    results := make([]fanoutResult, 0, len(contexts))
+    for i, name := range contexts {
+        if i > 0 { cmd.Println() }
+        r := fanoutResult{context: name}
+        start := time.Now()
+        if r.err = switchContext(name); r.err == nil {
+            r.adminURL = viper.GetString("admin_url")
+            cmd.Println(colorInfo(fmt.Sprintf("==> [%d/%d] 上下文 %s（%s）", i+1, len(contexts), name, r.adminURL)))
+            // cmd 为 apply 或 sync，fanoutActive 使其只作用于当前上下文
+            r.err = cmd.RunE(cmd, args)
+        }
+        r.elapsed = time.Since(start).Round(time.Millisecond)
+        if r.err != nil {
+            cmd.PrintErrln(ErrorMessage(fmt.Sprintf("上下文 %s 执行失败：%v", name, r.err)))
+        }
+        results = append(results, r)
+    }
+    cmd.Println()
+    cmd.Println(colorInfo("各集群结果："))
+    var failed []string
+    for _, r := range results {
+        status := colorSuccess(glyph("✓ 成功", "ok"))
+        if r.err != nil {
+            status = colorError(glyph("✗ 失败", "FAILED"))
+            failed = append(failed, r.context)
+        }
+        cmd.Printf("  %-16s %-8s %-36s %s\n", r.context, status, r.adminURL, r.elapsed)
+    }
+    if len(failed) > 0 {
+        return fmt.Errorf("%d/%d 个上下文执行失败：%s", len(failed), len(results), strings.Join(failed, ", "))
+    }
+    return nil
+}
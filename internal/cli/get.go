@@ -0,0 +1,221 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/url"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+var getSpec bool
+
+// getCollections 为 get 支持的资源类型及其 Admin API 集合路径（target 为 upstream 下的子集合）
+var getCollections = map[string]string{
+    "upstream": "upstreams", "service": "services", "route": "routes", "target": "targets",
+    "consumer": "consumers", "consumer_group": "consumer_groups", "certificate": "certificates", "sni": "snis",
+    "plugin": "plugins", "vault": "vaults", "key_set": "key-sets", "key": "keys",
+}
+
+// getKind 将 get 的资源类型参数规范化（接受单复数与 - / _ 写法，同 --select 的 kind）
+func getKind(raw string) (string, error) {
+    k := strings.ToLower(strings.TrimSpace(raw))
+    if v, ok := selectorKinds[k]; ok { return v, nil }
+    k = strings.TrimSuffix(k, "s")
+    if _, ok := getCollections[k]; ok { return k, nil }
+    kinds := make([]string, 0, len(getCollections))
+    for k := range getCollections { kinds = append(kinds, k) }
+    sort.Strings(kinds)
+    return "", withCode("usage", "", fmt.Errorf("不支持的资源类型：%s（可选：%s）", raw, strings.Join(kinds, "、")))
+}
+
+// getPath 返回单个资源的 Admin API 路径；target 的名称为 <upstream>/<host:port 或 id>
+func getPath(kind, name string) (string, error) {
+    if kind == "target" {
+        up, target, ok := strings.Cut(name, "/")
+        if !ok || up == "" || target == "" {
+            return "", withCode("usage", "", fmt.Errorf("target 的名称应为 <upstream>/<host:port>，例：user-up/10.0.0.1:8080"))
+        }
+        return "/upstreams/" + url.PathEscape(up) + "/targets/" + url.PathEscape(target), nil
+    }
+    return "/" + getCollections[kind] + "/" + url.PathEscape(name), nil
+}
+
+// getSpecEntry 将远程资源转换为 apply spec 中的条目；返回 spec 顶层的 key 与条目
+func getSpecEntry(ctx context.Context, client *kong.Client, kind, name string) (string, any, bool, error) {
+    switch kind {
+    case "upstream":
+        up, ok, err := client.GetUpstream(ctx, name)
+        if err != nil || !ok {
+            return "", nil, ok, err
+        }
+        ts, err := client.ListTargets(ctx, up.Name)
+        if err != nil {
+            return "", nil, false, fmt.Errorf("列出 upstream %s 的 targets 失败：%w", up.Name, err)
+        }
+        au := exportUpstreamOptions(*up)
+        au.Targets = []applyTarget{}
+        for _, t := range ts {
+            au.Targets = append(au.Targets, applyTarget{Target: t.Target, Weight: t.Weight, Zone: kong.TargetZone(t.Tags)})
+        }
+        return "upstreams", au, true, nil
+    case "service":
+        s, ok, err := client.GetService(ctx, name)
+        if err != nil || !ok {
+            return "", nil, ok, err
+        }
+        viaUpstream := false
+        if s.Host != "" {
+            if _, viaUpstream, err = client.GetUpstream(ctx, s.Host); err != nil {
+                return "", nil, false, err
+            }
+        }
+        return "services", exportService(*s, viaUpstream), true, nil
+    case "route":
+        r, ok, err := client.GetRoute(ctx, name)
+        if err != nil || !ok {
+            return "", nil, ok, err
+        }
+        svcName := ""
+        if r.Service.Name == "" && r.Service.ID != "" {
+            s, found, err := client.GetService(ctx, r.Service.ID)
+            if err != nil {
+                return "", nil, false, err
+            }
+            if found { svcName = s.Name }
+        }
+        return "routes", exportRoute(*r, svcName), true, nil
+    }
+    return "", nil, false, withCode("usage", "", fmt.Errorf("--spec 仅支持 upstream、service、route：%s", kind))
+}
+
+// compactSpecNode 删除映射中值为空（""、0、null、空列表/映射）的字段，使 --spec 的输出只保留有意义的配置；false 保留
+func compactSpecNode(n *yaml.Node) {
+    for _, c := range n.Content { compactSpecNode(c) }
+    if n.Kind != yaml.MappingNode { return }
+    kept := n.Content[:0]
+    for i := 0; i+1 < len(n.Content); i += 2 {
+        v := n.Content[i+1]
+        empty := false
+        switch v.Kind {
+        case yaml.ScalarNode:
+            empty = v.Tag == "!!null" || (v.Tag == "!!str" && v.Value == "") || (v.Tag == "!!int" && v.Value == "0")
+        case yaml.SequenceNode, yaml.MappingNode:
+            empty = len(v.Content) == 0
+        }
+        if !empty { kept = append(kept, n.Content[i], v) }
+    }
+    n.Content = kept
+}
+
+var getCmd = &cobra.Command{
+    Use:   "get <kind> <name|id>",
+    Short: "查看单个资源（YAML，--output json 时为 JSON），--spec 转换为 apply 文件格式",
+    Long: `按名称或 id 读取单个资源，默认原样输出 Admin API 返回的内容（YAML；--output json 时为 JSON）。
+kind 可为 upstream、service、route、target、consumer、consumer_group、certificate、sni、plugin、vault、key_set、key（支持复数与 - 写法）；
+target 的名称写作 <upstream>/<host:port>。
+--spec 将 upstream/service/route 转换为 apply 文件中的条目（与 kongctl export 的转换规则一致），可直接粘贴到 spec 文件中。
+--template 以 Go 模板渲染（字段名与 Admin API 一致，如 {{.host}}；与 --spec 同用时按 spec 字段名）。`,
+    Args: cobra.ExactArgs(2),
+    Example: `kongctl get service user-service
+kongctl get route user-list --output json
+kongctl get target user-up/10.0.0.1:8080
+
+# 以 Go 模板提取字段（字段名与 Admin API 一致）
+kongctl get service user-service --template '{{.host}}:{{.port}}'
+
+# 以 apply 文件格式输出，便于复制到 spec 中
+kongctl get route user-list --spec`,
+    Annotations: map[string]string{yamlOutputAnnotation: "true"},
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, err := getKind(args[0])
+        if err != nil {
+            return err
+        }
+        name := args[1]
+        path, err := getPath(kind, name)
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        hint := fmt.Sprintf("使用 kongctl %s list 查看已有资源", strings.ReplaceAll(kind, "_", "-"))
+        if kind == "target" { hint = "使用 kongctl target list --upstream <name> 查看已有 target" }
+        notFound := withCode("not_found", hint, fmt.Errorf("%s 不存在：%s", kind, name))
+        if getSpec {
+            key, entry, ok, err := getSpecEntry(ctx, client, kind, name)
+            if err != nil {
+                return err
+            }
+            if !ok {
+                return notFound
+            }
+            // 按 spec 结构的 yaml 标签编码（与 apply 文件一致），并去掉空值字段
+            var doc yaml.Node
+            if err := doc.Encode(map[string]any{key: []any{entry}}); err != nil {
+                return err
+            }
+            compactSpecNode(&doc)
+            if outputTemplate != "" || outputJSON() {
+                var obj any
+                if err := doc.Decode(&obj); err != nil {
+                    return err
+                }
+                if outputTemplate != "" {
+                    return printTemplate(cmd, obj)
+                }
+                b, _ := json.MarshalIndent(obj, "", "  ")
+                cmd.Println(string(b))
+                return nil
+            }
+            out, err := yaml.Marshal(&doc)
+            if err != nil {
+                return err
+            }
+            cmd.Print(string(out))
+            return nil
+        }
+        raw, ok, err := client.GetRaw(ctx, path)
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return notFound
+        }
+        if outputTemplate != "" {
+            // 按 Admin API 的字段名渲染，例：{{.name}} {{.host}}
+            var obj any
+            dec := json.NewDecoder(bytes.NewReader(raw))
+            dec.UseNumber()
+            if err := dec.Decode(&obj); err != nil {
+                return fmt.Errorf("解析 %s %s 失败：%w", kind, name, err)
+            }
+            return printTemplate(cmd, yamlNumbers(obj))
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(raw, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        return printYAML(cmd, raw)
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(getCmd)
+    getCmd.Flags().BoolVar(&getSpec, "spec", false, "转换为 apply 文件格式（仅 upstream、service、route）")
+    addTemplateFlag(getCmd)
+}
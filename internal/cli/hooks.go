@@ -0,0 +1,172 @@
+package cli
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+
+    "github.com/spf13/cobra"
+)
+
+// hookMarker 用于识别由 kongctl 生成的 hook，避免误删用户自定义脚本
+const hookMarker = "# generated by kongctl hooks install"
+
+var (
+    hookPatterns  []string
+    hookOverwrite bool
+)
+
+var hooksCmd = &cobra.Command{
+    Use:   "hooks",
+    Short: "管理 git hooks（推送前校验 spec 文件）",
+}
+
+var hooksInstallCmd = &cobra.Command{
+    Use:   "install",
+    Short: "安装 pre-push hook：推送前对变更的 spec 文件执行 validate",
+    Long: `对推送范围内变更的 spec 文件逐个执行 kongctl validate（离线），未通过时阻止推送。
+hook 只做离线校验、不执行 apply --dry-run：计划需要访问 Admin API 与凭据，推送时未必可用，
+网关不可达也不应阻止推送；待执行变更请在 CI 中以 apply --dry-run --detailed-exitcode 检查。`,
+    Example: `# 在当前仓库安装（默认校验 kong/ 目录下的 YAML）
+kongctl hooks install
+
+# 自定义匹配模式（支持 * 与 ?，* 可跨目录）
+kongctl hooks install --pattern 'gateway/*.yaml' --pattern 'specs/*.json'
+
+# 覆盖已存在的 pre-push hook
+kongctl hooks install --overwrite`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        dir, err := gitHooksDir()
+        if err != nil {
+            return err
+        }
+        if strings.TrimSpace(strings.Join(hookPatterns, "")) == "" {
+            return withCode("usage", "", fmt.Errorf("--pattern 不能为空"))
+        }
+        file := filepath.Join(dir, "pre-push")
+        if data, err := os.ReadFile(file); err == nil && !bytes.Contains(data, []byte(hookMarker)) && !hookOverwrite {
+            return fmt.Errorf("已存在非 kongctl 生成的 pre-push hook：%s（使用 --overwrite 覆盖）", file)
+        }
+        if err := os.MkdirAll(dir, 0o755); err != nil {
+            return fmt.Errorf("创建目录失败：%w", err)
+        }
+        if err := os.WriteFile(file, []byte(prePushHook(hookPatterns)), 0o755); err != nil {
+            return fmt.Errorf("写入 hook 失败：%w", err)
+        }
+        PrintSuccess(cmd, "已安装 pre-push hook：%s（匹配：%s）", file, strings.Join(hookPatterns, " "))
+        return nil
+    },
+}
+
+var hooksUninstallCmd = &cobra.Command{
+    Use:   "uninstall",
+    Short: "移除由 kongctl 安装的 pre-push hook",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        dir, err := gitHooksDir()
+        if err != nil {
+            return err
+        }
+        file := filepath.Join(dir, "pre-push")
+        data, err := os.ReadFile(file)
+        if os.IsNotExist(err) {
+            PrintInfo(cmd, "未安装 pre-push hook：%s", file)
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        if !bytes.Contains(data, []byte(hookMarker)) {
+            return fmt.Errorf("pre-push hook 非 kongctl 生成，未删除：%s", file)
+        }
+        if err := os.Remove(file); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已移除 pre-push hook：%s", file)
+        return nil
+    },
+}
+
+// gitHooksDir 通过 git 解析 hooks 目录（兼容 worktree 与 core.hooksPath）
+func gitHooksDir() (string, error) {
+    out, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+    if err != nil {
+        return "", fmt.Errorf("当前目录不是 git 仓库或未安装 git：%v", err)
+    }
+    return strings.TrimSpace(string(out)), nil
+}
+
+// prePushHook 生成 pre-push 脚本：对推送范围内变更的 spec 文件逐个执行 validate
+func prePushHook(patterns []string) string {
+    var quoted []string
+    for _, p := range patterns {
+        if p = strings.TrimSpace(p); p != "" { quoted = append(quoted, hookCasePattern(p)) }
+    }
+    var sb strings.Builder
+    sb.WriteString("#!/bin/sh\n")
+    sb.WriteString(hookMarker + "\n")
+    sb.WriteString(`# 推送前对变更的 spec 文件执行 kongctl validate（离线）；设置 KONGCTL_SKIP_HOOK=1 可跳过
+[ -n "$KONGCTL_SKIP_HOOK" ] && exit 0
+KONGCTL=${KONGCTL:-kongctl}
+zero=0000000000000000000000000000000000000000
+status=0
+while read local_ref local_sha remote_ref remote_sha; do
+  [ "$local_sha" = "$zero" ] && continue
+  if [ "$remote_sha" = "$zero" ]; then
+    # 新分支：检查尚未出现在任何远程分支上的全部提交
+    files=$(git -c core.quotePath=false log --format= --name-only --diff-filter=ACMR "$local_sha" --not --remotes | sort -u)
+  else
+    files=$(git -c core.quotePath=false diff --name-only --diff-filter=ACMR "$remote_sha" "$local_sha")
+  fi
+  # 逐行读取，文件名可含空格
+  while IFS= read -r f; do
+    [ -n "$f" ] && [ -f "$f" ] || continue
+    case "$f" in
+`)
+    sb.WriteString("      " + strings.Join(quoted, "|") + ")\n")
+    sb.WriteString(`        echo "kongctl: 校验 $f"
+        "$KONGCTL" validate -f "$f" --no-color || status=1
+        ;;
+    esac
+  done <<EOF
+$files
+EOF
+done
+if [ $status -ne 0 ]; then
+  echo "kongctl: spec 校验失败，已阻止推送（KONGCTL_SKIP_HOOK=1 可跳过）" >&2
+fi
+exit $status
+`)
+    return sb.String()
+}
+
+// hookCasePattern 将匹配模式转为 shell case 模式：* 与 ? 保留通配含义，其余字符加单引号按字面匹配
+func hookCasePattern(p string) string {
+    var sb, lit strings.Builder
+    flush := func() {
+        if lit.Len() > 0 {
+            sb.WriteString("'" + strings.ReplaceAll(lit.String(), "'", `'\''`) + "'")
+            lit.Reset()
+        }
+    }
+    for _, r := range p {
+        if r == '*' || r == '?' {
+            flush()
+            sb.WriteRune(r)
+            continue
+        }
+        lit.WriteRune(r)
+    }
+    flush()
+    return sb.String()
+}
+
+func init() {
+    rootCmd.AddCommand(hooksCmd)
+    hooksCmd.AddCommand(hooksInstallCmd)
+    hooksCmd.AddCommand(hooksUninstallCmd)
+    hooksInstallCmd.Flags().StringSliceVar(&hookPatterns, "pattern", []string{"kong/*.yaml", "kong/*.yml"}, "需要校验的 spec 文件匹配模式（支持 * 与 ?，* 可跨目录），例：--pattern 'gateway/*.yaml'")
+    hooksInstallCmd.Flags().BoolVar(&hookOverwrite, "overwrite", false, "覆盖已存在的非 kongctl pre-push hook")
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// redirectPlugin 为 Kong 3.9+ 内置的重定向插件
+const redirectPlugin = "redirect"
+
+// redirectAnnotation 标记 hosts migrate 创建的重定向路由（meta:redirect-to=<新域名>），再次迁移时跳过
+const redirectAnnotation = "redirect-to"
+
+var (
+    hostsFrom     string
+    hostsTo       string
+    hostsKeepOld  bool
+    hostsRedirect int
+)
+
+// hostMigration 为单个 route 的迁移：Edit 修改原路由 hosts，Redirect 为旧域名的重定向路由（可为空）
+type hostMigration struct {
+    Edit     bulkEdit
+    Redirect *kong.Route
+}
+
+// redirectLocation 返回重定向目标：路由仅接受 http 时使用 http，否则使用 https
+func redirectLocation(r kong.Route, host string) string {
+    scheme := "https"
+    if len(r.Protocols) > 0 && !sliceContains(r.Protocols, "https") { scheme = "http" }
+    return scheme + "://" + host
+}
+
+// planHostMigration 找出 hosts 包含 from 的路由并计算迁移计划
+func planHostMigration(routes []kong.Route) []hostMigration {
+    sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+    var out []hostMigration
+    for _, r := range routes {
+        if !sliceContains(r.Hosts, hostsFrom) { continue }
+        if _, ann := splitAnnotations(r.Tags); ann[redirectAnnotation] != "" { continue }
+        hosts := []string{}
+        for _, h := range r.Hosts {
+            if h == hostsFrom && !hostsKeepOld { h = hostsTo }
+            if !sliceContains(hosts, h) { hosts = append(hosts, h) }
+        }
+        if !sliceContains(hosts, hostsTo) { hosts = append(hosts, hostsTo) }
+        m := hostMigration{Edit: bulkEdit{Kind: "Route", Name: r.Name, Key: r.Name, Payload: map[string]any{"hosts": hosts}}}
+        if m.Edit.Key == "" { m.Edit.Key, m.Edit.Name = r.ID, r.ID }
+        m.Edit.Diff = diffSlice("hosts", r.Hosts, hosts)
+        if hostsRedirect > 0 {
+            // 重定向路由不在 apply 文件中声明，去掉 managed-by 标签以免被 apply --prune 删除
+            tags := []string{}
+            for _, t := range r.Tags {
+                if t != managedByTag() { tags = append(tags, t) }
+            }
+            rd := kong.Route{
+                Name:      m.Edit.Name + "-redirect",
+                Hosts:     []string{hostsFrom},
+                Paths:     r.Paths,
+                Methods:   r.Methods,
+                Protocols: r.Protocols,
+                Headers:   r.Headers,
+                Snis:      r.Snis,
+                Tags:      withAnnotations(tags, map[string]string{redirectAnnotation: hostsTo}),
+            }
+            rd.Service.ID = r.Service.ID
+            m.Redirect = &rd
+        }
+        out = append(out, m)
+    }
+    return out
+}
+
+var hostsCmd = &cobra.Command{
+    Use:   "hosts",
+    Short: "域名相关工具（域名迁移等）",
+}
+
+var hostsMigrateCmd = &cobra.Command{
+    Use:   "migrate",
+    Short: "将所有 routes 的旧域名迁移到新域名，可为旧域名创建重定向路由",
+    Long: `找出 hosts 包含 --from 的全部 routes，将其替换为 --to（先预览计划）。
+--keep-old 在原路由上保留旧域名（新旧域名同时可用）；--redirect 301|302|307|308 则为每个路由创建
+<路由名>-redirect 重定向路由：匹配旧域名与原路由的 paths/methods，通过 redirect 插件（Kong 3.9+）
+将请求重定向到新域名并保留请求路径。重定向路由先于原路由修改创建，迁移过程中旧域名不会中断。
+production 上下文执行前需确认。`,
+    Example: `# 预览迁移计划
+kongctl hosts migrate --from api.old.com --to api.new.com --dry-run
+
+# 新旧域名同时可用
+kongctl hosts migrate --from api.old.com --to api.new.com --keep-old
+
+# 旧域名返回 301 跳转到新域名
+kongctl hosts migrate --from api.old.com --to api.new.com --redirect 301`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        hostsFrom, hostsTo = strings.TrimSpace(hostsFrom), strings.TrimSpace(hostsTo)
+        if hostsFrom == "" || hostsTo == "" {
+            return withCode("usage", "", fmt.Errorf("必须通过 --from 与 --to 指定旧域名与新域名"))
+        }
+        if hostsFrom == hostsTo {
+            return withCode("usage", "", fmt.Errorf("--from 与 --to 不能相同：%s", hostsFrom))
+        }
+        switch hostsRedirect {
+        case 0, 301, 302, 307, 308:
+        default:
+            return withCode("usage", "", fmt.Errorf("--redirect 仅支持 301、302、307、308：%d", hostsRedirect))
+        }
+        if hostsRedirect > 0 && hostsKeepOld {
+            return withCode("usage", "", fmt.Errorf("--redirect 与 --keep-old 不能同时使用（--redirect 已通过重定向路由保留旧域名）"))
+        }
+
+        cfg, err := clientConfig(30 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        rts, err := client.ListRoutes(ctx)
+        if err != nil {
+            return fmt.Errorf("列出 routes 失败：%w", err)
+        }
+        plan := planHostMigration(rts)
+        if outputJSON() && dryRun {
+            items := []aplan.Change{}
+            for _, m := range plan {
+                if m.Redirect != nil {
+                    items = append(items, aplan.Change{Kind: "Route", Name: m.Redirect.Name, Action: "create", Diff: fmt.Sprintf("redirect %d: %s -> %s\n", hostsRedirect, hostsFrom, redirectLocation(*m.Redirect, hostsTo))})
+                }
+                items = append(items, aplan.Change{Kind: m.Edit.Kind, Name: m.Edit.Name, Action: "update", Diff: m.Edit.Diff})
+            }
+            b, _ := json.MarshalIndent(map[string]any{"from": hostsFrom, "to": hostsTo, "items": items}, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(plan) == 0 {
+            PrintInfo(cmd, "没有 hosts 包含 %s 的 routes，无需迁移", hostsFrom)
+            return nil
+        }
+        cmd.Println(colorInfo(fmt.Sprintf("%s%s域名迁移计划：%s -> %s，涉及 %d 个 routes", emojiDiff, contextBanner(), hostsFrom, hostsTo, len(plan))))
+        for _, m := range plan {
+            cmd.Printf("  Route %s\n", m.Edit.Name)
+            for _, line := range strings.Split(strings.TrimRight(m.Edit.Diff, "\n"), "\n") {
+                cmd.Printf("    %s\n", line)
+            }
+            if m.Redirect != nil {
+                cmd.Printf("    %s\n", colorSuccess(fmt.Sprintf("+ Route %s：%s%s -> %d %s", m.Redirect.Name, hostsFrom, strings.Join(m.Redirect.Paths, ","), hostsRedirect, redirectLocation(*m.Redirect, hostsTo))))
+            }
+        }
+        if dryRun {
+            cmd.Println("[dry-run] 以上为计划操作（未实际变更）" + emojiSuccess)
+            if applyDetailedExit { exitStatus = exitChanges }
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("hosts migrate %s -> %s（%d 个 routes）", hostsFrom, hostsTo, len(plan))); err != nil {
+            return err
+        }
+        redirects := 0
+        for _, m := range plan {
+            // 先创建重定向路由，再修改原路由，避免旧域名出现无路由可匹配的窗口
+            if m.Redirect != nil {
+                if _, _, err := client.CreateOrUpdateRoute(ctx, *m.Redirect); err != nil {
+                    return fmt.Errorf("创建重定向路由 %s 失败：%w", m.Redirect.Name, err)
+                }
+                enabled := true
+                conf := map[string]any{"status_code": hostsRedirect, "location": redirectLocation(*m.Redirect, hostsTo), "keep_incoming_path": true}
+                if _, _, err := client.CreateOrUpdateRoutePlugin(ctx, m.Redirect.Name, kong.Plugin{Name: redirectPlugin, Config: conf, Enabled: &enabled}); err != nil {
+                    return fmt.Errorf("配置 %s 插件失败（需 Kong 3.9+）：Route %s：%w", redirectPlugin, m.Redirect.Name, err)
+                }
+                redirects++
+            }
+            if _, err := client.PatchRoute(ctx, m.Edit.Key, m.Edit.Payload); err != nil {
+                return fmt.Errorf("修改 Route %s 失败：%w", m.Edit.Name, err)
+            }
+        }
+        if redirects > 0 {
+            PrintSuccess(cmd, "已将 %d 个 routes 从 %s 迁移到 %s，并创建 %d 个重定向路由（HTTP %d）", len(plan), hostsFrom, hostsTo, redirects, hostsRedirect)
+        } else {
+            PrintSuccess(cmd, "已将 %d 个 routes 从 %s 迁移到 %s", len(plan), hostsFrom, hostsTo)
+        }
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(hostsCmd)
+    hostsCmd.AddCommand(hostsMigrateCmd)
+    hostsMigrateCmd.Flags().StringVar(&hostsFrom, "from", "", "旧域名，例：--from api.old.com")
+    hostsMigrateCmd.Flags().StringVar(&hostsTo, "to", "", "新域名，例：--to api.new.com")
+    hostsMigrateCmd.Flags().BoolVar(&hostsKeepOld, "keep-old", false, "在原路由上保留旧域名（新旧域名同时可用）")
+    hostsMigrateCmd.Flags().IntVar(&hostsRedirect, "redirect", 0, "为旧域名创建重定向路由并返回该状态码（301/302/307/308，需 Kong 3.9+），例：--redirect 301")
+    hostsMigrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅预览迁移计划，不实际变更")
+    hostsMigrateCmd.Flags().BoolVar(&applyDetailedExit, "detailed-exitcode", false, "配合 --dry-run：存在待迁移路由时以退出码 2 结束")
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "net/url"
+    "strconv"
+    "strings"
+
+    "kongctl/internal/kong"
+)
+
+// applyIgnoreFields 为 apply --ignore-fields：对所有资源忽略的字段（如由其他控制器维护的 tags）
+var applyIgnoreFields []string
+
+// ignorableFields 为各类资源可通过 ignore_fields 交由其他工具管理的字段
+var ignorableFields = map[string][]string{
+    "upstream": {"targets", "client_certificate", "host_header", "healthchecks"},
+    "service":  {"url", "protocol", "port", "path", "retries", "connect_timeout", "read_timeout", "write_timeout", "targets", "annotations"},
+    "route":    {"hosts", "paths", "methods", "strip_path", "path_handling", "protocols", "preserve_host", "regex_priority", "https_redirect_status_code", "request_buffering", "response_buffering", "headers", "snis", "tags", "annotations"},
+}
+
+// checkIgnoreFlag 校验 --ignore-fields：字段需至少适用于一类资源
+func checkIgnoreFlag() error {
+    for _, f := range applyIgnoreFields {
+        ok := false
+        for _, fields := range ignorableFields {
+            if sliceContains(fields, f) { ok = true }
+        }
+        if !ok {
+            return withCode("usage", "", fmt.Errorf("--ignore-fields 不支持字段：%s", f))
+        }
+    }
+    return nil
+}
+
+// ignoredFields 合并资源自身的 ignore_fields 与 --ignore-fields（后者只取适用于该类资源的字段）
+func ignoredFields(kind, name string, own []string) (map[string]bool, error) {
+    out := map[string]bool{}
+    for _, f := range own {
+        if !sliceContains(ignorableFields[kind], f) {
+            return nil, fmt.Errorf("%ss[%s].ignore_fields 不支持字段 %s（可选：%s）", kind, name, f, strings.Join(ignorableFields[kind], ", "))
+        }
+        out[f] = true
+    }
+    for _, f := range applyIgnoreFields {
+        if sliceContains(ignorableFields[kind], f) { out[f] = true }
+    }
+    return out, nil
+}
+
+// replaceURLPart 将 URL 形式 service 的协议/端口/路径替换为远程值
+func replaceURLPart(raw string, cur *kong.Service, ign map[string]bool) string {
+    u, err := url.Parse(raw)
+    if err != nil || u.Host == "" {
+        return raw
+    }
+    if ign["protocol"] && cur.Protocol != "" { u.Scheme = cur.Protocol }
+    if ign["port"] && cur.Port != 0 { u.Host = u.Hostname() + ":" + strconv.Itoa(cur.Port) }
+    if ign["path"] { u.Path = cur.Path }
+    return u.String()
+}
+
+// resolveIgnoreFields 对远程已存在的资源，以远程值替换（或清空为“不管理”）被忽略的字段，
+// 使这些字段既不产生差异也不会被覆盖；新建的资源不受影响
+func resolveIgnoreFields(ctx context.Context, client *kong.Client, spec applySpec) (applySpec, error) {
+    out := spec
+    out.Upstreams = append([]applyUpstream(nil), spec.Upstreams...)
+    for i := range out.Upstreams {
+        up := &out.Upstreams[i]
+        ign, err := ignoredFields("upstream", up.Name, up.IgnoreFields)
+        if err != nil { return spec, err }
+        if len(ign) == 0 { continue }
+        if _, ok, err := client.GetUpstream(ctx, up.Name); err != nil {
+            return spec, err
+        } else if ok {
+            if ign["targets"] { up.Targets = nil }
+            if ign["client_certificate"] { up.ClientCertificate = "" }
+            if ign["host_header"] { up.HostHeader = "" }
+            if ign["healthchecks"] { up.Healthchecks = nil }
+        }
+    }
+    out.Services = append([]applyService(nil), spec.Services...)
+    for i := range out.Services {
+        s := &out.Services[i]
+        ign, err := ignoredFields("service", s.Name, s.IgnoreFields)
+        if err != nil { return spec, err }
+        if len(ign) == 0 { continue }
+        cur, ok, err := client.GetService(ctx, s.Name)
+        if err != nil { return spec, err }
+        if !ok { continue }
+        if s.URL != "" {
+            if ign["url"] {
+                s.URL = reconstructURL(cur)
+            } else if ign["protocol"] || ign["port"] || ign["path"] {
+                s.URL = replaceURLPart(s.URL, cur, ign)
+            }
+        } else {
+            if ign["protocol"] { s.Protocol = cur.Protocol }
+            if ign["port"] { s.Port = cur.Port }
+            if ign["path"] { s.Path = cur.Path }
+        }
+        // 取值为 0 / 空表示不管理该字段
+        if ign["retries"] { s.Retries = 0 }
+        if ign["connect_timeout"] { s.ConnectTimeout = 0 }
+        if ign["read_timeout"] { s.ReadTimeout = 0 }
+        if ign["write_timeout"] { s.WriteTimeout = 0 }
+        if ign["targets"] { s.Targets = nil }
+        if ign["annotations"] { s.Annotations = nil }
+    }
+    out.Routes = append([]applyRoute(nil), spec.Routes...)
+    for i := range out.Routes {
+        r := &out.Routes[i]
+        name := specRouteName(*r)
+        ign, err := ignoredFields("route", name, r.IgnoreFields)
+        if err != nil { return spec, err }
+        if len(ign) == 0 || name == "" { continue }
+        cur, ok, err := client.GetRoute(ctx, name)
+        if err != nil { return spec, err }
+        if !ok { continue }
+        if ign["hosts"] { r.Hosts = cur.Hosts }
+        if ign["paths"] { r.Paths = cur.Paths }
+        if ign["methods"] { r.Methods = cur.Methods }
+        if ign["strip_path"] && cur.StripPath != nil { r.StripPath = cur.StripPath }
+        if ign["path_handling"] { r.PathHandling = "" }
+        if ign["protocols"] { r.Protocols = nil }
+        if ign["preserve_host"] { r.PreserveHost = nil }
+        if ign["regex_priority"] { r.RegexPriority = 0 }
+        if ign["https_redirect_status_code"] { r.HTTPSRedirectStatusCode = 0 }
+        if ign["request_buffering"] { r.RequestBuffering = nil }
+        if ign["response_buffering"] { r.ResponseBuffering = nil }
+        if ign["headers"] { r.Headers = nil }
+        if ign["snis"] { r.Snis = nil }
+        if ign["annotations"] { r.Annotations = nil }
+        if ign["tags"] {
+            if r.Annotations == nil {
+                r.Tags = nil
+            } else {
+                // 仍管理 annotations 时保留远程的普通标签，仅比较 meta: 标签
+                r.Tags, _ = splitAnnotations(cur.Tags)
+            }
+        }
+    }
+    return out, nil
+}
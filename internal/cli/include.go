@@ -0,0 +1,148 @@
+package cli
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// resolveIncludes 展开 spec 顶层 include 声明的片段文件并合并资源；
+// 相对路径以声明它的文件所在目录为基准（-f - 时为当前目录），片段可继续 include，出现循环时报错
+func resolveIncludes(spec applySpec, file string) (applySpec, error) {
+    if len(spec.Include) == 0 {
+        return spec, nil
+    }
+    var stack []string
+    if file != "-" {
+        abs, err := filepath.Abs(expandPath(file))
+        if err != nil { return applySpec{}, err }
+        stack = append(stack, abs)
+    }
+    tagSource(&spec, file)
+    return expandIncludes(spec, file, stack)
+}
+
+// isSpecDir 判断 -f 的取值是否为目录
+func isSpecDir(file string) bool {
+    if file == "" || file == "-" { return false }
+    info, err := os.Stat(expandPath(file))
+    return err == nil && info.IsDir()
+}
+
+// dirSpec 为 -f <目录>：将目录下（不递归子目录）的 *.yaml/*.yml/*.json 按文件名顺序作为片段合并，
+// 等价于只包含 include 的 spec；overlays/ 等子目录不会被读取
+func dirSpec(dir string) (applySpec, error) {
+    abs, err := filepath.Abs(expandPath(dir))
+    if err != nil {
+        return applySpec{}, err
+    }
+    var spec applySpec
+    for _, pattern := range []string{"*.yaml", "*.yml", "*.json"} {
+        p := filepath.Join(abs, pattern)
+        if m, _ := filepath.Glob(p); len(m) > 0 { spec.Include = append(spec.Include, p) }
+    }
+    if len(spec.Include) == 0 {
+        return applySpec{}, fmt.Errorf("目录 %s 下没有 spec 文件（*.yaml/*.yml/*.json）", dir)
+    }
+    return spec, nil
+}
+
+// tagSource 为尚未记录来源的资源标注来源文件
+func tagSource(spec *applySpec, file string) {
+    for i := range spec.Upstreams {
+        if spec.Upstreams[i].source == "" { spec.Upstreams[i].source = file }
+    }
+    for i := range spec.Services {
+        if spec.Services[i].source == "" { spec.Services[i].source = file }
+    }
+    for i := range spec.Routes {
+        if spec.Routes[i].source == "" { spec.Routes[i].source = file }
+    }
+    for i := range spec.ConsumerGroups {
+        if spec.ConsumerGroups[i].source == "" { spec.ConsumerGroups[i].source = file }
+    }
+    for i := range spec.Consumers {
+        if spec.Consumers[i].source == "" { spec.Consumers[i].source = file }
+    }
+    for i := range spec.Certificates {
+        if spec.Certificates[i].source == "" { spec.Certificates[i].source = file }
+    }
+    for i := range spec.Vaults {
+        if spec.Vaults[i].source == "" { spec.Vaults[i].source = file }
+    }
+    for i := range spec.KeySets {
+        if spec.KeySets[i].source == "" { spec.KeySets[i].source = file }
+    }
+}
+
+func expandIncludes(spec applySpec, file string, stack []string) (applySpec, error) {
+    dir := "."
+    if file != "-" { dir = filepath.Dir(expandPath(file)) }
+    out := spec
+    out.Include = nil
+    for _, pattern := range spec.Include {
+        p := expandPath(pattern)
+        if !filepath.IsAbs(p) { p = filepath.Join(dir, p) }
+        matches, err := filepath.Glob(p)
+        if err != nil {
+            return applySpec{}, fmt.Errorf("%s：include 模式无效 %q：%v", file, pattern, err)
+        }
+        if len(matches) == 0 {
+            return applySpec{}, fmt.Errorf("%s：include %q 未匹配到任何文件", file, pattern)
+        }
+        sort.Strings(matches)
+        for _, m := range matches {
+            abs, err := filepath.Abs(m)
+            if err != nil { return applySpec{}, err }
+            for i, s := range stack {
+                if s == abs {
+                    chain := append(append([]string{}, stack[i:]...), abs)
+                    return applySpec{}, fmt.Errorf("%s：include 出现循环引用：%s", file, strings.Join(chain, " -> "))
+                }
+            }
+            frag, err := loadIncludeFile(m)
+            if err != nil {
+                return applySpec{}, err
+            }
+            frag, err = expandIncludes(frag, m, append(stack, abs))
+            if err != nil {
+                return applySpec{}, err
+            }
+            tagSource(&frag, m)
+            out.Upstreams = append(out.Upstreams, frag.Upstreams...)
+            out.Services = append(out.Services, frag.Services...)
+            out.Routes = append(out.Routes, frag.Routes...)
+            out.ConsumerGroups = append(out.ConsumerGroups, frag.ConsumerGroups...)
+            out.Consumers = append(out.Consumers, frag.Consumers...)
+            out.Certificates = append(out.Certificates, frag.Certificates...)
+            out.Vaults = append(out.Vaults, frag.Vaults...)
+            out.KeySets = append(out.KeySets, frag.KeySets...)
+            // 片段中的 common_tags 同样作用于整次 apply
+            for _, t := range frag.CommonTags {
+                if !sliceContains(out.CommonTags, t) { out.CommonTags = append(out.CommonTags, t) }
+            }
+        }
+    }
+    return out, nil
+}
+
+// loadIncludeFile 读取并解析单个片段；--template 模式下片段同样先渲染
+func loadIncludeFile(file string) (applySpec, error) {
+    content, err := os.ReadFile(file)
+    if err != nil {
+        return applySpec{}, fmt.Errorf("读取 include 文件失败：%w", err)
+    }
+    content = normalizeText(content)
+    if renderEnabled() {
+        if content, err = renderSpecTemplate(content, file); err != nil {
+            return applySpec{}, fmt.Errorf("%s：%w", file, err)
+        }
+    }
+    spec, err := parseApplySpec(content)
+    if err != nil {
+        return applySpec{}, fmt.Errorf("%s：%w", file, err)
+    }
+    return spec, nil
+}
@@ -0,0 +1,437 @@
+package cli
+
+import (
+    "context"
+    "crypto"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/hex"
+    "encoding/json"
+    "encoding/pem"
+    "fmt"
+    "os"
+    "reflect"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+var (
+    keyKid            string
+    keySet            string
+    keyName           string
+    keyJWK            string
+    keyPublicKeyFile  string
+    keyPrivateKeyFile string
+    keyTags           []string
+)
+
+// jwkPrivateMembers 为 JWK 中的私钥参数（RSA/EC/OKP 的私有部分与对称密钥 k），任何输出中均以 ****** 代替
+var jwkPrivateMembers = []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k"}
+
+var keyCmd = &cobra.Command{
+    Use:   "key",
+    Short: "管理密钥（JWK/PEM，供 jwt-signer、openid-connect 等插件使用）",
+    Long: `key 以 kid 标识，可归属于某个 key set（--set），同一 key set 内 kid 唯一。密钥内容为 JWK（--jwk）或 PEM（--public-key-file/--private-key-file）二选一；
+私钥只上传到 Kong：list/get 与差异中只显示密钥类型与公钥指纹，私钥参数以 ****** 代替。`,
+}
+
+// parseJWK 解析 JWK（JSON 对象），要求包含 kty
+func parseJWK(raw string) (map[string]any, error) {
+    var m map[string]any
+    if err := json.Unmarshal([]byte(raw), &m); err != nil {
+        return nil, fmt.Errorf("JWK 不是合法的 JSON 对象：%w", err)
+    }
+    if s, _ := m["kty"].(string); s == "" {
+        return nil, fmt.Errorf("JWK 缺少 kty")
+    }
+    return m, nil
+}
+
+// parsePrivateKey 解析 PEM 私钥（PKCS#8、PKCS#1 或 SEC 1 EC）
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+    if k, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+        if s, ok := k.(crypto.Signer); ok { return s, nil }
+    }
+    if k, err := x509.ParsePKCS1PrivateKey(der); err == nil { return k, nil }
+    if k, err := x509.ParseECPrivateKey(der); err == nil { return k, nil }
+    return nil, fmt.Errorf("无法解析私钥（支持 PKCS#8、PKCS#1、EC）")
+}
+
+// loadKeyMaterial 校验密钥内容并返回 Kong 的 jwk/pem 字段：jwk 与 PEM 二选一，JWK 中的 kid 需与 kid 一致，
+// PEM 同时给出公钥与私钥时需配对
+func loadKeyMaterial(kid, jwk string, publicPEM, privatePEM []byte) (string, *kong.KeyPEM, error) {
+    hasPEM := len(publicPEM) > 0 || len(privatePEM) > 0
+    switch {
+    case jwk != "" && hasPEM:
+        return "", nil, fmt.Errorf("JWK 与 PEM 只能指定一种")
+    case jwk != "":
+        m, err := parseJWK(jwk)
+        if err != nil {
+            return "", nil, err
+        }
+        if v, ok := m["kid"]; ok && fmt.Sprint(v) != kid {
+            return "", nil, fmt.Errorf("JWK 中的 kid（%v）与 kid（%s）不一致", v, kid)
+        }
+        return jwk, nil, nil
+    case !hasPEM:
+        return "", nil, fmt.Errorf("缺少密钥内容（JWK 或 PEM）")
+    }
+    var pub crypto.PublicKey
+    if len(publicPEM) > 0 {
+        block, _ := pem.Decode(publicPEM)
+        if block == nil { return "", nil, fmt.Errorf("公钥不是 PEM 格式") }
+        var err error
+        if pub, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil { return "", nil, fmt.Errorf("无法解析公钥：%w", err) }
+    }
+    if len(privatePEM) > 0 {
+        block, _ := pem.Decode(privatePEM)
+        if block == nil { return "", nil, fmt.Errorf("私钥不是 PEM 格式") }
+        priv, err := parsePrivateKey(block.Bytes)
+        if err != nil { return "", nil, err }
+        if eq, ok := priv.Public().(interface{ Equal(crypto.PublicKey) bool }); pub != nil && ok && !eq.Equal(pub) {
+            return "", nil, fmt.Errorf("公钥与私钥不匹配")
+        }
+    }
+    return "", &kong.KeyPEM{PublicKey: string(publicPEM), PrivateKey: string(privatePEM)}, nil
+}
+
+// keyFingerprint 返回 v 的 sha256 指纹前 12 位（v 为字符串时按去除首尾空白后的内容计算，否则按 JSON 计算）
+func keyFingerprint(v any) string {
+    var b []byte
+    if s, ok := v.(string); ok {
+        b = []byte(strings.TrimSpace(s))
+    } else {
+        b, _ = json.Marshal(v)
+    }
+    sum := sha256.Sum256(b)
+    return hex.EncodeToString(sum[:])[:12]
+}
+
+// keySummary 返回密钥的简要说明（类型与公钥指纹，不含私钥内容），例：jwk kty=RSA alg=RS256 sha256:1f2e3d4c5b6a（含私钥）
+func keySummary(k kong.Key) string {
+    switch {
+    case k.JWK != "":
+        m, err := parseJWK(k.JWK)
+        if err != nil { return "jwk（无法解析）" }
+        pub, secret := map[string]any{}, false
+        for f, v := range m {
+            if sliceContains(jwkPrivateMembers, f) { secret = true; continue }
+            pub[f] = v
+        }
+        s := fmt.Sprintf("jwk kty=%v", m["kty"])
+        if alg, ok := m["alg"]; ok { s += fmt.Sprintf(" alg=%v", alg) }
+        s += " sha256:" + keyFingerprint(pub)
+        if secret { s += "（含私钥）" }
+        return s
+    case k.PEM != nil:
+        s := "pem"
+        if k.PEM.PublicKey != "" { s += " sha256:" + keyFingerprint(k.PEM.PublicKey) }
+        if k.PEM.PrivateKey != "" { s += "（含私钥）" }
+        return s
+    }
+    return "（无密钥内容）"
+}
+
+// sameKeyMaterial 判断已有 key 的内容是否与期望一致（JWK 按 JSON 语义比较，PEM 忽略首尾空白）
+func sameKeyMaterial(cur *kong.Key, want kong.Key) bool {
+    if want.JWK != "" {
+        a, errA := parseJWK(cur.JWK)
+        b, errB := parseJWK(want.JWK)
+        return errA == nil && errB == nil && reflect.DeepEqual(a, b)
+    }
+    if want.PEM == nil { return true }
+    if cur.PEM == nil { return false }
+    same := func(x, y string) bool { return strings.TrimSpace(x) == strings.TrimSpace(y) }
+    return same(cur.PEM.PublicKey, want.PEM.PublicKey) && (want.PEM.PrivateKey == "" || same(cur.PEM.PrivateKey, want.PEM.PrivateKey))
+}
+
+// keyDiff 比较已有 key 与期望：密钥内容只显示类型与公钥指纹，公钥相同而私钥不同时标记“私钥已变更”；空的 name/tags 不比较
+func keyDiff(cur *kong.Key, want kong.Key, tags []string) string {
+    diff := ""
+    if want.Name != "" && cur.Name != want.Name { diff += fmt.Sprintf("name: %s -> %s\n", cur.Name, want.Name) }
+    if !sameKeyMaterial(cur, want) {
+        from, to := keySummary(*cur), keySummary(want)
+        if from == to { to += "（私钥已变更）" }
+        diff += fmt.Sprintf("key: %s -> %s\n", from, to)
+    }
+    if len(tags) > 0 && !sliceSetEqual(cur.Tags, tags) { diff += diffSlice("tags", cur.Tags, tags) }
+    return diff
+}
+
+// redactKey 返回将私钥参数替换为 ****** 后的 key，用于 list/get 输出
+func redactKey(k kong.Key) kong.Key {
+    if m, err := parseJWK(k.JWK); err == nil {
+        for _, f := range jwkPrivateMembers {
+            if _, ok := m[f]; ok { m[f] = "******" }
+        }
+        b, _ := json.Marshal(m)
+        k.JWK = string(b)
+    }
+    if k.PEM != nil {
+        p := *k.PEM
+        if p.PrivateKey != "" { p.PrivateKey = "******" }
+        k.PEM = &p
+    }
+    return k
+}
+
+// resolveKey 按名称、ID 或 kid（在 --set 指定的 key set 中，未指定时为不属于任何 key set 的 key）查找 key
+func resolveKey(ctx context.Context, client *kong.Client, ref string) (*kong.Key, bool, error) {
+    if keySet == "" {
+        cur, ok, err := client.GetKey(ctx, ref)
+        if err != nil || ok {
+            return cur, ok, err
+        }
+    }
+    return client.FindKey(ctx, keySet, ref)
+}
+
+// keySetNames 返回 key set ID 到名称的映射
+func keySetNames(ctx context.Context, client *kong.Client) (map[string]string, error) {
+    sets, err := client.ListKeySets(ctx)
+    if err != nil {
+        return nil, err
+    }
+    names := map[string]string{}
+    for _, s := range sets { names[s.ID] = s.Name }
+    return names, nil
+}
+
+var keySyncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "按 kid 创建或更新密钥（幂等，更新即轮换）",
+    Example: `# JWK（内联或 @文件），归属 key set jwt-signing
+kongctl key sync --set jwt-signing --kid 2024-01 --jwk @signing.jwk.json
+
+# PEM 密钥对
+kongctl key sync --set jwt-signing --kid legacy --public-key-file legacy.pub --private-key-file legacy.key --diff`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if keyKid == "" {
+            return fmt.Errorf("必须提供 --kid")
+        }
+        jwk := keyJWK
+        if strings.HasPrefix(jwk, "@") {
+            b, err := os.ReadFile(expandPath(jwk[1:]))
+            if err != nil {
+                return fmt.Errorf("读取 JWK 失败：%w", err)
+            }
+            jwk = string(b)
+        }
+        var pubPEM, privPEM []byte
+        for _, f := range []struct{ path string; out *[]byte; label string }{{keyPublicKeyFile, &pubPEM, "公钥"}, {keyPrivateKeyFile, &privPEM, "私钥"}} {
+            if f.path == "" { continue }
+            b, err := os.ReadFile(expandPath(f.path))
+            if err != nil {
+                return fmt.Errorf("读取%s失败：%w", f.label, err)
+            }
+            *f.out = b
+        }
+        jwk, keyPEM, err := loadKeyMaterial(keyKid, jwk, pubPEM, privPEM)
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        want := kong.Key{Name: keyName, Kid: keyKid, JWK: jwk, PEM: keyPEM, Tags: keyTags}
+        label := keyKid
+        if keySet != "" {
+            set, ok, err := client.GetKeySet(ctx, keySet)
+            if err != nil {
+                return err
+            }
+            if !ok {
+                return withCode("not_found", "使用 kongctl key-set sync --name "+keySet+" 创建", fmt.Errorf("key set 不存在：%s", keySet))
+            }
+            want.Set = &kong.EntityRef{ID: set.ID}
+            label = keySet + "/" + keyKid
+        }
+        cur, exists, err := client.FindKey(ctx, keySet, keyKid)
+        if err != nil {
+            return err
+        }
+        diff := ""
+        if exists {
+            if diff = keyDiff(cur, want, keyTags); diff == "" {
+                PrintInfo(cmd, "Key 无变更，未写入：%s", label)
+                return nil
+            }
+        }
+        if showDiff || dryRun {
+            PrintInfo(cmd, "%sDiff: Key %s", emojiDiff, label)
+            if !exists {
+                cmd.Printf("%s\n", colorInfo("+ kid: "+keyKid))
+                cmd.Printf("%s\n", colorInfo("+ key: "+keySummary(want)))
+            }
+            for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+                if l != "" { cmd.Printf("  %s\n", l) }
+            }
+        }
+        if dryRun {
+            action := "create"
+            if exists { action = "update" }
+            PrintInfo(cmd, "[dry-run] 将%s Key：%s", actionCN(action), label)
+            return nil
+        }
+        if exists {
+            _, err = client.UpdateKey(ctx, cur.ID, want)
+        } else {
+            _, err = client.CreateKey(ctx, want)
+        }
+        if err != nil {
+            return err
+        }
+        action := "create"
+        if exists { action = "update" }
+        PrintSuccess(cmd, "已%s Key：%s（%s）", actionCN(action), label, keySummary(want))
+        return nil
+    },
+}
+
+var keyListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出密钥（不显示私钥）",
+    Example: `kongctl key list
+kongctl key list --set jwt-signing --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListKeys(ctx, keySet)
+        if err != nil {
+            return err
+        }
+        names, err := keySetNames(ctx, client)
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Kid < list[j].Kid })
+        if outputJSON() {
+            out := make([]kong.Key, len(list))
+            for i, k := range list { out[i] = redactKey(k) }
+            b, _ := json.MarshalIndent(out, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Key")
+            return nil
+        }
+        cmd.Printf("%-20s %-20s %-16s %s\n", "KID", "NAME", "SET", "KEY")
+        for _, k := range list {
+            set := "-"
+            if k.Set != nil { set = names[k.Set.ID]; if set == "" { set = k.Set.ID } }
+            cmd.Printf("%-20s %-20s %-16s %s\n", k.Kid, k.Name, set, keySummary(k))
+        }
+        return nil
+    },
+}
+
+var keyGetCmd = &cobra.Command{
+    Use:   "get <name|id|kid>",
+    Short: "查看密钥（YAML，--output json 时为 JSON；私钥参数以 ****** 代替）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl key get 2024-01 --set jwt-signing`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        k, ok, err := resolveKey(ctx, client, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "使用 kongctl key list 查看已有 Key", fmt.Errorf("Key 不存在：%s", args[0]))
+        }
+        red := redactKey(*k)
+        if outputJSON() {
+            b, _ := json.MarshalIndent(red, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        var obj map[string]any
+        b, _ := json.Marshal(red)
+        _ = json.Unmarshal(b, &obj)
+        out, err := yaml.Marshal(obj)
+        if err != nil {
+            return err
+        }
+        cmd.Print(string(out))
+        return nil
+    },
+}
+
+var keyDeleteCmd = &cobra.Command{
+    Use:   "delete <name|id|kid>",
+    Short: "删除密钥（引用该 kid 签发的令牌将无法校验）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl key delete 2023-12 --set jwt-signing`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        k, ok, err := resolveKey(ctx, client, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Key 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Key：%s", k.Kid)
+            return nil
+        }
+        if err := confirmDestructive(cmd, "key delete "+k.Kid); err != nil {
+            return err
+        }
+        if err := client.DeleteKey(ctx, k.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Key：%s", k.Kid)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(keyCmd)
+    keyCmd.AddCommand(keySyncCmd, keyListCmd, keyGetCmd, keyDeleteCmd)
+    keySyncCmd.Flags().StringVar(&keyKid, "kid", "", "密钥 ID（JWK 中的 kid 需与之一致），例：--kid 2024-01")
+    keySyncCmd.Flags().StringVar(&keyName, "name", "", "密钥名称（全局唯一，可选）")
+    keySyncCmd.Flags().StringVar(&keyJWK, "jwk", "", "JWK：内联 JSON 或 @文件")
+    keySyncCmd.Flags().StringVar(&keyPublicKeyFile, "public-key-file", "", "PEM 公钥文件")
+    keySyncCmd.Flags().StringVar(&keyPrivateKeyFile, "private-key-file", "", "PEM 私钥文件")
+    keySyncCmd.Flags().StringSliceVar(&keyTags, "tags", nil, "密钥标签（覆盖现有标签）")
+    keySyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    for _, c := range []*cobra.Command{keySyncCmd, keyListCmd, keyGetCmd, keyDeleteCmd} {
+        c.Flags().StringVar(&keySet, "set", "", "所属 key set 名称或 ID，例：--set jwt-signing")
+    }
+    for _, c := range []*cobra.Command{keySyncCmd, keyDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    keySetName string
+    keySetTags []string
+)
+
+var keySetCmd = &cobra.Command{
+    Use:   "key-set",
+    Short: "管理 Key Set（一组密钥，按名称被插件引用）",
+    Long: `Key Set 以名称唯一标识，其中的密钥使用 kongctl key sync --set <name> 管理。删除 Key Set 时 Kong 会一并删除其中的密钥。
+声明式管理请在 apply spec 中使用 key_sets。`,
+}
+
+var keySetSyncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "按名称创建或更新 Key Set（幂等）",
+    Example: `kongctl key-set sync --name jwt-signing --tags auth
+kongctl key sync --set jwt-signing --kid 2024-01 --jwk @signing.jwk.json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if keySetName == "" {
+            return fmt.Errorf("必须提供 --name")
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        cur, exists, err := client.GetKeySet(ctx, keySetName)
+        if err != nil {
+            return err
+        }
+        diff := ""
+        if exists {
+            if len(keySetTags) > 0 && !sliceSetEqual(cur.Tags, keySetTags) { diff = diffSlice("tags", cur.Tags, keySetTags) }
+            if diff == "" {
+                PrintInfo(cmd, "Key Set 无变更，未写入：%s", keySetName)
+                return nil
+            }
+        }
+        if showDiff || dryRun {
+            PrintInfo(cmd, "%sDiff: Key Set %s", emojiDiff, keySetName)
+            if !exists {
+                cmd.Printf("%s\n", colorInfo("+ name: "+keySetName))
+                if len(keySetTags) > 0 { cmd.Printf("%s\n", colorInfo("+ tags: "+strings.Join(keySetTags, ","))) }
+            } else {
+                for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") { cmd.Printf("  %s\n", l) }
+            }
+        }
+        if dryRun {
+            action := "create"
+            if exists { action = "update" }
+            PrintInfo(cmd, "[dry-run] 将%s Key Set：%s", actionCN(action), keySetName)
+            return nil
+        }
+        action, _, err := client.CreateOrUpdateKeySet(ctx, kong.KeySet{Name: keySetName, Tags: keySetTags})
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s Key Set：%s", actionCN(action), keySetName)
+        return nil
+    },
+}
+
+var keySetListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Key Set 及其密钥数量",
+    Example: `kongctl key-set list
+kongctl key-set list --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        sets, err := client.ListKeySets(ctx)
+        if err != nil {
+            return err
+        }
+        keys, err := client.ListKeys(ctx, "")
+        if err != nil {
+            return err
+        }
+        counts := map[string]int{}
+        for _, k := range keys {
+            if k.Set != nil { counts[k.Set.ID]++ }
+        }
+        sort.SliceStable(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+        if outputJSON() {
+            type row struct {
+                kong.KeySet
+                Keys int `json:"keys"`
+            }
+            out := make([]row, len(sets))
+            for i, s := range sets { out[i] = row{s, counts[s.ID]} }
+            b, _ := json.MarshalIndent(out, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(sets) == 0 {
+            PrintInfo(cmd, "未找到 Key Set")
+            return nil
+        }
+        cmd.Printf("%-24s %-6s %s\n", "NAME", "KEYS", "TAGS")
+        for _, s := range sets {
+            cmd.Printf("%-24s %-6d %s\n", s.Name, counts[s.ID], strings.Join(s.Tags, ","))
+        }
+        return nil
+    },
+}
+
+var keySetDeleteCmd = &cobra.Command{
+    Use:   "delete <name|id>",
+    Short: "删除 Key Set（其中的密钥一并删除）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl key-set delete jwt-signing`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        set, ok, err := client.GetKeySet(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Key Set 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        keys, err := client.ListKeys(ctx, set.ID)
+        if err != nil {
+            return err
+        }
+        label := fmt.Sprintf("%s（含 %d 个密钥）", set.Name, len(keys))
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Key Set：%s", label)
+            return nil
+        }
+        if err := confirmDestructive(cmd, "key-set delete "+label); err != nil {
+            return err
+        }
+        if err := client.DeleteKeySet(ctx, set.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Key Set：%s", label)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(keySetCmd)
+    keySetCmd.AddCommand(keySetSyncCmd, keySetListCmd, keySetDeleteCmd)
+    keySetSyncCmd.Flags().StringVar(&keySetName, "name", "", "Key Set 名称，例：--name jwt-signing")
+    keySetSyncCmd.Flags().StringSliceVar(&keySetTags, "tags", nil, "Key Set 标签（覆盖现有标签）")
+    keySetSyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    for _, c := range []*cobra.Command{keySetSyncCmd, keySetDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    "kongctl/internal/kong"
+)
+
+// applyThreeWay 为 apply --three-way：结合上次 apply 写入的内容做三方比较
+var applyThreeWay bool
+
+// applyLastApplied 为本次 apply 使用的 last-applied 记录；为 nil 时不做三方合并（未启用、rollback、schedule 等）
+var applyLastApplied *lastAppliedState
+
+// lastAppliedState 记录每个资源最近一次由 kongctl apply 声明的内容，保存在 ~/.kongctl/state/<key>.json；
+// key 由上下文、admin_url 与 workspace 计算，不同网关的记录互不影响
+type lastAppliedState struct {
+    Context   string                  `json:"context,omitempty"`
+    AdminURL  string                  `json:"admin_url"`
+    Workspace string                  `json:"workspace,omitempty"`
+    UpdatedAt time.Time               `json:"updated_at"`
+    Services  map[string]applyService `json:"services,omitempty"`
+    Routes    map[string]applyRoute   `json:"routes,omitempty"`
+}
+
+func lastAppliedPath() (string, error) {
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    sum := sha256.Sum256([]byte(activeContext + "\x00" + viper.GetString("admin_url") + "\x00" + viper.GetString("workspace")))
+    return filepath.Join(dir, "state", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// loadLastApplied 读取当前网关的 last-applied 记录；不存在时返回 (空记录, false, nil)
+func loadLastApplied() (*lastAppliedState, bool, error) {
+    path, err := lastAppliedPath()
+    if err != nil {
+        return nil, false, err
+    }
+    st := &lastAppliedState{Services: map[string]applyService{}, Routes: map[string]applyRoute{}}
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return st, false, nil
+    } else if err != nil {
+        return nil, false, err
+    }
+    if err := json.Unmarshal(data, st); err != nil {
+        return nil, false, fmt.Errorf("解析 last-applied 记录失败（%s）：%w", path, err)
+    }
+    if st.Services == nil { st.Services = map[string]applyService{} }
+    if st.Routes == nil { st.Routes = map[string]applyRoute{} }
+    return st, true, nil
+}
+
+// recordLastApplied 在 apply 成功后记录本次声明的 services/routes；记录失败只告警，不影响 apply 结果
+func recordLastApplied(cmd *cobra.Command, spec applySpec) {
+    st, _, err := loadLastApplied()
+    if err == nil {
+        st.Context, st.AdminURL, st.Workspace, st.UpdatedAt = activeContext, viper.GetString("admin_url"), viper.GetString("workspace"), time.Now().UTC()
+        for _, s := range spec.Services { st.Services[s.Name] = s }
+        for _, r := range spec.Routes {
+            if name := specRouteName(r); name != "" { st.Routes[name] = r }
+        }
+        var b []byte
+        var path string
+        if b, err = json.MarshalIndent(st, "", "  "); err == nil {
+            if path, err = lastAppliedPath(); err == nil {
+                if err = os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+                    err = writeTextFile(path, append(b, '\n'), 0o600)
+                }
+            }
+        }
+    }
+    if err != nil {
+        PrintWarn(cmd, "保存 last-applied 记录失败（下次 --three-way 将无法识别本次写入的字段）：%v", err)
+    }
+}
+
+// specRouteName 返回 route 在 Kong 中的名称（未命名时按 service/paths/methods 生成）
+func specRouteName(r applyRoute) string {
+    if r.Name == "" && r.Service != "" { return defaultRouteName(r.Service, r.Paths, r.Methods) }
+    return r.Name
+}
+
+// mergeOwned 返回 desired ∪ (current − last)：保留远程中 kongctl 从未写入的项，
+// 只移除上次由 kongctl 写入、本次已从文件中删除的项；fold 为 true 时忽略大小写（methods）
+func mergeOwned(desired, current, last []string, fold bool) []string {
+    has := func(list []string, v string) bool {
+        for _, s := range list {
+            if s == v || (fold && strings.EqualFold(s, v)) { return true }
+        }
+        return false
+    }
+    out := append([]string(nil), desired...)
+    for _, v := range current {
+        if !has(last, v) && !has(out, v) { out = append(out, v) }
+    }
+    return out
+}
+
+// mergeOwnedAnnotations 对注解按 key 做同样的合并：远程存在而上次未声明的 key 保留远程值
+func mergeOwnedAnnotations(desired map[string]string, curTags []string, last map[string]string) map[string]string {
+    if desired == nil { return nil }
+    _, cur := splitAnnotations(curTags)
+    out := map[string]string{}
+    for k, v := range cur {
+        if _, ok := last[k]; !ok { out[k] = v }
+    }
+    for k, v := range desired { out[k] = v }
+    return out
+}
+
+// resolveLastApplied 对远程已存在且有 last-applied 记录的资源做三方合并：
+// 集合字段（hosts/paths/methods/protocols/snis/tags）、headers 与注解中由其他工具添加的项被保留，
+// 标量字段仍以文件为准
+func resolveLastApplied(ctx context.Context, client *kong.Client, spec applySpec, st *lastAppliedState) (applySpec, error) {
+    out := spec
+    out.Services = append([]applyService(nil), spec.Services...)
+    for i := range out.Services {
+        s := &out.Services[i]
+        last, ok := st.Services[s.Name]
+        if !ok || s.Annotations == nil { continue }
+        cur, ok, err := client.GetService(ctx, s.Name)
+        if err != nil { return spec, err }
+        if !ok { continue }
+        s.Annotations = mergeOwnedAnnotations(s.Annotations, cur.Tags, last.Annotations)
+    }
+    out.Routes = append([]applyRoute(nil), spec.Routes...)
+    for i := range out.Routes {
+        r := &out.Routes[i]
+        name := specRouteName(*r)
+        last, ok := st.Routes[name]
+        if !ok { continue }
+        cur, ok, err := client.GetRoute(ctx, name)
+        if err != nil { return spec, err }
+        if !ok { continue }
+        r.Hosts = mergeOwned(r.Hosts, cur.Hosts, last.Hosts, false)
+        r.Paths = mergeOwned(r.Paths, cur.Paths, last.Paths, false)
+        r.Methods = mergeOwned(r.Methods, cur.Methods, last.Methods, true)
+        // 以下字段未声明时本就不受管理
+        if len(r.Protocols) > 0 { r.Protocols = mergeOwned(r.Protocols, cur.Protocols, last.Protocols, false) }
+        if len(r.Snis) > 0 { r.Snis = mergeOwned(r.Snis, cur.Snis, last.Snis, false) }
+        if len(r.Tags) > 0 {
+            if r.Annotations != nil {
+                // meta: 标签由下方的注解合并处理
+                plain, _ := splitAnnotations(cur.Tags)
+                r.Tags = mergeOwned(r.Tags, plain, last.Tags, false)
+            } else {
+                r.Tags = mergeOwned(r.Tags, cur.Tags, append(append([]string(nil), last.Tags...), annotationTagList(last.Annotations)...), false)
+            }
+        }
+        if len(r.Headers) > 0 {
+            // header 名不区分大小写，统一按 Kong 保存的小写形式比较
+            lastHeaders := kong.NormalizeHeaders(last.Headers)
+            merged := map[string][]string{}
+            for k, v := range cur.Headers {
+                if _, ok := lastHeaders[strings.ToLower(k)]; !ok { merged[strings.ToLower(k)] = v }
+            }
+            for k, v := range kong.NormalizeHeaders(r.Headers) { merged[k] = v }
+            r.Headers = merged
+        }
+        r.Annotations = mergeOwnedAnnotations(r.Annotations, cur.Tags, last.Annotations)
+    }
+    return out, nil
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "reflect"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    licenseFile string
+    licenseEnv  string
+)
+
+// licenseDefaultEnv 为未指定 --file/--env 时读取许可证的环境变量（与 Kong 自身读取的变量一致）
+const licenseDefaultEnv = "KONG_LICENSE_DATA"
+
+// licenseExpiryWarnDays 为提示许可证即将过期的剩余天数
+const licenseExpiryWarnDays = 30
+
+var licenseCmd = &cobra.Command{
+    Use:   "license",
+    Short: "管理企业版许可证（上传/轮换、查看到期时间）",
+    Long: `通过 /licenses 上传或替换企业版许可证，使用与配置管理相同的上下文与凭证，便于在流水线中自动轮换。
+许可证内容为 Kong 提供的 JSON 文件（{"license": {"payload": {...}, "signature": "..."}}），输出中 license_key 只显示末尾 4 位。`,
+}
+
+// licenseDoc 为许可证文件的结构
+type licenseDoc struct {
+    License struct {
+        Version   int    `json:"version"`
+        Signature string `json:"signature"`
+        Payload   struct {
+            AdminSeats     string `json:"admin_seats"`
+            Customer       string `json:"customer"`
+            Dataplanes     string `json:"dataplanes"`
+            CreationDate   string `json:"license_creation_date"`
+            ExpirationDate string `json:"license_expiration_date"`
+            LicenseKey     string `json:"license_key"`
+            Product        string `json:"product_subscription"`
+            SupportPlan    string `json:"support_plan"`
+        } `json:"payload"`
+    } `json:"license"`
+}
+
+// licenseSummary 为许可证的可展示信息（不含签名，license_key 打码）
+type licenseSummary struct {
+    ID         string `json:"id,omitempty"`
+    Customer   string `json:"customer"`
+    Product    string `json:"product"`
+    Support    string `json:"support_plan,omitempty"`
+    Dataplanes string `json:"dataplanes,omitempty"`
+    AdminSeats string `json:"admin_seats,omitempty"`
+    Created    string `json:"created"`
+    Expires    string `json:"expires"`
+    DaysLeft   int    `json:"days_left"`
+    Key        string `json:"license_key"`
+}
+
+// parseLicense 解析并校验许可证 JSON，返回其摘要
+func parseLicense(raw string) (licenseSummary, error) {
+    var doc licenseDoc
+    if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+        return licenseSummary{}, fmt.Errorf("许可证不是合法的 JSON：%w", err)
+    }
+    p := doc.License.Payload
+    if p.LicenseKey == "" || doc.License.Signature == "" {
+        return licenseSummary{}, fmt.Errorf("许可证缺少 license.payload.license_key 或 license.signature")
+    }
+    exp, err := time.Parse("2006-01-02", p.ExpirationDate)
+    if err != nil {
+        return licenseSummary{}, fmt.Errorf("无法解析许可证到期时间 %q：%w", p.ExpirationDate, err)
+    }
+    key := "****"
+    if len(p.LicenseKey) > 4 { key += p.LicenseKey[len(p.LicenseKey)-4:] }
+    return licenseSummary{
+        Customer: p.Customer, Product: p.Product, Support: p.SupportPlan, Dataplanes: p.Dataplanes, AdminSeats: p.AdminSeats,
+        Created: p.CreationDate, Expires: p.ExpirationDate, DaysLeft: int(time.Until(exp.Add(24*time.Hour)).Hours() / 24), Key: key,
+    }, nil
+}
+
+// sameLicense 按 JSON 语义比较两份许可证
+func sameLicense(a, b string) bool {
+    var x, y any
+    if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal([]byte(b), &y) != nil {
+        return strings.TrimSpace(a) == strings.TrimSpace(b)
+    }
+    return reflect.DeepEqual(x, y)
+}
+
+// licenseDiff 比较已有许可证与新许可证的摘要
+func licenseDiff(cur, want licenseSummary) string {
+    diff := ""
+    for _, f := range []struct{ name, from, to string }{
+        {"customer", cur.Customer, want.Customer}, {"product", cur.Product, want.Product}, {"dataplanes", cur.Dataplanes, want.Dataplanes},
+        {"expires", cur.Expires, want.Expires}, {"license_key", cur.Key, want.Key},
+    } {
+        if f.from != f.to { diff += fmt.Sprintf("%s: %s -> %s\n", f.name, f.from, f.to) }
+    }
+    if diff == "" { diff = "signature: （已变更）\n" }
+    return diff
+}
+
+// licenseStatus 返回到期状态说明
+func licenseStatus(s licenseSummary) string {
+    switch {
+    case s.DaysLeft < 0:
+        return "已过期"
+    case s.DaysLeft <= licenseExpiryWarnDays:
+        return fmt.Sprintf("%d 天后过期", s.DaysLeft)
+    }
+    return fmt.Sprintf("剩余 %d 天", s.DaysLeft)
+}
+
+// readLicense 按 --file、--env、KONG_LICENSE_DATA 的顺序读取许可证
+func readLicense() (string, string, error) {
+    switch {
+    case licenseFile != "" && licenseEnv != "":
+        return "", "", fmt.Errorf("--file 与 --env 只能指定一个")
+    case licenseFile != "":
+        b, err := os.ReadFile(expandPath(licenseFile))
+        if err != nil {
+            return "", "", fmt.Errorf("读取许可证失败：%w", err)
+        }
+        return strings.TrimSpace(string(b)), licenseFile, nil
+    }
+    name := licenseEnv
+    if name == "" { name = licenseDefaultEnv }
+    val, set := os.LookupEnv(name)
+    if !set || strings.TrimSpace(val) == "" {
+        return "", "", fmt.Errorf("环境变量 %s 未设置（或使用 --file 指定许可证文件）", name)
+    }
+    return strings.TrimSpace(val), "$" + name, nil
+}
+
+var licenseApplyCmd = &cobra.Command{
+    Use:   "apply",
+    Short: "上传或替换许可证（幂等：内容相同时不写入）",
+    Example: `kongctl license apply --file license.json
+KONG_LICENSE_DATA="$(cat license.json)" kongctl license apply --dry-run`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        raw, source, err := readLicense()
+        if err != nil {
+            return err
+        }
+        want, err := parseLicense(raw)
+        if err != nil {
+            return fmt.Errorf("%s：%w", source, err)
+        }
+        if want.DaysLeft < 0 {
+            return fmt.Errorf("许可证已于 %s 过期：%s", want.Expires, source)
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListLicenses(ctx)
+        if err != nil {
+            return err
+        }
+        var cur *kong.License
+        if len(list) > 0 { cur = &list[0] }
+        if cur != nil && sameLicense(cur.Payload, raw) {
+            PrintInfo(cmd, "许可证无变更，未写入（%s，%s）", want.Expires, licenseStatus(want))
+            return nil
+        }
+        if showDiff || dryRun {
+            PrintInfo(cmd, "%sDiff: License", emojiDiff)
+            if cur == nil {
+                cmd.Printf("%s\n", colorInfo(fmt.Sprintf("+ license: %s / %s，有效期至 %s（%s）", want.Customer, want.Product, want.Expires, want.Key)))
+            } else {
+                old, perr := parseLicense(cur.Payload)
+                if perr != nil { old = licenseSummary{Expires: "（无法解析）"} }
+                for _, l := range strings.Split(strings.TrimRight(licenseDiff(old, want), "\n"), "\n") { cmd.Printf("  %s\n", l) }
+            }
+        }
+        action := "create"
+        if cur != nil { action = "update" }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将%s许可证：%s", actionCN(action), source)
+            return nil
+        }
+        if cur != nil {
+            _, err = client.UpdateLicense(ctx, cur.ID, raw)
+        } else {
+            _, err = client.CreateLicense(ctx, raw)
+        }
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s许可证：%s / %s，有效期至 %s（%s）", actionCN(action), want.Customer, want.Product, want.Expires, licenseStatus(want))
+        if want.DaysLeft <= licenseExpiryWarnDays { PrintWarn(cmd, "新许可证将在 %d 天内过期，请尽快续期", want.DaysLeft) }
+        return nil
+    },
+}
+
+var licenseShowCmd = &cobra.Command{
+    Use:   "show",
+    Short: "查看当前许可证（客户、产品、到期时间；不显示签名）",
+    Example: `kongctl license show
+kongctl license show --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListLicenses(ctx)
+        if err != nil {
+            return err
+        }
+        out := make([]licenseSummary, 0, len(list))
+        for _, l := range list {
+            s, err := parseLicense(l.Payload)
+            if err != nil {
+                PrintWarn(cmd, "无法解析许可证 %s：%v", l.ID, err)
+                continue
+            }
+            s.ID = l.ID
+            out = append(out, s)
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(out, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(out) == 0 {
+            PrintInfo(cmd, "未上传许可证（开源版或未激活企业版功能），使用 kongctl license apply 上传")
+            return nil
+        }
+        for _, s := range out {
+            cmd.Printf("id:          %s\n", s.ID)
+            cmd.Printf("customer:    %s\n", s.Customer)
+            cmd.Printf("product:     %s\n", s.Product)
+            if s.Support != "" { cmd.Printf("support:     %s\n", s.Support) }
+            if s.Dataplanes != "" { cmd.Printf("dataplanes:  %s\n", s.Dataplanes) }
+            cmd.Printf("license_key: %s\n", s.Key)
+            cmd.Printf("expires:     %s（%s）\n", s.Expires, licenseStatus(s))
+            switch {
+            case s.DaysLeft < 0:
+                PrintWarn(cmd, "许可证已于 %s 过期", s.Expires)
+            case s.DaysLeft <= licenseExpiryWarnDays:
+                PrintWarn(cmd, "许可证将在 %d 天内过期，请使用 kongctl license apply 续期", s.DaysLeft)
+            }
+        }
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(licenseCmd)
+    licenseCmd.AddCommand(licenseApplyCmd, licenseShowCmd)
+    licenseApplyCmd.Flags().StringVar(&licenseFile, "file", "", "许可证文件（JSON）")
+    licenseApplyCmd.Flags().StringVar(&licenseEnv, "env", "", "从该环境变量读取许可证（默认 "+licenseDefaultEnv+"）")
+    licenseApplyCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    licenseApplyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+}
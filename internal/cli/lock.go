@@ -0,0 +1,67 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    applyNoLock      bool
+    applyForceUnlock bool
+)
+
+// applyLockName 为 apply 锁在网关上的 upstream 名称（每个 workspace 一把）
+const applyLockName = "kongctl-apply-lock"
+
+// applyLockStale 为锁的最长持有时间：持有者异常退出（未释放）时，超过该时间的锁会被接管
+const applyLockStale = time.Hour
+
+func lockOwner() string {
+    host, _ := os.Hostname()
+    if host == "" { host = "unknown" }
+    return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// acquireApplyLock 在实际变更前获取网关上的 apply 锁，另一个 kongctl 正在执行时立即失败，避免两次 apply 交错写入；
+// 锁使用独立的客户端，不计入本次 apply 的调用预算与写操作摘要。返回的函数用于释放锁
+func acquireApplyLock(cmd *cobra.Command, ctx context.Context, cfg kong.Config) (func(), error) {
+    cfg.MaxCalls = 0
+    client := kong.NewClient(cfg)
+    owner := lockOwner()
+    lk, ok, err := client.AcquireLock(ctx, applyLockName, owner)
+    if err != nil {
+        return nil, fmt.Errorf("获取 apply 锁失败（可加 --no-lock 跳过）：%w", err)
+    }
+    if !ok {
+        age := time.Since(lk.Since)
+        switch {
+        case applyForceUnlock:
+            PrintWarn(cmd, "--force-unlock：移除 %s 持有的 apply 锁（开始于 %s）", lk.Owner, lk.Since.Local().Format("2006-01-02 15:04:05"))
+        case !lk.Since.IsZero() && age > applyLockStale:
+            PrintWarn(cmd, "%s 持有的 apply 锁已超过 %s 未释放（开始于 %s），视为异常退出并接管", lk.Owner, applyLockStale, lk.Since.Local().Format("2006-01-02 15:04:05"))
+        default:
+            return nil, withCode("locked", "", fmt.Errorf("另一个 kongctl 正在对该网关执行变更（持有者 %s，开始于 %s），已中止以免交错写入", lk.Owner, lk.Since.Local().Format("2006-01-02 15:04:05")))
+        }
+        if err := client.ReleaseLock(ctx, lk); err != nil {
+            return nil, fmt.Errorf("移除旧的 apply 锁失败：%w", err)
+        }
+        if lk, ok, err = client.AcquireLock(ctx, applyLockName, owner); err != nil {
+            return nil, fmt.Errorf("获取 apply 锁失败（可加 --no-lock 跳过）：%w", err)
+        } else if !ok {
+            return nil, withCode("locked", "", fmt.Errorf("另一个 kongctl（%s）已抢先获取 apply 锁，已中止", lk.Owner))
+        }
+    }
+    return func() {
+        // 本次 apply 被中断（Ctrl+C、--deadline）时仍需释放锁
+        rctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+        defer cancel()
+        if err := client.ReleaseLock(rctx, lk); err != nil {
+            PrintWarn(cmd, "释放 apply 锁失败：%v（%s 后自动失效，或下次执行时加 --force-unlock）", err, applyLockStale)
+        }
+    }, nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+    "fmt"
+    "strings"
+)
+
+// apply --name-prefix/--name-suffix：同一份 spec 以不同前后缀多次部署到同一个 Kong（多租户、并行环境）
+var (
+    applyNamePrefix string
+    applyNameSuffix string
+)
+
+// renamed 为名称加上 --name-prefix/--name-suffix；空名称保持为空
+func renamed(name string) string {
+    if name == "" { return "" }
+    return applyNamePrefix + name + applyNameSuffix
+}
+
+// nameInScope 判断远程资源名称是否属于本次前后缀；--prune 只删除范围内的资源，避免误删其他租户的同类资源
+func nameInScope(name string) bool {
+    return strings.HasPrefix(name, applyNamePrefix) && strings.HasSuffix(name, applyNameSuffix) && len(name) >= len(applyNamePrefix)+len(applyNameSuffix)
+}
+
+// checkNameAffixes 校验前后缀只包含 Kong 名称允许的字符
+func checkNameAffixes() error {
+    for flag, v := range map[string]string{"--name-prefix": applyNamePrefix, "--name-suffix": applyNameSuffix} {
+        for _, r := range v {
+            if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-._~", r)) {
+                return withCode("usage", "", fmt.Errorf("%s 只能包含字母、数字与 -._~：%q", flag, v))
+            }
+        }
+    }
+    return nil
+}
+
+// renameSpec 为 spec 中的 upstream/service/route 名称加上前后缀，并同步改写相互引用；
+// 未命名 route 与简写派生的 service/upstream 先按原名称生成再加前后缀，保证各名称形如 <prefix><原名><suffix>。
+// consumers 不改名（username 与凭证全局唯一，需在 spec 中自行区分）
+func renameSpec(spec applySpec) applySpec {
+    if applyNamePrefix == "" && applyNameSuffix == "" { return spec }
+    out := spec
+    out.Upstreams = append([]applyUpstream(nil), spec.Upstreams...)
+    for i := range out.Upstreams { out.Upstreams[i].Name = renamed(out.Upstreams[i].Name) }
+    out.Services = append([]applyService(nil), spec.Services...)
+    for i := range out.Services {
+        s := &out.Services[i]
+        s.Name, s.Upstream = renamed(s.Name), renamed(s.Upstream)
+    }
+    out.Routes = append([]applyRoute(nil), spec.Routes...)
+    for i := range out.Routes {
+        r := &out.Routes[i]
+        name := specRouteName(*r)
+        if r.Service == "" && name != "" {
+            r.ServiceName, r.UpstreamName = autoBackendNames(*r, name)
+        }
+        r.Name, r.Service = renamed(name), renamed(r.Service)
+        r.ServiceName, r.UpstreamName = renamed(r.ServiceName), renamed(r.UpstreamName)
+    }
+    return out
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// diffNested 递归比较嵌套的映射与列表（headers、插件 config、凭证字段等），按点分路径逐项输出差异，
+// 例：config.minute: 10 -> 20；只在一侧存在的键记为 null。标量列表按集合比较（与 hosts/paths 一致），
+// 等长的对象列表逐项比较（路径为 field[i]），其余列表整体输出
+func diffNested(path string, cur, want any) string {
+    var sb strings.Builder
+    walkNested(&sb, path, canonicalJSON(cur), canonicalJSON(want))
+    return sb.String()
+}
+
+func walkNested(sb *strings.Builder, path string, cur, want any) {
+    cm, cok := cur.(map[string]any)
+    wm, wok := want.(map[string]any)
+    // 一侧缺失（null）时按空映射展开，新增/删除的键同样按点分路径输出
+    if cok && want == nil || wok && cur == nil { cok, wok = true, true }
+    if cok && wok {
+        keys := make([]string, 0, len(cm)+len(wm))
+        for k := range cm { keys = append(keys, k) }
+        for k := range wm {
+            if _, dup := cm[k]; !dup { keys = append(keys, k) }
+        }
+        sort.Strings(keys)
+        for _, k := range keys { walkNested(sb, joinPath(path, k), cm[k], wm[k]) }
+        return
+    }
+    cl, cok := cur.([]any)
+    wl, wok := want.([]any)
+    if cok && wok {
+        if scalarList(cl) && scalarList(wl) {
+            if !sliceSetEqual(flowStrings(cl), flowStrings(wl)) { fmt.Fprintf(sb, "%s: %s -> %s\n", path, flowValue(cur), flowValue(want)) }
+            return
+        }
+        if len(cl) == len(wl) {
+            for i := range cl { walkNested(sb, fmt.Sprintf("%s[%d]", path, i), cl[i], wl[i]) }
+            return
+        }
+    }
+    if flowValue(cur) != flowValue(want) { fmt.Fprintf(sb, "%s: %s -> %s\n", path, flowValue(cur), flowValue(want)) }
+}
+
+// canonicalJSON 经 JSON 往返统一取值类型（数字为 float64、结构体转为映射），空列表/空映射视为 null
+func canonicalJSON(v any) any {
+    b, err := json.Marshal(v)
+    if err != nil { return v }
+    var out any
+    if err := json.Unmarshal(b, &out); err != nil { return v }
+    return dropEmpty(out)
+}
+
+func dropEmpty(v any) any {
+    switch x := v.(type) {
+    case map[string]any:
+        if len(x) == 0 { return nil }
+        for k, e := range x { x[k] = dropEmpty(e) }
+    case []any:
+        if len(x) == 0 { return nil }
+        for i, e := range x { x[i] = dropEmpty(e) }
+    }
+    return v
+}
+
+func scalarList(l []any) bool {
+    for _, e := range l {
+        switch e.(type) {
+        case map[string]any, []any:
+            return false
+        }
+    }
+    return true
+}
+
+func flowStrings(l []any) []string {
+    out := make([]string, len(l))
+    for i, e := range l { out[i] = flowValue(e) }
+    return out
+}
+
+// flowValue 以 YAML flow 风格输出取值（[a, b]、{k: v}），便于差异文本被重新解析
+func flowValue(v any) string {
+    switch x := v.(type) {
+    case nil:
+        return "null"
+    case float64:
+        return strconv.FormatFloat(x, 'f', -1, 64)
+    case []any:
+        return "[" + strings.Join(flowStrings(x), ", ") + "]"
+    case map[string]any:
+        keys := make([]string, 0, len(x))
+        for k := range x { keys = append(keys, k) }
+        sort.Strings(keys)
+        parts := make([]string, len(keys))
+        for i, k := range keys { parts[i] = k + ": " + flowValue(x[k]) }
+        return "{" + strings.Join(parts, ", ") + "}"
+    }
+    return fmt.Sprint(v)
+}
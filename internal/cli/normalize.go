@@ -0,0 +1,69 @@
+package cli
+
+import (
+    "fmt"
+    "net/url"
+    "strconv"
+    "strings"
+
+    "kongctl/internal/kong"
+)
+
+// 比较前的规范化：Kong 保存时会改写部分取值（结尾的 /、默认端口、空与 null 的 path、方法与 host 的大小写），
+// 双方按同一规则规范化后再比较，--dry-run --diff 只报告真实的差异
+
+// defaultPort 返回协议的默认端口；未知协议返回 0
+func defaultPort(proto string) int {
+    switch strings.ToLower(proto) {
+    case "http", "ws", "grpc":
+        return 80
+    case "https", "wss", "grpcs":
+        return 443
+    }
+    return 0
+}
+
+// normalizePath 去除结尾的 /，根路径 / 与空路径（null）视为相同；正则路径（~ 前缀）保持原样
+func normalizePath(p string) string {
+    if strings.HasPrefix(p, "~") { return p }
+    return strings.TrimRight(p, "/")
+}
+
+func normalizePaths(ps []string) []string {
+    out := make([]string, len(ps))
+    for i, p := range ps { out[i] = normalizePath(p) }
+    return out
+}
+
+// toLower 用于比较 hosts、protocols 与 headers 取值（均不区分大小写）
+func toLower(xs []string) []string {
+    out := make([]string, len(xs))
+    for i, x := range xs { out[i] = strings.ToLower(x) }
+    return out
+}
+
+// normalizeURL 规范化 service url：scheme 与 host 小写、去掉默认端口、规范化 path；无法解析时原样返回
+func normalizeURL(raw string) string {
+    u, err := url.Parse(raw)
+    if err != nil || u.Scheme == "" || u.Host == "" { return raw }
+    scheme := strings.ToLower(u.Scheme)
+    host := strings.ToLower(u.Hostname())
+    if strings.Contains(host, ":") { host = "[" + host + "]" }
+    if p := u.Port(); p != "" && p != strconv.Itoa(defaultPort(scheme)) { host += ":" + p }
+    return scheme + "://" + host + normalizePath(u.EscapedPath())
+}
+
+// sameURL 判断两个 service url 规范化后是否相同
+func sameURL(a, b string) bool { return normalizeURL(a) == normalizeURL(b) }
+
+// serviceTargetDiff 比较 service 的 host/protocol/port/path 与期望值，返回差异文本（无差异时为空）
+func serviceTargetDiff(cur *kong.Service, host, proto string, port int, path string) string {
+    diff := ""
+    if !strings.EqualFold(cur.Host, host) { diff += fmt.Sprintf("host: %s -> %s\n", cur.Host, host) }
+    if !strings.EqualFold(cur.Protocol, proto) { diff += fmt.Sprintf("protocol: %s -> %s\n", cur.Protocol, proto) }
+    curPort := cur.Port
+    if curPort == 0 { curPort = defaultPort(cur.Protocol) }
+    if curPort != port { diff += fmt.Sprintf("port: %d -> %d\n", cur.Port, port) }
+    if normalizePath(cur.Path) != normalizePath(path) { diff += fmt.Sprintf("path: %s -> %s\n", cur.Path, path) }
+    return diff
+}
@@ -0,0 +1,227 @@
+package cli
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// applyOverlays 为 apply --overlay：在基础 spec 上依次合并的环境补丁（名称或文件路径）
+var applyOverlays []string
+
+// overlayMergeKeys 为按 key 合并（而非整体替换）的列表字段
+var overlayMergeKeys = map[string]string{"targets": "target"}
+
+// overlayPath 将 --overlay 的取值解析为文件路径：含路径分隔符或扩展名时按路径处理（相对于当前目录），
+// 否则为 spec 所在目录（-f 为目录时即该目录）下的 overlays/<name>.yaml（也接受 .yml/.json）
+func overlayPath(name, specFile string) (string, error) {
+    if strings.ContainsAny(name, `/\`) || filepath.Ext(name) != "" {
+        return expandPath(name), nil
+    }
+    dir := "."
+    if isSpecDir(specFile) {
+        dir = expandPath(specFile)
+    } else if specFile != "-" {
+        dir = filepath.Dir(expandPath(specFile))
+    }
+    for _, ext := range []string{".yaml", ".yml", ".json"} {
+        p := filepath.Join(dir, "overlays", name+ext)
+        if _, err := os.Stat(p); err == nil { return p, nil }
+    }
+    return "", fmt.Errorf("未找到 overlay %s（%s）", name, filepath.Join(dir, "overlays", name+".yaml"))
+}
+
+// resolveOverlays 按顺序将 --overlay 指定的补丁合并到 spec：
+//   - 补丁与 spec 结构相同，upstreams/services/routes/consumer_groups/consumers/certificates/vaults/key_sets 中的项按名称
+//     （consumer 按 username/custom_id，certificate 按第一个 SNI，vault 按 prefix）匹配；
+//   - 匹配到的资源只覆盖补丁中出现的字段，映射字段（headers/annotations 等）逐 key 合并，值为 null 时删除该字段，
+//     targets 按 target 地址合并，其余列表整体替换；
+//   - $patch: delete 删除该资源，未匹配到的项作为新资源追加；common_tags 追加到基础 spec
+func resolveOverlays(spec applySpec, specFile string) (applySpec, error) {
+    for _, name := range applyOverlays {
+        path, err := overlayPath(name, specFile)
+        if err != nil {
+            return applySpec{}, err
+        }
+        content, err := os.ReadFile(path)
+        if err != nil {
+            return applySpec{}, fmt.Errorf("读取 overlay 失败：%w", err)
+        }
+        content = normalizeText(content)
+        if renderEnabled() {
+            if content, err = renderSpecTemplate(content, path); err != nil {
+                return applySpec{}, err
+            }
+        }
+        if spec, err = applyOverlay(spec, content); err != nil {
+            return applySpec{}, fmt.Errorf("合并 overlay %s 失败：%w", path, err)
+        }
+        // 被补丁修改或新增的资源以 overlay 文件作为来源（用于冲突报告），补丁中的证书路径相对于 overlay 文件
+        resolveCertificatePaths(&spec, path)
+        resolveKeySetPaths(&spec, path)
+        tagSource(&spec, path)
+    }
+    return spec, nil
+}
+
+func applyOverlay(spec applySpec, content []byte) (applySpec, error) {
+    var patch map[string]any
+    if err := yaml.Unmarshal(content, &patch); err != nil {
+        return spec, err
+    }
+    for k := range patch {
+        switch k {
+        case "upstreams", "services", "routes", "consumer_groups", "consumers", "certificates", "vaults", "key_sets", "common_tags":
+        default:
+            return spec, fmt.Errorf("不支持的顶层字段 %s（overlay 只能包含 upstreams/services/routes/consumer_groups/consumers/certificates/vaults/key_sets/common_tags）", k)
+        }
+    }
+    var err error
+    if spec.Upstreams, err = overlayList(spec.Upstreams, patch["upstreams"], "upstreams", func(u applyUpstream) string { return u.Name }, nameKey("name")); err != nil {
+        return spec, err
+    }
+    if spec.Services, err = overlayList(spec.Services, patch["services"], "services", func(s applyService) string { return s.Name }, nameKey("name")); err != nil {
+        return spec, err
+    }
+    if spec.Routes, err = overlayList(spec.Routes, patch["routes"], "routes", specRouteName, nameKey("name")); err != nil {
+        return spec, err
+    }
+    if spec.ConsumerGroups, err = overlayList(spec.ConsumerGroups, patch["consumer_groups"], "consumer_groups", func(g applyConsumerGroup) string { return g.Name }, nameKey("name")); err != nil {
+        return spec, err
+    }
+    if spec.Consumers, err = overlayList(spec.Consumers, patch["consumers"], "consumers", applyConsumer.key, func(m map[string]any) string {
+        if u := nameKey("username")(m); u != "" { return u }
+        return nameKey("custom_id")(m)
+    }); err != nil {
+        return spec, err
+    }
+    if spec.Certificates, err = overlayList(spec.Certificates, patch["certificates"], "certificates", applyCertificate.key, func(m map[string]any) string {
+        if snis, ok := m["snis"].([]any); ok && len(snis) > 0 { return fmt.Sprint(snis[0]) }
+        return ""
+    }); err != nil {
+        return spec, err
+    }
+    if spec.Vaults, err = overlayList(spec.Vaults, patch["vaults"], "vaults", func(v applyVault) string { return v.Prefix }, nameKey("prefix")); err != nil {
+        return spec, err
+    }
+    if spec.KeySets, err = overlayList(spec.KeySets, patch["key_sets"], "key_sets", func(ks applyKeySet) string { return ks.Name }, nameKey("name")); err != nil {
+        return spec, err
+    }
+    if tags, ok := patch["common_tags"].([]any); ok {
+        for _, t := range tags {
+            if s := fmt.Sprint(t); !sliceContains(spec.CommonTags, s) { spec.CommonTags = append(spec.CommonTags, s) }
+        }
+    }
+    return spec, nil
+}
+
+func nameKey(field string) func(map[string]any) string {
+    return func(m map[string]any) string {
+        if v, ok := m[field]; ok && v != nil { return fmt.Sprint(v) }
+        return ""
+    }
+}
+
+// overlayList 将补丁列表 raw 合并到 base；key/patchKey 分别取基础资源与补丁项的名称
+func overlayList[T any](base []T, raw any, kind string, key func(T) string, patchKey func(map[string]any) string) ([]T, error) {
+    if raw == nil { return base, nil }
+    items, ok := raw.([]any)
+    if !ok { return nil, fmt.Errorf("%s 必须为列表", kind) }
+    out := append([]T(nil), base...)
+    for i, it := range items {
+        p, ok := it.(map[string]any)
+        if !ok { return nil, fmt.Errorf("%s[%d] 必须为对象", kind, i) }
+        name := patchKey(p)
+        if name == "" { return nil, fmt.Errorf("%s[%d] 缺少名称，无法与基础 spec 匹配", kind, i) }
+        idx := -1
+        for j := range out {
+            if key(out[j]) == name { idx = j; break }
+        }
+        if op, _ := p["$patch"].(string); op != "" {
+            if op != "delete" { return nil, fmt.Errorf("%s[%s]：不支持的 $patch: %s（仅支持 delete）", kind, name, op) }
+            if idx < 0 { return nil, fmt.Errorf("%s[%s]：$patch: delete 未匹配到基础 spec 中的资源", kind, name) }
+            out = append(out[:idx], out[idx+1:]...)
+            continue
+        }
+        var cur map[string]any
+        if idx >= 0 {
+            b, err := yaml.Marshal(out[idx])
+            if err != nil { return nil, err }
+            if err := yaml.Unmarshal(b, &cur); err != nil { return nil, err }
+        }
+        merged, err := decodeStrict[T](mergeOverlay(cur, p))
+        if err != nil { return nil, fmt.Errorf("%s[%s]：%w", kind, name, err) }
+        if idx >= 0 {
+            out[idx] = merged
+        } else {
+            out = append(out, merged)
+        }
+    }
+    return out, nil
+}
+
+// mergeOverlay 将补丁 src 深度合并到 dst：映射逐 key 合并，null 删除字段，其余值整体替换
+func mergeOverlay(dst, src map[string]any) map[string]any {
+    out := map[string]any{}
+    for k, v := range dst { out[k] = v }
+    for k, v := range src {
+        if v == nil {
+            delete(out, k)
+            continue
+        }
+        if sm, ok := v.(map[string]any); ok {
+            if dm, ok := out[k].(map[string]any); ok {
+                out[k] = mergeOverlay(dm, sm)
+                continue
+            }
+        }
+        if field, ok := overlayMergeKeys[k]; ok {
+            if sl, ok := v.([]any); ok {
+                dl, _ := out[k].([]any)
+                out[k] = mergeKeyedList(dl, sl, field)
+                continue
+            }
+        }
+        out[k] = v
+    }
+    return out
+}
+
+// mergeKeyedList 按 field 合并对象列表：同 key 的项逐字段合并，$patch: delete 删除，其余追加
+func mergeKeyedList(dst, src []any, field string) []any {
+    out := append([]any(nil), dst...)
+    for _, it := range src {
+        sm, ok := it.(map[string]any)
+        if !ok { out = append(out, it); continue }
+        idx := -1
+        for j, d := range out {
+            if dm, ok := d.(map[string]any); ok && fmt.Sprint(dm[field]) == fmt.Sprint(sm[field]) { idx = j; break }
+        }
+        del := sm["$patch"] == "delete"
+        switch {
+        case idx >= 0 && del:
+            out = append(out[:idx], out[idx+1:]...)
+        case del:
+        case idx >= 0:
+            out[idx] = mergeOverlay(out[idx].(map[string]any), sm)
+        default:
+            out = append(out, sm)
+        }
+    }
+    return out
+}
+
+// decodeStrict 将合并结果解码为资源结构，拒绝未知字段（补丁中的拼写错误）
+func decodeStrict[T any](m map[string]any) (T, error) {
+    var v T
+    b, err := yaml.Marshal(m)
+    if err != nil { return v, err }
+    dec := yaml.NewDecoder(bytes.NewReader(b))
+    dec.KnownFields(true)
+    err = dec.Decode(&v)
+    return v, err
+}
@@ -0,0 +1,266 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// deprecationPlugin 用于在旧路径别名的响应中追加弃用提示头
+const deprecationPlugin = "response-transformer"
+
+// aliasAnnotation 标记 paths move --alias 创建的旧路径别名路由（meta:alias-of=<原路由>），再次迁移时跳过
+const aliasAnnotation = "alias-of"
+
+var (
+    pathsService string
+    pathsFrom    string
+    pathsTo      string
+    pathsAlias   bool
+)
+
+// pathMove 为单个 route 的路径迁移：Edit 修改原路由 paths，Alias 为保留旧路径的别名路由（可为空）
+type pathMove struct {
+    Edit  bulkEdit
+    Alias *kong.Route
+}
+
+// movePath 将以 from 为前缀的路径改为 to 前缀；正则路径（~ 开头）同样按前缀替换
+func movePath(p, from, to string) (string, bool) {
+    re := strings.HasPrefix(p, "~")
+    body := strings.TrimPrefix(p, "~")
+    if body != from && !strings.HasPrefix(body, strings.TrimSuffix(from, "/")+"/") {
+        return p, false
+    }
+    moved := strings.TrimSuffix(to, "/") + strings.TrimPrefix(body, strings.TrimSuffix(from, "/"))
+    if moved == "" { moved = "/" }
+    if re { moved = "~" + moved }
+    return moved, true
+}
+
+// applyPathPrefix 为 apply --path-prefix：为 spec 中所有 route 的 paths 加上统一前缀，同一 spec 可挂载到不同的基础路径
+var applyPathPrefix string
+
+// checkPathPrefix 校验 --path-prefix：以 / 开头，只包含路径中的普通字符（正则路径同样按字面前缀拼接）
+func checkPathPrefix() error {
+    if applyPathPrefix == "" { return nil }
+    if !strings.HasPrefix(applyPathPrefix, "/") || applyPathPrefix == "/" {
+        return withCode("usage", "", fmt.Errorf("--path-prefix 必须以 / 开头且不能只有 /：%q", applyPathPrefix))
+    }
+    for _, r := range applyPathPrefix {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("/-_~", r)) {
+            return withCode("usage", "", fmt.Errorf("--path-prefix 只能包含字母、数字与 /-_~：%q", applyPathPrefix))
+        }
+    }
+    return nil
+}
+
+// prefixSpecPaths 为各 route 的 paths 加上 --path-prefix（/ 变为前缀本身）；未声明 paths 的 route 不变，
+// 未命名 route 按加前缀后的 paths 生成名称。
+// strip_path 为 true（默认）时 Kong 剥离整个匹配部分，上游收到的路径与加前缀前一致；
+// strip_path: false 的 route 上游会收到带前缀的路径，需上游同时支持，这里给出提示
+func prefixSpecPaths(cmd *cobra.Command, spec applySpec) applySpec {
+    if applyPathPrefix == "" { return spec }
+    prefix := strings.TrimSuffix(applyPathPrefix, "/")
+    out := spec
+    out.Routes = append([]applyRoute(nil), spec.Routes...)
+    var unstripped []string
+    for i := range out.Routes {
+        r := &out.Routes[i]
+        if len(r.Tests) > 0 {
+            tests := append([]routeTest(nil), r.Tests...)
+            for j := range tests {
+                if tests[j].Path != "" { tests[j].Path, _ = movePath(tests[j].Path, "/", prefix+"/") }
+            }
+            r.Tests = tests
+        }
+        if len(r.Paths) == 0 { continue }
+        paths := make([]string, len(r.Paths))
+        for j, p := range r.Paths {
+            if p == "/" || p == "~/" { paths[j] = strings.TrimSuffix(p, "/") + prefix; continue }
+            paths[j], _ = movePath(p, "/", prefix+"/")
+        }
+        r.Paths = paths
+        if r.StripPath != nil && !*r.StripPath { unstripped = append(unstripped, specRouteName(*r)) }
+    }
+    if len(unstripped) > 0 {
+        PrintWarn(cmd, "--path-prefix：以下 route 设置了 strip_path: false，上游将收到带 %s 前缀的路径：%s", prefix, strings.Join(unstripped, ", "))
+    }
+    return out
+}
+
+// deprecationHeaders 返回旧路径别名响应中追加的弃用提示头（response-transformer 的 name:value 格式）
+func deprecationHeaders() []string {
+    return []string{"Deprecation:true", fmt.Sprintf("Link:<%s>; rel=\"successor-version\"", pathsTo)}
+}
+
+// planPathMove 计算 service 下各路由的路径迁移计划
+func planPathMove(routes []kong.Route, svcID string) []pathMove {
+    sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+    var out []pathMove
+    for _, r := range routes {
+        if r.Service.ID != svcID { continue }
+        if _, ann := splitAnnotations(r.Tags); ann[aliasAnnotation] != "" { continue }
+        var paths, old []string
+        for _, p := range r.Paths {
+            np, ok := movePath(p, pathsFrom, pathsTo)
+            if ok { old = append(old, p) }
+            if !sliceContains(paths, np) { paths = append(paths, np) }
+        }
+        if len(old) == 0 { continue }
+        m := pathMove{Edit: bulkEdit{Kind: "Route", Name: r.Name, Key: r.Name, Payload: map[string]any{"paths": paths}}}
+        if m.Edit.Key == "" { m.Edit.Key, m.Edit.Name = r.ID, r.ID }
+        m.Edit.Diff = diffSlice("paths", r.Paths, paths)
+        if pathsAlias {
+            // 别名路由不在 apply 文件中声明，去掉 managed-by 标签以免被 apply --prune 删除
+            tags := []string{}
+            for _, t := range r.Tags {
+                if t != managedByTag() { tags = append(tags, t) }
+            }
+            al := kong.Route{
+                Name:         m.Edit.Name + "-alias",
+                Hosts:        r.Hosts,
+                Paths:        old,
+                Methods:      r.Methods,
+                Protocols:    r.Protocols,
+                Headers:      r.Headers,
+                Snis:         r.Snis,
+                StripPath:    r.StripPath,
+                PreserveHost: r.PreserveHost,
+                PathHandling: r.PathHandling,
+                Tags:         withAnnotations(tags, map[string]string{aliasAnnotation: m.Edit.Name}),
+            }
+            al.Service.ID = svcID
+            m.Alias = &al
+        }
+        out = append(out, m)
+    }
+    return out
+}
+
+var pathsCmd = &cobra.Command{
+    Use:   "paths",
+    Short: "路由路径工具（版本前缀迁移等）",
+}
+
+var pathsMoveCmd = &cobra.Command{
+    Use:   "move",
+    Short: "将 Service 下 routes 的路径前缀从 --from 改为 --to（API 版本升级），可保留旧路径别名",
+    Long: `找出 --service 下 paths 以 --from 为前缀的 routes（含 ~ 开头的正则路径），将前缀改为 --to（先预览计划）。
+--alias 为每个路由创建 <路由名>-alias 别名路由，继续以旧路径转发到同一 Service，并通过
+response-transformer 插件在响应中追加 Deprecation 与 Link（successor-version）头，提示调用方迁移。
+别名路由先于原路由修改创建，迁移过程中旧路径不会中断。production 上下文执行前需确认。`,
+    Example: `# 预览：user-service 从 /v1 升级到 /v2
+kongctl paths move --service user-service --from /v1 --to /v2 --dry-run
+
+# 保留 /v1 别名并返回弃用提示头
+kongctl paths move --service user-service --from /v1 --to /v2 --alias`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if pathsService == "" {
+            return withCode("usage", "", fmt.Errorf("必须通过 --service 指定 Service"))
+        }
+        pathsFrom, pathsTo = strings.TrimSpace(pathsFrom), strings.TrimSpace(pathsTo)
+        if !strings.HasPrefix(pathsFrom, "/") || !strings.HasPrefix(pathsTo, "/") {
+            return withCode("usage", "", fmt.Errorf("--from 与 --to 必须为以 / 开头的路径前缀，例：--from /v1 --to /v2"))
+        }
+        if strings.TrimSuffix(pathsFrom, "/") == strings.TrimSuffix(pathsTo, "/") {
+            return withCode("usage", "", fmt.Errorf("--from 与 --to 不能相同：%s", pathsFrom))
+        }
+
+        cfg, err := clientConfig(30 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        svc, ok, err := client.GetService(ctx, pathsService)
+        if err != nil {
+            return err
+        } else if !ok {
+            return withCode("not_found", "", fmt.Errorf("Service 不存在：%s", pathsService))
+        }
+        rts, err := client.ListRoutes(ctx)
+        if err != nil {
+            return fmt.Errorf("列出 routes 失败：%w", err)
+        }
+        plan := planPathMove(rts, svc.ID)
+        if outputJSON() && dryRun {
+            items := []aplan.Change{}
+            for _, m := range plan {
+                if m.Alias != nil {
+                    items = append(items, aplan.Change{Kind: "Route", Name: m.Alias.Name, Action: "create", Diff: fmt.Sprintf("alias: %s (deprecated -> %s)\n", strings.Join(m.Alias.Paths, ","), pathsTo)})
+                }
+                items = append(items, aplan.Change{Kind: m.Edit.Kind, Name: m.Edit.Name, Action: "update", Diff: m.Edit.Diff})
+            }
+            b, _ := json.MarshalIndent(map[string]any{"service": pathsService, "from": pathsFrom, "to": pathsTo, "items": items}, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(plan) == 0 {
+            PrintInfo(cmd, "Service %s 下没有以 %s 为前缀的路由路径，无需迁移", pathsService, pathsFrom)
+            return nil
+        }
+        cmd.Println(colorInfo(fmt.Sprintf("%s%s路径迁移计划：Service %s：%s -> %s，涉及 %d 个 routes", emojiDiff, contextBanner(), pathsService, pathsFrom, pathsTo, len(plan))))
+        for _, m := range plan {
+            cmd.Printf("  Route %s\n", m.Edit.Name)
+            for _, line := range strings.Split(strings.TrimRight(m.Edit.Diff, "\n"), "\n") {
+                cmd.Printf("    %s\n", line)
+            }
+            if m.Alias != nil {
+                cmd.Printf("    %s\n", colorSuccess(fmt.Sprintf("+ Route %s：%s（响应追加 Deprecation 头，successor-version=%s）", m.Alias.Name, strings.Join(m.Alias.Paths, ","), pathsTo)))
+            }
+        }
+        if dryRun {
+            cmd.Println("[dry-run] 以上为计划操作（未实际变更）" + emojiSuccess)
+            if applyDetailedExit { exitStatus = exitChanges }
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("paths move %s：%s -> %s（%d 个 routes）", pathsService, pathsFrom, pathsTo, len(plan))); err != nil {
+            return err
+        }
+        aliases := 0
+        for _, m := range plan {
+            // 先创建别名路由，再修改原路由，避免旧路径出现无路由可匹配的窗口
+            if m.Alias != nil {
+                if _, _, err := client.CreateOrUpdateRoute(ctx, *m.Alias); err != nil {
+                    return fmt.Errorf("创建别名路由 %s 失败：%w", m.Alias.Name, err)
+                }
+                enabled := true
+                conf := map[string]any{"add": map[string]any{"headers": deprecationHeaders()}}
+                if _, _, err := client.CreateOrUpdateRoutePlugin(ctx, m.Alias.Name, kong.Plugin{Name: deprecationPlugin, Config: conf, Enabled: &enabled}); err != nil {
+                    return fmt.Errorf("配置 %s 插件失败：Route %s：%w", deprecationPlugin, m.Alias.Name, err)
+                }
+                aliases++
+            }
+            if _, err := client.PatchRoute(ctx, m.Edit.Key, m.Edit.Payload); err != nil {
+                return fmt.Errorf("修改 Route %s 失败：%w", m.Edit.Name, err)
+            }
+        }
+        if aliases > 0 {
+            PrintSuccess(cmd, "已将 Service %s 的 %d 个 routes 从 %s 迁移到 %s，并保留 %d 个旧路径别名", pathsService, len(plan), pathsFrom, pathsTo, aliases)
+        } else {
+            PrintSuccess(cmd, "已将 Service %s 的 %d 个 routes 从 %s 迁移到 %s", pathsService, len(plan), pathsFrom, pathsTo)
+        }
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(pathsCmd)
+    pathsCmd.AddCommand(pathsMoveCmd)
+    pathsMoveCmd.Flags().StringVar(&pathsService, "service", "", "Service 名称或 id，例：--service user-service")
+    pathsMoveCmd.Flags().StringVar(&pathsFrom, "from", "", "旧路径前缀，例：--from /v1")
+    pathsMoveCmd.Flags().StringVar(&pathsTo, "to", "", "新路径前缀，例：--to /v2")
+    pathsMoveCmd.Flags().BoolVar(&pathsAlias, "alias", false, "保留旧路径别名路由，并在响应中追加 Deprecation/Link 弃用提示头")
+    pathsMoveCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅预览迁移计划，不实际变更")
+    pathsMoveCmd.Flags().BoolVar(&applyDetailedExit, "detailed-exitcode", false, "配合 --dry-run：存在待迁移路由时以退出码 2 结束")
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// planFileVersion 为 apply --out 输出的计划文件格式版本
+const planFileVersion = 1
+
+var (
+    applyPlanOut  string
+    applyPlanFile string
+)
+
+// planFile 为 apply --dry-run --out 保存的计划：展开后的 spec、执行选项，以及规划时远程资源的指纹
+type planFile struct {
+    Version      int               `json:"kongctl_plan"`
+    CreatedAt    time.Time         `json:"created_at"`
+    Context      string            `json:"context,omitempty"`
+    AdminURL     string            `json:"admin_url"`
+    Overwrite    bool              `json:"overwrite"`
+    Prune        bool              `json:"prune"`
+    PruneTargets bool              `json:"prune_targets,omitempty"`
+    IgnoreFields []string          `json:"ignore_fields,omitempty"`
+    ThreeWay     bool              `json:"three_way,omitempty"`
+    AddTags      []string          `json:"add_tags,omitempty"`
+    NamePrefix   string            `json:"name_prefix,omitempty"`
+    NameSuffix   string            `json:"name_suffix,omitempty"`
+    Spec         applySpec         `json:"spec"`
+    Plan         aplan.Plan        `json:"plan"`
+    Fingerprints map[string]string `json:"fingerprints"`
+}
+
+// absentFingerprint 表示规划时远程资源不存在
+const absentFingerprint = "absent"
+
+func fingerprint(v any) string {
+    b, _ := json.Marshal(v)
+    sum := sha256.Sum256(b)
+    return hex.EncodeToString(sum[:8])
+}
+
+// remoteFingerprint 计算计划项对应远程资源的指纹；资源不存在时返回 absentFingerprint
+func remoteFingerprint(ctx context.Context, client *kong.Client, ch aplan.Change) (string, error) {
+    var (
+        obj any
+        ok  bool
+        err error
+    )
+    switch ch.Kind {
+    case "Upstream":
+        obj, ok, err = client.GetUpstream(ctx, ch.Name)
+    case "Service":
+        obj, ok, err = client.GetService(ctx, ch.Name)
+    case "Route":
+        obj, ok, err = client.GetRoute(ctx, ch.Name)
+    case "Consumer":
+        obj, ok, err = client.GetConsumer(ctx, ch.Name)
+    case "ConsumerGroup":
+        // 组上插件的配置同样属于计划内容，一并计算
+        var g *kong.ConsumerGroup
+        if g, ok, err = client.GetConsumerGroup(ctx, ch.Name); err == nil && ok {
+            plugins, perr := client.ListConsumerGroupPlugins(ctx, g.ID)
+            if perr != nil { return "", perr }
+            for i := range plugins { plugins[i].ID = "" }
+            sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+            obj = map[string]any{"group": g, "plugins": plugins}
+        }
+    case "Certificate":
+        obj, ok, err = client.FindCertificateBySNI(ctx, ch.Name)
+    case "Vault":
+        obj, ok, err = client.GetVault(ctx, ch.Name)
+    case "KeySet":
+        obj, ok, err = client.GetKeySet(ctx, ch.Name)
+    case "Key":
+        set, kid, _ := strings.Cut(ch.Name, "/")
+        obj, ok, err = client.FindKey(ctx, set, kid)
+    case "Target":
+        up, target, _ := strings.Cut(ch.Name, "/")
+        list, lerr := client.ListTargets(ctx, up)
+        if lerr != nil {
+            var apiErr *kong.APIError
+            if errors.As(lerr, &apiErr) && apiErr.Status == http.StatusNotFound { return absentFingerprint, nil }
+            return "", lerr
+        }
+        for _, t := range list {
+            if t.Target == target { obj, ok = kong.Target{Target: t.Target, Weight: t.Weight, Tags: t.Tags}, true }
+        }
+    case "Credential":
+        // 凭证标识可能打码，按 consumer + 凭证类型整体计算
+        consumer, rest, _ := strings.Cut(ch.Name, "/")
+        kind, _, _ := strings.Cut(rest, ":")
+        if _, exists, gerr := client.GetConsumer(ctx, consumer); gerr != nil || !exists {
+            return absentFingerprint, gerr
+        }
+        list, lerr := client.ListCredentials(ctx, consumer, kind)
+        if lerr != nil { return "", lerr }
+        for _, c := range list { delete(c, "created_at"); delete(c, "updated_at") }
+        obj, ok = list, true
+    default:
+        return "", fmt.Errorf("计划文件不支持的资源类型：%s", ch.Kind)
+    }
+    if err != nil {
+        return "", err
+    }
+    if !ok {
+        return absentFingerprint, nil
+    }
+    return fingerprint(obj), nil
+}
+
+func planKey(ch aplan.Change) string { return ch.Kind + "/" + ch.Name }
+
+// collectFingerprints 为计划中的每个资源记录远程指纹
+func collectFingerprints(ctx context.Context, client *kong.Client, plan aplan.Plan) (map[string]string, error) {
+    out := map[string]string{}
+    for _, ch := range plan.Items {
+        k := planKey(ch)
+        if _, done := out[k]; done { continue }
+        fp, err := remoteFingerprint(ctx, client, ch)
+        if err != nil {
+            return nil, fmt.Errorf("读取 %s 的远程状态失败：%w", k, err)
+        }
+        out[k] = fp
+    }
+    return out, nil
+}
+
+// writePlanFile 保存计划文件
+func writePlanFile(ctx context.Context, client *kong.Client, path string, spec applySpec, plan aplan.Plan) error {
+    fps, err := collectFingerprints(ctx, client, plan)
+    if err != nil {
+        return err
+    }
+    spec.Include = nil
+    pf := planFile{
+        Version: planFileVersion, CreatedAt: time.Now().UTC(), Context: activeContext, AdminURL: viper.GetString("admin_url"),
+        Overwrite: applyOverwrite, Prune: applyPrune, PruneTargets: applyPruneTargets, IgnoreFields: applyIgnoreFields, ThreeWay: applyThreeWay, AddTags: applyAddTags, NamePrefix: applyNamePrefix, NameSuffix: applyNameSuffix, Spec: spec, Plan: plan, Fingerprints: fps,
+    }
+    b, err := json.MarshalIndent(pf, "", "  ")
+    if err != nil {
+        return err
+    }
+    if err := writeTextFile(expandPath(path), append(b, '\n'), 0o600); err != nil {
+        return fmt.Errorf("写入计划文件失败：%w", err)
+    }
+    return nil
+}
+
+// applyOutputPlan 为 apply --dry-run --output json/github/gitlab 待输出的计划
+var applyOutputPlan aplan.Plan
+
+// planJSON 为 apply --dry-run --output json 输出的计划：供 CI 在合并请求中发布计划评论
+type planJSON struct {
+    Context   string         `json:"context,omitempty"`
+    AdminURL  string         `json:"admin_url"`
+    Overwrite bool           `json:"overwrite"`
+    Summary   map[string]int `json:"summary"` // 各动作（create/update/delete/none）的数量
+    Pending   int            `json:"pending"` // 实际会执行的变更数（未启用 --overwrite 时 update 被跳过，不计入）
+    Items     []planJSONItem `json:"items"`
+}
+
+type planJSONItem struct {
+    aplan.Change
+    Fields []aplan.FieldDiff `json:"fields,omitempty"`
+}
+
+// silencePlanOutput 关闭颜色并丢弃命令的提示输出，返回计划 JSON 的写入目标（标准输出）与恢复函数
+func silencePlanOutput(cmd *cobra.Command) (io.Writer, func()) {
+    out, prev, noColor := cmd.OutOrStdout(), cmd.OutOrStderr(), viper.GetBool("no_color")
+    cmd.SetOut(io.Discard)
+    viper.Set("no_color", true)
+    return out, func() {
+        cmd.SetOut(prev)
+        viper.Set("no_color", noColor)
+    }
+}
+
+// writePlanJSON 将计划写为 JSON；Diff 为纯文本，并附带解析出的字段级差异
+func writePlanJSON(w io.Writer, plan aplan.Plan) error {
+    pj := planJSON{Context: activeContext, AdminURL: viper.GetString("admin_url"), Overwrite: applyOverwrite, Summary: map[string]int{"create": 0, "update": 0, "delete": 0, "none": 0}, Items: []planJSONItem{}}
+    pj.Pending, _ = pendingChanges(plan)
+    for _, it := range plan.Items {
+        pj.Summary[it.Action]++
+        pj.Items = append(pj.Items, planJSONItem{Change: it, Fields: it.Fields()})
+    }
+    // diff 中的 "->" 原样输出，便于直接贴到评论中
+    enc := json.NewEncoder(w)
+    enc.SetEscapeHTML(false)
+    enc.SetIndent("", "  ")
+    return enc.Encode(pj)
+}
+
+// loadPlanFile 读取计划文件，并确认其目标与当前上下文一致
+func loadPlanFile(path string) (*planFile, error) {
+    b, err := os.ReadFile(expandPath(path))
+    if err != nil {
+        return nil, fmt.Errorf("读取计划文件失败：%w", err)
+    }
+    var pf planFile
+    if err := json.Unmarshal(b, &pf); err != nil {
+        return nil, fmt.Errorf("解析计划文件失败：%w", err)
+    }
+    if pf.Version != planFileVersion {
+        return nil, fmt.Errorf("不支持的计划文件版本：%d（当前支持 %d）", pf.Version, planFileVersion)
+    }
+    if cur := viper.GetString("admin_url"); pf.AdminURL != cur || pf.Context != activeContext {
+        return nil, withCode("config", "切换到生成计划时的上下文，或重新生成计划", fmt.Errorf("计划文件生成于 context=%s admin_url=%s，与当前 context=%s admin_url=%s 不一致", pf.Context, pf.AdminURL, activeContext, cur))
+    }
+    return &pf, nil
+}
+
+// verifyPlanFile 比对规划时与当前的远程指纹；任一资源发生变化时拒绝执行
+func verifyPlanFile(ctx context.Context, client *kong.Client, pf *planFile) error {
+    changed, err := changedSincePlan(ctx, client, pf.Plan, pf.Fingerprints)
+    if err != nil || len(changed) == 0 {
+        return err
+    }
+    return withCode("remote_changed", "", fmt.Errorf("生成计划（%s）后以下远程资源已发生变化，拒绝执行，请重新生成计划：\n  - %s", pf.CreatedAt.Local().Format("2006-01-02 15:04:05"), strings.Join(changed, "\n  - ")))
+}
+
+// changedSincePlan 返回指纹与规划时 fps 不一致的计划项（排序后）
+func changedSincePlan(ctx context.Context, client *kong.Client, plan aplan.Plan, fps map[string]string) ([]string, error) {
+    cur, err := collectFingerprints(ctx, client, plan)
+    if err != nil {
+        return nil, err
+    }
+    var changed []string
+    for k, fp := range cur {
+        if fps[k] != fp { changed = append(changed, k) }
+    }
+    sort.Strings(changed)
+    return changed, nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "regexp"
+    "runtime"
+    "strings"
+
+    "github.com/spf13/viper"
+)
+
+// plainTerminal 为 true 时输出纯 ASCII 且不带颜色（旧版 Windows 控制台无法渲染 ANSI 与 emoji）
+var plainTerminal bool
+
+// detectPlainTerminal 根据 KONGCTL_ASCII / 配置项 ascii 或终端环境判断是否回落为纯文本输出。
+// 显式设置为 true 强制纯文本，false 关闭自动回落。
+func detectPlainTerminal() bool {
+    if viper.IsSet("ascii") {
+        return viper.GetBool("ascii")
+    }
+    if runtime.GOOS != "windows" {
+        return false
+    }
+    // Windows Terminal / VS Code / ConEmu / ANSICON / MSYS 等均支持 ANSI
+    for _, env := range []string{"WT_SESSION", "TERM_PROGRAM", "ANSICON", "MSYSTEM"} {
+        if os.Getenv(env) != "" {
+            return false
+        }
+    }
+    if strings.EqualFold(os.Getenv("ConEmuANSI"), "ON") {
+        return false
+    }
+    return os.Getenv("TERM") == ""
+}
+
+// applyTerminalFallback 在纯文本终端或 minimal 风格下将状态图标替换为 ASCII 标记
+func applyTerminalFallback() {
+    plainTerminal = detectPlainTerminal()
+    if minimalStyle() {
+        emojiSuccess, emojiInfo, emojiWarn, emojiError, emojiDiff = "[OK]", "[INFO]", "[WARN]", "[ERROR]", ""
+    } else {
+        emojiSuccess, emojiInfo, emojiWarn, emojiError, emojiDiff = "✅", "ℹ️", "⚠️", "❌", "📝 "
+    }
+}
+
+// userConfigDir 返回默认配置目录 ~/.kongctl（Windows 下为 %USERPROFILE%\.kongctl）
+func userConfigDir() (string, error) {
+    home, err := os.UserHomeDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(home, ".kongctl"), nil
+}
+
+var windowsEnvRef = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+// expandPath 展开用户传入路径中的 ~、$VAR 与 %VAR%，便于在 cmd/PowerShell/sh 中使用同一写法
+func expandPath(p string) string {
+    if p == "" || p == "-" {
+        return p
+    }
+    p = windowsEnvRef.ReplaceAllStringFunc(p, func(m string) string {
+        if v, ok := os.LookupEnv(m[1 : len(m)-1]); ok {
+            return v
+        }
+        return m
+    })
+    p = os.ExpandEnv(p)
+    if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
+        if home, err := os.UserHomeDir(); err == nil {
+            p = filepath.Join(home, p[1:])
+        }
+    }
+    return filepath.Clean(p)
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// normalizeText 去除 UTF-8 BOM 并将 CRLF 统一为 LF（Windows 编辑器保存的 spec/配置文件）
+func normalizeText(b []byte) []byte {
+    b = bytes.TrimPrefix(b, utf8BOM)
+    return bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+}
+
+// writeTextFile 写入文本文件；Windows 下使用 CRLF 换行，便于记事本等工具编辑
+func writeTextFile(file string, data []byte, perm os.FileMode) error {
+    if runtime.GOOS == "windows" {
+        data = bytes.ReplaceAll(normalizeText(data), []byte("\n"), []byte("\r\n"))
+    }
+    return os.WriteFile(file, data, perm)
+}
@@ -0,0 +1,395 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+var (
+    pluginService  string
+    pluginRoute    string
+    pluginConsumer string
+    pluginGroup    string
+    pluginGlobal   bool
+    pluginConfig   string
+    pluginDisabled bool
+)
+
+var pluginCmd = &cobra.Command{
+    Use:   "plugin",
+    Short: "管理 Kong 插件（同步/列出/查看/删除）",
+    Long: `按作用范围管理任意 Kong 插件：--service、--route、--consumer、--consumer-group 绑定到对应资源，--global 为全局插件。
+同一作用范围内插件按名称唯一，sync 幂等地创建或更新（只下发 --config 中给出的字段，其余字段保持网关当前值）。`,
+}
+
+// pluginScope 校验 --service/--route/--consumer/--consumer-group/--global 五选一，返回资源类型与名称（全局插件名称为空）；
+// optional 为 true 时允许都不指定（kind 为空，表示全部插件）
+func pluginScope(optional bool) (string, string, error) {
+    var kind, name string
+    n := 0
+    for _, s := range []struct{ kind, name string; set bool }{
+        {"Service", pluginService, pluginService != ""},
+        {"Route", pluginRoute, pluginRoute != ""},
+        {"Consumer", pluginConsumer, pluginConsumer != ""},
+        {"ConsumerGroup", pluginGroup, pluginGroup != ""},
+        {"Global", "", pluginGlobal},
+    } {
+        if s.set { kind, name, n = s.kind, s.name, n+1 }
+    }
+    switch {
+    case n > 1:
+        return "", "", fmt.Errorf("--service、--route、--consumer、--consumer-group 与 --global 只能指定一个")
+    case n == 0 && !optional:
+        return "", "", fmt.Errorf("必须通过 --service、--route、--consumer、--consumer-group 或 --global 指定插件的作用范围")
+    }
+    return kind, name, nil
+}
+
+// pluginScopeLabel 返回作用范围的展示名，例：Route catalog-list、全局作用范围
+func pluginScopeLabel(kind, name string) string {
+    if kind == "Global" { return "全局作用范围" }
+    return kind + " " + name
+}
+
+// findScopedPlugin 在作用范围内按名称查找插件
+func findScopedPlugin(ctx context.Context, client *kong.Client, kind, name, plugin string) (*kong.Plugin, bool, error) {
+    switch kind {
+    case "Service":
+        return client.GetServicePlugin(ctx, name, plugin)
+    case "Route":
+        return client.GetRoutePlugin(ctx, name, plugin)
+    case "Consumer":
+        return client.GetConsumerPlugin(ctx, name, plugin)
+    case "ConsumerGroup":
+        return client.GetConsumerGroupPlugin(ctx, name, plugin)
+    }
+    return client.GetGlobalPlugin(ctx, plugin)
+}
+
+// listScopedPlugins 列出作用范围内的插件；kind 为空时列出全部插件
+func listScopedPlugins(ctx context.Context, client *kong.Client, kind, name string) ([]kong.Plugin, error) {
+    switch kind {
+    case "Service":
+        return client.ListServicePlugins(ctx, name)
+    case "Route":
+        return client.ListRoutePlugins(ctx, name)
+    case "Consumer":
+        return client.ListConsumerPlugins(ctx, name)
+    case "ConsumerGroup":
+        return client.ListConsumerGroupPlugins(ctx, name)
+    case "Global":
+        return client.ListGlobalPlugins(ctx)
+    }
+    return client.ListPlugins(ctx)
+}
+
+// parsePluginConfig 解析 --config：内联的 JSON/YAML 对象，或以 @ 开头的文件路径（@- 为标准输入）
+func parsePluginConfig(cmd *cobra.Command, raw string) (map[string]any, error) {
+    content := []byte(raw)
+    if file, ok := strings.CutPrefix(raw, "@"); ok {
+        b, err := readSpecFile(cmd, file)
+        if err != nil {
+            return nil, err
+        }
+        content = b
+    }
+    var conf map[string]any
+    if err := yaml.Unmarshal(content, &conf); err != nil {
+        return nil, fmt.Errorf("解析 --config 失败（需为 JSON/YAML 对象）：%w", err)
+    }
+    return conf, nil
+}
+
+// pluginConfigDiff 比较插件当前配置与期望配置（--config 或 spec），只比较期望配置中给出的字段；path 为差异行的前缀
+func pluginConfigDiff(path string, cur, want map[string]any) string {
+    sub := make(map[string]any, len(want))
+    for k := range want {
+        if v, ok := cur[k]; ok { sub[k] = v }
+    }
+    return diffNested(path, sub, want)
+}
+
+var pluginSyncCmd = &cobra.Command{
+    Use:   "sync <plugin>",
+    Short: "在指定作用范围上幂等地创建或更新插件",
+    Args:  cobra.ExactArgs(1),
+    Example: `# 为 catalog 服务限流（内联 JSON）
+kongctl plugin sync rate-limiting --service catalog --config '{"minute": 100, "policy": "local"}'
+
+# 从 YAML 文件读取配置，作用于单个路由
+kongctl plugin sync cors --route catalog-list --config @cors.yaml
+
+# 全局启用 prometheus；暂时停用某个 consumer 上的插件
+kongctl plugin sync prometheus --global
+kongctl plugin sync rate-limiting --consumer alice --disabled
+
+# 分级限流：gold 组内的 Consumer 每分钟 1000 次
+kongctl plugin sync rate-limiting --consumer-group gold --config '{"minute": 1000, "policy": "local"}'`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := pluginScope(false)
+        if err != nil {
+            return err
+        }
+        plugin := args[0]
+        var conf map[string]any
+        if pluginConfig != "" {
+            if conf, err = parsePluginConfig(cmd, pluginConfig); err != nil {
+                return err
+            }
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        enabled := !pluginDisabled
+        label := pluginScopeLabel(kind, name)
+        cur, exists, err := findScopedPlugin(ctx, client, kind, name, plugin)
+        if err != nil {
+            return err
+        }
+        var diff string
+        if exists {
+            diff = pluginConfigDiff("config", cur.Config, conf)
+            if cur.Enabled != nil && *cur.Enabled != enabled { diff += fmt.Sprintf("enabled: %t -> %t\n", *cur.Enabled, enabled) }
+            if diff == "" {
+                PrintInfo(cmd, "%s 上的 %s 已是期望配置，无需变更", label, plugin)
+                return nil
+            }
+        }
+        if dryRun {
+            if !exists {
+                PrintInfo(cmd, "[dry-run] 将在 %s 上创建 %s", label, plugin)
+                diff = diffNested("config", nil, conf)
+            } else {
+                PrintInfo(cmd, "[dry-run] 将更新 %s 上的 %s", label, plugin)
+            }
+            for _, l := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+                if l != "" { cmd.Printf("    %s\n", l) }
+            }
+            return nil
+        }
+        desired := kong.Plugin{Name: plugin, Config: conf, Enabled: &enabled}
+        var action string
+        switch kind {
+        case "Service":
+            action, _, err = client.CreateOrUpdateServicePlugin(ctx, name, desired)
+        case "Route":
+            action, _, err = client.CreateOrUpdateRoutePlugin(ctx, name, desired)
+        case "Consumer":
+            action, _, err = client.CreateOrUpdateConsumerPlugin(ctx, name, desired)
+        case "ConsumerGroup":
+            action, _, err = client.CreateOrUpdateConsumerGroupPlugin(ctx, name, desired)
+        default:
+            action, _, err = client.CreateOrUpdateGlobalPlugin(ctx, desired)
+        }
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s %s：%s", actionCN(action), plugin, label)
+        return nil
+    },
+}
+
+var pluginListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出插件（不指定作用范围时列出全部）",
+    Example: `kongctl plugin list
+kongctl plugin list --route catalog-list
+kongctl plugin list --global --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := pluginScope(true)
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := listScopedPlugins(ctx, client, kind, name)
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+        if outputJSON() {
+            if list == nil { list = []kong.Plugin{} }
+            b, _ := json.MarshalIndent(list, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到插件")
+            return nil
+        }
+        // 列出全部插件时将关联资源的 ID 解析为名称
+        names := map[string]string{}
+        if kind == "" {
+            if err := pluginRefNames(ctx, client, names); err != nil {
+                return err
+            }
+        }
+        cmd.Printf("%-28s %-36s %-8s %s\n", "NAME", "SCOPE", "ENABLED", "ID")
+        for _, p := range list {
+            scope := pluginScopeLabel(kind, name)
+            if kind == "" { scope = pluginRefLabel(p, names) }
+            enabled := p.Enabled == nil || *p.Enabled
+            cmd.Printf("%-28s %-36s %-8t %s\n", p.Name, scope, enabled, p.ID)
+        }
+        return nil
+    },
+}
+
+// pluginRefNames 收集 service/route/consumer/consumer_group 的 ID 到名称的映射
+func pluginRefNames(ctx context.Context, client *kong.Client, names map[string]string) error {
+    svcs, err := client.ListServices(ctx)
+    if err != nil {
+        return err
+    }
+    for _, s := range svcs { names[s.ID] = s.Name }
+    rts, err := client.ListRoutes(ctx)
+    if err != nil {
+        return err
+    }
+    for _, r := range rts { names[r.ID] = r.Name }
+    cons, err := client.ListConsumers(ctx)
+    if err != nil {
+        return err
+    }
+    for _, c := range cons { names[c.ID] = c.Username }
+    groups, err := client.ListConsumerGroups(ctx)
+    if err != nil {
+        return err
+    }
+    for _, g := range groups { names[g.ID] = g.Name }
+    return nil
+}
+
+// pluginRefLabel 返回插件绑定的资源，例：Route catalog-list、Consumer alice + Service catalog
+func pluginRefLabel(p kong.Plugin, names map[string]string) string {
+    var parts []string
+    for _, r := range []struct{ kind string; ref *kong.EntityRef }{{"ConsumerGroup", p.ConsumerGroup}, {"Consumer", p.Consumer}, {"Route", p.Route}, {"Service", p.Service}} {
+        if r.ref == nil { continue }
+        n := names[r.ref.ID]
+        if n == "" { n = r.ref.ID }
+        parts = append(parts, r.kind+" "+n)
+    }
+    if len(parts) == 0 { return "全局" }
+    return strings.Join(parts, " + ")
+}
+
+var pluginGetCmd = &cobra.Command{
+    Use:   "get <plugin>",
+    Short: "查看作用范围内指定插件的完整配置（YAML，--output json 时为 JSON）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl plugin get rate-limiting --service catalog
+kongctl plugin get prometheus --global --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := pluginScope(false)
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        p, ok, err := findScopedPlugin(ctx, client, kind, name, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "使用 kongctl plugin list 查看已有插件", fmt.Errorf("%s 上不存在插件 %s", pluginScopeLabel(kind, name), args[0]))
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(p, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        var obj map[string]any
+        b, _ := json.Marshal(p)
+        _ = json.Unmarshal(b, &obj)
+        out, err := yaml.Marshal(obj)
+        if err != nil {
+            return err
+        }
+        cmd.Print(string(out))
+        return nil
+    },
+}
+
+var pluginDeleteCmd = &cobra.Command{
+    Use:   "delete <plugin>",
+    Short: "删除作用范围内的指定插件",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl plugin delete rate-limiting --service catalog`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        kind, name, err := pluginScope(false)
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        label := pluginScopeLabel(kind, name)
+        p, ok, err := findScopedPlugin(ctx, client, kind, name, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "%s 上不存在插件 %s，无需删除", label, args[0])
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 %s：%s", args[0], label)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("plugin delete %s（%s）", args[0], label)); err != nil {
+            return err
+        }
+        if err := client.DeletePlugin(ctx, p.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 %s：%s", args[0], label)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(pluginCmd)
+    pluginCmd.AddCommand(pluginSyncCmd, pluginListCmd, pluginGetCmd, pluginDeleteCmd)
+    for _, c := range []*cobra.Command{pluginSyncCmd, pluginListCmd, pluginGetCmd, pluginDeleteCmd} {
+        c.Flags().StringVar(&pluginService, "service", "", "Service 名称，例：--service catalog")
+        c.Flags().StringVar(&pluginRoute, "route", "", "Route 名称")
+        c.Flags().StringVar(&pluginConsumer, "consumer", "", "Consumer 用户名")
+        c.Flags().StringVar(&pluginGroup, "consumer-group", "", "Consumer Group 名称（Kong Enterprise / 3.4+）")
+        c.Flags().BoolVar(&pluginGlobal, "global", false, "全局插件（未绑定 service/route/consumer/consumer-group）")
+    }
+    for _, c := range []*cobra.Command{pluginSyncCmd, pluginDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+    pluginSyncCmd.Flags().StringVar(&pluginConfig, "config", "", "插件配置：内联 JSON/YAML 对象，或 @文件路径（@- 为标准输入）；此命令中覆盖全局的 --config 配置文件参数")
+    pluginSyncCmd.Flags().BoolVar(&pluginDisabled, "disabled", false, "以停用状态创建/更新插件（enabled=false）")
+}
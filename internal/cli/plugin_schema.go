@@ -0,0 +1,173 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+var pluginSchemaFormat string
+
+// pluginSchemaRow 为插件 config 中的单个字段（record 的子字段按点分路径展开）
+type pluginSchemaRow struct {
+    Field       string    `json:"field"`
+    Type        string    `json:"type"`
+    Required    bool      `json:"required,omitempty"`
+    Default     any       `json:"default,omitempty"`
+    OneOf       []any     `json:"one_of,omitempty"`
+    Between     []float64 `json:"between,omitempty"`
+    Description string    `json:"description,omitempty"`
+    def         kong.SchemaField
+}
+
+// schemaTypeName 返回字段类型的展示名，数组/集合带上元素类型，例：set<string>
+func schemaTypeName(def kong.SchemaField) string {
+    if def.Elements != nil && def.Elements.Type != "" { return def.Type + "<" + def.Elements.Type + ">" }
+    return def.Type
+}
+
+// schemaConstraint 返回字段取值约束的简短描述（枚举或取值范围）
+func schemaConstraint(def kong.SchemaField) string {
+    oneOf, between := def.OneOf, def.Between
+    if def.Elements != nil && len(oneOf) == 0 && len(between) == 0 { oneOf, between = def.Elements.OneOf, def.Elements.Between }
+    if len(oneOf) > 0 {
+        vals := make([]string, len(oneOf))
+        for i, o := range oneOf { vals[i] = fmt.Sprint(o) }
+        return "one_of: " + strings.Join(vals, "/")
+    }
+    if len(between) == 2 { return fmt.Sprintf("between: %v-%v", between[0], between[1]) }
+    return ""
+}
+
+// flattenSchemaFields 按 schema 顺序展开字段，record 类型递归展开为 parent.child
+func flattenSchemaFields(prefix string, fields []map[string]kong.SchemaField, out *[]pluginSchemaRow) {
+    for _, f := range fields {
+        for name, def := range f {
+            path := joinPath(prefix, name)
+            if def.Type == "record" && len(def.Fields) > 0 {
+                flattenSchemaFields(path, def.Fields, out)
+                continue
+            }
+            *out = append(*out, pluginSchemaRow{Field: path, Type: schemaTypeName(def), Required: def.Required, Default: def.Default, OneOf: def.OneOf, Between: def.Between, Description: def.Description, def: def})
+        }
+    }
+}
+
+// schemaTemplateNode 将字段生成可直接编辑的 YAML 配置模板：取值为默认值（无默认值时为 null），
+// 行尾注释标明类型、是否必填与取值约束
+func schemaTemplateNode(fields []map[string]kong.SchemaField) (*yaml.Node, error) {
+    node := &yaml.Node{Kind: yaml.MappingNode}
+    for _, f := range fields {
+        for name, def := range f {
+            key := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+            var val *yaml.Node
+            if def.Type == "record" && len(def.Fields) > 0 {
+                sub, err := schemaTemplateNode(def.Fields)
+                if err != nil {
+                    return nil, err
+                }
+                val = sub
+            } else {
+                val = &yaml.Node{}
+                if err := val.Encode(def.Default); err != nil {
+                    return nil, err
+                }
+            }
+            note := []string{schemaTypeName(def)}
+            if def.Required { note = append(note, "必填") }
+            if c := schemaConstraint(def); c != "" { note = append(note, c) }
+            if val.Kind == yaml.MappingNode { key.LineComment = strings.Join(note, "，") } else { val.LineComment = strings.Join(note, "，") }
+            if def.Description != "" { key.HeadComment = def.Description }
+            node.Content = append(node.Content, key, val)
+        }
+    }
+    return node, nil
+}
+
+var pluginSchemaCmd = &cobra.Command{
+    Use:   "schema <plugin>",
+    Short: "查看插件 config 的字段、类型与默认值（读取网关的 /schemas/plugins/<name>）",
+    Long: `读取网关上插件的 schema，列出 config 的全部字段（record 的子字段按点分路径展开）、类型、是否必填、默认值与取值约束。
+--format yaml 输出以默认值填充的配置模板，可修改后通过 kongctl plugin sync <plugin> --config @文件 下发。
+schema 与 apply 预检共用 ~/.kongctl/schemas/<version>/ 下的缓存，网关不可达时使用已缓存的版本。`,
+    Args: cobra.ExactArgs(1),
+    Example: `kongctl plugin schema rate-limiting
+
+# 生成配置模板，编辑后下发
+kongctl plugin schema rate-limiting --format yaml > rate-limiting.yaml
+kongctl plugin sync rate-limiting --service catalog --config @rate-limiting.yaml`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if pluginSchemaFormat != "table" && pluginSchemaFormat != "yaml" {
+            return fmt.Errorf("--format 仅支持 table 或 yaml：%s", pluginSchemaFormat)
+        }
+        name := args[0]
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        sc, err := openSchemaCache(ctx, client, false)
+        if err != nil {
+            return err
+        }
+        schema, found, err := sc.get("plugins/" + name)
+        if err != nil {
+            return err
+        }
+        if !found {
+            hint := "确认插件名称，且已在网关的 plugins 配置中启用"
+            if sc.Offline { hint = "无法连接网关，且本地没有该插件在 Kong " + sc.Version + " 下的缓存" }
+            return withCode("not_found", hint, fmt.Errorf("网关上不存在插件 %s 的 schema", name))
+        }
+        if sc.Offline { PrintWarn(cmd, "无法连接网关，使用已缓存的 Kong %s schema", sc.Version) }
+        conf, ok := schema.Field("config")
+        if !ok || len(conf.Fields) == 0 {
+            PrintInfo(cmd, "插件 %s 没有可配置的 config 字段", name)
+            return nil
+        }
+        var rows []pluginSchemaRow
+        flattenSchemaFields("", conf.Fields, &rows)
+        switch {
+        case outputJSON():
+            enc := json.NewEncoder(cmd.OutOrStdout())
+            enc.SetEscapeHTML(false)
+            enc.SetIndent("", "  ")
+            if err := enc.Encode(rows); err != nil {
+                return err
+            }
+        case pluginSchemaFormat == "yaml":
+            node, err := schemaTemplateNode(conf.Fields)
+            if err != nil {
+                return err
+            }
+            out, err := yaml.Marshal(node)
+            if err != nil {
+                return err
+            }
+            cmd.Print(string(out))
+        default:
+            cmd.Printf("%-32s %-16s %-8s %-16s %s\n", "FIELD", "TYPE", "REQUIRED", "DEFAULT", "CONSTRAINT")
+            for _, r := range rows {
+                def := "-"
+                if r.Default != nil { def = flowValue(r.Default) }
+                req := ""
+                if r.Required { req = "yes" }
+                cmd.Printf("%-32s %-16s %-8s %-16s %s\n", r.Field, r.Type, req, def, schemaConstraint(r.def))
+            }
+        }
+        return nil
+    },
+}
+
+func init() {
+    pluginCmd.AddCommand(pluginSchemaCmd)
+    pluginSchemaCmd.Flags().StringVar(&pluginSchemaFormat, "format", "table", "输出格式：table（字段列表）或 yaml（以默认值填充的配置模板）")
+}
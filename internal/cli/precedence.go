@@ -0,0 +1,72 @@
+package cli
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// toRouteRule 将 Kong Route 转为路由模拟所需的规则
+func toRouteRule(r kong.Route) aplan.RouteRule {
+    return aplan.RouteRule{
+        Name:          r.Name,
+        Hosts:         r.Hosts,
+        Paths:         r.Paths,
+        Methods:       r.Methods,
+        Headers:       r.Headers,
+        RegexPriority: r.RegexPriority,
+    }
+}
+
+// mergedRouteRules 返回“应用计划后”的路由表：远程已有路由被同名的计划路由替换，新路由追加
+func mergedRouteRules(remote, planned []kong.Route) []aplan.RouteRule {
+    byName := map[string]int{}
+    rules := make([]aplan.RouteRule, 0, len(remote)+len(planned))
+    for _, r := range remote {
+        byName[r.Name] = len(rules)
+        rules = append(rules, toRouteRule(r))
+    }
+    for _, r := range planned {
+        if i, ok := byName[r.Name]; ok && r.Name != "" {
+            rules[i] = toRouteRule(r)
+            continue
+        }
+        rules = append(rules, toRouteRule(r))
+    }
+    return rules
+}
+
+// annotateRoutePrecedence 为计划中创建/更新的路由附加匹配优先级说明，
+// 并提示其是否会接管已有路由的流量或被已有路由遮蔽。
+func annotateRoutePrecedence(cmd *cobra.Command, plan *aplan.Plan, remote, planned []kong.Route) {
+    existing := map[string]bool{}
+    for _, r := range remote { existing[r.Name] = true }
+    router := aplan.NewRouter(mergedRouteRules(remote, planned))
+    for _, e := range router.Invalid {
+        PrintWarn(cmd, "%v（不参与匹配优先级与流量估算）", e)
+    }
+    for i := range plan.Items {
+        it := &plan.Items[i]
+        if it.Kind != "Route" || (it.Action != "create" && it.Action != "update") {
+            continue
+        }
+        pr := router.PrecedenceOf(it.Name)
+        if pr.Rank == 0 {
+            continue
+        }
+        it.Notes = append(it.Notes, fmt.Sprintf("匹配优先级：第 %d/%d 位", pr.Rank, pr.Total))
+        var steals []string
+        for _, n := range pr.Steals {
+            if existing[n] { steals = append(steals, n) }
+        }
+        if len(steals) > 0 {
+            it.Notes = append(it.Notes, fmt.Sprintf("%s 优先于并覆盖已有路由，将接管其流量：%s", emojiWarn, strings.Join(steals, ", ")))
+        }
+        if len(pr.ShadowedBy) > 0 {
+            it.Notes = append(it.Notes, fmt.Sprintf("%s 被更高优先级的路由覆盖，可能收不到流量：%s", emojiWarn, strings.Join(pr.ShadowedBy, ", ")))
+        }
+    }
+}
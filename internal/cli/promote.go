@@ -0,0 +1,124 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    "kongctl/internal/kong"
+)
+
+var (
+    promoteFrom string
+    promoteTo   string
+)
+
+// applyInputSpec 非空时 apply 以其替代 -f 读取的 spec（由 promote 从源上下文导出）；
+// overlay、--select 与冲突检测照常处理
+var applyInputSpec *applySpec
+
+// stripContextTags 移除 route 上由源上下文自动附加的标签（上下文 tags/select_tags 与 managed-by），
+// 目标上下文会按自身配置重新附加
+func stripContextTags(spec applySpec) applySpec {
+    drop := append(defaultTags(), managedByTag())
+    out := spec
+    out.Routes = append([]applyRoute(nil), spec.Routes...)
+    for i := range out.Routes {
+        var tags []string
+        for _, t := range out.Routes[i].Tags {
+            if !sliceContains(drop, t) { tags = append(tags, t) }
+        }
+        out.Routes[i].Tags = tags
+    }
+    return out
+}
+
+// withDependencies 为 --select 选中的 route/service 补上 full 中被其引用的 service 与 upstream
+func withDependencies(full, sel applySpec) applySpec {
+    out := sel
+    hasSvc := map[string]bool{}
+    for _, s := range out.Services { hasSvc[s.Name] = true }
+    for _, r := range sel.Routes {
+        if r.Service == "" || hasSvc[r.Service] { continue }
+        for _, s := range full.Services {
+            if s.Name == r.Service { out.Services = append(out.Services, s); hasSvc[s.Name] = true; break }
+        }
+    }
+    hasUp := map[string]bool{}
+    for _, up := range out.Upstreams { hasUp[up.Name] = true }
+    for _, s := range out.Services {
+        if s.Upstream == "" || hasUp[s.Upstream] { continue }
+        for _, up := range full.Upstreams {
+            if up.Name == s.Upstream { out.Upstreams = append(out.Upstreams, up); hasUp[up.Name] = true; break }
+        }
+    }
+    return out
+}
+
+var promoteCmd = &cobra.Command{
+    Use:   "promote",
+    Short: "将一个上下文中的资源提升到另一个上下文（导出、对比并应用）",
+    Long: `从 --from 上下文导出当前配置（可用 --select 只取部分资源），与 --to 上下文对比后应用（隐含 --overwrite）：
+  - --select 选中的 route/service 所引用的 service/upstream 会一并提升；
+  - 先展示字段级差异并交互确认，--yes 跳过确认，--dry-run 只展示差异；
+  - 环境间不同的配置（域名、targets 等）可用 --overlay 补丁调整，补丁查找 ./overlays/<name>.yaml；
+  - 源上下文自动附加的标签（tags/select_tags、managed-by）不会带到目标上下文，目标按自身配置打标签。
+不会删除目标上下文中的资源。`,
+    Example: `# 预览从 staging 提升到 prod 的差异
+kongctl promote --from staging --to prod --dry-run
+
+# 只提升 user 相关的路由及其依赖，并套用 prod 补丁
+kongctl promote --from staging --to prod --select kind=route,name=user-* --overlay prod`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if promoteFrom == "" || promoteTo == "" {
+            return withCode("usage", "", fmt.Errorf("必须同时指定 --from 与 --to"))
+        }
+        if promoteFrom == promoteTo {
+            return withCode("usage", "", fmt.Errorf("--from 与 --to 不能是同一个上下文"))
+        }
+        if cmd.Flags().Changed("context") {
+            return withCode("usage", "", fmt.Errorf("promote 通过 --from/--to 指定上下文，不能与 --context 同时使用"))
+        }
+        for _, name := range []string{promoteFrom, promoteTo} {
+            if viper.Sub("contexts."+name) == nil {
+                return withCode("config", "运行 kongctl context list 查看可用上下文", fmt.Errorf("未找到上下文：%s", name))
+            }
+        }
+        if err := switchContext(promoteFrom); err != nil {
+            return err
+        }
+        cfg, err := clientConfig(30 * time.Second)
+        if err != nil {
+            return err
+        }
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        rs, err := exportRemote(ctx, kong.NewClient(cfg), true)
+        cancel()
+        if err != nil {
+            return fmt.Errorf("导出上下文 %s 失败：%w", promoteFrom, err)
+        }
+        spec := stripContextTags(rs.spec())
+        PrintInfo(cmd, "已从上下文 %s 导出：upstreams=%d services=%d routes=%d", promoteFrom, len(spec.Upstreams), len(spec.Services), len(spec.Routes))
+
+        if err := switchContext(promoteTo); err != nil {
+            return err
+        }
+        applyInputSpec = &spec
+        defer func() { applyInputSpec = nil }()
+        applyOverwrite, showDiff = true, true
+        if !dryRun && !applyYes { applyConfirm = true }
+        return applyCmd.RunE(cmd, args)
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(promoteCmd)
+    promoteCmd.Flags().StringVar(&promoteFrom, "from", "", "源上下文，例：--from staging")
+    promoteCmd.Flags().StringVar(&promoteTo, "to", "", "目标上下文，例：--to prod")
+    promoteCmd.Flags().StringArrayVar(&applySelect, "select", nil, "只提升匹配的资源（同 apply --select），例：--select kind=route,name=user-*")
+    promoteCmd.Flags().StringSliceVar(&applyOverlays, "overlay", nil, "应用前合并的环境补丁（./overlays/<name>.yaml 或文件路径），例：--overlay prod")
+    promoteCmd.Flags().BoolVar(&dryRun, "dry-run", false, "只展示与目标上下文的差异，不做变更")
+    promoteCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "跳过交互确认")
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    propagationDPs      []string
+    propagationWait     bool
+    propagationTimeout  time.Duration
+    propagationInterval time.Duration
+)
+
+// propagationNode 为一个 data plane 节点的配置同步状态
+type propagationNode struct {
+    Name       string `json:"name"`
+    Source     string `json:"source"` // clustering：控制面登记；status：--dp 指定的 status 接口
+    Hash       string `json:"config_hash"`
+    SyncStatus string `json:"sync_status,omitempty"`
+    LastSeen   int64  `json:"last_seen,omitempty"`
+    Synced     bool   `json:"synced"`
+    Error      string `json:"error,omitempty"`
+}
+
+// propagationReport 为一次检查的结果；Expected 为控制面配置哈希（控制面未提供时取各节点一致的哈希）
+type propagationReport struct {
+    Expected  string            `json:"expected_hash"`
+    FromCP    bool              `json:"expected_from_control_plane"`
+    Hybrid    bool              `json:"hybrid"`
+    Nodes     []propagationNode `json:"nodes"`
+    Converged bool              `json:"converged"`
+}
+
+func (r propagationReport) pending() int {
+    n := 0
+    for _, nd := range r.Nodes {
+        if !nd.Synced { n++ }
+    }
+    return n
+}
+
+// checkPropagation 读取控制面与各 data plane 的配置哈希并判断是否一致
+func checkPropagation(ctx context.Context, cfg kong.Config, client *kong.Client) (propagationReport, error) {
+    var rep propagationReport
+    st, err := client.GetStatus(ctx)
+    if err != nil {
+        return rep, fmt.Errorf("读取控制面 /status 失败：%w", err)
+    }
+    rep.Expected, rep.FromCP = st.ConfigurationHash, st.ConfigurationHash != ""
+    dps, hybrid, err := client.ListDataPlanes(ctx)
+    if err != nil {
+        return rep, fmt.Errorf("读取 /clustering/data-planes 失败：%w", err)
+    }
+    rep.Hybrid = hybrid
+    for _, dp := range dps {
+        name := dp.Hostname
+        if name == "" { name = dp.ID }
+        rep.Nodes = append(rep.Nodes, propagationNode{Name: name, Source: "clustering", Hash: dp.ConfigHash, SyncStatus: dp.SyncStatus, LastSeen: dp.LastSeen})
+    }
+    for _, u := range propagationDPs {
+        // data plane 的 status 接口不需要 Admin token
+        dcfg := cfg
+        dcfg.AdminURL, dcfg.Token, dcfg.TokenSource, dcfg.MaxCalls = u, "", nil, 0
+        node := propagationNode{Name: u, Source: "status"}
+        if st, err := kong.NewClient(dcfg).GetStatus(ctx); err != nil {
+            node.Error = err.Error()
+        } else {
+            node.Hash = st.ConfigurationHash
+        }
+        rep.Nodes = append(rep.Nodes, node)
+    }
+    if rep.Expected == "" {
+        // 控制面未提供配置哈希：各节点哈希一致即视为已收敛
+        for _, nd := range rep.Nodes {
+            if nd.Hash != "" { rep.Expected = nd.Hash; break }
+        }
+    }
+    rep.Converged = len(rep.Nodes) > 0 || !rep.Hybrid
+    for i := range rep.Nodes {
+        nd := &rep.Nodes[i]
+        nd.Synced = nd.Error == "" && nd.Hash != "" && nd.Hash == rep.Expected
+        if !nd.Synced { rep.Converged = false }
+    }
+    return rep, nil
+}
+
+func printPropagation(cmd *cobra.Command, rep propagationReport) {
+    src := "控制面"
+    if !rep.FromCP { src = "节点一致性" }
+    exp := rep.Expected
+    if exp == "" { exp = "-" }
+    cmd.Printf("期望配置哈希：%s（来源：%s）\n", exp, src)
+    if len(rep.Nodes) == 0 {
+        return
+    }
+    cmd.Printf("%-32s %-10s %-34s %-14s %-12s %s\n", "NODE", "SOURCE", "CONFIG_HASH", "SYNC_STATUS", "LAST_SEEN", "SYNCED")
+    for _, nd := range rep.Nodes {
+        mark := colorSuccess(glyph("✔", "[OK]"))
+        if !nd.Synced { mark = colorWarn(glyph("✘", "[PENDING]")) }
+        hash, status, seen := nd.Hash, nd.SyncStatus, "-"
+        if hash == "" { hash = "-" }
+        if status == "" { status = "-" }
+        if nd.LastSeen > 0 { seen = time.Since(time.Unix(nd.LastSeen, 0)).Round(time.Second).String() + " ago" }
+        cmd.Printf("%-32s %-10s %-34s %-14s %-12s %s\n", nd.Name, nd.Source, hash, status, seen, mark)
+        if nd.Error != "" { cmd.Printf("  %s %s\n", colorError(glyph("✘", "[ERROR]")), nd.Error) }
+    }
+}
+
+var propagationCmd = &cobra.Command{
+    Use:   "propagation",
+    Short: "配置下发（控制面 -> data plane）状态",
+}
+
+var propagationCheckCmd = &cobra.Command{
+    Use:   "check",
+    Short: "检查最近一次变更是否已同步到全部 data plane",
+    Long: `读取控制面 /status 的配置哈希与 /clustering/data-planes 中各 data plane 上报的哈希，判断配置是否已下发到所有节点。
+也可通过 --dp 直接读取 data plane 的 status 接口（如 http://dp1:8100）。控制面未提供配置哈希时，以各节点哈希一致作为收敛条件。
+非混合模式且未指定 --dp 时，变更写入数据库即生效，直接视为已同步。
+--wait 持续检查直到全部同步或超过 --wait-timeout；未同步时以非零状态退出。`,
+    Example: `# 检查配置下发状态
+kongctl propagation check
+
+# apply 后等待全部 data plane 同步（最多 2 分钟）
+kongctl apply -f spec.yaml && kongctl propagation check --wait --wait-timeout 2m
+
+# 直接读取 data plane 的 status 接口
+kongctl propagation check --dp http://dp1:8100 --dp http://dp2:8100`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if propagationWait && (propagationTimeout <= 0 || propagationInterval <= 0) {
+            return withCode("usage", "", fmt.Errorf("--wait-timeout 与 --interval 必须大于 0"))
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        timeout := cfg.Timeout
+        if propagationWait { timeout = propagationTimeout + cfg.Timeout }
+        ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+        defer cancel()
+
+        deadline := time.Now().Add(propagationTimeout)
+        for {
+            rep, err := checkPropagation(ctx, cfg, client)
+            if err != nil {
+                return err
+            }
+            last := rep.Converged || !propagationWait || time.Now().Add(propagationInterval).After(deadline)
+            if rep.Converged && !rep.Hybrid && len(rep.Nodes) == 0 {
+                if outputJSON() {
+                    b, _ := json.MarshalIndent(rep, "", "  ")
+                    cmd.Println(string(b))
+                    return nil
+                }
+                PrintInfo(cmd, "未发现 data plane（非混合模式）：配置写入后即生效")
+                return nil
+            }
+            if last {
+                if outputJSON() {
+                    b, _ := json.MarshalIndent(rep, "", "  ")
+                    cmd.Println(string(b))
+                } else {
+                    printPropagation(cmd, rep)
+                }
+                if rep.Converged {
+                    PrintSuccess(cmd, "配置已同步到全部 %d 个 data plane（哈希 %s）", len(rep.Nodes), rep.Expected)
+                    return nil
+                }
+                if rep.Hybrid && len(rep.Nodes) == 0 {
+                    return fmt.Errorf("控制面未登记任何 data plane")
+                }
+                if propagationWait {
+                    return fmt.Errorf("等待 %s 后仍有 %d/%d 个 data plane 未同步", propagationTimeout, rep.pending(), len(rep.Nodes))
+                }
+                return fmt.Errorf("%d/%d 个 data plane 尚未同步（可使用 --wait 等待收敛）", rep.pending(), len(rep.Nodes))
+            }
+            if !outputJSON() {
+                PrintInfo(cmd, "%d/%d 个 data plane 尚未同步，%s 后重试", rep.pending(), len(rep.Nodes), propagationInterval)
+            }
+            select {
+            case <-ctx.Done():
+                return ctx.Err()
+            case <-time.After(propagationInterval):
+            }
+        }
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(propagationCmd)
+    propagationCmd.AddCommand(propagationCheckCmd)
+    propagationCheckCmd.Flags().StringArrayVar(&propagationDPs, "dp", nil, "data plane status 接口地址（可重复指定），例：--dp http://dp1:8100")
+    propagationCheckCmd.Flags().BoolVar(&propagationWait, "wait", false, "持续检查直到全部 data plane 同步")
+    propagationCheckCmd.Flags().DurationVar(&propagationTimeout, "wait-timeout", 2*time.Minute, "--wait 的最长等待时间")
+    propagationCheckCmd.Flags().DurationVar(&propagationInterval, "interval", 5*time.Second, "--wait 的检查间隔")
+}
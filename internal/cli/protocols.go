@@ -0,0 +1,102 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "net/url"
+    "strings"
+
+    "kongctl/internal/kong"
+)
+
+// protocolFamilies 为 route protocols 与 service protocol 的兼容分组：route 只能使用与其 service 同组的协议
+var protocolFamilies = map[string][]string{
+    "http":   {"http", "https"},
+    "grpc":   {"grpc", "grpcs"},
+    "stream": {"tcp", "tls", "tls_passthrough", "udp"},
+}
+
+// protocolFamily 返回协议所属分组；未知协议（如 ws/wss）返回空，不做检查
+func protocolFamily(proto string) string {
+    for fam, list := range protocolFamilies {
+        if sliceContains(list, strings.ToLower(proto)) { return fam }
+    }
+    return ""
+}
+
+// defaultRouteProtocols 返回 route 未声明 protocols 时按 service 协议使用的默认值；http/https 沿用 Kong 默认（返回 nil）
+func defaultRouteProtocols(serviceProto string) []string {
+    switch strings.ToLower(serviceProto) {
+    case "grpc", "grpcs":
+        return []string{"grpc", "grpcs"}
+    case "tcp":
+        return []string{"tcp", "tls"}
+    case "tls", "tls_passthrough", "udp":
+        return []string{strings.ToLower(serviceProto)}
+    }
+    return nil
+}
+
+// specServiceProtocol 返回 spec 中 service 的协议：url 的 scheme 优先，其次 protocol，默认 http
+func specServiceProtocol(s applyService) string {
+    if s.URL != "" {
+        if u, err := url.Parse(s.URL); err == nil && u.Scheme != "" { return strings.ToLower(u.Scheme) }
+    }
+    if s.Protocol != "" { return strings.ToLower(s.Protocol) }
+    return "http"
+}
+
+// resolveRouteProtocols 在执行前按 route 所属 service 的协议检查 protocols：
+// 未声明时为 grpc/tcp/tls/udp service 自动设置兼容的协议（而非 Kong 默认的 http/https），
+// 声明了不兼容的协议或该协议不支持的匹配字段时直接报错并指出 route，避免执行到一半收到 Kong 的 400
+func resolveRouteProtocols(ctx context.Context, client *kong.Client, spec applySpec) (applySpec, error) {
+    protos := map[string]string{}
+    for _, s := range spec.Services { protos[s.Name] = specServiceProtocol(s) }
+    out := spec
+    out.Routes = append([]applyRoute(nil), spec.Routes...)
+    for i := range out.Routes {
+        r := &out.Routes[i]
+        name := specRouteName(*r)
+        svc, proto := r.Service, r.Backend.Protocol
+        if svc == "" {
+            svc, _ = autoBackendNames(*r, name)
+            if proto == "" { proto = "http" }
+        } else if p, ok := protos[svc]; ok {
+            proto = p
+        } else {
+            cur, found, err := client.GetService(ctx, svc)
+            if err != nil { return spec, err }
+            // service 不存在时由后续步骤报错
+            if !found { continue }
+            proto = cur.Protocol
+        }
+        fam := protocolFamily(proto)
+        if fam == "" { continue }
+        if len(r.Protocols) == 0 {
+            r.Protocols = defaultRouteProtocols(proto)
+        } else {
+            for _, p := range r.Protocols {
+                if protocolFamily(p) != fam {
+                    return spec, fmt.Errorf("route %s 的 protocols %v 与 service %s 的协议 %s 不兼容（可用：%s）", name, r.Protocols, svc, proto, strings.Join(protocolFamilies[fam], "/"))
+                }
+            }
+        }
+        switch fam {
+        case "grpc":
+            // Kong 要求 grpc/grpcs route 不设置 methods，且 strip_path 为 false
+            if len(r.Methods) > 0 {
+                return spec, fmt.Errorf("route %s 使用 %s 协议（service %s），不能声明 methods", name, strings.Join(r.Protocols, "/"), svc)
+            }
+            if r.StripPath != nil && *r.StripPath {
+                return spec, fmt.Errorf("route %s 使用 %s 协议（service %s），strip_path 必须为 false", name, strings.Join(r.Protocols, "/"), svc)
+            }
+            sp := false
+            r.StripPath = &sp
+        case "stream":
+            if len(r.Paths) > 0 || len(r.Hosts) > 0 || len(r.Methods) > 0 || len(r.Headers) > 0 {
+                return spec, fmt.Errorf("route %s 使用 %s 协议（service %s），不能声明 paths/hosts/methods/headers（stream 路由按 snis 等匹配）", name, strings.Join(r.Protocols, "/"), svc)
+            }
+        }
+    }
+    return out, nil
+}
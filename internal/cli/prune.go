@@ -0,0 +1,196 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// defaultManagedByTag 为配置项 managed_by_tag 未设置时使用的标签
+const defaultManagedByTag = "managed-by:kongctl"
+
+// managedByTag 返回标记由 kongctl 管理的资源的标签（配置项 managed_by_tag，可按上下文设置）；
+// kongctl 创建的资源都会带上此标签，--prune 与 sync 只会删除带此标签的资源
+func managedByTag() string {
+    if t := strings.TrimSpace(viper.GetString("managed_by_tag")); t != "" { return t }
+    return defaultManagedByTag
+}
+
+var applyPrune bool
+
+// applyPruneTargets 为 apply --prune-targets：删除文件中声明了 targets 的 upstream 下未声明的 targets（不要求 managed-by 标签）
+var applyPruneTargets bool
+
+// managedInScope 判断远程资源是否可被 --prune 删除：带 managed-by 标签且属于上下文 select_tags 范围
+func managedInScope(tags []string) bool {
+    return kong.HasTag(tags, managedByTag()) && inTagScope(tags)
+}
+
+// declaredResources 为 spec 中声明（含简写派生）的资源名称集合
+type declaredResources struct {
+    upstreams map[string]bool
+    services  map[string]bool
+    routes    map[string]bool
+    targets   map[string]map[string]bool // upstream -> target
+}
+
+func (d declaredResources) addTargets(up string, ts []applyTarget) {
+    if d.targets[up] == nil { d.targets[up] = map[string]bool{} }
+    for _, t := range ts { d.targets[up][t.Target] = true }
+}
+
+// collectDeclared 按 apply 的命名规则汇总 spec 中的 upstream/service/route/target
+func collectDeclared(spec applySpec) declaredResources {
+    d := declaredResources{upstreams: map[string]bool{}, services: map[string]bool{}, routes: map[string]bool{}, targets: map[string]map[string]bool{}}
+    for _, up := range spec.Upstreams {
+        d.upstreams[up.Name] = true
+        d.addTargets(up.Name, up.Targets)
+    }
+    for _, s := range spec.Services {
+        d.services[s.Name] = true
+        if s.Upstream != "" {
+            d.upstreams[s.Upstream] = true
+            d.addTargets(s.Upstream, s.Targets)
+        }
+    }
+    for _, r := range spec.Routes {
+        name := r.Name
+        if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        if name == "" { continue }
+        d.routes[name] = true
+        if r.Service == "" {
+            svcName, upName := autoBackendNames(r, name)
+            d.services[svcName] = true
+            d.upstreams[upName] = true
+            d.addTargets(upName, r.Backend.Targets)
+        }
+    }
+    return d
+}
+
+// planPrune 找出带 managed-by 标签（且在 select_tags 范围内）但未在 spec 中声明的远程资源，按安全的删除顺序返回：
+// routes -> services -> targets -> upstreams；指定了 --name-prefix/--name-suffix 时只考虑名称匹配的资源
+func planPrune(ctx context.Context, client *kong.Client, spec applySpec) ([]aplan.Change, error) {
+    d := collectDeclared(spec)
+    var routes, services, targets, upstreams []aplan.Change
+
+    rts, err := client.ListRoutes(ctx)
+    if err != nil { return nil, fmt.Errorf("列出 routes 失败：%w", err) }
+    for _, r := range rts {
+        if r.Name != "" && !d.routes[r.Name] && nameInScope(r.Name) && managedInScope(r.Tags) {
+            routes = append(routes, aplan.Change{Kind: "Route", Name: r.Name, Action: "delete"})
+        }
+    }
+    svcs, err := client.ListServices(ctx)
+    if err != nil { return nil, fmt.Errorf("列出 services 失败：%w", err) }
+    for _, s := range svcs {
+        if s.Name != "" && !d.services[s.Name] && nameInScope(s.Name) && managedInScope(s.Tags) {
+            services = append(services, aplan.Change{Kind: "Service", Name: s.Name, Action: "delete"})
+        }
+    }
+    ups, err := client.ListUpstreams(ctx)
+    if err != nil { return nil, fmt.Errorf("列出 upstreams 失败：%w", err) }
+    for _, up := range ups {
+        if !d.upstreams[up.Name] {
+            // 删除 upstream 时其 targets 一并删除，无需单独列出
+            if nameInScope(up.Name) && managedInScope(up.Tags) {
+                upstreams = append(upstreams, aplan.Change{Kind: "Upstream", Name: up.Name, Action: "delete"})
+            }
+            continue
+        }
+        list, err := client.ListTargets(ctx, up.Name)
+        if err != nil { return nil, fmt.Errorf("列出 upstream %s 的 targets 失败：%w", up.Name, err) }
+        for _, t := range list {
+            if !d.targets[up.Name][t.Target] && managedInScope(t.Tags) {
+                targets = append(targets, aplan.Change{Kind: "Target", Name: up.Name + "/" + t.Target, Action: "delete"})
+            }
+        }
+    }
+    out := append(routes, services...)
+    out = append(out, targets...)
+    return append(out, upstreams...), nil
+}
+
+// planPruneTargets 找出文件中声明了 targets 的 upstream 下、远程存在但未声明的 targets；
+// 未声明任何 target 的 upstream 不处理，避免误删全部后端
+func planPruneTargets(ctx context.Context, client *kong.Client, spec applySpec) ([]aplan.Change, error) {
+    d := collectDeclared(spec)
+    ups := make([]string, 0, len(d.targets))
+    for up, ts := range d.targets {
+        if len(ts) > 0 { ups = append(ups, up) }
+    }
+    sort.Strings(ups)
+    var out []aplan.Change
+    for _, up := range ups {
+        list, err := client.ListTargets(ctx, up)
+        if err != nil {
+            // dry-run 时 upstream 可能尚未创建
+            if _, ok, gerr := client.GetUpstream(ctx, up); gerr == nil && !ok { continue }
+            return nil, fmt.Errorf("列出 upstream %s 的 targets 失败：%w", up, err)
+        }
+        for _, t := range list {
+            if !d.targets[up][t.Target] {
+                out = append(out, aplan.Change{Kind: "Target", Name: up + "/" + t.Target, Action: "delete"})
+            }
+        }
+    }
+    return out, nil
+}
+
+// mergePruned 将 --prune-targets 的删除项并入 --prune 的计划（去重），targets 排在最前
+func mergePruned(pruned, targets []aplan.Change) []aplan.Change {
+    seen := map[string]bool{}
+    for _, ch := range pruned { seen[planKey(ch)] = true }
+    var out []aplan.Change
+    for _, ch := range targets {
+        if !seen[planKey(ch)] { out = append(out, ch) }
+    }
+    return append(out, pruned...)
+}
+
+// confirmPrune 在生产上下文执行 --prune/--prune-targets 前要求确认；调用方须在创建执行时限 context、
+// 获取 apply 锁之前调用，避免等待输入期间计时并持有锁。n < 0 表示尚未规划、删除数量未知
+func confirmPrune(cmd *cobra.Command, n int) error {
+    if dryRun || !(applyPrune || applyPruneTargets) || n == 0 {
+        return nil
+    }
+    op := "apply --prune"
+    if !applyPrune { op = "apply --prune-targets" }
+    if n < 0 {
+        return confirmDestructive(cmd, op+"（删除未在文件中声明的资源）")
+    }
+    return confirmDestructive(cmd, fmt.Sprintf("%s（删除 %d 个未在文件中声明的资源）", op, n))
+}
+
+// executePrune 按计划顺序删除资源；生产上下文的确认已由 confirmPrune 在执行前完成
+func executePrune(cmd *cobra.Command, ctx context.Context, client *kong.Client, changes []aplan.Change) error {
+    if len(changes) == 0 {
+        PrintInfo(cmd, "prune：没有需要删除的受管资源")
+        return nil
+    }
+    for _, ch := range changes {
+        var err error
+        switch ch.Kind {
+        case "Route":
+            err = client.DeleteRoute(ctx, ch.Name)
+        case "Service":
+            err = client.DeleteService(ctx, ch.Name)
+        case "Upstream":
+            err = client.DeleteUpstream(ctx, ch.Name)
+        case "Target":
+            up, target, _ := strings.Cut(ch.Name, "/")
+            err = client.DeleteTarget(ctx, up, target)
+        }
+        if err != nil {
+            return fmt.Errorf("删除 %s %s 失败：%w", ch.Kind, ch.Name, err)
+        }
+        PrintSuccess(cmd, "已删除 %s：%s（prune）", ch.Kind, ch.Name)
+    }
+    return nil
+}
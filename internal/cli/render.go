@@ -0,0 +1,96 @@
+package cli
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+    "strings"
+    "text/template"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+)
+
+// apply 的模板渲染模式：spec 先经 text/template 渲染再解析，便于循环生成重复 routes 与按环境参数化
+var (
+    applyRender bool
+    applyValues []string
+)
+
+// renderFuncs 在 --template 输出函数基础上补充常用的 spec 生成函数
+var renderFuncs = template.FuncMap{
+    "default": func(def, v any) any {
+        if v == nil || fmt.Sprint(v) == "" { return def }
+        return v
+    },
+    "required": func(msg string, v any) (any, error) {
+        if v == nil || fmt.Sprint(v) == "" { return nil, fmt.Errorf("%s", msg) }
+        return v, nil
+    },
+    "quote": func(v any) string { return fmt.Sprintf("%q", fmt.Sprint(v)) },
+    "indent": func(n int, s string) string {
+        pad := strings.Repeat(" ", n)
+        return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+    },
+    "toYaml": func(v any) (string, error) {
+        b, err := yaml.Marshal(v)
+        return strings.TrimSuffix(string(b), "\n"), err
+    },
+    "env": os.Getenv,
+}
+
+func addRenderFlags(cmd *cobra.Command) {
+    cmd.Flags().BoolVar(&applyRender, "template", false, "先以 Go 模板渲染 spec（.Values 为 --values 内容，.Context 为当前上下文名）")
+    cmd.Flags().StringArrayVar(&applyValues, "values", nil, "模板取值文件（YAML，可重复指定，后者覆盖前者；指定时自动启用 --template），例：--values values/prod.yaml")
+}
+
+func renderEnabled() bool { return applyRender || len(applyValues) > 0 }
+
+// loadTemplateValues 按顺序读取并深度合并 values 文件
+func loadTemplateValues(files []string) (map[string]any, error) {
+    values := map[string]any{}
+    for _, f := range files {
+        data, err := os.ReadFile(expandPath(f))
+        if err != nil {
+            return nil, fmt.Errorf("读取 values 文件失败：%w", err)
+        }
+        var m map[string]any
+        if err := yaml.Unmarshal(normalizeText(data), &m); err != nil {
+            return nil, fmt.Errorf("解析 values 文件 %s 失败：%w", f, err)
+        }
+        mergeValues(values, m)
+    }
+    return values, nil
+}
+
+func mergeValues(dst, src map[string]any) {
+    for k, v := range src {
+        if sm, ok := v.(map[string]any); ok {
+            if dm, ok := dst[k].(map[string]any); ok {
+                mergeValues(dm, sm)
+                continue
+            }
+        }
+        dst[k] = v
+    }
+}
+
+// renderSpecTemplate 渲染 spec 模板；引用不存在的 values 键会报错，避免生成空字段
+func renderSpecTemplate(content []byte, name string) ([]byte, error) {
+    values, err := loadTemplateValues(applyValues)
+    if err != nil {
+        return nil, err
+    }
+    funcs := template.FuncMap{}
+    for k, f := range templateFuncs { funcs[k] = f }
+    for k, f := range renderFuncs { funcs[k] = f }
+    tpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(content))
+    if err != nil {
+        return nil, fmt.Errorf("模板解析失败：%w", err)
+    }
+    var buf bytes.Buffer
+    if err := tpl.Execute(&buf, map[string]any{"Values": values, "Context": activeContext}); err != nil {
+        return nil, fmt.Errorf("模板渲染失败：%w", err)
+    }
+    return buf.Bytes(), nil
+}
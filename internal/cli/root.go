@@ -3,6 +3,8 @@ package cli
 import (
     "fmt"
     "os"
+    "strings"
+    "time"
 
     "github.com/spf13/cobra"
     "github.com/spf13/viper"
@@ -20,6 +22,34 @@ var rootCmd = &cobra.Command{
     Long:  "Kong 管理命令行工具：支持基于 OpenAPI 与 Admin API 的幂等创建/更新 Service、Route、Upstream、Target 与常用插件。",
     SilenceUsage:  true,  // 出错时不显示 usage/help
     SilenceErrors: true,  // 交由自定义 Execute 统一打印错误
+    PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+        switch s := strings.ToLower(viper.GetString("style")); s {
+        case "", "fancy", "minimal":
+        default:
+            return withCode("usage", "", fmt.Errorf("无效的 --style：%s（可选：minimal、fancy）", s))
+        }
+        switch o := strings.ToLower(viper.GetString("output")); o {
+        case "", "text", "json":
+        case "yaml":
+            if cmd.Annotations[yamlOutputAnnotation] == "" {
+                return withCode("usage", "", fmt.Errorf("%s 不支持 --output yaml（可用于 get 与 service/route/upstream/target list）", cmd.CommandPath()))
+            }
+        case "github", "gitlab":
+            if p := cmd.CommandPath(); p != "kongctl apply" && p != "kongctl sync" {
+                return withCode("usage", "", fmt.Errorf("--output %s 仅用于 apply/sync --dry-run 输出 CI 注解", o))
+            }
+        default:
+            return withCode("usage", "", fmt.Errorf("无效的 --output：%s（可选：text、json；apply/sync --dry-run 另支持 github、gitlab，get 与 service/route/upstream/target list 另支持 yaml）", o))
+        }
+        // context 子命令需在上下文无效时仍可用于修复配置；explain、stats 为离线命令
+        if cmd.Parent() == contextCmd || cmd == explainCmd || cmd.Parent() == statsCmd {
+            return nil
+        }
+        if contextErr == nil && len(fanoutContexts) > 0 && cmd.CommandPath() != "kongctl apply" && cmd.CommandPath() != "kongctl sync" {
+            return withCode("usage", "", fmt.Errorf("--context 指定多个上下文仅支持 apply 与 sync"))
+        }
+        return withCode("config", "运行 kongctl context list 查看可用上下文", contextErr)
+    },
     Example: `# 1) 首次配置（写入 ~/.kongctl/config.yaml）
 kongctl init --admin-url http://localhost:8001 --token <KONG_ADMIN_TOKEN>
 
@@ -37,12 +67,33 @@ kongctl upstream sync --name user-service-upstream
 kongctl target add --upstream user-service-upstream --target user-svc-1:8080 --weight 100`,
 }
 
+// 进程退出码：出错为 1；apply --dry-run --detailed-exitcode 检测到待执行变更时为 2
+const (
+    exitError   = 1
+    exitChanges = 2
+)
+
+// exitStatus 为命令成功结束时的退出码，由支持 --detailed-exitcode 的命令设置
+var exitStatus int
+
 // Execute 入口
 func Execute() {
-    if err := rootCmd.Execute(); err != nil {
-        fmt.Fprintf(os.Stderr, "%s\n", ErrorMessage(err.Error()))
-        // 不返回非零退出码，避免 shell 显示 "exit status 1"
-        return
+    start := time.Now()
+    cmd, err := rootCmd.ExecuteC()
+    recordUsage(cmd, time.Since(start), err)
+    if err != nil {
+        if outputJSON() {
+            writeJSONError(os.Stderr, err)
+        } else {
+            fmt.Fprintf(os.Stderr, "%s\n", ErrorMessage(err.Error()))
+            if info := describeError(err); info.Hint != "" {
+                fmt.Fprintf(os.Stderr, "%s\n", colorInfo(fmt.Sprintf("%s 提示：%s（详见 kongctl explain %s）", emojiInfo, info.Hint, info.Code)))
+            }
+        }
+        os.Exit(exitError)
+    }
+    if exitStatus != 0 {
+        os.Exit(exitStatus)
     }
 }
 
@@ -58,6 +109,12 @@ func init() {
     rootCmd.PersistentFlags().String("workspace", "", "Kong Workspace（可选），例：--workspace default")
     rootCmd.PersistentFlags().Bool("tls-skip-verify", false, "跳过 TLS 证书校验（不建议生产使用），例：--tls-skip-verify")
     rootCmd.PersistentFlags().Bool("no-color", false, "禁用彩色输出（环境变量 NO_COLOR 亦可生效），例：--no-color")
+    rootCmd.PersistentFlags().String("style", "fancy", "输出风格：fancy（emoji/框线）或 minimal（纯文本，适合日志系统），例：--style minimal")
+    rootCmd.PersistentFlags().StringP("output", "o", "text", "输出格式：text 或 json（json 时错误以结构化 JSON 写入 stderr；apply/sync --dry-run 另支持 github、gitlab 输出 CI 注解；get 与 service/route/upstream/target list 另支持 yaml；自带 -o/--output 文件参数的命令请用 KONGCTL_OUTPUT=json），例：--output json")
+    rootCmd.PersistentFlags().String("context", "", "仅本次调用使用指定配置上下文（不修改 current_context），例：--context prod")
+    rootCmd.PersistentFlags().Bool("debug-http", false, "在 stderr 输出每个 Admin API 请求的状态码、耗时与 X-Kong-Admin-Latency，例：--debug-http")
+    rootCmd.PersistentFlags().StringArray("header", nil, "为所有 Admin API 请求附加请求头（可重复，覆盖配置中的 headers），例：--header 'X-Request-Source: ci'")
+    rootCmd.PersistentFlags().Bool("force", false, "跳过 production 上下文的破坏性操作确认（prune/delete/rollback），例：--force")
 
     // 绑定 Viper
     _ = viper.BindPFlag("admin_url", rootCmd.PersistentFlags().Lookup("admin-url"))
@@ -65,6 +122,12 @@ func init() {
     _ = viper.BindPFlag("workspace", rootCmd.PersistentFlags().Lookup("workspace"))
     _ = viper.BindPFlag("tls_skip_verify", rootCmd.PersistentFlags().Lookup("tls-skip-verify"))
     _ = viper.BindPFlag("no_color", rootCmd.PersistentFlags().Lookup("no-color"))
+    _ = viper.BindPFlag("style", rootCmd.PersistentFlags().Lookup("style"))
+    _ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+    _ = viper.BindPFlag("context", rootCmd.PersistentFlags().Lookup("context"))
+    _ = viper.BindPFlag("force", rootCmd.PersistentFlags().Lookup("force"))
+    _ = viper.BindPFlag("debug_http", rootCmd.PersistentFlags().Lookup("debug-http"))
+    _ = viper.BindPFlag("header", rootCmd.PersistentFlags().Lookup("header"))
 
     // 环境变量：KONGCTL_ADMIN_URL 等
     viper.SetEnvPrefix("KONGCTL")
@@ -79,18 +142,23 @@ func init() {
     rootCmd.AddCommand(targetCmd)
     rootCmd.AddCommand(completionCmd)
     rootCmd.AddCommand(versionCmd)
+    rootCmd.AddCommand(contextCmd)
 
 }
 
 func initConfig() {
     // 配置加载顺序：flag > env > file
     if cfgFile != "" {
+        cfgFile = expandPath(cfgFile)
         viper.SetConfigFile(cfgFile)
     } else {
-        home, _ := os.UserHomeDir()
-        viper.AddConfigPath(home + "/.kongctl")
+        dir, _ := userConfigDir()
+        viper.AddConfigPath(dir)
         viper.SetConfigName("config")
         viper.SetConfigType("yaml")
     }
     _ = viper.ReadInConfig() // 文件不存在也不报错
+    activateContext()
+    applyTerminalFallback()
+    loadTheme()
 }
@@ -9,7 +9,8 @@ import (
     "time"
 
     "github.com/spf13/cobra"
-    "github.com/spf13/viper"
+    "gopkg.in/yaml.v3"
+    aplan "kongctl/internal/apply"
     "kongctl/internal/kong"
 )
 
@@ -40,19 +41,25 @@ kongctl route sync --service user-service --paths /v1/ping --hosts api.example.c
 kongctl route sync --service user-service --paths /v1/orders --methods GET,POST
 
 # 指定路径处理版本（v0/v1）
-kongctl route sync --service user-service --paths /v1 --methods GET --path-handling v1 --diff`,
+kongctl route sync --service user-service --paths /v1 --methods GET --path-handling v1 --diff
+
+# 从单个 route 文件同步（与 apply 文件的 route 条目格式、校验一致，可含 backend 简写）
+kongctl route sync -f route.yaml --dry-run --diff`,
     RunE: func(cmd *cobra.Command, args []string) error {
-        if routeService == "" || len(routePaths) == 0 {
-            return fmt.Errorf("必须提供 --service 与 --paths")
+        if applyFile != "" {
+            for _, f := range []string{"service", "name", "paths", "methods", "hosts", "path-handling"} {
+                if cmd.Flags().Changed(f) {
+                    return withCode("usage", "", fmt.Errorf("-f 不能与 --%s 同时使用（请在文件中声明）", f))
+                }
+            }
+            return syncRouteFile(cmd)
         }
-        cfg := kong.Config{
-            AdminURL:      viper.GetString("admin_url"),
-            Token:         viper.GetString("token"),
-            TLSSkipVerify: viper.GetBool("tls_skip_verify"),
-            Timeout:       10 * time.Second,
+        if routeService == "" || len(routePaths) == 0 {
+            return fmt.Errorf("必须提供 --service 与 --paths（或通过 -f 指定 route 文件）")
         }
-        if cfg.AdminURL == "" {
-            return fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置")
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
         }
         client := kong.NewClient(cfg)
         ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
@@ -85,9 +92,9 @@ kongctl route sync --service user-service --paths /v1 --methods GET --path-handl
         }
         if showDiff {
             if !exists {
-                PrintInfo(cmd, "📝 Diff: 新建 Route %s", name)
+                PrintInfo(cmd, "%sDiff: 新建 Route %s", emojiDiff, name)
             } else {
-                PrintInfo(cmd, "📝 Diff:")
+                PrintInfo(cmd, "%sDiff:", emojiDiff)
                 cmd.Print(diffSlice("hosts", cur.Hosts, desired.Hosts))
                 cmd.Print(diffSlice("paths", cur.Paths, desired.Paths))
                 cmd.Print(diffSlice("methods", cur.Methods, desired.Methods))
@@ -110,13 +117,188 @@ kongctl route sync --service user-service --paths /v1 --methods GET --path-handl
         if err != nil {
             return err
         }
+        if action == "none" {
+            PrintInfo(cmd, "Route 无变更，未写入：name=%s service=%s", name, routeService)
+            return nil
+        }
         PrintSuccess(cmd, "已%sed Route：name=%s service=%s", actionCN(action), name, routeService)
         return nil
     },
 }
 
+// syncRouteFile 处理 route sync -f：文件为单个 route（apply 文件中 routes[] 条目的格式），
+// 按内置 schema 校验后沿用 apply 的解析与执行逻辑（含 backend 简写、annotations 等），并总是覆盖已有配置
+func syncRouteFile(cmd *cobra.Command) error {
+    content, err := readSpecFile(cmd, applyFile)
+    if err != nil {
+        return err
+    }
+    schema, err := loadSpecSchema()
+    if err != nil {
+        return err
+    }
+    issues, root, err := validateSpecContent(schema, applyFile, content)
+    if err != nil {
+        return err
+    }
+    if root != nil && (root.Kind != yaml.MappingNode || isTopLevelSpec(root)) {
+        return withCode("usage", "", fmt.Errorf("route sync -f 仅接受单个 route；包含多个资源的文件请使用 kongctl apply -f %s", applyFile))
+    }
+    if len(issues) > 0 {
+        for _, i := range issues {
+            cmd.Printf("%s %s\n", colorError(glyph("✘", "[ERROR]")), i)
+        }
+        return withCode("usage", "对照 kongctl apply example 或 kongctl validate --print-schema 修正字段", fmt.Errorf("校验未通过：%s 共 %d 个问题", applyFile, len(issues)))
+    }
+    spec, err := parseApplySpec(content)
+    if err != nil {
+        return err
+    }
+    if err := checkSpecConflicts(spec); err != nil {
+        return err
+    }
+
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return err
+    }
+    cfg.Tags = defaultTags()
+    cfg.CreateTags = []string{managedByTag()}
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    defer cancel()
+
+    // 与不带 -f 的 route sync 一致：已存在时直接更新
+    applyOverwrite, applyPrune, applyPruneTargets = true, false, false
+    if err := runApplyPhase(cmd, ctx, client, spec, &aplan.Plan{}); err != nil {
+        return err
+    }
+    if dryRun {
+        cmd.Println("[dry-run] 以上为计划操作（未实际变更）" + emojiSuccess)
+    }
+    return nil
+}
+
+var routeListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Route（名称、所属 Service、匹配条件、标签与创建时长）",
+    Example: `kongctl route list
+kongctl route list --service user-service
+kongctl route list --filter tag=team-a --output json
+kongctl route list --service user-service --template '{{.Name}} {{join .Paths ","}}'`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        match, err := listFilterMatch()
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        svcs, err := client.ListServices(ctx)
+        if err != nil {
+            return fmt.Errorf("列出 services 失败：%w", err)
+        }
+        svcNames := map[string]string{}
+        for _, s := range svcs { svcNames[s.ID] = s.Name }
+        var svcID string
+        if routeService != "" {
+            svc, ok, err := client.GetService(ctx, routeService)
+            if err != nil {
+                return err
+            }
+            if !ok {
+                return withCode("not_found", "使用 kongctl service list 查看已有 Service", fmt.Errorf("Service 不存在：%s", routeService))
+            }
+            svcID = svc.ID
+        }
+        all, err := client.ListRoutes(ctx)
+        if err != nil {
+            return err
+        }
+        list := []kong.Route{}
+        for _, r := range all {
+            if svcID != "" && r.Service.ID != svcID { continue }
+            if match(r.Name, r.Tags) { list = append(list, r) }
+        }
+        sort.SliceStable(list, func(i, j int) bool {
+            a, b := svcNames[list[i].Service.ID], svcNames[list[j].Service.ID]
+            if a != b { return a < b }
+            return list[i].Name < list[j].Name
+        })
+        if outputTemplate != "" {
+            return printTemplate(cmd, list)
+        }
+        if ok, err := printStructured(cmd, list); ok {
+            return err
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Route")
+            return nil
+        }
+        rows := make([][]string, 0, len(list))
+        for _, r := range list {
+            svc := svcNames[r.Service.ID]
+            if svc == "" { svc = r.Service.ID }
+            name := r.Name
+            if name == "" { name = r.ID }
+            rows = append(rows, []string{name, svc, strings.Join(r.Methods, ","), strings.Join(r.Hosts, ","), strings.Join(r.Paths, ","), strings.Join(r.Tags, ","), formatAge(r.CreatedAt)})
+        }
+        printTable(cmd, []string{"NAME", "SERVICE", "METHODS", "HOSTS", "PATHS", "TAGS", "AGE"}, rows)
+        return nil
+    },
+}
+
+var routeDeleteCmd = &cobra.Command{
+    Use:   "delete <name|id>",
+    Short: "删除 Route（其上的插件一并删除，所属 Service 保留）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl route delete user-list
+kongctl route delete user-list --yes`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        rt, ok, err := client.GetRoute(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Route 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        label := rt.Name
+        if label == "" { label = rt.ID }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Route：%s（含其上的插件）", label)
+            return nil
+        }
+        if ok, err := confirmDelete(cmd, fmt.Sprintf("route delete %s（含其上的插件）", label)); err != nil || !ok {
+            return err
+        }
+        // 等待确认的时间不计入超时：删除使用新的 context
+        ctx, cancel = context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        if err := client.DeleteRoute(ctx, rt.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Route：%s", label)
+        return nil
+    },
+}
+
 func init() {
-    routeCmd.AddCommand(routeSyncCmd)
+    routeCmd.AddCommand(routeSyncCmd, routeListCmd, routeDeleteCmd)
+    routeSyncCmd.Flags().StringVarP(&applyFile, "file", "f", "", "从单个 route 文件同步（格式同 apply 文件的 routes[] 条目，- 表示标准输入），例：-f route.yaml")
     routeSyncCmd.Flags().StringVar(&routeService, "service", "", "关联 Service 名称，例：--service user-service")
     routeSyncCmd.Flags().StringVar(&routeName, "name", "", "Route 名称（留空自动生成），例：--name user-list")
     routeSyncCmd.Flags().StringSliceVar(&routePaths, "paths", nil, "匹配路径，逗号分隔或多次传入，例：--paths /v1/ping,/v1/users")
@@ -125,6 +307,11 @@ func init() {
     routeSyncCmd.Flags().StringVar(&routePathHandling, "path-handling", "", "路径匹配规则：v0 或 v1（默认沿用 Kong 端）")
     routeSyncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更，例：--dry-run --diff")
     routeSyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    routeListCmd.Flags().StringVar(&routeService, "service", "", "只列出该 Service 下的 routes，例：--service user-service")
+    listFilterFlag(routeListCmd)
+    addTemplateFlag(routeListCmd)
+    routeDeleteCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    routeDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "跳过删除前的 [y/N] 确认（非交互环境必需）")
 }
 
 func toUpper(xs []string) []string {
@@ -0,0 +1,144 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// terminationPlugin 为 Kong 内置的请求终止插件
+const terminationPlugin = "request-termination"
+
+var (
+    terminateRoute       string
+    terminateStatus      int
+    terminateMessage     string
+    terminateBody        string
+    terminateContentType string
+    terminateRemove      bool
+)
+
+var routeTerminateCmd = &cobra.Command{
+    Use:   "terminate",
+    Short: "让 Route 直接返回固定响应（下线/弃用接口），基于 request-termination 插件",
+    Long: `在指定 Route 上创建或更新 request-termination 插件，请求不再转发到上游，而是直接返回
+--status 与 --message（或自定义 --body/--content-type）。重复执行为幂等更新；--remove 删除该插件以恢复转发。`,
+    Example: `# 接口下线：返回 410 与提示信息
+kongctl route terminate --name legacy-api --status 410 --message 'API retired'
+
+# 自定义响应体
+kongctl route terminate --name legacy-api --status 404 --body '{"error":"moved"}' --content-type application/json
+
+# 预览变更
+kongctl route terminate --name legacy-api --status 410 --dry-run
+
+# 恢复转发
+kongctl route terminate --name legacy-api --remove`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if terminateRoute == "" {
+            return fmt.Errorf("必须通过 --name 指定 Route")
+        }
+        if !terminateRemove && (terminateStatus < 100 || terminateStatus > 599) {
+            return fmt.Errorf("--status 必须为 100~599 的 HTTP 状态码：%d", terminateStatus)
+        }
+        if terminateMessage != "" && terminateBody != "" {
+            return fmt.Errorf("--message 与 --body 不能同时使用")
+        }
+        if terminateContentType != "" && terminateBody == "" {
+            return fmt.Errorf("--content-type 需与 --body 一起使用")
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        if _, ok, err := client.GetRoute(ctx, terminateRoute); err != nil {
+            return err
+        } else if !ok {
+            return withCode("not_found", "", fmt.Errorf("Route 不存在：%s", terminateRoute))
+        }
+        cur, exists, err := client.GetRoutePlugin(ctx, terminateRoute, terminationPlugin)
+        if err != nil {
+            return err
+        }
+
+        if terminateRemove {
+            if !exists {
+                PrintInfo(cmd, "Route %s 未配置 %s，无需移除", terminateRoute, terminationPlugin)
+                return nil
+            }
+            if dryRun {
+                PrintInfo(cmd, "[dry-run] 将移除 Route %s 的 %s 插件（恢复转发）", terminateRoute, terminationPlugin)
+                return nil
+            }
+            if err := client.DeletePlugin(ctx, cur.ID); err != nil {
+                return err
+            }
+            PrintSuccess(cmd, "已移除 Route %s 的 %s，请求恢复转发", terminateRoute, terminationPlugin)
+            return nil
+        }
+
+        conf := map[string]any{"status_code": terminateStatus}
+        if terminateMessage != "" { conf["message"] = terminateMessage }
+        if terminateBody != "" {
+            conf["body"] = terminateBody
+            ct := terminateContentType
+            if ct == "" { ct = "text/plain" }
+            conf["content_type"] = ct
+        }
+        // 更新时显式清空未使用的字段，避免 message/body 并存
+        if exists {
+            for _, k := range []string{"message", "body", "content_type"} {
+                if _, ok := conf[k]; !ok { conf[k] = nil }
+            }
+        }
+        enabled := true
+        desired := kong.Plugin{Name: terminationPlugin, Config: conf, Enabled: &enabled}
+
+        if showDiff || dryRun {
+            if !exists {
+                PrintInfo(cmd, "%sDiff: 新建 %s（Route %s）", emojiDiff, terminationPlugin, terminateRoute)
+            } else {
+                PrintInfo(cmd, "%sDiff:", emojiDiff)
+                for _, k := range []string{"status_code", "message", "body", "content_type"} {
+                    was, now := fmt.Sprint(cur.Config[k]), fmt.Sprint(conf[k])
+                    if cur.Config[k] == nil { was = "" }
+                    if conf[k] == nil { now = "" }
+                    if was != now { cmd.Printf("%s: %s -> %s\n", k, was, now) }
+                }
+                if cur.Enabled != nil && !*cur.Enabled { cmd.Printf("enabled: false -> true\n") }
+            }
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将使 Route %s 直接返回 HTTP %d", terminateRoute, terminateStatus)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("route terminate %s（HTTP %d）", terminateRoute, terminateStatus)); err != nil {
+            return err
+        }
+        action, _, err := client.CreateOrUpdateRoutePlugin(ctx, terminateRoute, desired)
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s %s：Route %s 将直接返回 HTTP %d", actionCN(action), terminationPlugin, terminateRoute, terminateStatus)
+        return nil
+    },
+}
+
+func init() {
+    routeCmd.AddCommand(routeTerminateCmd)
+    routeTerminateCmd.Flags().StringVar(&terminateRoute, "name", "", "Route 名称或 id，例：--name legacy-api")
+    routeTerminateCmd.Flags().IntVar(&terminateStatus, "status", 503, "返回的 HTTP 状态码，例：--status 410")
+    routeTerminateCmd.Flags().StringVar(&terminateMessage, "message", "", "返回的提示信息（Kong 以 JSON {\"message\": ...} 返回），例：--message 'API retired'")
+    routeTerminateCmd.Flags().StringVar(&terminateBody, "body", "", "自定义原始响应体（与 --message 二选一）")
+    routeTerminateCmd.Flags().StringVar(&terminateContentType, "content-type", "", "--body 的 Content-Type（默认 text/plain）")
+    routeTerminateCmd.Flags().BoolVar(&terminateRemove, "remove", false, "移除 request-termination 插件，恢复转发")
+    routeTerminateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    routeTerminateCmd.Flags().BoolVar(&showDiff, "diff", false, "显示与当前插件配置的差异")
+}
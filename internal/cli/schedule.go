@@ -0,0 +1,425 @@
+package cli
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// 定时变更的状态
+const (
+    schedulePending  = "pending"  // 等待执行
+    scheduleRunning  = "running"  // 执行/回滚中（防止并发的 runner 重复处理）
+    scheduleApplied  = "applied"  // 已执行，等待自动回滚
+    scheduleDone     = "done"     // 已执行，无需回滚
+    scheduleReverted = "reverted" // 已回滚
+    scheduleFailed   = "failed"
+    scheduleCanceled = "canceled"
+)
+
+var (
+    scheduleAt          string
+    scheduleRevertAfter time.Duration
+    scheduleLoop        bool
+    scheduleInterval    time.Duration
+)
+
+// scheduledChange 为一条定时变更，保存在 ~/.kongctl/schedules/<id>.json
+type scheduledChange struct {
+    ID          string         `json:"id"`
+    File        string         `json:"file"`
+    Context     string         `json:"context,omitempty"`
+    AdminURL    string         `json:"admin_url"`
+    CreatedAt   time.Time      `json:"created_at"`
+    At          time.Time      `json:"at"`
+    RevertAfter string         `json:"revert_after,omitempty"`
+    Overwrite   bool           `json:"overwrite"`
+    Spec        applySpec      `json:"spec"`
+    Status      string         `json:"status"`
+    AppliedAt   *time.Time     `json:"applied_at,omitempty"`
+    RevertedAt  *time.Time     `json:"reverted_at,omitempty"`
+    Error       string         `json:"error,omitempty"`
+    // 执行前的远程状态：Snapshot 为被更新资源的原配置，Created 为本次新建、回滚时需删除的资源
+    Snapshot *applySpec    `json:"snapshot,omitempty"`
+    Created  []aplan.Change `json:"created,omitempty"`
+}
+
+// revertAt 返回自动回滚时间；未设置 --revert-after 或尚未执行时返回零值
+func (s *scheduledChange) revertAt() time.Time {
+    if s.RevertAfter == "" || s.AppliedAt == nil { return time.Time{} }
+    d, err := time.ParseDuration(s.RevertAfter)
+    if err != nil { return time.Time{} }
+    return s.AppliedAt.Add(d)
+}
+
+func scheduleDir() (string, error) {
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "schedules"), nil
+}
+
+func saveSchedule(s *scheduledChange) error {
+    dir, err := scheduleDir()
+    if err != nil {
+        return err
+    }
+    b, err := json.MarshalIndent(s, "", "  ")
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(dir, 0o700); err != nil {
+        return fmt.Errorf("创建目录失败：%w", err)
+    }
+    if err := writeTextFile(filepath.Join(dir, s.ID+".json"), append(b, '\n'), 0o600); err != nil {
+        return fmt.Errorf("保存定时变更失败：%w", err)
+    }
+    return nil
+}
+
+// loadSchedules 读取全部定时变更，按执行时间排序
+func loadSchedules() ([]*scheduledChange, error) {
+    dir, err := scheduleDir()
+    if err != nil {
+        return nil, err
+    }
+    files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+    if err != nil {
+        return nil, err
+    }
+    var out []*scheduledChange
+    for _, f := range files {
+        b, err := os.ReadFile(f)
+        if err != nil {
+            return nil, fmt.Errorf("读取定时变更失败：%w", err)
+        }
+        var s scheduledChange
+        if err := json.Unmarshal(b, &s); err != nil {
+            return nil, fmt.Errorf("解析定时变更 %s 失败：%w", filepath.Base(f), err)
+        }
+        out = append(out, &s)
+    }
+    sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+    return out, nil
+}
+
+func findSchedule(id string) (*scheduledChange, error) {
+    all, err := loadSchedules()
+    if err != nil {
+        return nil, err
+    }
+    for _, s := range all {
+        if s.ID == id { return s, nil }
+    }
+    return nil, withCode("not_found", "使用 kongctl schedule list 查看已有的定时变更", fmt.Errorf("未找到定时变更：%s", id))
+}
+
+// scheduleTimeLayouts 为 --at 接受的时间格式；未带时区的按本地时间解析
+var scheduleTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+func parseScheduleTime(v string) (time.Time, error) {
+    v = strings.TrimSpace(v)
+    for _, layout := range scheduleTimeLayouts {
+        if t, err := time.ParseInLocation(layout, v, time.Local); err == nil { return t, nil }
+    }
+    return time.Time{}, fmt.Errorf("--at 时间格式无效：%s（例：2024-06-01T02:00Z、2024-06-01 10:00）", v)
+}
+
+func newScheduleID(at time.Time) string {
+    b := make([]byte, 3)
+    _, _ = rand.Read(b)
+    return at.UTC().Format("20060102T1504Z") + "-" + hex.EncodeToString(b)
+}
+
+var scheduleCmd = &cobra.Command{
+    Use:   "schedule",
+    Short: "定时执行 apply（计划内的流量切换/维护窗口），可到期自动回滚",
+    Long: `定时变更保存在本地 ~/.kongctl/schedules/ 下，由 kongctl schedule run 到期执行：
+可通过 cron/systemd timer 周期调用 kongctl schedule run，或常驻运行 kongctl schedule run --loop。
+指定 --revert-after 时，执行前记录被变更资源的原配置，到期后恢复原配置并删除本次新建的资源。`,
+}
+
+var scheduleApplyCmd = &cobra.Command{
+    Use:   "apply",
+    Short: "登记一次定时 apply",
+    Example: `# 周六 02:00（UTC）切换到维护配置，4 小时后自动回滚
+kongctl schedule apply -f weekend-maintenance.yaml --at '2024-06-01T02:00Z' --revert-after 4h
+
+# 执行到期的定时变更（可放入 cron：* * * * * kongctl schedule run）
+kongctl schedule run`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if scheduleAt == "" {
+            return fmt.Errorf("必须通过 --at 指定执行时间")
+        }
+        at, err := parseScheduleTime(scheduleAt)
+        if err != nil {
+            return err
+        }
+        if !at.After(time.Now()) {
+            return fmt.Errorf("执行时间 %s 已过，请指定将来的时间", at.Local().Format("2006-01-02 15:04:05"))
+        }
+        if scheduleRevertAfter < 0 {
+            return fmt.Errorf("--revert-after 不能为负数")
+        }
+        spec, err := loadApplyInput(cmd)
+        if err != nil {
+            return err
+        }
+        spec.Include = nil
+        op := "schedule apply（" + applyFile + "）"
+        if scheduleRevertAfter > 0 { op += "，" + scheduleRevertAfter.String() + " 后自动回滚" }
+        if err := confirmDestructive(cmd, op); err != nil {
+            return err
+        }
+        s := &scheduledChange{
+            ID: newScheduleID(at), File: applyFile, Context: activeContext, AdminURL: viper.GetString("admin_url"),
+            CreatedAt: time.Now().UTC(), At: at.UTC(), Overwrite: applyOverwrite, Spec: spec, Status: schedulePending,
+        }
+        if scheduleRevertAfter > 0 { s.RevertAfter = scheduleRevertAfter.String() }
+        if err := saveSchedule(s); err != nil {
+            return err
+        }
+        msg := fmt.Sprintf("已登记定时变更 %s：%s 执行 %s", s.ID, at.Local().Format("2006-01-02 15:04:05"), applyFile)
+        if s.RevertAfter != "" { msg += "，" + s.RevertAfter + " 后自动回滚" }
+        PrintSuccess(cmd, "%s", msg)
+        PrintInfo(cmd, "定时变更由 kongctl schedule run 执行：请通过 cron 周期调用，或常驻运行 kongctl schedule run --loop")
+        return nil
+    },
+}
+
+var scheduleListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出定时变更",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        all, err := loadSchedules()
+        if err != nil {
+            return err
+        }
+        if outputJSON() {
+            if all == nil { all = []*scheduledChange{} }
+            b, _ := json.MarshalIndent(all, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(all) == 0 {
+            PrintInfo(cmd, "没有定时变更")
+            return nil
+        }
+        cmd.Printf("%-24s %-9s %-19s %-19s %-10s %s\n", "ID", "STATUS", "AT", "REVERT", "CONTEXT", "FILE")
+        for _, s := range all {
+            revert := "-"
+            if t := s.revertAt(); !t.IsZero() {
+                revert = t.Local().Format("2006-01-02 15:04:05")
+            } else if s.RevertAfter != "" {
+                revert = "+" + s.RevertAfter
+            }
+            ctxName := s.Context
+            if ctxName == "" { ctxName = "-" }
+            cmd.Printf("%-24s %-9s %-19s %-19s %-10s %s\n", s.ID, s.Status, s.At.Local().Format("2006-01-02 15:04:05"), revert, ctxName, s.File)
+            if s.Error != "" { cmd.Printf("  %s %s\n", colorError(glyph("✘", "[ERROR]")), s.Error) }
+        }
+        return nil
+    },
+}
+
+var scheduleCancelCmd = &cobra.Command{
+    Use:   "cancel <id>",
+    Short: "取消尚未执行的定时变更，或取消已执行变更的自动回滚",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        s, err := findSchedule(args[0])
+        if err != nil {
+            return err
+        }
+        switch s.Status {
+        case schedulePending:
+            s.Status = scheduleCanceled
+            if err := saveSchedule(s); err != nil { return err }
+            PrintSuccess(cmd, "已取消定时变更：%s", s.ID)
+        case scheduleApplied:
+            s.Status = scheduleDone
+            if err := saveSchedule(s); err != nil { return err }
+            PrintSuccess(cmd, "已取消定时变更 %s 的自动回滚（变更保留）", s.ID)
+        default:
+            return fmt.Errorf("定时变更 %s 当前状态为 %s，无法取消", s.ID, s.Status)
+        }
+        return nil
+    },
+}
+
+var scheduleRunCmd = &cobra.Command{
+    Use:   "run",
+    Short: "执行到期的定时变更与自动回滚",
+    Long: `执行当前上下文中已到期的定时变更与自动回滚；其他上下文的定时变更需以对应 --context 运行。
+不带 --loop 时处理一轮后退出，适合由 cron/systemd timer 周期调用。`,
+    Example: `# 单次执行（cron）
+kongctl schedule run --context prod
+
+# 常驻运行，每 30 秒检查一次
+kongctl schedule run --loop --interval 30s`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if scheduleInterval <= 0 {
+            return fmt.Errorf("--interval 必须大于 0")
+        }
+        for {
+            err := runDueSchedules(cmd)
+            if !scheduleLoop {
+                return err
+            }
+            if err != nil {
+                PrintWarn(cmd, "%v", err)
+            }
+            select {
+            case <-cmd.Context().Done():
+                return nil
+            case <-time.After(scheduleInterval):
+            }
+        }
+    },
+}
+
+// runDueSchedules 处理一轮到期的定时变更；单条失败不影响其余条目，最后汇总返回
+func runDueSchedules(cmd *cobra.Command) error {
+    all, err := loadSchedules()
+    if err != nil {
+        return err
+    }
+    now := time.Now()
+    adminURL := viper.GetString("admin_url")
+    failed := 0
+    for _, s := range all {
+        var run func(*cobra.Command, *scheduledChange) error
+        switch {
+        case s.Status == schedulePending && !s.At.After(now):
+            run = executeSchedule
+        case s.Status == scheduleApplied && !s.revertAt().IsZero() && !s.revertAt().After(now):
+            run = revertSchedule
+        default:
+            continue
+        }
+        if s.Context != activeContext || s.AdminURL != adminURL {
+            PrintInfo(cmd, "跳过定时变更 %s：属于 context=%s admin_url=%s（请以 --context %s 运行）", s.ID, s.Context, s.AdminURL, s.Context)
+            continue
+        }
+        s.Status = scheduleRunning
+        if err := saveSchedule(s); err != nil {
+            return err
+        }
+        if err := run(cmd, s); err != nil {
+            failed++
+            s.Status, s.Error = scheduleFailed, err.Error()
+            PrintWarn(cmd, "定时变更 %s 失败：%v", s.ID, err)
+        }
+        if err := saveSchedule(s); err != nil {
+            return err
+        }
+    }
+    if failed > 0 {
+        return fmt.Errorf("%d 项定时变更执行失败，详见 kongctl schedule list", failed)
+    }
+    return nil
+}
+
+func scheduleClient(tags []string) (*kong.Client, context.Context, context.CancelFunc, error) {
+    cfg, err := clientConfig(15 * time.Second)
+    if err != nil {
+        return nil, nil, nil, err
+    }
+    cfg.Tags = tags
+    cfg.CreateTags = []string{managedByTag()}
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+    return client, ctx, cancel, nil
+}
+
+// executeSchedule 执行定时变更；需自动回滚时先以 dry-run 计算计划并记录执行前的远程状态
+func executeSchedule(cmd *cobra.Command, s *scheduledChange) error {
+    client, ctx, cancel, err := scheduleClient(applyTags(s.Spec))
+    if err != nil {
+        return err
+    }
+    defer cancel()
+    applyOverwrite, applyPrune, applyPruneTargets = s.Overwrite, false, false
+    applyIgnoreFields = nil
+    applyLastApplied = nil
+    PrintInfo(cmd, "执行定时变更 %s（%s，计划时间 %s）", s.ID, s.File, s.At.Local().Format("2006-01-02 15:04:05"))
+    if s.RevertAfter != "" {
+        if err := snapshotSchedule(cmd, ctx, client, s); err != nil {
+            return fmt.Errorf("记录执行前状态失败：%w", err)
+        }
+        // 先落盘快照，执行中途失败也能据此手工恢复
+        if err := saveSchedule(s); err != nil {
+            return err
+        }
+    }
+    if err := runApplyPhase(cmd, ctx, client, s.Spec, &aplan.Plan{}); err != nil {
+        return err
+    }
+    now := time.Now().UTC()
+    s.AppliedAt = &now
+    s.Status = scheduleDone
+    if s.RevertAfter != "" {
+        s.Status = scheduleApplied
+        PrintSuccess(cmd, "已执行定时变更 %s，将于 %s 自动回滚", s.ID, s.revertAt().Local().Format("2006-01-02 15:04:05"))
+    } else {
+        PrintSuccess(cmd, "已执行定时变更 %s", s.ID)
+    }
+    return nil
+}
+
+// snapshotSchedule 计算计划，记录将被更新的 upstream/service/route 的当前配置与将新建的资源
+func snapshotSchedule(cmd *cobra.Command, ctx context.Context, client *kong.Client, s *scheduledChange) error {
+    plan := &aplan.Plan{}
+    dryRun = true
+    err := runApplyPhase(cmd, ctx, client, s.Spec, plan)
+    dryRun = false
+    if err != nil {
+        return err
+    }
+    s.Snapshot, s.Created, err = captureSnapshot(cmd, ctx, client, *plan)
+    return err
+}
+
+// revertSchedule 恢复执行前的配置并删除本次新建的资源
+func revertSchedule(cmd *cobra.Command, s *scheduledChange) error {
+    client, ctx, cancel, err := scheduleClient(defaultTags())
+    if err != nil {
+        return err
+    }
+    defer cancel()
+    PrintInfo(cmd, "回滚定时变更 %s（%s）", s.ID, s.File)
+    if err := restoreSnapshot(cmd, ctx, client, s.Snapshot, s.Created); err != nil {
+        return err
+    }
+    now := time.Now().UTC()
+    s.RevertedAt = &now
+    s.Status = scheduleReverted
+    PrintSuccess(cmd, "已回滚定时变更 %s", s.ID)
+    return nil
+}
+
+func init() {
+    rootCmd.AddCommand(scheduleCmd)
+    scheduleCmd.AddCommand(scheduleApplyCmd, scheduleListCmd, scheduleCancelCmd, scheduleRunCmd)
+    scheduleApplyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "配置文件路径（YAML/JSON，- 表示标准输入）")
+    scheduleApplyCmd.Flags().StringVar(&scheduleAt, "at", "", "执行时间，例：2024-06-01T02:00Z（未带时区按本地时间）")
+    scheduleApplyCmd.Flags().DurationVar(&scheduleRevertAfter, "revert-after", 0, "执行后经过该时长自动回滚，例：4h")
+    scheduleApplyCmd.Flags().BoolVar(&applyOverwrite, "overwrite", false, "执行时覆盖已存在资源的差异（同 apply --overwrite）")
+    scheduleApplyCmd.Flags().StringArrayVar(&applySelect, "select", nil, "仅处理匹配的资源（同 apply --select）")
+    addRenderFlags(scheduleApplyCmd)
+    scheduleRunCmd.Flags().BoolVar(&scheduleLoop, "loop", false, "常驻运行，按 --interval 周期检查")
+    scheduleRunCmd.Flags().DurationVar(&scheduleInterval, "interval", 30*time.Second, "--loop 模式下的检查间隔")
+}
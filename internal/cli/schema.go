@@ -0,0 +1,286 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    "kongctl/internal/kong"
+)
+
+// schemaEntities 为 apply 预检与 schema refresh 使用的实体 schema
+var schemaEntities = []string{"services", "routes", "upstreams", "targets", "consumers"}
+
+// schemaVersionTTL 内复用记录的网关版本，不再请求 Admin API 根路径；升级 Kong 后可执行 schema refresh 立即更新
+const schemaVersionTTL = 24 * time.Hour
+
+var schemaRefreshAll bool
+
+// schemaIndex 记录各网关（admin_url + workspace）最近一次读取到的版本，保存在 ~/.kongctl/schemas/gateways.json；
+// 网关不可达时据此找到对应版本的缓存
+type schemaIndex struct {
+    Gateways map[string]schemaGateway `json:"gateways"`
+}
+
+type schemaGateway struct {
+    Version   string    `json:"version"`
+    CheckedAt time.Time `json:"checked_at"`
+}
+
+// schemaCache 为某个 Kong 版本的 schema 磁盘缓存（~/.kongctl/schemas/<version>/<entity>.json）；
+// 同版本的 schema 不会变化，命中时不访问 Admin API
+type schemaCache struct {
+    ctx     context.Context
+    client  *kong.Client
+    dir     string
+    Version string
+    Offline bool // 网关不可达，只使用已有缓存
+}
+
+func schemaRoot() (string, error) {
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "schemas"), nil
+}
+
+func schemaGatewayKey() string {
+    return viper.GetString("admin_url") + "\x00" + viper.GetString("workspace")
+}
+
+func loadSchemaIndex(root string) schemaIndex {
+    idx := schemaIndex{Gateways: map[string]schemaGateway{}}
+    if data, err := os.ReadFile(filepath.Join(root, "gateways.json")); err == nil {
+        _ = json.Unmarshal(data, &idx)
+        if idx.Gateways == nil { idx.Gateways = map[string]schemaGateway{} }
+    }
+    return idx
+}
+
+func saveSchemaIndex(root string, idx schemaIndex) error {
+    if err := os.MkdirAll(root, 0o700); err != nil {
+        return err
+    }
+    b, err := json.MarshalIndent(idx, "", "  ")
+    if err != nil {
+        return err
+    }
+    return writeTextFile(filepath.Join(root, "gateways.json"), append(b, '\n'), 0o600)
+}
+
+// openSchemaCache 确定当前网关的版本并打开对应的缓存；refresh 为 true 时总是重新读取版本。
+// 读取版本失败（离线）时回落到上次记录的版本，仅使用已缓存的 schema
+func openSchemaCache(ctx context.Context, client *kong.Client, refresh bool) (*schemaCache, error) {
+    root, err := schemaRoot()
+    if err != nil {
+        return nil, err
+    }
+    idx := loadSchemaIndex(root)
+    key := schemaGatewayKey()
+    gw, known := idx.Gateways[key]
+    sc := &schemaCache{ctx: ctx, client: client, Version: gw.Version}
+    if refresh || !known || time.Since(gw.CheckedAt) >= schemaVersionTTL {
+        info, err := client.GetInfo(ctx)
+        switch {
+        case err == nil:
+            sc.Version = info.Version
+            idx.Gateways[key] = schemaGateway{Version: info.Version, CheckedAt: time.Now().UTC()}
+            if err := saveSchemaIndex(root, idx); err != nil {
+                return nil, err
+            }
+        case known:
+            sc.Offline = true
+        default:
+            return nil, fmt.Errorf("读取网关版本失败，且本地没有该网关的 schema 缓存：%w", err)
+        }
+    }
+    sc.dir = filepath.Join(root, strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(sc.Version))
+    return sc, nil
+}
+
+// get 返回实体的 schema：优先读取磁盘缓存，未命中时请求 /schemas/<entity> 并写入缓存；
+// 离线且未缓存或网关不提供该 schema 时返回 found=false
+func (sc *schemaCache) get(entity string) (schema kong.EntitySchema, found bool, err error) {
+    path := filepath.Join(sc.dir, strings.ReplaceAll(entity, "/", "_")+".json")
+    data, rerr := os.ReadFile(path)
+    if rerr != nil {
+        if sc.Offline { return kong.EntitySchema{}, false, nil }
+        raw, ok, err := sc.client.GetSchema(sc.ctx, entity)
+        if err != nil || !ok { return kong.EntitySchema{}, false, err }
+        data = raw
+        if err := os.MkdirAll(sc.dir, 0o700); err != nil {
+            return kong.EntitySchema{}, false, err
+        }
+        if err := writeTextFile(path, data, 0o600); err != nil {
+            return kong.EntitySchema{}, false, err
+        }
+    }
+    if err := json.Unmarshal(data, &schema); err != nil {
+        return kong.EntitySchema{}, false, fmt.Errorf("解析 %s 的 schema 失败（可执行 kongctl schema refresh）：%w", entity, err)
+    }
+    return schema, true, nil
+}
+
+// schemaValueIssue 按字段定义的 one_of/between 检查取值，不符合时返回问题描述
+func schemaValueIssue(where, field string, def kong.SchemaField, v any) string {
+    if len(def.OneOf) > 0 {
+        allowed := make([]string, len(def.OneOf))
+        hit := false
+        for i, o := range def.OneOf {
+            allowed[i] = fmt.Sprint(o)
+            if allowed[i] == fmt.Sprint(v) { hit = true }
+        }
+        if !hit { return fmt.Sprintf("%s.%s 取值 %v 不在网关允许的范围内（%s）", where, field, v, strings.Join(allowed, "/")) }
+    }
+    if len(def.Between) == 2 {
+        if n, ok := v.(int); ok && (float64(n) < def.Between[0] || float64(n) > def.Between[1]) {
+            return fmt.Sprintf("%s.%s 取值 %d 超出网关允许的范围（%v-%v）", where, field, n, def.Between[0], def.Between[1])
+        }
+    }
+    return ""
+}
+
+// checkSpecSchemas 按网关的 services/routes schema 检查 spec 中的枚举与取值范围；
+// 某个实体的 schema 不可用时跳过该实体
+func checkSpecSchemas(sc *schemaCache, spec applySpec) ([]string, error) {
+    var issues []string
+    check := func(s kong.EntitySchema, where, field string, v any, set bool) {
+        if !set { return }
+        def, ok := s.Field(field)
+        if !ok { return }
+        if msg := schemaValueIssue(where, field, def, v); msg != "" { issues = append(issues, msg) }
+    }
+    if len(spec.Services) > 0 {
+        s, ok, err := sc.get("services")
+        if err != nil { return nil, err }
+        for _, svc := range spec.Services {
+            if !ok { break }
+            where := "services[" + svc.Name + "]"
+            check(s, where, "protocol", specServiceProtocol(svc), true)
+            check(s, where, "retries", svc.Retries, svc.Retries > 0)
+            check(s, where, "connect_timeout", svc.ConnectTimeout, svc.ConnectTimeout > 0)
+            check(s, where, "read_timeout", svc.ReadTimeout, svc.ReadTimeout > 0)
+            check(s, where, "write_timeout", svc.WriteTimeout, svc.WriteTimeout > 0)
+        }
+    }
+    if len(spec.Routes) > 0 {
+        s, ok, err := sc.get("routes")
+        if err != nil { return nil, err }
+        for _, r := range spec.Routes {
+            if !ok { break }
+            where := "routes[" + specRouteName(r) + "]"
+            if def, found := s.Field("protocols"); found && def.Elements != nil {
+                for _, p := range r.Protocols {
+                    if msg := schemaValueIssue(where, "protocols", *def.Elements, p); msg != "" { issues = append(issues, msg) }
+                }
+            }
+            check(s, where, "path_handling", r.PathHandling, r.PathHandling != "")
+            check(s, where, "https_redirect_status_code", r.HTTPSRedirectStatusCode, r.HTTPSRedirectStatusCode != 0)
+            check(s, where, "regex_priority", r.RegexPriority, r.RegexPriority != 0)
+        }
+    }
+    return issues, nil
+}
+
+// preflightSchemas 在 apply 规划前按网关 schema 检查 spec；schema 不可用时只提示，不阻止 apply
+func preflightSchemas(cmd *cobra.Command, ctx context.Context, client *kong.Client, spec applySpec) error {
+    sc, err := openSchemaCache(ctx, client, false)
+    if err == nil {
+        var issues []string
+        if issues, err = checkSpecSchemas(sc, spec); err == nil && len(issues) > 0 {
+            return withCode("usage", "", fmt.Errorf("spec 不符合网关（Kong %s）的 schema：\n  %s", sc.Version, strings.Join(issues, "\n  ")))
+        }
+    }
+    if err != nil {
+        PrintWarn(cmd, "读取网关 schema 失败，跳过 schema 预检：%v", err)
+    }
+    return nil
+}
+
+// validateAgainstGateway 为 validate --gateway：按当前网关的 schema 检查 spec，必要时使用离线缓存
+func validateAgainstGateway(cmd *cobra.Command, spec applySpec) ([]string, error) {
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return nil, err
+    }
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    defer cancel()
+    sc, err := openSchemaCache(ctx, client, false)
+    if err != nil {
+        return nil, err
+    }
+    if sc.Offline { PrintWarn(cmd, "无法连接网关，使用已缓存的 Kong %s schema 校验", sc.Version) }
+    return checkSpecSchemas(sc, spec)
+}
+
+var schemaCmd = &cobra.Command{
+    Use:   "schema",
+    Short: "管理本地的网关 schema 缓存",
+    Long: `apply 与 validate --gateway 按网关的 /schemas 检查协议、path_handling、超时等字段的取值。
+schema 按 Kong 版本缓存在 ~/.kongctl/schemas/<version>/ 下，同版本只请求一次；网关不可达时使用已缓存的版本离线校验。`,
+}
+
+var schemaRefreshCmd = &cobra.Command{
+    Use:   "refresh",
+    Short: "重新读取当前网关的版本并更新其 schema 缓存",
+    Example: `# 升级 Kong 后更新缓存
+kongctl schema refresh
+
+# 清空所有网关版本的缓存
+kongctl schema refresh --all`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        root, err := schemaRoot()
+        if err != nil {
+            return err
+        }
+        if schemaRefreshAll {
+            if err := os.RemoveAll(root); err != nil {
+                return err
+            }
+            PrintInfo(cmd, "已清空 schema 缓存：%s", root)
+        }
+        cfg, err := clientConfig(15 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        sc, err := openSchemaCache(ctx, client, true)
+        if err != nil {
+            return err
+        }
+        if sc.Offline {
+            return fmt.Errorf("无法连接网关，未更新 schema 缓存")
+        }
+        if err := os.RemoveAll(sc.dir); err != nil {
+            return err
+        }
+        var got []string
+        for _, e := range schemaEntities {
+            if _, ok, err := sc.get(e); err != nil {
+                return err
+            } else if ok {
+                got = append(got, e)
+            }
+        }
+        sort.Strings(got)
+        PrintSuccess(cmd, "已更新 Kong %s 的 schema 缓存（%s）：%s", sc.Version, strings.Join(got, ", "), sc.dir)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(schemaCmd)
+    schemaCmd.AddCommand(schemaRefreshCmd)
+    schemaRefreshCmd.Flags().BoolVar(&schemaRefreshAll, "all", false, "同时清空其他网关版本的缓存")
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+    "fmt"
+    "path"
+    "strings"
+)
+
+var applySelect []string
+
+// specSelector 为一条 --select 过滤条件；同一条内各项需同时满足，多条 --select 之间满足任一即可
+type specSelector struct {
+    Kind string // upstream/service/route/consumer/consumer_group/certificate/vault/key_set，空表示任意
+    Name string // 名称通配（path.Match 语法，如 user-*）
+    Tag  string // 需包含的标签（支持通配）
+}
+
+var selectorKinds = map[string]string{
+    "upstream": "upstream", "upstreams": "upstream",
+    "service": "service", "services": "service",
+    "route": "route", "routes": "route",
+    "consumer": "consumer", "consumers": "consumer",
+    "consumer_group": "consumer_group", "consumer_groups": "consumer_group", "consumer-group": "consumer_group",
+    "certificate": "certificate", "certificates": "certificate", "cert": "certificate",
+    "vault": "vault", "vaults": "vault",
+    "key_set": "key_set", "key_sets": "key_set", "key-set": "key_set",
+}
+
+// parseSelectors 解析 --select/--selector 参数，例如 kind=route,name=user-*；flag 为报错时展示的参数名
+func parseSelectors(flag string, raw []string) ([]specSelector, error) {
+    var out []specSelector
+    for _, r := range raw {
+        var sel specSelector
+        for _, part := range strings.Split(r, ",") {
+            part = strings.TrimSpace(part)
+            if part == "" { continue }
+            k, v, ok := strings.Cut(part, "=")
+            if !ok {
+                return nil, fmt.Errorf("%s 格式错误：%q（应为 key=value，可用 key：kind、name、tag）", flag, part)
+            }
+            k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+            if _, err := path.Match(v, ""); err != nil {
+                return nil, fmt.Errorf("%s 通配符无效：%q：%v", flag, v, err)
+            }
+            switch k {
+            case "kind":
+                kind, ok := selectorKinds[strings.ToLower(v)]
+                if !ok { return nil, fmt.Errorf("%s 不支持的 kind：%s（可选：upstream、service、route、consumer、consumer_group、certificate、vault、key_set）", flag, v) }
+                sel.Kind = kind
+            case "name":
+                sel.Name = v
+            case "tag", "tags":
+                sel.Tag = v
+            default:
+                return nil, fmt.Errorf("%s 不支持的 key：%s（可用 key：kind、name、tag）", flag, k)
+            }
+        }
+        out = append(out, sel)
+    }
+    return out, nil
+}
+
+func (s specSelector) matches(kind, name string, tags []string) bool {
+    if s.Kind != "" && s.Kind != kind { return false }
+    if s.Name != "" {
+        if ok, _ := path.Match(s.Name, name); !ok { return false }
+    }
+    if s.Tag != "" {
+        found := false
+        for _, t := range tags {
+            if ok, _ := path.Match(s.Tag, t); ok { found = true; break }
+        }
+        if !found { return false }
+    }
+    return true
+}
+
+func anySelected(sels []specSelector, kind, name string, tags []string) bool {
+    for _, s := range sels {
+        if s.matches(kind, name, tags) { return true }
+    }
+    return false
+}
+
+// selectSpec 返回仅包含匹配资源的 spec；route 简写的 backend 随 route 一并保留，
+// common_tags 等 spec 级字段原样保留（只清空资源列表后按选择器重新填充）
+func selectSpec(spec applySpec, sels []specSelector) (applySpec, int) {
+    out := spec
+    out.Upstreams, out.Services, out.Routes, out.ConsumerGroups = nil, nil, nil, nil
+    out.Consumers, out.Certificates, out.Vaults, out.KeySets = nil, nil, nil, nil
+    for _, up := range spec.Upstreams {
+        if anySelected(sels, "upstream", up.Name, nil) { out.Upstreams = append(out.Upstreams, up) }
+    }
+    for _, s := range spec.Services {
+        if anySelected(sels, "service", s.Name, nil) { out.Services = append(out.Services, s) }
+    }
+    for _, r := range spec.Routes {
+        name := r.Name
+        if name == "" && r.Service != "" { name = defaultRouteName(r.Service, r.Paths, r.Methods) }
+        if anySelected(sels, "route", name, r.Tags) { out.Routes = append(out.Routes, r) }
+    }
+    for _, g := range spec.ConsumerGroups {
+        if anySelected(sels, "consumer_group", g.Name, g.Tags) { out.ConsumerGroups = append(out.ConsumerGroups, g) }
+    }
+    for _, c := range spec.Consumers {
+        if anySelected(sels, "consumer", c.key(), c.Tags) { out.Consumers = append(out.Consumers, c) }
+    }
+    for _, c := range spec.Certificates {
+        if anySelected(sels, "certificate", c.key(), c.Tags) { out.Certificates = append(out.Certificates, c) }
+    }
+    for _, v := range spec.Vaults {
+        if anySelected(sels, "vault", v.Prefix, v.Tags) { out.Vaults = append(out.Vaults, v) }
+    }
+    for _, ks := range spec.KeySets {
+        if anySelected(sels, "key_set", ks.Name, ks.Tags) { out.KeySets = append(out.KeySets, ks) }
+    }
+    return out, len(out.Upstreams) + len(out.Services) + len(out.Routes) + len(out.ConsumerGroups) + len(out.Consumers) + len(out.Certificates) + len(out.Vaults) + len(out.KeySets)
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "os/signal"
+    "path/filepath"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/testutil"
+)
+
+var (
+    selftestImage         string
+    selftestPostgresImage string
+    selftestKeep          bool
+    selftestTimeout       time.Duration
+)
+
+// selftestSpec 为 selftest 往返验证使用的 spec，覆盖 upstream/target、service 与 route
+const selftestSpec = `upstreams:
+  - name: selftest-upstream
+    targets:
+      - target: 127.0.0.1:9001
+        weight: 100
+services:
+  - name: selftest-service
+    upstream: selftest-upstream
+    protocol: http
+    port: 9001
+    path: /api
+    retries: 3
+routes:
+  - name: selftest-route
+    service: selftest-service
+    paths: ["/selftest"]
+    methods: ["GET"]
+    strip_path: true
+`
+
+// selftestStep 为一次 kongctl 调用；Want 为期望的退出码，Check 对输出做额外校验
+type selftestStep struct {
+    Name  string
+    Args  []string
+    Want  int
+    Check func(dir, out string) error
+}
+
+// selftestResult 为单步执行结果
+type selftestResult struct {
+    Step     string  `json:"step"`
+    Command  string  `json:"command"`
+    OK       bool    `json:"ok"`
+    ExitCode int     `json:"exit_code"`
+    Seconds  float64 `json:"seconds"`
+    Error    string  `json:"error,omitempty"`
+    Output   string  `json:"output,omitempty"`
+}
+
+func selftestSteps() []selftestStep {
+    contains := func(file, want string) func(dir, out string) error {
+        return func(dir, out string) error {
+            b, err := os.ReadFile(filepath.Join(dir, file))
+            if err != nil {
+                return err
+            }
+            if !strings.Contains(string(b), want) {
+                return fmt.Errorf("%s 中缺少 %s", file, want)
+            }
+            return nil
+        }
+    }
+    return []selftestStep{
+        {Name: "apply 创建资源", Args: []string{"apply", "-f", "spec.yaml"}},
+        {Name: "再次规划无差异", Args: []string{"apply", "-f", "spec.yaml", "--dry-run", "--detailed-exitcode"}},
+        {Name: "export 导出", Args: []string{"export", "-o", "export.yaml"}, Check: contains("export.yaml", "selftest-route")},
+        {Name: "导出结果回放无差异", Args: []string{"apply", "-f", "export.yaml", "--dry-run", "--detailed-exitcode"}},
+        {Name: "识别字段差异", Args: []string{"apply", "-f", "changed.yaml", "--overwrite", "--dry-run", "--diff", "--detailed-exitcode"}, Want: exitChanges,
+            Check: func(dir, out string) error {
+                if !strings.Contains(out, "retries") { return fmt.Errorf("计划中未列出 retries 的变更") }
+                return nil
+            }},
+        {Name: "apply --overwrite 更新", Args: []string{"apply", "-f", "changed.yaml", "--overwrite"}},
+        {Name: "更新后无差异", Args: []string{"apply", "-f", "changed.yaml", "--dry-run", "--detailed-exitcode"}},
+    }
+}
+
+// selftestEnv 为子进程准备隔离的环境：独立 HOME（不读取用户配置与上下文），并清除 KONGCTL_* 变量
+func selftestEnv(home, adminURL string) []string {
+    var env []string
+    for _, kv := range os.Environ() {
+        k, _, _ := strings.Cut(kv, "=")
+        if strings.HasPrefix(strings.ToUpper(k), "KONGCTL_") { continue }
+        switch strings.ToUpper(k) {
+        case "HOME", "USERPROFILE", "NO_COLOR":
+            continue
+        }
+        env = append(env, kv)
+    }
+    return append(env, "HOME="+home, "USERPROFILE="+home, "KONGCTL_ADMIN_URL="+adminURL, "NO_COLOR=1")
+}
+
+// runSelftestSteps 依次以当前可执行文件运行各步骤，遇到失败即停止
+func runSelftestSteps(ctx context.Context, dir, adminURL string) ([]selftestResult, error) {
+    exe, err := os.Executable()
+    if err != nil {
+        return nil, err
+    }
+    files := map[string]string{"spec.yaml": selftestSpec, "changed.yaml": strings.Replace(selftestSpec, "retries: 3", "retries: 5", 1)}
+    for name, content := range files {
+        if err := writeTextFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+            return nil, err
+        }
+    }
+    env := selftestEnv(dir, adminURL)
+    var results []selftestResult
+    for _, st := range selftestSteps() {
+        start := time.Now()
+        c := exec.CommandContext(ctx, exe, st.Args...)
+        c.Dir, c.Env = dir, env
+        out, err := c.CombinedOutput()
+        res := selftestResult{Step: st.Name, Command: "kongctl " + strings.Join(st.Args, " "), Seconds: time.Since(start).Seconds()}
+        var ee *exec.ExitError
+        switch {
+        case errors.As(err, &ee):
+            res.ExitCode = ee.ExitCode()
+        case err != nil:
+            return results, err
+        }
+        if res.ExitCode != st.Want {
+            res.Error = fmt.Sprintf("退出码 %d，期望 %d", res.ExitCode, st.Want)
+        } else if st.Check != nil {
+            if err := st.Check(dir, string(out)); err != nil { res.Error = err.Error() }
+        }
+        res.OK = res.Error == ""
+        if !res.OK { res.Output = lastLines(string(out), 20) }
+        results = append(results, res)
+        if !res.OK { break }
+    }
+    return results, nil
+}
+
+// lastLines 返回文本的最后 n 行
+func lastLines(s string, n int) string {
+    lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+    if len(lines) > n { lines = lines[len(lines)-n:] }
+    return strings.Join(lines, "\n")
+}
+
+var selftestCmd = &cobra.Command{
+    Use:   "selftest",
+    Short: "在一次性的 Kong 容器中验证当前 kongctl 与指定 Kong 版本的兼容性",
+    Long: `通过 docker 启动一次性的 Postgres 与 Kong（--image 指定版本），以当前 kongctl 依次执行
+apply 创建、dry-run 幂等检查、export 导出与回放、差异识别与 --overwrite 更新等往返验证，并报告各步结果。
+各步骤在独立的 HOME 中运行，不读取也不修改用户的配置与上下文；结束后删除容器与网络（--keep 保留以便排查）。
+需要本机可用的 docker；首次运行需拉取镜像，耗时较长。`,
+    Example: `# 验证当前 kongctl 对 Kong 3.4 的兼容性
+kongctl selftest
+
+# 指定 Kong 版本，失败时保留容器排查
+kongctl selftest --image kong:3.6 --keep`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if selftestTimeout <= 0 {
+            return withCode("usage", "", fmt.Errorf("--timeout 必须大于 0"))
+        }
+        // 中断时仍需执行清理，不能直接退出进程
+        ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+        defer stop()
+        ctx, cancel := context.WithTimeout(ctx, selftestTimeout)
+        defer cancel()
+        if err := testutil.DockerAvailable(ctx); err != nil {
+            return fmt.Errorf("selftest 需要本机可用的 docker：%w", err)
+        }
+        logf := func(format string, a ...any) {
+            if !outputJSON() { PrintInfo(cmd, format, a...) }
+        }
+        k, err := testutil.StartKong(ctx, testutil.Options{Image: selftestImage, PostgresImage: selftestPostgresImage, Logf: logf})
+        if err != nil {
+            return err
+        }
+        defer func() {
+            if selftestKeep {
+                PrintInfo(cmd, "已保留容器 %s（Admin API：%s），清理：docker rm -f -v %s && docker network rm %s", strings.Join(k.Names(), ", "), k.AdminURL, strings.Join(k.Names(), " "), k.Network)
+                return
+            }
+            if err := k.Close(); err != nil {
+                PrintWarn(cmd, "清理 selftest 容器失败：%v（可执行 docker rm -f $(docker ps -aq --filter label=%s)）", err, testutil.Label)
+            }
+        }()
+        logf("Kong %s 已就绪：%s", k.Version, k.AdminURL)
+
+        dir, err := os.MkdirTemp("", "kongctl-selftest-")
+        if err != nil {
+            return err
+        }
+        defer os.RemoveAll(dir)
+        results, err := runSelftestSteps(ctx, dir, k.AdminURL)
+        if err != nil {
+            return err
+        }
+
+        total := len(selftestSteps())
+        passed := 0
+        for _, r := range results {
+            if r.OK { passed++ }
+        }
+        v := version
+        if v == "" { v = "dev" }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(map[string]any{"kongctl": v, "kong_version": k.Version, "image": k.Image, "passed": passed, "total": total, "steps": results}, "", "  ")
+            cmd.Println(string(b))
+        } else {
+            for _, r := range results {
+                if r.OK {
+                    cmd.Printf("  %s %s（%.1fs）\n", colorSuccess(glyph("✔", "[OK]")), r.Step, r.Seconds)
+                    continue
+                }
+                cmd.Printf("  %s %s：%s\n", colorError(glyph("✘", "[FAIL]")), r.Step, r.Error)
+                cmd.Printf("    $ %s\n", r.Command)
+                for _, line := range strings.Split(r.Output, "\n") {
+                    cmd.Printf("    %s\n", line)
+                }
+            }
+        }
+        if passed < total {
+            return fmt.Errorf("selftest 未通过：kongctl %s 与 Kong %s（%s）共 %d 步，通过 %d 步", v, k.Version, k.Image, total, passed)
+        }
+        // 不使用 PrintSuccess：验证针对临时容器，与当前上下文无关
+        cmd.Println(colorSuccess(fmt.Sprintf("%s selftest 通过：kongctl %s 与 Kong %s（%s）往返验证 %d 步全部成功", emojiSuccess, v, k.Version, k.Image, total)))
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(selftestCmd)
+    selftestCmd.Flags().StringVar(&selftestImage, "image", "kong:3.4", "Kong 镜像，例：--image kong:3.6 或 kong/kong-gateway:3.4")
+    selftestCmd.Flags().StringVar(&selftestPostgresImage, "postgres-image", "postgres:13", "Postgres 镜像")
+    selftestCmd.Flags().BoolVar(&selftestKeep, "keep", false, "结束后保留容器与网络（便于排查失败原因）")
+    selftestCmd.Flags().DurationVar(&selftestTimeout, "timeout", 10*time.Minute, "整体超时（含拉取镜像与启动 Kong），例：--timeout 15m")
+}
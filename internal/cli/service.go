@@ -4,12 +4,12 @@ import (
     "context"
     "fmt"
     "net/url"
+    "sort"
     "strconv"
     "strings"
     "time"
 
     "github.com/spf13/cobra"
-    "github.com/spf13/viper"
     "kongctl/internal/kong"
 )
 
@@ -22,6 +22,7 @@ var (
     autoUpstream bool
     svcUpstream string
     targetWeight int
+    svcCascade bool
 )
 
 var serviceCmd = &cobra.Command{
@@ -47,14 +48,9 @@ kongctl service sync --name user --url http://user-svc:8080 --dry-run --diff`,
         if svcName == "" || svcURL == "" {
             return fmt.Errorf("必须提供 --name 与 --url")
         }
-        cfg := kong.Config{
-            AdminURL:      viper.GetString("admin_url"),
-            Token:         viper.GetString("token"),
-            TLSSkipVerify: viper.GetBool("tls_skip_verify"),
-            Timeout:       10 * time.Second,
-        }
-        if cfg.AdminURL == "" {
-            return fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置")
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
         }
         client := kong.NewClient(cfg)
         ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
@@ -87,7 +83,7 @@ kongctl service sync --name user --url http://user-svc:8080 --dry-run --diff`,
         if err != nil { return err }
         if autoUpstream {
             if showDiff {
-                PrintInfo(cmd, "📝 Diff: Service")
+                PrintInfo(cmd, "%sDiff: Service", emojiDiff)
                 if !exists {
                     cmd.Printf("%s\n", colorInfo(fmt.Sprintf("+ service: %s (host=%s port=%d path=%s protocol=%s)", svcName, upName, port, path, proto)))
                     cmd.Printf("%s\n", colorInfo(fmt.Sprintf("+ upstream: %s", upName)))
@@ -117,6 +113,10 @@ kongctl service sync --name user --url http://user-svc:8080 --dry-run --diff`,
             // 绑定 Service 到 Upstream
             action, _, err := client.CreateOrUpdateServiceViaUpstream(ctx, svcName, upName, proto, port, path)
             if err != nil { return err }
+            if action == "none" {
+                PrintInfo(cmd, "Service 无变更，未写入：%s（upstream=%s，target=%s）", svcName, upName, target)
+                return nil
+            }
             PrintSuccess(cmd, "已%sed Service：%s，关联 Upstream：%s（target=%s）", actionCN(action), svcName, upName, target)
             return nil
         }
@@ -124,15 +124,15 @@ kongctl service sync --name user --url http://user-svc:8080 --dry-run --diff`,
         // 非自动 Upstream：使用 URL 直接同步 Service
         if showDiff {
             if !exists {
-                PrintInfo(cmd, "📝 Diff: 新建 Service")
+                PrintInfo(cmd, "%sDiff: 新建 Service", emojiDiff)
                 cmd.Printf("%s\n", colorInfo(fmt.Sprintf("+ name: %s", svcName)))
                 cmd.Printf("%s\n", colorInfo(fmt.Sprintf("+ url: %s", svcURL)))
             } else {
                 curURL := reconstructURL(cur)
-                if curURL == svcURL {
-                    PrintInfo(cmd, "📝 Diff: 无字段变更")
+                if sameURL(curURL, svcURL) {
+                    PrintInfo(cmd, "%sDiff: 无字段变更", emojiDiff)
                 } else {
-                    PrintInfo(cmd, "📝 Diff:")
+                    PrintInfo(cmd, "%sDiff:", emojiDiff)
                     cmd.Printf("%s\n", colorWarn(fmt.Sprintf("- url: %s", curURL)))
                     cmd.Printf("%s\n", colorInfo(fmt.Sprintf("+ url: %s", svcURL)))
                 }
@@ -149,6 +149,8 @@ kongctl service sync --name user --url http://user-svc:8080 --dry-run --diff`,
             PrintSuccess(cmd, "已创建 Service：name=%s url=%s", svcName, svcURL)
         case "update":
             PrintSuccess(cmd, "已更新 Service：name=%s url=%s", svcName, svcURL)
+        case "none":
+            PrintInfo(cmd, "Service 无变更，未写入：name=%s url=%s", svcName, svcURL)
         default:
             PrintSuccess(cmd, "已同步 Service：name=%s url=%s", svcName, svcURL)
         }
@@ -156,8 +158,147 @@ kongctl service sync --name user --url http://user-svc:8080 --dry-run --diff`,
     },
 }
 
+// serviceRoutes 列出挂在 Service 下的全部 routes
+func serviceRoutes(ctx context.Context, client *kong.Client, svc *kong.Service) ([]kong.Route, error) {
+    rts, err := client.ListRoutes(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("列出 routes 失败：%w", err)
+    }
+    var out []kong.Route
+    for _, r := range rts {
+        if r.Service.ID == svc.ID { out = append(out, r) }
+    }
+    return out, nil
+}
+
+// routeLabels 返回用于提示的 route 名称（未命名的 route 显示 id）
+func routeLabels(rts []kong.Route) []string {
+    out := make([]string, 0, len(rts))
+    for _, r := range rts {
+        if r.Name != "" { out = append(out, r.Name) } else { out = append(out, r.ID) }
+    }
+    return out
+}
+
+var serviceListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Service（名称、后端地址、routes 数量、标签与创建时长）",
+    Example: `kongctl service list
+kongctl service list --filter name=user-* -o yaml
+kongctl service list --template '{{.Name}} {{.Host}}:{{.Port}}'`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        match, err := listFilterMatch()
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        all, err := client.ListServices(ctx)
+        if err != nil {
+            return err
+        }
+        list := []kong.Service{}
+        for _, s := range all {
+            if match(s.Name, s.Tags) { list = append(list, s) }
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+        if outputTemplate != "" {
+            return printTemplate(cmd, list)
+        }
+        if ok, err := printStructured(cmd, list); ok {
+            return err
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Service")
+            return nil
+        }
+        rts, err := client.ListRoutes(ctx)
+        if err != nil {
+            return fmt.Errorf("列出 routes 失败：%w", err)
+        }
+        nroutes := map[string]int{}
+        for _, r := range rts { nroutes[r.Service.ID]++ }
+        rows := make([][]string, 0, len(list))
+        for _, s := range list {
+            port := ""
+            if s.Port > 0 { port = strconv.Itoa(s.Port) }
+            rows = append(rows, []string{s.Name, s.Protocol, s.Host, port, s.Path, strconv.Itoa(nroutes[s.ID]), strings.Join(s.Tags, ","), formatAge(s.CreatedAt)})
+        }
+        printTable(cmd, []string{"NAME", "PROTOCOL", "HOST", "PORT", "PATH", "ROUTES", "TAGS", "AGE"}, rows)
+        return nil
+    },
+}
+
+var serviceDeleteCmd = &cobra.Command{
+    Use:   "delete <name|id>",
+    Short: "删除 Service（其上的插件一并删除；仍有 routes 时需 --cascade）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl service delete user-service
+kongctl service delete user-service --cascade --dry-run
+
+# 流水线中（非交互）需 --yes
+kongctl service delete user-service --cascade --yes`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        svc, ok, err := client.GetService(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Service 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        rts, err := serviceRoutes(ctx, client, svc)
+        if err != nil {
+            return err
+        }
+        label := svc.Name
+        if label == "" { label = svc.ID }
+        if len(rts) > 0 && !svcCascade {
+            return withCode("foreign_key_violation", "使用 --cascade 一并删除这些 routes", fmt.Errorf("Service %s 下仍有 %d 个 route（%s），未删除", label, len(rts), strings.Join(routeLabels(rts), ", ")))
+        }
+        op := label + "（含其上的插件）"
+        if len(rts) > 0 { op = fmt.Sprintf("%s（含 %d 个 route 及其上的插件）", label, len(rts)) }
+        if dryRun {
+            for _, r := range routeLabels(rts) { PrintInfo(cmd, "[dry-run] 将删除 Route：%s", r) }
+            PrintInfo(cmd, "[dry-run] 将删除 Service：%s", op)
+            return nil
+        }
+        if ok, err := confirmDelete(cmd, "service delete "+op); err != nil || !ok {
+            return err
+        }
+        // 等待确认的时间不计入超时：删除使用新的 context
+        ctx, cancel = context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        for i, r := range rts {
+            if err := client.DeleteRoute(ctx, r.ID); err != nil {
+                return fmt.Errorf("删除 Route %s 失败（已删除 %d/%d 个 route，Service 未删除）：%w", routeLabels(rts)[i], i, len(rts), err)
+            }
+            PrintSuccess(cmd, "已删除 Route：%s", routeLabels(rts)[i])
+        }
+        if err := client.DeleteService(ctx, svc.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Service：%s", label)
+        return nil
+    },
+}
+
 func init() {
-    serviceCmd.AddCommand(serviceSyncCmd)
+    serviceCmd.AddCommand(serviceSyncCmd, serviceListCmd, serviceDeleteCmd)
     serviceSyncCmd.Flags().StringVar(&svcName, "name", "", "Service 名称，例：echo 或 user")
     serviceSyncCmd.Flags().StringVar(&svcURL, "url", "", "上游 URL，例：http://httpbin.org 或 http://backend:8080")
     serviceSyncCmd.Flags().StringVar(&svcPath, "path", "", "上游基础路径（可覆盖 URL 中的路径），例：/api 或 v1；自动补前导 /")
@@ -167,6 +308,11 @@ func init() {
     serviceSyncCmd.Flags().BoolVar(&autoUpstream, "auto-upstream", autoUpstream, "自动创建 Upstream 并将 Service 指向它，例：--auto-upstream")
     serviceSyncCmd.Flags().StringVar(&svcUpstream, "upstream", "", "Upstream 名称（未提供则默认 name-upstream），例：--upstream user-up")
     serviceSyncCmd.Flags().IntVar(&targetWeight, "weight", 100, "首个 target 权重（默认 100），例：--weight 100")
+    listFilterFlag(serviceListCmd)
+    addTemplateFlag(serviceListCmd)
+    serviceDeleteCmd.Flags().BoolVar(&svcCascade, "cascade", false, "一并删除 Service 下的全部 routes")
+    serviceDeleteCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    serviceDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "跳过删除前的 [y/N] 确认（非交互环境必需）")
 }
 
 func reconstructURL(s *kong.Service) string {
@@ -0,0 +1,396 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    "gopkg.in/yaml.v3"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// snapshotTimeLayout 为快照文件名（~/.kongctl/snapshots/<timestamp>.yaml）使用的时间格式
+const snapshotTimeLayout = "20060102-150405"
+
+// snapshotKeep 为保留的快照数量，超出后删除最旧的快照
+const snapshotKeep = 20
+
+var (
+    applyNoSnapshot bool
+    rollbackTo      string
+    rollbackList    bool
+)
+
+// applySnapshot 为 apply 执行前的远程状态：Spec 为将被修改/删除的资源原配置，Created 为本次将新建的资源
+type applySnapshot struct {
+    Timestamp    string         `yaml:"timestamp"`
+    Context      string         `yaml:"context,omitempty"`
+    AdminURL     string         `yaml:"admin_url"`
+    File         string         `yaml:"file,omitempty"`
+    RolledBackAt *time.Time     `yaml:"rolled_back_at,omitempty"`
+    Created      []aplan.Change `yaml:"created,omitempty"`
+    Spec         applySpec      `yaml:"spec"`
+}
+
+// captureSnapshot 按计划记录将被更新/删除的 upstream/service/route 的当前配置，以及将新建、回滚时需删除的资源
+func captureSnapshot(cmd *cobra.Command, ctx context.Context, client *kong.Client, plan aplan.Plan) (*applySpec, []aplan.Change, error) {
+    rs, err := exportRemote(ctx, client, false)
+    if err != nil {
+        return nil, nil, err
+    }
+    // 权重变化的 target 在计划中同样记为 create；执行前已存在的由快照恢复，不能删除
+    existingTargets := map[string]bool{}
+    for _, up := range rs.Upstreams {
+        for _, t := range up.Targets { existingTargets[up.Name+"/"+t.Target] = true }
+    }
+    touched := map[string]bool{}
+    var created []aplan.Change
+    for _, it := range plan.Items {
+        switch it.Action {
+        case "create":
+            if it.Kind == "Target" && existingTargets[it.Name] { break }
+            created = append(created, aplan.Change{Kind: it.Kind, Name: it.Name, Action: "delete"})
+        case "none":
+        default:
+            if it.Kind == "Consumer" || it.Kind == "Credential" || it.Kind == "ConsumerGroup" || it.Kind == "Certificate" || it.Kind == "Vault" || it.Kind == "KeySet" || it.Kind == "Key" {
+                PrintWarn(cmd, "%s %s 的更新不会被自动回滚", it.Kind, it.Name)
+            }
+        }
+        if it.Kind == "Target" {
+            up, _, _ := strings.Cut(it.Name, "/")
+            touched["Upstream/"+up] = true
+        } else {
+            touched[planKey(it)] = true
+        }
+    }
+    snap := applySpec{KongctlFormat: currentSpecFormat}
+    for _, up := range rs.Upstreams {
+        if touched["Upstream/"+up.Name] { snap.Upstreams = append(snap.Upstreams, up) }
+    }
+    for _, svc := range rs.Services {
+        if touched["Service/"+svc.Name] { snap.Services = append(snap.Services, svc) }
+    }
+    for _, rt := range rs.Routes {
+        if touched["Route/"+rt.Name] { snap.Routes = append(snap.Routes, rt) }
+    }
+    return &snap, created, nil
+}
+
+// revertKindOrder 为回滚时删除新建资源的顺序（先删除依赖方）
+var revertKindOrder = map[string]int{"Route": 0, "Credential": 1, "Service": 2, "Target": 3, "Upstream": 4, "Consumer": 5, "ConsumerGroup": 6, "Certificate": 7, "Vault": 8, "Key": 9, "KeySet": 10}
+
+// restoreSnapshot 以覆盖模式恢复快照中的原配置，并删除快照记录的新建资源
+func restoreSnapshot(cmd *cobra.Command, ctx context.Context, client *kong.Client, snap *applySpec, created []aplan.Change) error {
+    if snap != nil {
+        applyOverwrite, applyPrune, applyPruneTargets = true, false, false
+        applyIgnoreFields = nil
+        applyLastApplied = nil
+        if err := runApplyPhase(cmd, ctx, client, *snap, &aplan.Plan{}); err != nil {
+            return fmt.Errorf("恢复原配置失败：%w", err)
+        }
+    }
+    created = append([]aplan.Change{}, created...)
+    sort.SliceStable(created, func(i, j int) bool { return revertKindOrder[created[i].Kind] < revertKindOrder[created[j].Kind] })
+    createdConsumers := map[string]bool{}
+    for _, ch := range created {
+        if ch.Kind == "Consumer" { createdConsumers[ch.Name] = true }
+    }
+    for _, ch := range created {
+        var err error
+        switch ch.Kind {
+        case "Route":
+            err = client.DeleteRoute(ctx, ch.Name)
+        case "Service":
+            err = client.DeleteService(ctx, ch.Name)
+        case "Upstream":
+            err = client.DeleteUpstream(ctx, ch.Name)
+        case "Target":
+            up, target, _ := strings.Cut(ch.Name, "/")
+            err = client.DeleteTarget(ctx, up, target)
+        case "Consumer":
+            err = client.DeleteConsumer(ctx, ch.Name)
+        case "ConsumerGroup":
+            err = client.DeleteConsumerGroup(ctx, ch.Name)
+        case "Vault":
+            err = client.DeleteVault(ctx, ch.Name)
+        case "KeySet":
+            err = client.DeleteKeySet(ctx, ch.Name)
+        case "Key":
+            // 计划中的名称为 <key set>/<kid>
+            set, kid, _ := strings.Cut(ch.Name, "/")
+            var k *kong.Key
+            var ok bool
+            if k, ok, err = client.FindKey(ctx, set, kid); err == nil && ok { err = client.DeleteKey(ctx, k.ID) }
+        case "Certificate":
+            // 计划中的名称为证书的第一个 SNI
+            var cert *kong.Certificate
+            var ok bool
+            if cert, ok, err = client.FindCertificateBySNI(ctx, ch.Name); err == nil && ok { err = client.DeleteCertificate(ctx, cert.ID) }
+        default:
+            // 凭证随 consumer 删除；已有 consumer 上新建的凭证需手工清理
+            if consumer, _, _ := strings.Cut(ch.Name, "/"); !createdConsumers[consumer] {
+                PrintWarn(cmd, "未自动删除 %s：%s，请手工清理", ch.Kind, ch.Name)
+            }
+            continue
+        }
+        if err != nil {
+            return fmt.Errorf("删除 %s %s 失败：%w", ch.Kind, ch.Name, err)
+        }
+        PrintSuccess(cmd, "已删除 %s：%s（回滚）", ch.Kind, ch.Name)
+    }
+    return nil
+}
+
+func snapshotDir() (string, error) {
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "snapshots"), nil
+}
+
+func saveSnapshot(s *applySnapshot) (string, error) {
+    dir, err := snapshotDir()
+    if err != nil {
+        return "", err
+    }
+    var buf bytes.Buffer
+    buf.WriteString("# kongctl apply 执行前快照，使用 kongctl rollback --to " + s.Timestamp + " 恢复\n")
+    enc := yaml.NewEncoder(&buf)
+    enc.SetIndent(2)
+    if err := enc.Encode(s); err != nil {
+        return "", err
+    }
+    enc.Close()
+    if err := os.MkdirAll(dir, 0o700); err != nil {
+        return "", fmt.Errorf("创建目录失败：%w", err)
+    }
+    file := filepath.Join(dir, s.Timestamp+".yaml")
+    if err := writeTextFile(file, buf.Bytes(), 0o600); err != nil {
+        return "", fmt.Errorf("保存快照失败：%w", err)
+    }
+    return file, nil
+}
+
+// loadSnapshots 读取全部快照，按时间从旧到新排序
+func loadSnapshots() ([]*applySnapshot, error) {
+    dir, err := snapshotDir()
+    if err != nil {
+        return nil, err
+    }
+    files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+    if err != nil {
+        return nil, err
+    }
+    sort.Strings(files)
+    var out []*applySnapshot
+    for _, f := range files {
+        b, err := os.ReadFile(f)
+        if err != nil {
+            return nil, fmt.Errorf("读取快照失败：%w", err)
+        }
+        var s applySnapshot
+        if err := yaml.Unmarshal(b, &s); err != nil {
+            return nil, fmt.Errorf("解析快照 %s 失败：%w", filepath.Base(f), err)
+        }
+        out = append(out, &s)
+    }
+    return out, nil
+}
+
+// pruneSnapshots 仅保留最新的 snapshotKeep 个快照
+func pruneSnapshots() {
+    dir, err := snapshotDir()
+    if err != nil {
+        return
+    }
+    files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+    sort.Strings(files)
+    for len(files) > snapshotKeep {
+        _ = os.Remove(files[0])
+        files = files[1:]
+    }
+}
+
+// silentPlan 以 dry-run 方式静默计算 spec 的计划（不输出、不变更）
+func silentPlan(cmd *cobra.Command, ctx context.Context, client *kong.Client, spec applySpec) (aplan.Plan, error) {
+    out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+    cmd.SetOut(io.Discard)
+    cmd.SetErr(io.Discard)
+    defer func() {
+        cmd.SetOut(out)
+        cmd.SetErr(errOut)
+    }()
+    plan := &aplan.Plan{}
+    dryRun = true
+    err := runApplyPhase(cmd, ctx, client, spec, plan)
+    dryRun = false
+    return *plan, err
+}
+
+// snapshotBeforeApply 在 apply 实际变更前静默计算计划，并将受影响资源的当前状态保存为快照；无变更时不保存
+func snapshotBeforeApply(cmd *cobra.Command, ctx context.Context, client *kong.Client, spec applySpec) error {
+    if applyNoSnapshot {
+        return nil
+    }
+    plan, err := silentPlan(cmd, ctx, client, spec)
+    var snap *applySpec
+    var created []aplan.Change
+    if n, _ := pendingChanges(plan); err == nil && n > 0 {
+        out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+        cmd.SetOut(io.Discard)
+        cmd.SetErr(io.Discard)
+        snap, created, err = captureSnapshot(cmd, ctx, client, plan)
+        cmd.SetOut(out)
+        cmd.SetErr(errOut)
+    }
+    if err != nil {
+        return fmt.Errorf("保存执行前快照失败：%w（可使用 --no-snapshot 跳过）", err)
+    }
+    if snap == nil {
+        return nil
+    }
+    dir, err := snapshotDir()
+    if err != nil {
+        return err
+    }
+    // 同一秒内多次 apply 时追加序号，避免覆盖
+    ts := time.Now().UTC().Format(snapshotTimeLayout)
+    for i, base := 2, ts; ; i++ {
+        if _, err := os.Stat(filepath.Join(dir, ts+".yaml")); os.IsNotExist(err) { break }
+        ts = fmt.Sprintf("%s_%d", base, i)
+    }
+    s := &applySnapshot{
+        Timestamp: ts,
+        Context:   activeContext,
+        AdminURL:  viper.GetString("admin_url"),
+        File:      applyFile,
+        Created:   created,
+        Spec:      *snap,
+    }
+    file, err := saveSnapshot(s)
+    if err != nil {
+        return err
+    }
+    pruneSnapshots()
+    PrintInfo(cmd, "已保存执行前快照：%s（回滚：kongctl rollback --to %s）", file, s.Timestamp)
+    return nil
+}
+
+// snapshotSummary 返回快照中恢复与删除的资源数量
+func snapshotSummary(s *applySnapshot) string {
+    return fmt.Sprintf("恢复 upstreams=%d services=%d routes=%d，删除新建资源 %d 项", len(s.Spec.Upstreams), len(s.Spec.Services), len(s.Spec.Routes), len(s.Created))
+}
+
+var rollbackCmd = &cobra.Command{
+    Use:   "rollback",
+    Short: "恢复 apply 执行前的快照（撤销最近一次 apply）",
+    Long: `apply 在实际变更前会将受影响资源的当前配置保存到 ~/.kongctl/snapshots/<timestamp>.yaml（保留最近 20 个）。
+rollback 以覆盖模式恢复快照中的原配置，并删除该次 apply 新建的资源。
+默认选择当前上下文最近一个未回滚的快照；--to 指定快照时间戳，--list 列出全部快照。
+Consumer 与凭证的更新不会被恢复。production 上下文执行前需确认。`,
+    Example: `# 列出快照
+kongctl rollback --list
+
+# 预览并撤销最近一次 apply
+kongctl rollback --dry-run
+kongctl rollback
+
+# 恢复到指定快照
+kongctl rollback --to 20240601-020000`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        all, err := loadSnapshots()
+        if err != nil {
+            return err
+        }
+        if rollbackList {
+            if len(all) == 0 {
+                PrintInfo(cmd, "没有快照")
+                return nil
+            }
+            cmd.Printf("%-18s %-10s %-12s %-28s %s\n", "TIMESTAMP", "CONTEXT", "STATUS", "ADMIN_URL", "FILE")
+            for i := len(all) - 1; i >= 0; i-- {
+                s := all[i]
+                ctxName, status := s.Context, "-"
+                if ctxName == "" { ctxName = "-" }
+                if s.RolledBackAt != nil { status = "rolled-back" }
+                cmd.Printf("%-18s %-10s %-12s %-28s %s\n", s.Timestamp, ctxName, status, s.AdminURL, s.File)
+            }
+            return nil
+        }
+        adminURL := viper.GetString("admin_url")
+        var snap *applySnapshot
+        for i := len(all) - 1; i >= 0; i-- {
+            s := all[i]
+            if rollbackTo != "" {
+                if s.Timestamp == rollbackTo { snap = s; break }
+                continue
+            }
+            if s.RolledBackAt == nil && s.Context == activeContext && s.AdminURL == adminURL { snap = s; break }
+        }
+        if snap == nil {
+            if rollbackTo != "" {
+                return withCode("not_found", "", fmt.Errorf("快照不存在：%s（使用 kongctl rollback --list 查看）", rollbackTo))
+            }
+            return withCode("not_found", "", fmt.Errorf("当前上下文没有可回滚的快照（使用 kongctl rollback --list 查看）"))
+        }
+        if snap.Context != activeContext || snap.AdminURL != adminURL {
+            return fmt.Errorf("快照 %s 属于 context=%s admin_url=%s，请以 --context %s 运行", snap.Timestamp, snap.Context, snap.AdminURL, snap.Context)
+        }
+        if snap.RolledBackAt != nil {
+            PrintWarn(cmd, "快照 %s 已于 %s 回滚过，将再次恢复", snap.Timestamp, snap.RolledBackAt.Local().Format("2006-01-02 15:04:05"))
+        }
+
+        cfg, err := clientConfig(30 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        PrintInfo(cmd, "快照 %s（%s）：%s", snap.Timestamp, snap.File, snapshotSummary(snap))
+        if dryRun {
+            applyOverwrite, applyPrune, applyPruneTargets = true, false, false
+            applyIgnoreFields = nil
+            applyLastApplied = nil
+            if err := runApplyPhase(cmd, ctx, client, snap.Spec, &aplan.Plan{}); err != nil {
+                return err
+            }
+            for _, ch := range snap.Created {
+                cmd.Printf("  %s %s %s\n", colorWarn("-"), ch.Kind, ch.Name)
+            }
+            cmd.Println("[dry-run] 以上为回滚计划（未实际变更）" + emojiSuccess)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("rollback 到快照 %s（%s）", snap.Timestamp, snapshotSummary(snap))); err != nil {
+            return err
+        }
+        if err := restoreSnapshot(cmd, ctx, client, &snap.Spec, snap.Created); err != nil {
+            return err
+        }
+        now := time.Now().UTC()
+        snap.RolledBackAt = &now
+        if _, err := saveSnapshot(snap); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已回滚到快照 %s", snap.Timestamp)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(rollbackCmd)
+    rollbackCmd.Flags().StringVar(&rollbackTo, "to", "", "快照时间戳（见 --list），默认为当前上下文最近一个未回滚的快照")
+    rollbackCmd.Flags().BoolVar(&rollbackList, "list", false, "列出全部快照")
+    rollbackCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅预览回滚计划，不实际变更")
+}
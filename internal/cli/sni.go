@@ -0,0 +1,179 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    sniCertificate string
+    sniNames       []string
+    sniTags        []string
+)
+
+var sniCmd = &cobra.Command{
+    Use:   "sni",
+    Short: "管理绑定到证书的服务器名称（SNI）",
+    Long: `SNI 决定 TLS 握手时 Kong 使用哪张证书；--certificate 接受证书 ID 或该证书上已绑定的任一 SNI。
+上传或续期证书请使用 kongctl certificate sync；声明式管理请在 apply spec 的 certificates[].snis 中声明。`,
+}
+
+// sniSetup 创建客户端与上下文，并在给出 --certificate 时确认证书存在
+func sniSetup(cmd *cobra.Command) (*kong.Client, context.Context, context.CancelFunc, *kong.Certificate, error) {
+    cfg, err := clientConfig(10 * time.Second)
+    if err != nil {
+        return nil, nil, nil, nil, err
+    }
+    client := kong.NewClient(cfg)
+    ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+    if sniCertificate == "" {
+        return client, ctx, cancel, nil, nil
+    }
+    cert, ok, err := resolveCertificate(ctx, client, sniCertificate)
+    if err != nil {
+        cancel()
+        return nil, nil, nil, nil, err
+    } else if !ok {
+        cancel()
+        return nil, nil, nil, nil, withCode("not_found", "使用 kongctl certificate list 查看已有证书", fmt.Errorf("证书不存在：%s", sniCertificate))
+    }
+    return client, ctx, cancel, cert, nil
+}
+
+var sniAddCmd = &cobra.Command{
+    Use:   "add",
+    Short: "将服务器名称绑定到证书（已绑定到该证书时跳过）",
+    Example: `kongctl sni add --certificate 4e3ad2e4-0bc4-4638-8e34-c84a417ba39b --name api.example.com
+# 按证书已有的 SNI 指定证书
+kongctl sni add --certificate api.example.com --name www.example.com,*.example.com`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if sniCertificate == "" {
+            return fmt.Errorf("必须提供 --certificate")
+        }
+        if len(sniNames) == 0 {
+            return fmt.Errorf("必须提供 --name")
+        }
+        client, ctx, cancel, cert, err := sniSetup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        if leaf, err := parseLeafCert(cert.Cert); err == nil { warnSniMismatch(cmd, leaf, sniNames) }
+        for _, name := range sniNames {
+            cur, ok, err := client.GetSNI(ctx, name)
+            if err != nil {
+                return err
+            }
+            if ok {
+                if cur.Certificate != nil && cur.Certificate.ID == cert.ID {
+                    PrintInfo(cmd, "SNI %s 已绑定到证书 %s", name, cert.ID)
+                    continue
+                }
+                other := "-"
+                if cur.Certificate != nil { other = cur.Certificate.ID }
+                return fmt.Errorf("SNI %s 已绑定到证书 %s，如需改绑请先执行 kongctl sni delete %s", name, other, name)
+            }
+            if dryRun {
+                PrintInfo(cmd, "[dry-run] 将把 SNI %s 绑定到证书 %s", name, cert.ID)
+                continue
+            }
+            if _, err := client.CreateSNI(ctx, name, cert.ID, sniTags); err != nil {
+                return err
+            }
+            PrintSuccess(cmd, "已将 SNI %s 绑定到证书 %s", name, cert.ID)
+        }
+        return nil
+    },
+}
+
+var sniListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 SNI 及其绑定的证书",
+    Example: `kongctl sni list
+kongctl sni list --certificate api.example.com --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, cert, err := sniSetup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        certID := ""
+        if cert != nil { certID = cert.ID }
+        list, err := client.ListSNIs(ctx, certID)
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+        if outputJSON() {
+            if list == nil { list = []kong.SNI{} }
+            b, _ := json.MarshalIndent(list, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 SNI")
+            return nil
+        }
+        cmd.Printf("%-40s %-36s %s\n", "NAME", "CERTIFICATE", "TAGS")
+        for _, s := range list {
+            ref := "-"
+            if s.Certificate != nil { ref = s.Certificate.ID }
+            cmd.Printf("%-40s %-36s %s\n", s.Name, ref, strings.Join(s.Tags, ","))
+        }
+        return nil
+    },
+}
+
+var sniDeleteCmd = &cobra.Command{
+    Use:   "delete <name>",
+    Short: "解除服务器名称与证书的绑定（证书保留）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl sni delete www.example.com`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        client, ctx, cancel, _, err := sniSetup(cmd)
+        if err != nil {
+            return err
+        }
+        defer cancel()
+        cur, ok, err := client.GetSNI(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "SNI 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 SNI：%s", cur.Name)
+            return nil
+        }
+        if err := confirmDestructive(cmd, "sni delete "+cur.Name); err != nil {
+            return err
+        }
+        if err := client.DeleteSNI(ctx, cur.Name); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 SNI：%s", cur.Name)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(sniCmd)
+    sniCmd.AddCommand(sniAddCmd, sniListCmd, sniDeleteCmd)
+    for _, c := range []*cobra.Command{sniAddCmd, sniListCmd} {
+        c.Flags().StringVar(&sniCertificate, "certificate", "", "证书 ID 或其已绑定的 SNI，例：--certificate api.example.com")
+    }
+    sniAddCmd.Flags().StringSliceVar(&sniNames, "name", nil, "服务器名称（可重复或逗号分隔，支持 *.example.com 通配）")
+    sniAddCmd.Flags().StringSliceVar(&sniTags, "tags", nil, "SNI 标签")
+    for _, c := range []*cobra.Command{sniAddCmd, sniDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
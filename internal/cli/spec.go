@@ -0,0 +1,188 @@
+package cli
+
+import (
+    "bytes"
+    "fmt"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+)
+
+// currentSpecFormat 为当前 apply/export 文件格式版本（写入顶层 kongctl_format 字段）
+const currentSpecFormat = "v1"
+
+// legacySpecFormat 表示未标注 kongctl_format 的旧文件
+const legacySpecFormat = "v0"
+
+// specMigration 描述从某一格式版本升级到下一版本的迁移
+type specMigration struct {
+    From, To string
+    Desc     string
+    Apply    func(root *yaml.Node) (*yaml.Node, error)
+}
+
+// specMigrations 按版本顺序登记；新增格式版本时在末尾追加一项并更新 currentSpecFormat
+var specMigrations = []specMigration{
+    {From: legacySpecFormat, To: "v1", Desc: "routes 列表/单个 route 简写改为顶层 {routes: [...]}，并标注 kongctl_format", Apply: migrateSpecV0},
+}
+
+// checkSpecFormat 校验文件声明的格式版本是否可被当前 kongctl 读取
+func checkSpecFormat(format string) error {
+    if format == "" || format == currentSpecFormat {
+        return nil
+    }
+    for _, m := range specMigrations {
+        if m.From == format { return nil }
+    }
+    return fmt.Errorf("不支持的 kongctl_format：%s（当前版本支持 %s）；文件可能由更新版本的 kongctl 生成，请升级 kongctl", format, currentSpecFormat)
+}
+
+// specTopLevelKeys 为完整写法的顶层字段；均不包含时视为单个 route 简写
+var specTopLevelKeys = map[string]bool{"kongctl_format": true, "include": true, "common_tags": true, "upstreams": true, "services": true, "routes": true, "consumer_groups": true, "consumers": true, "certificates": true, "vaults": true, "key_sets": true}
+
+// mappingValue 返回映射节点中 key 对应的值节点
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+    if n == nil || n.Kind != yaml.MappingNode { return nil }
+    for i := 0; i+1 < len(n.Content); i += 2 {
+        if n.Content[i].Value == key { return n.Content[i+1] }
+    }
+    return nil
+}
+
+func scalarNode(v string) *yaml.Node { return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v} }
+
+// migrateSpecV0 将旧版简写布局包装为顶层对象，并在首位写入 kongctl_format
+func migrateSpecV0(root *yaml.Node) (*yaml.Node, error) {
+    isTop := false
+    if root.Kind == yaml.MappingNode {
+        for i := 0; i+1 < len(root.Content); i += 2 {
+            if specTopLevelKeys[root.Content[i].Value] { isTop = true; break }
+        }
+    }
+    switch {
+    case root.Kind == yaml.SequenceNode:
+        root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalarNode("routes"), root}}
+    case root.Kind == yaml.MappingNode && !isTop:
+        seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{root}}
+        root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalarNode("routes"), seq}}
+    case root.Kind != yaml.MappingNode:
+        return nil, fmt.Errorf("无法识别的文件结构：顶层应为对象或 routes 列表")
+    }
+    if mappingValue(root, "kongctl_format") == nil {
+        root.Content = append([]*yaml.Node{scalarNode("kongctl_format"), scalarNode("")}, root.Content...)
+    }
+    return root, nil
+}
+
+// upgradeSpec 将文件内容逐级迁移到当前格式，返回新内容与原始版本；已是当前版本时返回 nil
+func upgradeSpec(content []byte) ([]byte, string, error) {
+    var doc yaml.Node
+    if err := yaml.Unmarshal(content, &doc); err != nil {
+        return nil, "", fmt.Errorf("解析文件失败：%w", err)
+    }
+    if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+        return nil, "", fmt.Errorf("文件为空")
+    }
+    root := doc.Content[0]
+    from := legacySpecFormat
+    if v := mappingValue(root, "kongctl_format"); v != nil && v.Value != "" {
+        from = v.Value
+    }
+    if from == currentSpecFormat {
+        return nil, from, nil
+    }
+    if err := checkSpecFormat(from); err != nil {
+        return nil, from, err
+    }
+    started := false
+    for _, m := range specMigrations {
+        if m.From == from { started = true }
+        if !started { continue }
+        next, err := m.Apply(root)
+        if err != nil { return nil, from, fmt.Errorf("%s -> %s 迁移失败：%w", m.From, m.To, err) }
+        root = next
+        mappingValue(root, "kongctl_format").Value = m.To
+    }
+    doc.Content[0] = root
+    var buf bytes.Buffer
+    enc := yaml.NewEncoder(&buf)
+    enc.SetIndent(2)
+    if err := enc.Encode(&doc); err != nil {
+        return nil, from, err
+    }
+    enc.Close()
+    // 迁移结果须能被 apply 正常读取
+    if _, err := parseApplySpec(buf.Bytes()); err != nil {
+        return nil, from, fmt.Errorf("迁移后的文件校验失败：%w", err)
+    }
+    return buf.Bytes(), from, nil
+}
+
+var (
+    specFile    string
+    specOutput  string
+    specInPlace bool
+)
+
+var specCmd = &cobra.Command{
+    Use:   "spec",
+    Short: "apply/export 文件格式工具",
+}
+
+var specUpgradeCmd = &cobra.Command{
+    Use:   "upgrade",
+    Short: "将旧版 apply/export 文件迁移到当前格式（kongctl_format）",
+    Long: `按版本逐级迁移文件布局到当前格式，并写入顶层 kongctl_format 字段。
+未标注 kongctl_format 的文件视为 v0（routes 列表或单个 route 简写也会被包装为 {routes: [...]}）。
+保留原有注释与字段顺序；默认输出到标准输出。`,
+    Example: `# 预览迁移结果
+kongctl spec upgrade -f old.yaml
+
+# 写入新文件
+kongctl spec upgrade -f old.yaml -o spec.yaml
+
+# 原地升级
+kongctl spec upgrade -f old.yaml --in-place`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if specFile == "" {
+            return fmt.Errorf("必须通过 -f/--file 指定配置文件")
+        }
+        if specInPlace && specOutput != "" {
+            return fmt.Errorf("--in-place 与 -o/--output 不能同时使用")
+        }
+        if specInPlace && specFile == "-" {
+            return fmt.Errorf("从标准输入读取时不能使用 --in-place")
+        }
+        content, err := readSpecFile(cmd, specFile)
+        if err != nil {
+            return err
+        }
+        out, from, err := upgradeSpec(content)
+        if err != nil {
+            return err
+        }
+        if out == nil {
+            PrintInfo(cmd, "%s 已是当前格式 %s，无需升级", specFile, currentSpecFormat)
+            return nil
+        }
+        dest := specOutput
+        if specInPlace { dest = specFile }
+        if dest == "" || dest == "-" {
+            cmd.Print(string(out))
+            return nil
+        }
+        if err := writeTextFile(expandPath(dest), out, 0o644); err != nil {
+            return fmt.Errorf("写入文件失败：%w", err)
+        }
+        PrintSuccess(cmd, "已将 %s 从 %s 升级到 %s：%s", specFile, from, currentSpecFormat, dest)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(specCmd)
+    specCmd.AddCommand(specUpgradeCmd)
+    specUpgradeCmd.Flags().StringVarP(&specFile, "file", "f", "", "待升级的文件路径（YAML/JSON，- 表示标准输入）")
+    specUpgradeCmd.Flags().StringVarP(&specOutput, "output", "o", "", "输出文件路径（默认输出到标准输出）")
+    specUpgradeCmd.Flags().BoolVar(&specInPlace, "in-place", false, "直接覆盖原文件")
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+)
+
+// usageStats 为本机的命令使用统计，保存在 ~/.kongctl/stats.json；只记录命令路径、次数、耗时与错误码，
+// 不记录参数、文件内容或 Admin API 地址，且从不上传
+type usageStats struct {
+    Since    time.Time                `json:"since"`
+    Commands map[string]*commandStats `json:"commands"`
+}
+
+// commandStats 为单个命令（如 "apply"、"route sync"）的累计统计
+type commandStats struct {
+    Count       int            `json:"count"`
+    Errors      int            `json:"errors"`
+    TotalMillis int64          `json:"total_ms"`
+    MaxMillis   int64          `json:"max_ms"`
+    LastUsed    time.Time      `json:"last_used"`
+    ErrorCodes  map[string]int `json:"error_codes,omitempty"`
+}
+
+func statsPath() (string, error) {
+    dir, err := userConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "stats.json"), nil
+}
+
+// statsEnabled 表示是否记录使用统计（配置项 stats: false 或 KONGCTL_STATS=false 关闭）
+func statsEnabled() bool { return !viper.IsSet("stats") || viper.GetBool("stats") }
+
+func loadUsageStats() (*usageStats, error) {
+    st := &usageStats{Since: time.Now().UTC(), Commands: map[string]*commandStats{}}
+    path, err := statsPath()
+    if err != nil {
+        return nil, err
+    }
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return st, nil
+    } else if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(data, st); err != nil {
+        return nil, fmt.Errorf("解析使用统计失败（%s）：%w", path, err)
+    }
+    if st.Commands == nil { st.Commands = map[string]*commandStats{} }
+    return st, nil
+}
+
+// recordUsage 在命令结束时累加统计；任何失败都静默忽略，不影响命令本身的结果
+func recordUsage(cmd *cobra.Command, elapsed time.Duration, err error) {
+    // stats 自身不计入，避免 reset 后立即重新生成记录
+    if cmd == nil || !statsEnabled() || cmd == rootCmd || cmd.Parent() == statsCmd || cmd.Hidden || cmd.Name() == "help" || strings.HasPrefix(cmd.Name(), "__") {
+        return
+    }
+    st, lerr := loadUsageStats()
+    if lerr != nil { return }
+    key := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+    cs := st.Commands[key]
+    if cs == nil {
+        cs = &commandStats{}
+        st.Commands[key] = cs
+    }
+    ms := elapsed.Milliseconds()
+    cs.Count++
+    cs.TotalMillis += ms
+    if ms > cs.MaxMillis { cs.MaxMillis = ms }
+    cs.LastUsed = time.Now().UTC()
+    if err != nil {
+        cs.Errors++
+        if cs.ErrorCodes == nil { cs.ErrorCodes = map[string]int{} }
+        cs.ErrorCodes[describeError(err).Code]++
+    }
+    path, perr := statsPath()
+    if perr != nil { return }
+    b, merr := json.MarshalIndent(st, "", "  ")
+    if merr != nil { return }
+    if os.MkdirAll(filepath.Dir(path), 0o700) == nil {
+        _ = writeTextFile(path, append(b, '\n'), 0o600)
+    }
+}
+
+var statsCmd = &cobra.Command{
+    Use:   "stats",
+    Short: "查看本机的命令使用统计（仅保存在本地，不上传）",
+    Long: `kongctl 会在 ~/.kongctl/stats.json 中累计各命令的调用次数、耗时与失败次数（按错误码分类），
+便于平台团队了解常用的工作流以及失败集中的环节。只记录命令路径，不记录参数、文件内容或 Admin API 地址，
+也不会发送到任何地方。可在配置文件中设置 stats: false（或环境变量 KONGCTL_STATS=false）关闭记录。`,
+}
+
+var statsShowCmd = &cobra.Command{
+    Use:   "show",
+    Short: "按调用次数列出各命令的使用统计与失败率",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        st, err := loadUsageStats()
+        if err != nil {
+            return err
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(st, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if !statsEnabled() { PrintWarn(cmd, "使用统计已关闭（stats: false），以下为关闭前的记录") }
+        if len(st.Commands) == 0 {
+            PrintInfo(cmd, "暂无使用记录")
+            return nil
+        }
+        keys := make([]string, 0, len(st.Commands))
+        total, failed := 0, 0
+        codes := map[string]int{}
+        for k, cs := range st.Commands {
+            keys = append(keys, k)
+            total += cs.Count
+            failed += cs.Errors
+            for c, n := range cs.ErrorCodes { codes[c] += n }
+        }
+        sort.Slice(keys, func(i, j int) bool {
+            a, b := st.Commands[keys[i]], st.Commands[keys[j]]
+            if a.Count != b.Count { return a.Count > b.Count }
+            return keys[i] < keys[j]
+        })
+        cmd.Printf("自 %s 起共 %d 次调用，失败 %d 次\n", st.Since.Local().Format("2006-01-02"), total, failed)
+        cmd.Printf("%-24s %6s %6s %7s %9s %9s  %s\n", "COMMAND", "COUNT", "ERRORS", "FAIL%", "AVG", "MAX", "LAST USED")
+        for _, k := range keys {
+            cs := st.Commands[k]
+            rate := fmt.Sprintf("%.1f%%", float64(cs.Errors)*100/float64(cs.Count))
+            if cs.Errors > 0 { rate = colorWarn(fmt.Sprintf("%7s", rate)) } else { rate = fmt.Sprintf("%7s", rate) }
+            avg := time.Duration(cs.TotalMillis/int64(cs.Count)) * time.Millisecond
+            cmd.Printf("%-24s %6d %6d %s %9s %9s  %s\n", k, cs.Count, cs.Errors, rate, avg, time.Duration(cs.MaxMillis)*time.Millisecond, cs.LastUsed.Local().Format("2006-01-02 15:04"))
+        }
+        if len(codes) > 0 {
+            names := make([]string, 0, len(codes))
+            for c := range codes { names = append(names, c) }
+            sort.Slice(names, func(i, j int) bool {
+                if codes[names[i]] != codes[names[j]] { return codes[names[i]] > codes[names[j]] }
+                return names[i] < names[j]
+            })
+            parts := make([]string, len(names))
+            for i, c := range names { parts[i] = fmt.Sprintf("%s×%d", c, codes[c]) }
+            cmd.Printf("失败原因（错误码，详见 kongctl explain <code>）：%s\n", strings.Join(parts, "，"))
+        }
+        return nil
+    },
+}
+
+var statsResetCmd = &cobra.Command{
+    Use:   "reset",
+    Short: "清空本机的使用统计",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        path, err := statsPath()
+        if err != nil {
+            return err
+        }
+        if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+            return err
+        }
+        PrintInfo(cmd, "已清空使用统计：%s", path)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(statsCmd)
+    statsCmd.AddCommand(statsShowCmd)
+    statsCmd.AddCommand(statsResetCmd)
+}
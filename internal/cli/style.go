@@ -12,29 +12,49 @@ import (
 func useColor() bool {
     if viper.GetBool("no_color") { return false }
     if strings.ToLower(os.Getenv("NO_COLOR")) != "" { return false }
+    if plainTerminal { return false }
     return true
 }
 
 func colorize(s, code string) string {
     if !useColor() { return s }
-    return code + s + "\033[0m"
+    return sgr(code) + s + "\033[0m"
 }
 
-func colorSuccess(s string) string { return colorize(s, "\033[32m") }
-func colorInfo(s string) string    { return colorize(s, "\033[36m") }
-func colorWarn(s string) string    { return colorize(s, "\033[33m") }
-func colorError(s string) string   { return colorize(s, "\033[31;1m") }
+func colorSuccess(s string) string { return colorize(s, activePalette.Success) }
+func colorInfo(s string) string    { return colorize(s, activePalette.Info) }
+func colorWarn(s string) string    { return colorize(s, activePalette.Warn) }
+func colorError(s string) string   { return colorize(s, activePalette.Error) }
 
-const (
+// 状态图标；minimal 风格下由 applyTerminalFallback 替换为 ASCII 标记
+var (
     emojiSuccess = "✅"
     emojiInfo    = "ℹ️"
     emojiWarn    = "⚠️"
     emojiError   = "❌"
+    emojiDiff    = "📝 "
 )
 
+// minimalStyle 为 true 时不输出 emoji、框线字符与装饰性提示（--style minimal 或纯文本终端）
+func minimalStyle() bool {
+    return plainTerminal || strings.EqualFold(viper.GetString("style"), "minimal")
+}
+
+// glyph 按输出风格选择装饰字符
+func glyph(fancy, plain string) string {
+    if minimalStyle() { return plain }
+    return fancy
+}
+
 func PrintSuccess(cmd *cobra.Command, format string, args ...any) {
     msg := fmt.Sprintf(format, args...)
-    cmd.Println(colorSuccess(emojiSuccess + " " + msg))
+    cmd.Println(colorSuccess(emojiSuccess + " " + contextBanner() + msg))
+}
+
+// contextBanner 在成功/汇总信息前标注当前上下文，降低误操作其他集群的风险
+func contextBanner() string {
+    if activeContext == "" { return "" }
+    return "[" + activeContext + "] "
 }
 
 func PrintInfo(cmd *cobra.Command, format string, args ...any) {
@@ -0,0 +1,135 @@
+package cli
+
+import (
+    "context"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// syncScoped 为 kongctl sync：只调和带 managed-by 标签的资源，远程已存在但不带该标签的资源不做任何变更
+var syncScoped bool
+
+// scopeToManaged 从 spec 中移除远程已存在但不带 managed-by 标签的资源（手工创建，不归 kongctl 管理）
+func scopeToManaged(cmd *cobra.Command, ctx context.Context, client *kong.Client, spec applySpec) (applySpec, error) {
+    tag := managedByTag()
+    var skipped []string
+    unmanaged := func(kind, name string, tags []string) bool {
+        if kong.HasTag(tags, tag) { return false }
+        skipped = append(skipped, kind+" "+name)
+        return true
+    }
+    out := spec
+    out.Upstreams, out.Services, out.Routes, out.ConsumerGroups, out.Consumers, out.Certificates, out.Vaults, out.KeySets = nil, nil, nil, nil, nil, nil, nil, nil
+    skipUpstream := map[string]bool{}
+    for _, up := range spec.Upstreams {
+        cur, ok, err := client.GetUpstream(ctx, up.Name)
+        if err != nil { return spec, err }
+        if ok && unmanaged("Upstream", up.Name, cur.Tags) { skipUpstream[up.Name] = true; continue }
+        out.Upstreams = append(out.Upstreams, up)
+    }
+    for _, s := range spec.Services {
+        cur, ok, err := client.GetService(ctx, s.Name)
+        if err != nil { return spec, err }
+        if ok && unmanaged("Service", s.Name, cur.Tags) { continue }
+        if s.Upstream != "" && len(s.Targets) > 0 && !skipUpstream[s.Upstream] {
+            if cur, ok, err := client.GetUpstream(ctx, s.Upstream); err != nil {
+                return spec, err
+            } else if ok && unmanaged("Upstream", s.Upstream, cur.Tags) {
+                skipUpstream[s.Upstream] = true
+            }
+        }
+        // 不为手工创建的 upstream 增删 targets
+        if skipUpstream[s.Upstream] { s.Targets = nil }
+        out.Services = append(out.Services, s)
+    }
+    for _, r := range spec.Routes {
+        name := specRouteName(r)
+        if name != "" {
+            cur, ok, err := client.GetRoute(ctx, name)
+            if err != nil { return spec, err }
+            if ok && unmanaged("Route", name, cur.Tags) { continue }
+        }
+        out.Routes = append(out.Routes, r)
+    }
+    for _, g := range spec.ConsumerGroups {
+        cur, ok, err := client.GetConsumerGroup(ctx, g.Name)
+        if err != nil { return spec, err }
+        if ok && unmanaged("ConsumerGroup", g.Name, cur.Tags) { continue }
+        out.ConsumerGroups = append(out.ConsumerGroups, g)
+    }
+    for _, c := range spec.Consumers {
+        cur, ok, err := client.GetConsumer(ctx, c.key())
+        if err != nil { return spec, err }
+        if ok && unmanaged("Consumer", c.key(), cur.Tags) { continue }
+        out.Consumers = append(out.Consumers, c)
+    }
+    for _, c := range spec.Certificates {
+        cur, err := findCertificateForSnis(ctx, client, c.Snis)
+        if err != nil { return spec, err }
+        if cur != nil && unmanaged("Certificate", c.key(), cur.Tags) { continue }
+        out.Certificates = append(out.Certificates, c)
+    }
+    for _, v := range spec.Vaults {
+        cur, ok, err := client.GetVault(ctx, v.Prefix)
+        if err != nil { return spec, err }
+        if ok && unmanaged("Vault", v.Prefix, cur.Tags) { continue }
+        out.Vaults = append(out.Vaults, v)
+    }
+    for _, ks := range spec.KeySets {
+        cur, ok, err := client.GetKeySet(ctx, ks.Name)
+        if err != nil { return spec, err }
+        if ok && unmanaged("KeySet", ks.Name, cur.Tags) { continue }
+        out.KeySets = append(out.KeySets, ks)
+    }
+    if len(skipped) > 0 {
+        PrintWarn(cmd, "sync：跳过 %d 个不带 %s 标签的已有资源（非 kongctl 创建，不做变更）：%s", len(skipped), tag, strings.Join(skipped, ", "))
+    }
+    return out, nil
+}
+
+var syncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "声明式同步：只调和带 managed-by 标签的资源（含删除），不触碰手工创建的资源",
+    Long: `以文件为准调和 kongctl 管理的资源，等价于 apply --overwrite --prune，但只作用于带 managed-by 标签
+（配置项 managed_by_tag，默认 managed-by:kongctl）的资源：
+  - 文件中新增的资源会被创建并打上该标签；
+  - 带该标签的资源按文件更新，未在文件中声明的被删除（routes/services/upstreams/targets）；
+  - 远程已存在但不带该标签的同名资源（如在 Kong Manager 中手工创建）会被跳过并提示，不会被修改或接管。
+kongctl apply 创建的资源同样会自动带上该标签，因此可随时从 apply 切换到 sync。`,
+    Example: `# 预览同步计划
+kongctl sync -f kong.yaml --dry-run --diff
+
+# 执行同步（production 上下文需确认或 --force）
+kongctl sync -f kong.yaml`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        applyOverwrite, applyPrune, syncScoped = true, true, true
+        return applyCmd.RunE(cmd, args)
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(syncCmd)
+    syncCmd.Flags().StringVarP(&applyFile, "file", "f", "", "spec 文件路径（YAML/JSON，- 表示标准输入；目录表示合并其下的 spec 文件），例：-f kong.yaml")
+    syncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅展示同步计划，不做变更")
+    syncCmd.Flags().BoolVar(&showDiff, "diff", false, "展示字段级差异")
+    syncCmd.Flags().StringVar(&applyDiffFormat, "diff-format", "text", "--diff 的展示格式：text（逐字段差异）或 unified（资源 YAML 的统一 diff）")
+    syncCmd.Flags().BoolVar(&applyNoLock, "no-lock", false, "不获取网关上的 apply 锁（默认执行变更前获取，另一个 kongctl 正在执行时立即失败）")
+    syncCmd.Flags().BoolVar(&applyForceUnlock, "force-unlock", false, "移除他人持有的 apply 锁后再执行（确认对方已异常退出时使用）")
+    syncCmd.Flags().BoolVar(&applyServerValidate, "server-validate", false, "同步前将待创建/更新的资源提交到 Kong 的 /schemas/<entity>/validate 校验，未通过时不做任何变更")
+    syncCmd.Flags().BoolVar(&applyDetailedExit, "detailed-exitcode", false, "配合 --dry-run 使用退出码表示结果：0 无变更，1 出错，2 存在待执行变更")
+    syncCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "跳过 confirm: true 的交互确认")
+    syncCmd.Flags().StringSliceVar(&applyOverlays, "overlay", nil, "合并环境补丁（overlays/<name>.yaml 或文件路径），例：--overlay prod")
+    syncCmd.Flags().StringVar(&applyNamePrefix, "name-prefix", "", "为所有 upstream/service/route 名称加前缀；只删除名称带相同前后缀的受管资源")
+    syncCmd.Flags().StringVar(&applyNameSuffix, "name-suffix", "", "为所有 upstream/service/route 名称加后缀")
+    syncCmd.Flags().StringVar(&applyPathPrefix, "path-prefix", "", "为所有 route 的 paths 加上统一前缀，例：--path-prefix /team-a")
+    syncCmd.Flags().BoolVar(&applyAllContexts, "all-contexts", false, "对全部上下文（集群）依次同步，汇总各集群结果；也可用 --context a,b 指定多个")
+    syncCmd.Flags().BoolVar(&applyWatch, "watch", false, "持续运行：按 --interval 周期重新读取 spec 并同步（生产上下文删除资源需 --force）")
+    syncCmd.Flags().DurationVar(&applyWatchInterval, "interval", 30*time.Second, "--watch 的同步间隔，例：--interval 1m")
+    syncCmd.Flags().BoolVar(&applyVerify, "verify", false, "同步后经 Kong 代理发送 routes[].tests 声明的冒烟测试，任一未达预期时以非零状态退出")
+    syncCmd.Flags().StringVar(&applyVerifyProxy, "proxy-url", "", "--verify 使用的 Kong 代理地址（默认取配置项 proxy_url，否则 http://localhost:8000）")
+    syncCmd.Flags().DurationVar(&applyVerifyTimeout, "verify-timeout", 30*time.Second, "--verify 等待配置生效的最长时间（未达预期时每秒重试）")
+    addRenderFlags(syncCmd)
+}
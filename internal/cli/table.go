@@ -0,0 +1,119 @@
+package cli
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+    "unicode/utf8"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+)
+
+// yamlOutputAnnotation 标记支持 --output yaml 的命令（cobra Annotations 的 key）
+const yamlOutputAnnotation = "kongctl/output-yaml"
+
+var listFilter []string
+
+// listFilterFlag 为列表命令注册 --filter（语法同 apply --select 的 name=、tag=，多次指定时满足任一即可）
+func listFilterFlag(cmd *cobra.Command) {
+    cmd.Flags().StringArrayVar(&listFilter, "filter", nil, "按名称或标签过滤（通配），同一条内逗号分隔的条件需同时满足，可重复（满足任一即可），例：--filter name=user-* --filter tag=team-a")
+    if cmd.Annotations == nil { cmd.Annotations = map[string]string{} }
+    cmd.Annotations[yamlOutputAnnotation] = "true"
+}
+
+// listFilterMatch 返回按 --filter 过滤资源的函数；未指定 --filter 时全部保留
+func listFilterMatch() (func(name string, tags []string) bool, error) {
+    sels, err := parseSelectors("--filter", listFilter)
+    if err != nil {
+        return nil, withCode("usage", "", err)
+    }
+    return func(name string, tags []string) bool {
+        return len(sels) == 0 || anySelected(sels, "", name, tags)
+    }, nil
+}
+
+// printStructured 在 --output json/yaml 时输出 v 并返回 true；文本输出时返回 false
+func printStructured(cmd *cobra.Command, v any) (bool, error) {
+    switch {
+    case outputJSON():
+        b, _ := json.MarshalIndent(v, "", "  ")
+        cmd.Println(string(b))
+        return true, nil
+    case outputYAML():
+        return true, printYAML(cmd, v)
+    }
+    return false, nil
+}
+
+// printYAML 以 YAML 输出 v：经 JSON 转换，使字段名与 Admin API 一致；保留整数（如 created_at）不转为浮点
+func printYAML(cmd *cobra.Command, v any) error {
+    var obj any
+    b, _ := json.Marshal(v)
+    dec := json.NewDecoder(bytes.NewReader(b))
+    dec.UseNumber()
+    _ = dec.Decode(&obj)
+    out, err := yaml.Marshal(yamlNumbers(obj))
+    if err != nil {
+        return err
+    }
+    cmd.Print(string(out))
+    return nil
+}
+
+// yamlNumbers 将 json.Number 转为整数或浮点数，避免 YAML 中输出为字符串
+func yamlNumbers(v any) any {
+    switch x := v.(type) {
+    case map[string]any:
+        for k, e := range x { x[k] = yamlNumbers(e) }
+    case []any:
+        for i, e := range x { x[i] = yamlNumbers(e) }
+    case json.Number:
+        if n, err := x.Int64(); err == nil { return n }
+        f, _ := x.Float64()
+        return f
+    }
+    return v
+}
+
+// printTable 按各列最长的值对齐输出表格；空值显示为 -
+func printTable(cmd *cobra.Command, headers []string, rows [][]string) {
+    widths := make([]int, len(headers))
+    for i, h := range headers { widths[i] = utf8.RuneCountInString(h) }
+    for _, r := range rows {
+        for i := range r {
+            if r[i] == "" { r[i] = "-" }
+            if n := utf8.RuneCountInString(r[i]); n > widths[i] { widths[i] = n }
+        }
+    }
+    line := func(cells []string) {
+        var b strings.Builder
+        for i, c := range cells {
+            if i == len(cells)-1 {
+                b.WriteString(c)
+                break
+            }
+            b.WriteString(c + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c)+2))
+        }
+        cmd.Println(strings.TrimRight(b.String(), " "))
+    }
+    line(headers)
+    for _, r := range rows { line(r) }
+}
+
+// formatAge 将 Unix 时间戳格式化为距今的时长（如 45s、12m、3h、5d），0 时返回空
+func formatAge(ts int64) string {
+    if ts <= 0 { return "" }
+    d := time.Since(time.Unix(ts, 0))
+    switch {
+    case d < time.Minute:
+        return fmt.Sprintf("%ds", int(d.Seconds()))
+    case d < time.Hour:
+        return fmt.Sprintf("%dm", int(d.Minutes()))
+    case d < 48*time.Hour:
+        return fmt.Sprintf("%dh", int(d.Hours()))
+    }
+    return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -3,17 +3,24 @@ package cli
 import (
     "context"
     "fmt"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
     "time"
 
     "github.com/spf13/cobra"
-    "github.com/spf13/viper"
+    "gopkg.in/yaml.v3"
     "kongctl/internal/kong"
 )
 
 var (
-    tgtUpstream string
-    tgtAddress  string
-    tgtWeight   int
+    tgtUpstream  string
+    tgtAddresses []string
+    tgtWeight    int
+    tgtDryRun    bool
+    tgtFile      string
+    tgtParallel  int
 )
 
 var targetCmd = &cobra.Command{
@@ -21,30 +28,376 @@ var targetCmd = &cobra.Command{
     Short: "管理 Upstream 的 Target（后端节点）",
 }
 
+// parseTargetArg 解析 --target 的取值：host:port，可用 /<权重> 单独指定权重（未指定时使用 --weight）
+func parseTargetArg(s string, weight int) (applyTarget, error) {
+    s = strings.TrimSpace(s)
+    t := applyTarget{Target: s, Weight: weight}
+    if i := strings.LastIndex(s, "/"); i >= 0 {
+        w, err := strconv.Atoi(s[i+1:])
+        if err != nil || w < 0 {
+            return applyTarget{}, fmt.Errorf("target %q 的权重无效（格式：host:port/权重）", s)
+        }
+        t.Target, t.Weight = s[:i], w
+    }
+    if t.Target == "" {
+        return applyTarget{}, fmt.Errorf("target 地址不能为空")
+    }
+    return t, nil
+}
+
+// loadTargetFile 读取 target add -f 的文件：列表项为 "host:port[/权重]" 字符串或 {target, weight, zone, tags} 对象，
+// 也接受顶层 {targets: [...]}；未声明权重的项使用 --weight
+func loadTargetFile(cmd *cobra.Command, file string, weight int) ([]applyTarget, error) {
+    content, err := readSpecFile(cmd, file)
+    if err != nil {
+        return nil, err
+    }
+    var raw any
+    if err := yaml.Unmarshal(content, &raw); err != nil {
+        return nil, fmt.Errorf("%s：解析失败：%w", file, err)
+    }
+    if m, ok := raw.(map[string]any); ok { raw = m["targets"] }
+    items, ok := raw.([]any)
+    if !ok {
+        return nil, fmt.Errorf("%s：应为 target 列表（或 targets: [...]）", file)
+    }
+    var out []applyTarget
+    for i, it := range items {
+        switch v := it.(type) {
+        case string:
+            t, err := parseTargetArg(v, weight)
+            if err != nil { return nil, fmt.Errorf("%s：第 %d 项：%w", file, i+1, err) }
+            out = append(out, t)
+        case map[string]any:
+            t, err := decodeStrict[applyTarget](v)
+            if err != nil { return nil, fmt.Errorf("%s：第 %d 项：%w", file, i+1, err) }
+            if t.Target == "" { return nil, fmt.Errorf("%s：第 %d 项缺少 target", file, i+1) }
+            if t.Weight == 0 { t.Weight = weight }
+            out = append(out, t)
+        default:
+            return nil, fmt.Errorf("%s：第 %d 项应为 host:port 字符串或对象", file, i+1)
+        }
+    }
+    return out, nil
+}
+
 var targetAddCmd = &cobra.Command{
     Use:   "add",
-    Short: "向 Upstream 添加 Target",
+    Short: "向 Upstream 添加 Target（可批量）",
     Example: `# 向 user-service-upstream 添加一个后端节点
-kongctl target add --upstream user-service-upstream --target user-svc-1:8080 --weight 100`,
+kongctl target add --upstream user-service-upstream --target user-svc-1:8080 --weight 100
+
+# 一次添加多个节点（/<权重> 可单独指定权重）
+kongctl target add --upstream user-service-upstream --target 10.0.0.1:8080 --target 10.0.0.2:8080/50
+
+# 从文件批量添加（列表项为 host:port[/权重] 或 {target, weight, zone, tags}）
+kongctl target add --upstream user-service-upstream -f targets.yaml --parallel 16`,
     RunE: func(cmd *cobra.Command, args []string) error {
-        if tgtUpstream == "" || tgtAddress == "" {
-            return fmt.Errorf("必须提供 --upstream 与 --target")
+        if tgtUpstream == "" || (len(tgtAddresses) == 0 && tgtFile == "") {
+            return fmt.Errorf("必须提供 --upstream 与 --target（或 -f 文件）")
         }
         if tgtWeight == 0 { tgtWeight = 100 }
-        cfg := kong.Config{
-            AdminURL:      viper.GetString("admin_url"),
-            Token:         viper.GetString("token"),
-            TLSSkipVerify: viper.GetBool("tls_skip_verify"),
-            Timeout:       10 * time.Second,
+        var targets []applyTarget
+        for _, a := range tgtAddresses {
+            t, err := parseTargetArg(a, tgtWeight)
+            if err != nil {
+                return withCode("usage", "", err)
+            }
+            targets = append(targets, t)
+        }
+        if tgtFile != "" {
+            list, err := loadTargetFile(cmd, tgtFile, tgtWeight)
+            if err != nil {
+                return err
+            }
+            targets = append(targets, list...)
+        }
+        seen := map[string]bool{}
+        for _, t := range targets {
+            if seen[t.Target] {
+                return withCode("usage", "", fmt.Errorf("target %s 重复声明", t.Target))
+            }
+            seen[t.Target] = true
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        if len(targets) == 1 {
+            t := targets[0]
+            ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+            defer cancel()
+            if _, err := client.AddTarget(ctx, tgtUpstream, t.Target, t.Weight, t.tagList()...); err != nil {
+                return err
+            }
+            PrintSuccess(cmd, "已添加 Target：%s (weight=%d) 到 Upstream：%s", t.Target, t.Weight, tgtUpstream)
+            return nil
+        }
+        return addTargets(cmd, client, cfg.Timeout, targets)
+    },
+}
+
+// addTargets 以 --parallel 个 worker 并发添加 targets（每个请求单独计时），结束后按输入顺序汇总结果；
+// 部分失败时已添加的不回滚，以非零状态退出
+func addTargets(cmd *cobra.Command, client *kong.Client, timeout time.Duration, targets []applyTarget) error {
+    ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+    _, found, err := client.GetUpstream(ctx, tgtUpstream)
+    cancel()
+    if err != nil {
+        return err
+    }
+    if !found {
+        return withCode("not_found", "", fmt.Errorf("Upstream 不存在：%s", tgtUpstream))
+    }
+    workers := tgtParallel
+    if workers < 1 { workers = 1 }
+    if workers > len(targets) { workers = len(targets) }
+    errs := make([]error, len(targets))
+    jobs := make(chan int)
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+                _, errs[i] = client.AddTarget(ctx, tgtUpstream, targets[i].Target, targets[i].Weight, targets[i].tagList()...)
+                cancel()
+            }
+        }()
+    }
+    for i := range targets { jobs <- i }
+    close(jobs)
+    wg.Wait()
+    var failed []string
+    for i, t := range targets {
+        if errs[i] != nil {
+            failed = append(failed, t.Target)
+            cmd.Printf("  %s %s (weight=%d)：%v\n", colorError(glyph("✗", "x")), t.Target, t.Weight, errs[i])
+        } else {
+            cmd.Printf("  %s %s (weight=%d)\n", colorSuccess(glyph("✓", "+")), t.Target, t.Weight)
+        }
+    }
+    if len(failed) > 0 {
+        return fmt.Errorf("Upstream %s：已添加 %d 个 Target，%d 个失败：%s", tgtUpstream, len(targets)-len(failed), len(failed), strings.Join(failed, ", "))
+    }
+    PrintSuccess(cmd, "已添加 %d 个 Target 到 Upstream：%s", len(targets), tgtUpstream)
+    return nil
+}
+
+// duplicateTargetSummary 将重复记录按地址排序并格式化为 "addr×N, ..."
+func duplicateTargetSummary(dups map[string]int) string {
+    addrs := make([]string, 0, len(dups))
+    for a := range dups { addrs = append(addrs, a) }
+    sort.Strings(addrs)
+    parts := make([]string, len(addrs))
+    for i, a := range addrs { parts[i] = fmt.Sprintf("%s×%d", a, dups[a]) }
+    return strings.Join(parts, ", ")
+}
+
+// warnDuplicateTargets 在 dry-run 时检查声明了 targets 的 upstream 是否存在同一地址的多条历史记录；
+// 计划已按最新记录计算，这里只提示清理
+func warnDuplicateTargets(cmd *cobra.Command, ctx context.Context, client *kong.Client, spec applySpec) {
+    d := collectDeclared(spec)
+    ups := make([]string, 0, len(d.targets))
+    for up, ts := range d.targets {
+        if len(ts) > 0 { ups = append(ups, up) }
+    }
+    sort.Strings(ups)
+    for _, up := range ups {
+        // upstream 尚未创建等情况下无法列出，交由后续计划处理
+        list, err := client.ListTargetRecords(ctx, up)
+        if err != nil { continue }
+        if _, dups := kong.CollapseTargets(list); len(dups) > 0 {
+            PrintWarn(cmd, "Upstream %s 存在重复的 target 记录（%s），已按各地址最新一条规划；可执行 kongctl target compact --upstream %s 清理历史记录", up, duplicateTargetSummary(dups), up)
+        }
+    }
+}
+
+var targetCompactCmd = &cobra.Command{
+    Use:   "compact",
+    Short: "清理 Upstream 下同一地址的历史 Target 记录，只保留最新一条",
+    Long: `部分 Kong 版本中 targets 只追加不修改，每次调整权重都会新增一条同地址记录，列表随时间不断变长。
+compact 按 created_at 保留每个地址最新的一条记录，按 id 删除其余记录；生效的权重不变。`,
+    Example: `# 预览将删除的历史记录
+kongctl target compact --upstream user-service-upstream --dry-run
+
+# 执行清理
+kongctl target compact --upstream user-service-upstream`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if tgtUpstream == "" {
+            return withCode("usage", "", fmt.Errorf("必须提供 --upstream"))
+        }
+        cfg, err := clientConfig(30 * time.Second)
+        if err != nil {
+            return err
         }
-        if cfg.AdminURL == "" { return fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置") }
         client := kong.NewClient(cfg)
         ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
         defer cancel()
-        if _, err := client.AddTarget(ctx, tgtUpstream, tgtAddress, tgtWeight); err != nil {
+        list, err := client.ListTargetRecords(ctx, tgtUpstream)
+        if err != nil {
             return err
         }
-        PrintSuccess(cmd, "已添加 Target：%s (weight=%d) 到 Upstream：%s", tgtAddress, tgtWeight, tgtUpstream)
+        latest, dups := kong.CollapseTargets(list)
+        if len(dups) == 0 {
+            PrintInfo(cmd, "Upstream %s 没有重复的 target 记录", tgtUpstream)
+            return nil
+        }
+        keep := map[string]string{}
+        for _, t := range latest { keep[t.Target] = t.ID }
+        var stale []kong.Target
+        for _, t := range list {
+            if dups[t.Target] > 0 && t.ID != keep[t.Target] { stale = append(stale, t) }
+        }
+        PrintInfo(cmd, "Upstream %s：%d 个地址存在重复记录（%s），将删除 %d 条历史记录", tgtUpstream, len(dups), duplicateTargetSummary(dups), len(stale))
+        if tgtDryRun {
+            for _, t := range stale {
+                cmd.Printf("  - %s (id=%s, weight=%d)\n", t.Target, t.ID, t.Weight)
+            }
+            cmd.Println("[dry-run] 以上为计划删除的记录（未实际变更）")
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("target compact %s（删除 %d 条历史记录）", tgtUpstream, len(stale))); err != nil {
+            return err
+        }
+        for _, t := range stale {
+            if t.ID == "" { continue }
+            if err := client.DeleteTarget(ctx, tgtUpstream, t.ID); err != nil {
+                return fmt.Errorf("删除 target 记录 %s（%s）失败：%w", t.ID, t.Target, err)
+            }
+        }
+        PrintSuccess(cmd, "已清理 Upstream %s 的 %d 条历史 target 记录", tgtUpstream, len(stale))
+        return nil
+    },
+}
+
+var targetListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Upstream 的 Target（地址、权重、健康状态、标签与创建时长）",
+    Long: `列出 upstream 下生效的 targets（同一地址的历史记录只显示最新一条），HEALTH 取自 /upstreams/<name>/health。
+--filter name=<通配> 按地址过滤。`,
+    Example: `kongctl target list --upstream user-service-upstream
+kongctl target list --upstream user-service-upstream --filter tag=zone=eu-1 -o json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if tgtUpstream == "" {
+            return withCode("usage", "", fmt.Errorf("必须提供 --upstream"))
+        }
+        match, err := listFilterMatch()
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        if _, ok, err := client.GetUpstream(ctx, tgtUpstream); err != nil {
+            return err
+        } else if !ok {
+            return withCode("not_found", "使用 kongctl upstream list 查看已有 Upstream", fmt.Errorf("Upstream 不存在：%s", tgtUpstream))
+        }
+        all, err := client.ListTargets(ctx, tgtUpstream)
+        if err != nil {
+            return err
+        }
+        list := []kong.Target{}
+        for _, t := range all {
+            if match(t.Target, t.Tags) { list = append(list, t) }
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Target < list[j].Target })
+        if outputTemplate != "" {
+            return printTemplate(cmd, list)
+        }
+        if ok, err := printStructured(cmd, list); ok {
+            return err
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "Upstream %s 下没有 target", tgtUpstream)
+            return nil
+        }
+        // 健康状态仅用于展示，读取失败（如权限受限）时显示 -
+        health := map[string]string{}
+        if hs, err := client.UpstreamHealth(ctx, tgtUpstream); err == nil {
+            for _, h := range hs { health[h.Target] = h.Health }
+        }
+        rows := make([][]string, 0, len(list))
+        for _, t := range list {
+            rows = append(rows, []string{t.Target, strconv.Itoa(t.Weight), health[t.Target], strings.Join(t.Tags, ","), formatAge(int64(t.CreatedAt))})
+        }
+        printTable(cmd, []string{"TARGET", "WEIGHT", "HEALTH", "TAGS", "AGE"}, rows)
+        return nil
+    },
+}
+
+var targetDeleteCmd = &cobra.Command{
+    Use:   "delete",
+    Short: "从 Upstream 中删除 Target",
+    Long: `按地址（host:port）或 id 删除 upstream 下的 target，可重复指定 --target；不存在的 target 跳过。
+删除后 upstream 不再有任何 target 且仍被 Service 引用时会给出提示（这些 Service 的请求将返回 503）。`,
+    Example: `kongctl target delete --upstream user-service-upstream --target 10.0.0.1:8080
+kongctl target delete --upstream user-service-upstream --target 10.0.0.1:8080 --target 10.0.0.2:8080 --dry-run
+kongctl target delete --upstream user-service-upstream --target 10.0.0.1:8080 -y`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if tgtUpstream == "" || len(tgtAddresses) == 0 {
+            return withCode("usage", "", fmt.Errorf("必须提供 --upstream 与至少一个 --target"))
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        if _, ok, err := client.GetUpstream(ctx, tgtUpstream); err != nil {
+            return err
+        } else if !ok {
+            return withCode("not_found", "", fmt.Errorf("Upstream 不存在：%s", tgtUpstream))
+        }
+        list, err := client.ListTargets(ctx, tgtUpstream)
+        if err != nil {
+            return err
+        }
+        var del []kong.Target
+        for _, a := range tgtAddresses {
+            a = strings.TrimSpace(a)
+            found := false
+            for _, t := range list {
+                if t.Target == a || t.ID == a { del = append(del, t); found = true; break }
+            }
+            if !found { PrintInfo(cmd, "Target 不存在，跳过：%s/%s", tgtUpstream, a) }
+        }
+        if len(del) == 0 {
+            return nil
+        }
+        if len(del) == len(list) {
+            svcs, err := upstreamServices(ctx, client, tgtUpstream)
+            if err != nil {
+                return err
+            }
+            if len(svcs) > 0 { PrintWarn(cmd, "删除后 Upstream %s 将没有任何 target，仍引用它的 %d 个 Service 将不可用", tgtUpstream, len(svcs)) }
+        }
+        if tgtDryRun {
+            for _, t := range del { PrintInfo(cmd, "[dry-run] 将删除 Target：%s/%s（weight=%d）", tgtUpstream, t.Target, t.Weight) }
+            return nil
+        }
+        if ok, err := confirmDelete(cmd, fmt.Sprintf("target delete %s（删除 %d 个 target）", tgtUpstream, len(del))); err != nil || !ok {
+            return err
+        }
+        // 等待确认的时间不计入超时：删除使用新的 context
+        ctx, cancel = context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        for _, t := range del {
+            if err := client.DeleteTarget(ctx, tgtUpstream, t.Target); err != nil {
+                return fmt.Errorf("删除 Target %s/%s 失败：%w", tgtUpstream, t.Target, err)
+            }
+            PrintSuccess(cmd, "已删除 Target：%s/%s", tgtUpstream, t.Target)
+        }
         return nil
     },
 }
@@ -52,6 +405,20 @@ kongctl target add --upstream user-service-upstream --target user-svc-1:8080 --w
 func init() {
     targetCmd.AddCommand(targetAddCmd)
     targetAddCmd.Flags().StringVar(&tgtUpstream, "upstream", "", "Upstream 名称，例：user-service-upstream")
-    targetAddCmd.Flags().StringVar(&tgtAddress, "target", "", "后端地址 host:port，例：10.0.0.1:8080 或 app:8080")
-    targetAddCmd.Flags().IntVar(&tgtWeight, "weight", 100, "权重（默认 100），例：--weight 100")
+    targetAddCmd.Flags().StringArrayVar(&tgtAddresses, "target", nil, "后端地址 host:port[/权重]，可重复指定，例：--target 10.0.0.1:8080 --target app:8080/50")
+    targetAddCmd.Flags().IntVar(&tgtWeight, "weight", 100, "权重（默认 100，未单独指定权重的 target 使用），例：--weight 100")
+    targetAddCmd.Flags().StringVarP(&tgtFile, "file", "f", "", "从文件批量添加（YAML/JSON 列表，- 表示标准输入），例：-f targets.yaml")
+    targetAddCmd.Flags().IntVar(&tgtParallel, "parallel", 8, "批量添加时的并发数")
+    targetCmd.AddCommand(targetCompactCmd)
+    targetCompactCmd.Flags().StringVar(&tgtUpstream, "upstream", "", "Upstream 名称，例：user-service-upstream")
+    targetCompactCmd.Flags().BoolVar(&tgtDryRun, "dry-run", false, "只列出将删除的记录，不做变更")
+    targetCmd.AddCommand(targetListCmd)
+    targetListCmd.Flags().StringVar(&tgtUpstream, "upstream", "", "Upstream 名称，例：user-service-upstream")
+    listFilterFlag(targetListCmd)
+    addTemplateFlag(targetListCmd)
+    targetCmd.AddCommand(targetDeleteCmd)
+    targetDeleteCmd.Flags().StringVar(&tgtUpstream, "upstream", "", "Upstream 名称，例：user-service-upstream")
+    targetDeleteCmd.Flags().StringArrayVar(&tgtAddresses, "target", nil, "要删除的 target 地址 host:port 或 id，可重复指定，例：--target 10.0.0.1:8080")
+    targetDeleteCmd.Flags().BoolVar(&tgtDryRun, "dry-run", false, "仅显示计划，不实际变更")
+    targetDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "跳过删除前的 [y/N] 确认（非交互环境必需）")
 }
@@ -0,0 +1,78 @@
+package cli
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "reflect"
+    "strings"
+    "text/template"
+
+    "github.com/spf13/cobra"
+)
+
+// outputTemplate 为 --template 指定的 Go 模板；设置后替代命令的默认输出，便于脚本直接提取字段
+var outputTemplate string
+
+func addTemplateFlag(cmd *cobra.Command) {
+    cmd.Flags().StringVar(&outputTemplate, "template", "", "以 Go 模板输出结果（列表逐项渲染并换行），例：--template '{{.Name}} {{.Host}}'")
+}
+
+var templateFuncs = template.FuncMap{
+    "join":  templateJoin,
+    "upper": strings.ToUpper,
+    "lower": strings.ToLower,
+    "json": func(v any) (string, error) {
+        b, err := json.Marshal(v)
+        return string(b), err
+    },
+}
+
+// templateJoin 为模板中的 join：同时接受 []string 与解码 JSON 得到的 []any（如 get 输出的 tags）
+func templateJoin(v any, sep string) (string, error) {
+    switch x := v.(type) {
+    case []string:
+        return strings.Join(x, sep), nil
+    case []any:
+        parts := make([]string, len(x))
+        for i, e := range x { parts[i] = fmt.Sprint(e) }
+        return strings.Join(parts, sep), nil
+    case nil:
+        return "", nil
+    }
+    return "", fmt.Errorf("join 需要列表参数，得到 %T", v)
+}
+
+// renderTemplate 渲染 --template：data 为切片时逐项渲染并各占一行，否则整体渲染一次
+func renderTemplate(data any) ([]byte, error) {
+    tpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=error").Parse(outputTemplate)
+    if err != nil {
+        return nil, fmt.Errorf("--template 解析失败：%w", err)
+    }
+    var buf bytes.Buffer
+    exec := func(v any) error {
+        if err := tpl.Execute(&buf, v); err != nil {
+            return fmt.Errorf("--template 渲染失败：%w", err)
+        }
+        if b := buf.Bytes(); len(b) == 0 || b[len(b)-1] != '\n' { buf.WriteByte('\n') }
+        return nil
+    }
+    if rv := reflect.ValueOf(data); rv.Kind() == reflect.Slice {
+        for i := 0; i < rv.Len(); i++ {
+            if err := exec(rv.Index(i).Interface()); err != nil { return nil, err }
+        }
+        return buf.Bytes(), nil
+    }
+    if err := exec(data); err != nil { return nil, err }
+    return buf.Bytes(), nil
+}
+
+// printTemplate 将 --template 渲染结果写入标准输出
+func printTemplate(cmd *cobra.Command, data any) error {
+    out, err := renderTemplate(data)
+    if err != nil {
+        return err
+    }
+    cmd.Print(string(out))
+    return nil
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/spf13/viper"
+)
+
+// palette 为输出配色方案，各字段为 ANSI SGR 参数（如 "32"、"1;36"、"38;5;208"）
+type palette struct {
+    Success string
+    Info    string
+    Warn    string
+    Error   string
+    Header  string // 计划标题
+    Accent  string // 分组分隔线
+    Subtle  string // 提示与无变化项
+    Create  string // 计划：创建
+    Update  string // 计划：更新
+    Added   string // diff：新增行
+    Removed string // diff：删除行
+}
+
+// themes 为内置预设：
+// default    经典绿/黄/红
+// colorblind 蓝/橙配色，避免红绿色盲无法区分新增与删除
+// light      适合浅色背景终端的深色系（不使用亮黄与灰色）
+var themes = map[string]palette{
+    "default": {
+        Success: "32", Info: "36", Warn: "33", Error: "31;1",
+        Header: "36;1", Accent: "35;1", Subtle: "90",
+        Create: "32", Update: "33", Added: "32", Removed: "31",
+    },
+    "colorblind": {
+        Success: "34", Info: "36", Warn: "38;5;208", Error: "38;5;208;1",
+        Header: "34;1", Accent: "35;1", Subtle: "90",
+        Create: "34", Update: "38;5;208", Added: "34", Removed: "38;5;208",
+    },
+    "light": {
+        Success: "32", Info: "34", Warn: "35", Error: "31;1",
+        Header: "34;1", Accent: "35;1", Subtle: "2",
+        Create: "32", Update: "35", Added: "32", Removed: "31",
+    },
+}
+
+// activePalette 为当前生效的配色，由 loadTheme 根据配置设置
+var activePalette = themes["default"]
+
+func themeNames() []string {
+    names := make([]string, 0, len(themes))
+    for n := range themes { names = append(names, n) }
+    sort.Strings(names)
+    return names
+}
+
+// loadTheme 读取配置项 theme（预设名）与 colors（逐项覆盖），例如：
+//
+//	theme: colorblind
+//	colors:
+//	  create: blue
+//	  removed: "bold #ff8800"
+func loadTheme() {
+    name := strings.ToLower(strings.TrimSpace(viper.GetString("theme")))
+    if name == "" { name = "default" }
+    pal, ok := themes[name]
+    if !ok {
+        fmt.Fprintf(os.Stderr, "未知配色主题：%s（可用：%s），已使用 default\n", name, strings.Join(themeNames(), ", "))
+        pal = themes["default"]
+    }
+    fields := map[string]*string{
+        "success": &pal.Success, "info": &pal.Info, "warn": &pal.Warn, "error": &pal.Error,
+        "header": &pal.Header, "accent": &pal.Accent, "subtle": &pal.Subtle,
+        "create": &pal.Create, "update": &pal.Update, "added": &pal.Added, "removed": &pal.Removed,
+    }
+    for key, val := range viper.GetStringMapString("colors") {
+        dst, ok := fields[strings.ToLower(key)]
+        if !ok {
+            fmt.Fprintf(os.Stderr, "忽略未知颜色项：colors.%s\n", key)
+            continue
+        }
+        code, err := parseColorSpec(val)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "忽略无效颜色 colors.%s：%v\n", key, err)
+            continue
+        }
+        *dst = code
+    }
+    activePalette = pal
+}
+
+var colorNames = map[string]int{
+    "black": 30, "red": 31, "green": 32, "yellow": 33, "blue": 34, "magenta": 35, "cyan": 36, "white": 37,
+    "gray": 90, "grey": 90,
+    "bright-red": 91, "bright-green": 92, "bright-yellow": 93, "bright-blue": 94, "bright-magenta": 95, "bright-cyan": 96, "bright-white": 97,
+}
+
+// parseColorSpec 将颜色描述转为 SGR 参数。支持：
+// 颜色名（"blue"、"bright-red"）、修饰词（"bold"、"dim"、"underline"）、
+// 256 色编号（"208"）、十六进制真彩色（"#ff8800"）以及原始 SGR（"38;5;208"），可用空格组合。
+func parseColorSpec(spec string) (string, error) {
+    var codes []string
+    for _, tok := range strings.Fields(strings.ToLower(spec)) {
+        switch {
+        case tok == "bold":
+            codes = append(codes, "1")
+        case tok == "dim":
+            codes = append(codes, "2")
+        case tok == "underline":
+            codes = append(codes, "4")
+        case colorNames[tok] != 0:
+            codes = append(codes, strconv.Itoa(colorNames[tok]))
+        case strings.HasPrefix(tok, "#") && len(tok) == 7:
+            v, err := strconv.ParseUint(tok[1:], 16, 32)
+            if err != nil { return "", fmt.Errorf("无效的十六进制颜色：%s", tok) }
+            codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", v>>16, (v>>8)&0xff, v&0xff))
+        case strings.Contains(tok, ";"):
+            codes = append(codes, tok)
+        default:
+            n, err := strconv.Atoi(tok)
+            if err != nil || n < 0 || n > 255 { return "", fmt.Errorf("无法识别：%s", tok) }
+            codes = append(codes, "38;5;"+tok)
+        }
+    }
+    if len(codes) == 0 { return "", fmt.Errorf("颜色为空") }
+    return strings.Join(codes, ";"), nil
+}
+
+// sgr 返回 SGR 参数对应的 ANSI 转义序列
+func sgr(code string) string { return "\033[" + code + "m" }
@@ -0,0 +1,314 @@
+package cli
+
+import (
+    "bufio"
+    "context"
+    "crypto/tls"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "os"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// sampledRequest 为一条请求样本：来自访问日志时 Weight 为 1，来自 Prometheus 时为所代表的请求数
+type sampledRequest struct {
+    Host    string
+    Method  string
+    Path    string
+    Headers map[string]string
+    Weight  float64
+}
+
+var (
+    applyPrometheusURL    string
+    applyPrometheusQuery  string
+    applyPrometheusWindow time.Duration
+)
+
+// prometheusDefaultQuery 为按 route 汇总请求数的默认查询（Kong prometheus 插件的指标），%s 为时间窗口
+const prometheusDefaultQuery = `sum by (route) (increase(kong_http_requests_total[%s]))`
+
+// combinedRequestLine 匹配 combined/common 日志格式中的 "GET /path HTTP/1.1"
+var combinedRequestLine = regexp.MustCompile(`"([A-Z]+) (\S+) HTTP/[0-9.]+"`)
+
+// parseAccessLogLine 解析单行访问日志；支持 Kong file-log 插件 JSON 行与 nginx combined 格式
+func parseAccessLogLine(line string) (sampledRequest, bool) {
+    line = strings.TrimSpace(line)
+    if line == "" {
+        return sampledRequest{}, false
+    }
+    if strings.HasPrefix(line, "{") {
+        var entry struct {
+            Request struct {
+                Method  string         `json:"method"`
+                URI     string         `json:"uri"`
+                Headers map[string]any `json:"headers"`
+            } `json:"request"`
+            Method string `json:"method"`
+            Path   string `json:"path"`
+            Host   string `json:"host"`
+        }
+        if err := json.Unmarshal([]byte(line), &entry); err != nil {
+            return sampledRequest{}, false
+        }
+        req := sampledRequest{Method: entry.Method, Path: entry.Path, Host: entry.Host, Headers: map[string]string{}}
+        if entry.Request.Method != "" { req.Method = entry.Request.Method }
+        if entry.Request.URI != "" { req.Path = entry.Request.URI }
+        for k, v := range entry.Request.Headers {
+            if s, ok := v.(string); ok { req.Headers[strings.ToLower(k)] = s }
+        }
+        if req.Host == "" { req.Host = req.Headers["host"] }
+        if req.Path == "" { return sampledRequest{}, false }
+        req.Path = stripQuery(req.Path)
+        req.Weight = 1
+        return req, true
+    }
+    m := combinedRequestLine.FindStringSubmatch(line)
+    if m == nil {
+        return sampledRequest{}, false
+    }
+    return sampledRequest{Method: m[1], Path: stripQuery(m[2]), Weight: 1}, true
+}
+
+func stripQuery(p string) string {
+    if u, err := url.Parse(p); err == nil && u.Path != "" {
+        return u.Path
+    }
+    if i := strings.IndexByte(p, '?'); i >= 0 {
+        return p[:i]
+    }
+    return p
+}
+
+func readAccessLog(path string) ([]sampledRequest, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, fmt.Errorf("读取访问日志失败：%w", err)
+    }
+    defer f.Close()
+    var out []sampledRequest
+    sc := bufio.NewScanner(f)
+    sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+    for sc.Scan() {
+        if req, ok := parseAccessLogLine(sc.Text()); ok {
+            out = append(out, req)
+        }
+    }
+    if err := sc.Err(); err != nil {
+        return nil, fmt.Errorf("读取访问日志失败：%w", err)
+    }
+    return out, nil
+}
+
+// shortDuration 去掉时长末尾多余的 0 单位（1h0m0s → 1h），用于提示与 PromQL
+func shortDuration(d time.Duration) string {
+    out := d.String()
+    if strings.HasSuffix(out, "m0s") { out = strings.TrimSuffix(out, "0s") }
+    if strings.HasSuffix(out, "h0m") { out = strings.TrimSuffix(out, "0m") }
+    return out
+}
+
+// queryPrometheusRoutes 通过 Prometheus HTTP API（/api/v1/query）查询各 route 的请求数；
+// 查询结果需为带 route 标签的 vector（Kong prometheus 插件的 route 标签为路由名称，未命名时为 id）。
+// 单次查询最长 10s（ctx 的截止时间更早时以其为准），HTTPS 沿用 tls_skip_verify 配置
+func queryPrometheusRoutes(ctx context.Context, base, query string) (map[string]float64, error) {
+    u := strings.TrimRight(base, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+    if err != nil {
+        return nil, fmt.Errorf("--prometheus-url 无效：%w", err)
+    }
+    prom := &http.Client{
+        Timeout:   10 * time.Second,
+        Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: viper.GetBool("tls_skip_verify")}}, //nolint:gosec
+    }
+    resp, err := prom.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("查询 Prometheus 失败：%w", err)
+    }
+    defer resp.Body.Close()
+    body, _ := io.ReadAll(resp.Body)
+    var out struct {
+        Status string `json:"status"`
+        Error  string `json:"error"`
+        Data   struct {
+            ResultType string `json:"resultType"`
+            Result     []struct {
+                Metric map[string]string `json:"metric"`
+                Value  []any             `json:"value"`
+            } `json:"result"`
+        } `json:"data"`
+    }
+    if err := json.Unmarshal(body, &out); err != nil {
+        return nil, fmt.Errorf("解析 Prometheus 响应失败（HTTP %d）：%w", resp.StatusCode, err)
+    }
+    if out.Status != "success" {
+        return nil, fmt.Errorf("Prometheus 查询失败（HTTP %d）：%s", resp.StatusCode, out.Error)
+    }
+    if out.Data.ResultType != "vector" {
+        return nil, fmt.Errorf("Prometheus 查询结果应为 vector，实际为 %s：%s", out.Data.ResultType, query)
+    }
+    rates := map[string]float64{}
+    for _, r := range out.Data.Result {
+        route := r.Metric["route"]
+        if route == "" || len(r.Value) != 2 { continue }
+        v, _ := r.Value[1].(string)
+        if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 { rates[route] += n }
+    }
+    return rates, nil
+}
+
+// representativeRequests 按路由自身的 hosts/methods/paths/headers 构造代表性请求（正则路径无法构造，跳过）
+func representativeRequests(r kong.Route) []sampledRequest {
+    hosts := r.Hosts
+    if len(hosts) == 0 { hosts = []string{""} }
+    methods := r.Methods
+    if len(methods) == 0 { methods = []string{"GET"} }
+    paths := r.Paths
+    if len(paths) == 0 { paths = []string{"/"} }
+    headers := map[string]string{}
+    for k, vs := range r.Headers {
+        if len(vs) > 0 { headers[strings.ToLower(k)] = vs[0] }
+    }
+    var out []sampledRequest
+    for _, h := range hosts {
+        // 通配 host 以任意标签代入
+        if strings.HasPrefix(h, "*.") { h = "sample" + h[1:] }
+        if strings.HasSuffix(h, ".*") { h = h[:len(h)-1] + "sample" }
+        for _, m := range methods {
+            for _, p := range paths {
+                if strings.HasPrefix(p, "~") { continue }
+                out = append(out, sampledRequest{Host: h, Method: m, Path: p, Headers: headers})
+            }
+        }
+    }
+    return out
+}
+
+// prometheusSamples 将各 route 的请求数换算为加权样本：每条 route 的请求数平均分配到其代表性请求上，
+// 且只保留当前路由表中确实由该 route 处理的请求；无法构造代表性请求的 route 计入返回的未估算请求数
+func prometheusSamples(rates map[string]float64, remote []kong.Route) ([]sampledRequest, float64) {
+    rules := make([]aplan.RouteRule, 0, len(remote))
+    for _, r := range remote { rules = append(rules, toRouteRule(r)) }
+    cur := aplan.NewRouter(rules)
+    var out []sampledRequest
+    unresolved := 0.0
+    for _, r := range remote {
+        n := rates[r.ID]
+        if r.Name != "" { n += rates[r.Name] }
+        if n <= 0 { continue }
+        var reqs []sampledRequest
+        for _, req := range representativeRequests(r) {
+            if r.Name != "" && cur.Match(req.Host, req.Method, req.Path, req.Headers) == r.Name { reqs = append(reqs, req) }
+        }
+        if len(reqs) == 0 {
+            unresolved += n
+            continue
+        }
+        for _, req := range reqs {
+            req.Weight = n / float64(len(reqs))
+            out = append(out, req)
+        }
+    }
+    return out, unresolved
+}
+
+// trafficSamples 按 --access-log 或 --prometheus-url 读取流量样本；返回样本与来源说明（均未指定时返回 nil）
+func trafficSamples(cmd *cobra.Command, ctx context.Context, remote []kong.Route) ([]sampledRequest, string, error) {
+    if applyAccessLog != "" {
+        samples, err := readAccessLog(applyAccessLog)
+        if err != nil {
+            return nil, "", err
+        }
+        if len(samples) == 0 { PrintWarn(cmd, "访问日志中未解析到任何请求：%s", applyAccessLog) }
+        return samples, "访问日志采样", nil
+    }
+    if applyPrometheusURL == "" {
+        return nil, "", nil
+    }
+    query, source := applyPrometheusQuery, "Prometheus 指标"
+    if query == "" {
+        query = fmt.Sprintf(prometheusDefaultQuery, shortDuration(applyPrometheusWindow))
+        source = "近 " + shortDuration(applyPrometheusWindow) + " 的 Prometheus 指标"
+    }
+    rates, err := queryPrometheusRoutes(ctx, applyPrometheusURL, query)
+    if err != nil {
+        return nil, "", err
+    }
+    samples, unresolved := prometheusSamples(rates, remote)
+    if len(rates) == 0 {
+        PrintWarn(cmd, "Prometheus 未返回任何 route 的请求数（确认已启用 prometheus 插件并按 route 统计）：%s", query)
+    }
+    if unresolved > 0 {
+        PrintWarn(cmd, "约 %.0f 次请求所属的 route 仅有正则路径或未命名，无法按路由模拟，未计入估算", unresolved)
+    }
+    return samples, source, nil
+}
+
+// annotateTrafficImpact 用流量样本分别在当前路由表与计划后路由表上模拟匹配，
+// 估算每条计划路由将接管/失去的流量占比，并输出总体变化比例。
+func annotateTrafficImpact(cmd *cobra.Command, plan *aplan.Plan, remote, planned []kong.Route, samples []sampledRequest, source string) {
+    total := 0.0
+    for _, req := range samples { total += req.Weight }
+    if total <= 0 {
+        return
+    }
+    before := make([]aplan.RouteRule, 0, len(remote))
+    for _, r := range remote { before = append(before, toRouteRule(r)) }
+    curRouter := aplan.NewRouter(before)
+    newRouter := aplan.NewRouter(mergedRouteRules(remote, planned))
+
+    gained := map[string]float64{}
+    lost := map[string]float64{}
+    changed := 0.0
+    for _, req := range samples {
+        a := curRouter.Match(req.Host, req.Method, req.Path, req.Headers)
+        b := newRouter.Match(req.Host, req.Method, req.Path, req.Headers)
+        if a == b { continue }
+        changed += req.Weight
+        if b != "" { gained[b] += req.Weight }
+        if a != "" { lost[a] += req.Weight }
+    }
+    pct := func(n float64) float64 { return n * 100 / total }
+    for i := range plan.Items {
+        it := &plan.Items[i]
+        if it.Kind != "Route" { continue }
+        if n := gained[it.Name]; n > 0 {
+            it.Notes = append(it.Notes, fmt.Sprintf("流量影响：%s中 %.0f/%.0f 次（%.1f%%）请求将改由此路由处理", source, n, total, pct(n)))
+        }
+        if n := lost[it.Name]; n > 0 {
+            it.Notes = append(it.Notes, fmt.Sprintf("流量影响：%s中 %.0f/%.0f 次（%.1f%%）请求将不再由此路由处理", source, n, total, pct(n)))
+        }
+    }
+    if changed == 0 {
+        PrintInfo(cmd, "流量影响估算（%s）：共 %.0f 次请求，目标路由均不变", source, total)
+        return
+    }
+    PrintWarn(cmd, "流量影响估算（%s）：共 %.0f 次请求，其中 %.0f 次（%.1f%%）的目标路由将发生变化", source, total, changed, pct(changed))
+    names := make([]string, 0, len(lost))
+    for name := range lost { names = append(names, name) }
+    sort.Strings(names)
+    for _, name := range names {
+        if !planHasRoute(plan, name) {
+            PrintWarn(cmd, "  已有路由 %s 将失去 %.0f 次（%.1f%%）请求", name, lost[name], pct(lost[name]))
+        }
+    }
+}
+
+func planHasRoute(plan *aplan.Plan, name string) bool {
+    for _, it := range plan.Items {
+        if it.Kind == "Route" && it.Name == name { return true }
+    }
+    return false
+}
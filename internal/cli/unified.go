@@ -0,0 +1,262 @@
+package cli
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyDiffFormat 为 --diff 的展示格式：text（逐字段）或 unified（资源 YAML 的统一 diff）
+var applyDiffFormat string
+
+// applyUnifiedDiffs 为本次 dry-run 生成的统一 diff（键为 "Kind/Name"），由 printHierPlan 展示
+var applyUnifiedDiffs map[string]string
+
+// unifiedContext 为统一 diff 每个变更块前后保留的上下文行数
+const unifiedContext = 3
+
+// unifiedVolatileFields 为远程资源中与配置无关、不参与展示的字段
+var unifiedVolatileFields = []string{"id", "created_at", "updated_at"}
+
+func checkDiffFormat() error {
+    switch applyDiffFormat {
+    case "", "text", "unified":
+        return nil
+    }
+    return withCode("usage", "", fmt.Errorf("无效的 --diff-format：%s（可选：text、unified）", applyDiffFormat))
+}
+
+// remoteObject 读取计划项对应的远程资源（预取后命中缓存），转为去掉 id/时间戳的字段映射；
+// 不支持的类型或资源不存在时返回 false
+func remoteObject(ctx context.Context, client *kong.Client, ch aplan.Change) (map[string]any, bool) {
+    var (
+        obj any
+        ok  bool
+        err error
+    )
+    switch ch.Kind {
+    case "Service":
+        obj, ok, err = client.GetService(ctx, ch.Name)
+    case "Route":
+        obj, ok, err = client.GetRoute(ctx, ch.Name)
+    case "Upstream":
+        obj, ok, err = client.GetUpstream(ctx, ch.Name)
+    case "Consumer":
+        obj, ok, err = client.GetConsumer(ctx, ch.Name)
+    default:
+        return nil, false
+    }
+    if err != nil || !ok { return nil, false }
+    m, err := toFieldMap(obj)
+    if err != nil { return nil, false }
+    for _, f := range unifiedVolatileFields { delete(m, f) }
+    return m, true
+}
+
+func toFieldMap(v any) (map[string]any, error) {
+    b, err := json.Marshal(v)
+    if err != nil { return nil, err }
+    var m map[string]any
+    err = json.Unmarshal(b, &m)
+    return m, err
+}
+
+// scalarValue 将差异文本中的取值解析为 YAML 值（数字、布尔、flow 风格的列表/映射保持类型）
+func scalarValue(s string) any {
+    var v any
+    if err := yaml.Unmarshal([]byte(s), &v); err != nil { return s }
+    if m, ok := v.(map[string]any); ok {
+        // {a=b} 等非 YAML 映射的文本按字符串处理
+        for _, e := range m {
+            if e == nil { return s }
+        }
+    }
+    return v
+}
+
+// pathSegments 将 a.b[0].c 形式的路径拆为映射键（string）与列表下标（int）
+func pathSegments(path string) []any {
+    var segs []any
+    for _, part := range strings.Split(path, ".") {
+        key, rest, _ := strings.Cut(part, "[")
+        if key != "" { segs = append(segs, key) }
+        for rest != "" {
+            n, tail, _ := strings.Cut(rest, "]")
+            idx, err := strconv.Atoi(n)
+            if err != nil { return append(segs, part) }
+            segs = append(segs, idx)
+            rest = strings.TrimPrefix(tail, "[")
+        }
+    }
+    return segs
+}
+
+// lookupPath/setPath 按 a.b[0].c 形式的路径读写嵌套的映射与列表；setPath 的取值为 nil 时删除该键
+func lookupPath(m map[string]any, path string) (any, bool) {
+    var cur any = m
+    for _, seg := range pathSegments(path) {
+        switch k := seg.(type) {
+        case string:
+            mm, ok := cur.(map[string]any)
+            if !ok { return nil, false }
+            if cur, ok = mm[k]; !ok { return nil, false }
+        case int:
+            l, ok := cur.([]any)
+            if !ok || k >= len(l) { return nil, false }
+            cur = l[k]
+        }
+    }
+    return cur, true
+}
+
+func setPath(m map[string]any, path string, v any) {
+    segs := pathSegments(path)
+    var cur any = m
+    for i, seg := range segs {
+        last := i == len(segs)-1
+        switch k := seg.(type) {
+        case string:
+            mm, ok := cur.(map[string]any)
+            if !ok { return }
+            if last {
+                if v == nil { delete(mm, k) } else { mm[k] = v }
+                return
+            }
+            if _, ok := mm[k]; !ok {
+                if _, idx := segs[i+1].(int); idx { return }
+                mm[k] = map[string]any{}
+            }
+            cur = mm[k]
+        case int:
+            l, ok := cur.([]any)
+            if !ok || k >= len(l) { return }
+            if last {
+                l[k] = v
+                return
+            }
+            cur = l[k]
+        }
+    }
+}
+
+// desiredObject 在远程资源上应用计划中的字段差异，得到执行前后的资源；
+// cur 中缺少的字段（如 url、annotations）在执行前一侧补上旧值
+func desiredObject(cur map[string]any, fields []aplan.FieldDiff) (before, after map[string]any) {
+    before, _ = toFieldMap(cur)
+    after, _ = toFieldMap(cur)
+    for _, f := range fields {
+        if f.Old != nil {
+            // 关联字段（service 等）为 {id, name}，差异按名称给出
+            if ref, ok := before[f.Field].(map[string]any); ok {
+                if _, named := ref["name"]; named {
+                    setPath(after, f.Field+".name", scalarValue(*f.New))
+                    continue
+                }
+            }
+            if _, ok := lookupPath(before, f.Field); !ok { setPath(before, f.Field, scalarValue(*f.Old)) }
+            setPath(after, f.Field, scalarValue(*f.New))
+            continue
+        }
+        v, _ := lookupPath(after, f.Field)
+        list, _ := v.([]any)
+        out := []any{}
+        for _, x := range list {
+            if !sliceContains(f.Removed, fmt.Sprint(x)) { out = append(out, x) }
+        }
+        for _, a := range f.Added { out = append(out, a) }
+        setPath(after, f.Field, out)
+    }
+    return before, after
+}
+
+// unifiedPlanDiffs 为计划中的更新项生成资源 YAML 的统一 diff，键为 "Kind/Name"；无法读取远程资源的项沿用逐字段差异
+func unifiedPlanDiffs(ctx context.Context, client *kong.Client, plan aplan.Plan) map[string]string {
+    out := map[string]string{}
+    for _, it := range plan.Items {
+        if it.Action != "update" || it.Diff == "" { continue }
+        cur, ok := remoteObject(ctx, client, it)
+        if !ok { continue }
+        before, after := desiredObject(cur, it.Fields())
+        a, err := resourceYAML(before)
+        if err != nil { continue }
+        b, err := resourceYAML(after)
+        if err != nil { continue }
+        key := it.Kind + "/" + it.Name
+        if d := unifiedDiff(string(a), string(b), "远程 "+key, "期望 "+key); d != "" { out[key] = d }
+    }
+    return out
+}
+
+func resourceYAML(m map[string]any) (string, error) {
+    var buf bytes.Buffer
+    enc := yaml.NewEncoder(&buf)
+    enc.SetIndent(2)
+    if err := enc.Encode(m); err != nil {
+        return "", err
+    }
+    enc.Close()
+    return buf.String(), nil
+}
+
+// unifiedDiff 按行比较两段文本，输出带上下文的统一 diff（--- / +++ / @@ 块）；无差异时返回空
+func unifiedDiff(a, b, fromName, toName string) string {
+    x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+    y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+    // lcs[i][j] 为 x[i:] 与 y[j:] 的最长公共子序列长度
+    lcs := make([][]int, len(x)+1)
+    for i := range lcs { lcs[i] = make([]int, len(y)+1) }
+    for i := len(x) - 1; i >= 0; i-- {
+        for j := len(y) - 1; j >= 0; j-- {
+            if x[i] == y[j] {
+                lcs[i][j] = lcs[i+1][j+1] + 1
+            } else {
+                lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+            }
+        }
+    }
+    type op struct {
+        kind byte // ' '、'-'、'+'
+        text string
+        ai, bi int // 该行在 a/b 中的行号（从 0 开始）
+    }
+    var ops []op
+    i, j := 0, 0
+    for i < len(x) || j < len(y) {
+        switch {
+        case i < len(x) && j < len(y) && x[i] == y[j]:
+            ops = append(ops, op{' ', x[i], i, j}); i++; j++
+        case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+            ops = append(ops, op{'-', x[i], i, j}); i++
+        default:
+            ops = append(ops, op{'+', y[j], i, j}); j++
+        }
+    }
+    var sb strings.Builder
+    for k := 0; k < len(ops); {
+        if ops[k].kind == ' ' { k++; continue }
+        // 变更块：向前取上下文，向后合并间隔不超过 2*unifiedContext 的变更
+        start := max(k-unifiedContext, 0)
+        end := k
+        for n := k; n < len(ops); n++ {
+            if ops[n].kind != ' ' { end = n } else if n-end > 2*unifiedContext { break }
+        }
+        end = min(end+unifiedContext, len(ops)-1)
+        if sb.Len() == 0 { fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName) }
+        var na, nb int
+        for _, o := range ops[start : end+1] {
+            if o.kind != '+' { na++ }
+            if o.kind != '-' { nb++ }
+        }
+        fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", ops[start].ai+1, na, ops[start].bi+1, nb)
+        for _, o := range ops[start : end+1] { fmt.Fprintf(&sb, "%c%s\n", o.kind, o.text) }
+        k = end + 1
+    }
+    return sb.String()
+}
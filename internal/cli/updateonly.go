@@ -0,0 +1,51 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/spf13/cobra"
+    aplan "kongctl/internal/apply"
+    "kongctl/internal/kong"
+)
+
+// applyUpdateOnly 为 apply --update-only：只更新远程已存在的资源，spec 需要新建资源时拒绝执行
+var applyUpdateOnly bool
+
+// plannedCreates 返回计划中需要新建的资源；权重变化的 target 在计划中同样记为 create，远程已存在的不计入
+func plannedCreates(ctx context.Context, client *kong.Client, plan aplan.Plan) []aplan.Change {
+    var out []aplan.Change
+    targets := map[string][]kong.Target{}
+    for _, it := range plan.Items {
+        if it.Action != "create" { continue }
+        if it.Kind == "Target" {
+            up, target, _ := strings.Cut(it.Name, "/")
+            list, ok := targets[up]
+            if !ok {
+                // 读取失败（如 upstream 不存在）时按需新建处理
+                list, _ = client.ListTargets(ctx, up)
+                targets[up] = list
+            }
+            exists := false
+            for _, t := range list {
+                if t.Target == target { exists = true; break }
+            }
+            if exists { continue }
+        }
+        out = append(out, it)
+    }
+    return out
+}
+
+// checkUpdateOnly 在 --update-only 下检查计划：存在需新建的资源时列出并返回错误（不做任何变更）
+func checkUpdateOnly(cmd *cobra.Command, ctx context.Context, client *kong.Client, plan aplan.Plan) error {
+    creates := plannedCreates(ctx, client, plan)
+    if len(creates) == 0 {
+        return nil
+    }
+    for _, it := range creates {
+        cmd.Printf("  %s %s %s\n", colorError(glyph("✘", "[CREATE]")), it.Kind, it.Name)
+    }
+    return fmt.Errorf("--update-only：%d 项资源在远程不存在，拒绝创建（未做任何变更；可用 --select 排除这些资源后重试）", len(creates))
+}
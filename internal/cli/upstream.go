@@ -3,15 +3,22 @@ package cli
 import (
     "context"
     "fmt"
+    "sort"
+    "strconv"
+    "strings"
     "time"
 
     "github.com/spf13/cobra"
-    "github.com/spf13/viper"
     "kongctl/internal/kong"
 )
 
 var (
-    upstreamName string
+    upstreamName       string
+    upstreamClientCert string
+    upstreamHostHeader string
+    upstreamHC         applyActiveHealthcheck
+    upstreamHCVerify   bool
+    upstreamCascade    bool
 )
 
 var upstreamCmd = &cobra.Command{
@@ -19,35 +26,274 @@ var upstreamCmd = &cobra.Command{
     Short: "管理 Upstream（负载均衡上游）",
 }
 
+// upstreamChanges 比较 spec 中声明的 upstream 选项（client_certificate/host_header/healthchecks）与远程值，
+// 返回 PATCH 载荷与差异描述；未声明的字段不比较。cur 为 nil（新建）时返回全部声明的字段
+func upstreamChanges(up applyUpstream, cur *kong.Upstream) (map[string]any, string) {
+    var remote kong.Upstream
+    if cur != nil { remote = *cur }
+    payload := map[string]any{}
+    diff := ""
+    if up.ClientCertificate != "" {
+        old := ""
+        if remote.ClientCertificate != nil { old = remote.ClientCertificate.ID }
+        if old != up.ClientCertificate {
+            payload["client_certificate"] = map[string]any{"id": up.ClientCertificate}
+            diff += fmt.Sprintf("client_certificate: %s -> %s\n", old, up.ClientCertificate)
+        }
+    }
+    if up.HostHeader != "" && remote.HostHeader != up.HostHeader {
+        payload["host_header"] = up.HostHeader
+        diff += fmt.Sprintf("host_header: %s -> %s\n", remote.HostHeader, up.HostHeader)
+    }
+    if up.Healthchecks != nil && up.Healthchecks.Active != nil {
+        a := up.Healthchecks.Active
+        var ra kong.ActiveHealthcheck
+        if remote.Healthchecks != nil && remote.Healthchecks.Active != nil { ra = *remote.Healthchecks.Active }
+        active := map[string]any{}
+        if a.Type != "" && a.Type != ra.Type {
+            active["type"] = a.Type
+            diff += fmt.Sprintf("healthchecks.active.type: %s -> %s\n", ra.Type, a.Type)
+        }
+        if a.HTTPPath != "" && a.HTTPPath != ra.HTTPPath {
+            active["http_path"] = a.HTTPPath
+            diff += fmt.Sprintf("healthchecks.active.http_path: %s -> %s\n", ra.HTTPPath, a.HTTPPath)
+        }
+        if a.HTTPSVerifyCertificate != nil && (ra.HTTPSVerifyCertificate == nil || *ra.HTTPSVerifyCertificate != *a.HTTPSVerifyCertificate) {
+            active["https_verify_certificate"] = *a.HTTPSVerifyCertificate
+            old := "-"
+            if ra.HTTPSVerifyCertificate != nil { old = fmt.Sprint(*ra.HTTPSVerifyCertificate) }
+            diff += fmt.Sprintf("healthchecks.active.https_verify_certificate: %s -> %t\n", old, *a.HTTPSVerifyCertificate)
+        }
+        oldSNI := ""
+        if ra.HTTPSSni != nil { oldSNI = *ra.HTTPSSni }
+        if a.HTTPSSni != "" && a.HTTPSSni != oldSNI {
+            active["https_sni"] = a.HTTPSSni
+            diff += fmt.Sprintf("healthchecks.active.https_sni: %s -> %s\n", oldSNI, a.HTTPSSni)
+        }
+        if len(active) > 0 { payload["healthchecks"] = map[string]any{"active": active} }
+    }
+    return payload, diff
+}
+
+// exportUpstreamOptions 将远程 upstream 转为 spec，只保留与 Kong 默认值不同的 TLS/健康检查选项
+func exportUpstreamOptions(up kong.Upstream) applyUpstream {
+    out := applyUpstream{Name: up.Name, HostHeader: up.HostHeader}
+    if up.ClientCertificate != nil { out.ClientCertificate = up.ClientCertificate.ID }
+    if up.Healthchecks != nil && up.Healthchecks.Active != nil {
+        ra := up.Healthchecks.Active
+        var a applyActiveHealthcheck
+        if ra.Type != "" && ra.Type != "http" { a.Type = ra.Type }
+        if ra.HTTPPath != "" && ra.HTTPPath != "/" { a.HTTPPath = ra.HTTPPath }
+        if ra.HTTPSVerifyCertificate != nil && !*ra.HTTPSVerifyCertificate { a.HTTPSVerifyCertificate = ra.HTTPSVerifyCertificate }
+        if ra.HTTPSSni != nil { a.HTTPSSni = *ra.HTTPSSni }
+        if a != (applyActiveHealthcheck{}) { out.Healthchecks = &applyHealthchecks{Active: &a} }
+    }
+    return out
+}
+
 var upstreamSyncCmd = &cobra.Command{
     Use:   "sync",
     Short: "创建或更新 Upstream（幂等）",
     Example: `# 创建或确保存在一个名为 user-service-upstream 的上游
-kongctl upstream sync --name user-service-upstream`,
+kongctl upstream sync --name user-service-upstream
+
+# 后端要求 mTLS：指定客户端证书，并以 HTTPS 探测健康状态
+kongctl upstream sync --name user-service-upstream --client-certificate <证书ID> \
+  --healthcheck-type https --https-sni user.internal --host-header user.internal`,
     RunE: func(cmd *cobra.Command, args []string) error {
         if upstreamName == "" { return fmt.Errorf("必须提供 --name") }
-        cfg := kong.Config{
-            AdminURL:      viper.GetString("admin_url"),
-            Token:         viper.GetString("token"),
-            TLSSkipVerify: viper.GetBool("tls_skip_verify"),
-            Timeout:       10 * time.Second,
+        if upstreamHC.Type != "" && !sliceContains([]string{"http", "https", "tcp", "grpc", "grpcs"}, upstreamHC.Type) {
+            return withCode("usage", "", fmt.Errorf("--healthcheck-type 仅支持 http/https/tcp/grpc/grpcs"))
+        }
+        up := applyUpstream{Name: upstreamName, ClientCertificate: upstreamClientCert, HostHeader: upstreamHostHeader}
+        hc := upstreamHC
+        if cmd.Flags().Changed("https-verify-certificate") { hc.HTTPSVerifyCertificate = &upstreamHCVerify }
+        if hc != (applyActiveHealthcheck{}) { up.Healthchecks = &applyHealthchecks{Active: &hc} }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
         }
-        if cfg.AdminURL == "" { return fmt.Errorf("请通过 --admin-url 或 KONGCTL_ADMIN_URL 指定 Admin API 地址；或运行 'kongctl init --admin-url <url>' 持久化配置") }
         client := kong.NewClient(cfg)
         ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
         defer cancel()
+        cur, found, err := client.GetUpstream(ctx, upstreamName)
+        if err != nil { return err }
         action, _, err := client.CreateOrUpdateUpstream(ctx, upstreamName)
         if err != nil { return err }
+        if !found { cur = nil }
+        payload, diff := upstreamChanges(up, cur)
+        if len(payload) > 0 {
+            if _, err := client.PatchUpstream(ctx, upstreamName, payload); err != nil { return err }
+        }
         if action == "create" {
             PrintSuccess(cmd, "已创建 Upstream：%s", upstreamName)
         } else {
             PrintSuccess(cmd, "已更新 Upstream：%s", upstreamName)
         }
+        if diff != "" && found { cmd.Print(diff) }
+        return nil
+    },
+}
+
+// upstreamServices 列出 host 指向该 upstream 的 Service
+func upstreamServices(ctx context.Context, client *kong.Client, up string) ([]kong.Service, error) {
+    svcs, err := client.ListServices(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("列出 services 失败：%w", err)
+    }
+    var out []kong.Service
+    for _, s := range svcs {
+        if s.Host == up { out = append(out, s) }
+    }
+    return out, nil
+}
+
+var upstreamListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Upstream（名称、负载均衡算法、targets 数量、标签与创建时长）",
+    Example: `kongctl upstream list
+kongctl upstream list --filter name=user-* -o yaml`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        match, err := listFilterMatch()
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        all, err := client.ListUpstreams(ctx)
+        if err != nil {
+            return err
+        }
+        list := []kong.Upstream{}
+        for _, up := range all {
+            if match(up.Name, up.Tags) { list = append(list, up) }
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+        if outputTemplate != "" {
+            return printTemplate(cmd, list)
+        }
+        if ok, err := printStructured(cmd, list); ok {
+            return err
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Upstream")
+            return nil
+        }
+        rows := make([][]string, 0, len(list))
+        for _, up := range list {
+            targets, err := client.ListTargets(ctx, up.Name)
+            if err != nil {
+                return fmt.Errorf("列出 upstream %s 的 targets 失败：%w", up.Name, err)
+            }
+            rows = append(rows, []string{up.Name, up.Algorithm, strconv.Itoa(len(targets)), up.HostHeader, strings.Join(up.Tags, ","), formatAge(up.CreatedAt)})
+        }
+        printTable(cmd, []string{"NAME", "ALGORITHM", "TARGETS", "HOST_HEADER", "TAGS", "AGE"}, rows)
+        return nil
+    },
+}
+
+var upstreamDeleteCmd = &cobra.Command{
+    Use:   "delete <name|id>",
+    Short: "删除 Upstream（其 targets 一并删除；仍被 Service 引用时需 --cascade）",
+    Long: `删除 Upstream 时 Kong 会一并删除其下的全部 targets。
+仍有 Service 的 host 指向该 upstream 时默认拒绝删除（否则这些 Service 会因无法解析 host 而失败）；
+--cascade 会先删除这些 Service 及其 routes。`,
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl upstream delete user-service-upstream
+kongctl upstream delete user-service-upstream --cascade --dry-run
+kongctl upstream delete user-service-upstream --yes`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        up, ok, err := client.GetUpstream(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Upstream 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        svcs, err := upstreamServices(ctx, client, up.Name)
+        if err != nil {
+            return err
+        }
+        names := make([]string, 0, len(svcs))
+        for _, s := range svcs { names = append(names, s.Name) }
+        if len(svcs) > 0 && !upstreamCascade {
+            return withCode("foreign_key_violation", "先将这些 Service 指向其他后端，或使用 --cascade 一并删除", fmt.Errorf("Upstream %s 仍被 %d 个 Service 引用（%s），未删除", up.Name, len(svcs), strings.Join(names, ", ")))
+        }
+        targets, err := client.ListTargets(ctx, up.Name)
+        if err != nil {
+            return fmt.Errorf("列出 upstream %s 的 targets 失败：%w", up.Name, err)
+        }
+        routes := make([][]kong.Route, len(svcs))
+        nroutes := 0
+        for i := range svcs {
+            if routes[i], err = serviceRoutes(ctx, client, &svcs[i]); err != nil {
+                return err
+            }
+            nroutes += len(routes[i])
+        }
+        op := fmt.Sprintf("%s（含 %d 个 target）", up.Name, len(targets))
+        if len(svcs) > 0 { op = fmt.Sprintf("%s（含 %d 个 target，及引用它的 %d 个 Service、%d 个 route）", up.Name, len(targets), len(svcs), nroutes) }
+        if dryRun {
+            for i, s := range svcs {
+                for _, r := range routeLabels(routes[i]) { PrintInfo(cmd, "[dry-run] 将删除 Route：%s", r) }
+                PrintInfo(cmd, "[dry-run] 将删除 Service：%s", s.Name)
+            }
+            PrintInfo(cmd, "[dry-run] 将删除 Upstream：%s", op)
+            return nil
+        }
+        if ok, err := confirmDelete(cmd, "upstream delete "+op); err != nil || !ok {
+            return err
+        }
+        // 等待确认的时间不计入超时：删除使用新的 context
+        ctx, cancel = context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+        for i, s := range svcs {
+            for j, r := range routes[i] {
+                if err := client.DeleteRoute(ctx, r.ID); err != nil {
+                    return fmt.Errorf("删除 Route %s 失败（Upstream 未删除）：%w", routeLabels(routes[i])[j], err)
+                }
+                PrintSuccess(cmd, "已删除 Route：%s", routeLabels(routes[i])[j])
+            }
+            if err := client.DeleteService(ctx, s.ID); err != nil {
+                return fmt.Errorf("删除 Service %s 失败（Upstream 未删除）：%w", s.Name, err)
+            }
+            PrintSuccess(cmd, "已删除 Service：%s", s.Name)
+        }
+        if err := client.DeleteUpstream(ctx, up.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Upstream：%s", op)
         return nil
     },
 }
 
 func init() {
-    upstreamCmd.AddCommand(upstreamSyncCmd)
+    upstreamCmd.AddCommand(upstreamSyncCmd, upstreamListCmd, upstreamDeleteCmd)
     upstreamSyncCmd.Flags().StringVar(&upstreamName, "name", "", "Upstream 名称，例：user-service-upstream")
+    upstreamSyncCmd.Flags().StringVar(&upstreamClientCert, "client-certificate", "", "后端要求 mTLS 时 Kong 出示的客户端证书 ID")
+    upstreamSyncCmd.Flags().StringVar(&upstreamHostHeader, "host-header", "", "代理请求与健康检查探测使用的 Host 头，例：--host-header user.internal")
+    upstreamSyncCmd.Flags().StringVar(&upstreamHC.Type, "healthcheck-type", "", "主动健康检查类型：http/https/tcp/grpc/grpcs")
+    upstreamSyncCmd.Flags().StringVar(&upstreamHC.HTTPPath, "healthcheck-path", "", "主动健康检查的探测路径，例：--healthcheck-path /health")
+    upstreamSyncCmd.Flags().BoolVar(&upstreamHCVerify, "https-verify-certificate", true, "HTTPS 探测时校验后端证书（--https-verify-certificate=false 关闭）")
+    upstreamSyncCmd.Flags().StringVar(&upstreamHC.HTTPSSni, "https-sni", "", "HTTPS 探测使用的 SNI，例：--https-sni user.internal")
+    listFilterFlag(upstreamListCmd)
+    addTemplateFlag(upstreamListCmd)
+    upstreamDeleteCmd.Flags().BoolVar(&upstreamCascade, "cascade", false, "一并删除 host 指向该 upstream 的 Service 及其 routes")
+    upstreamDeleteCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    upstreamDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "跳过删除前的 [y/N] 确认（非交互环境必需）")
 }
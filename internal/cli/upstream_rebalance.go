@@ -0,0 +1,163 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "math"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+// rebalanceTotalWeight 为按区域分配时所有 target 权重之和的基数（与 spec 中 0~1000 的权重范围一致）
+const rebalanceTotalWeight = 1000
+
+var (
+    rebalanceUpstream    string
+    rebalanceZoneWeights string
+)
+
+// parseZoneWeights 解析 eu-1=70,eu-2=30 形式的区域占比
+func parseZoneWeights(raw string) (map[string]float64, error) {
+    out := map[string]float64{}
+    sum := 0.0
+    for _, part := range strings.Split(raw, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" { continue }
+        zone, v, ok := strings.Cut(part, "=")
+        zone = strings.TrimSpace(zone)
+        if !ok || zone == "" {
+            return nil, fmt.Errorf("--zone-weights 格式错误：%q（应为 zone=占比，例：eu-1=70,eu-2=30）", part)
+        }
+        f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+        if err != nil || f < 0 {
+            return nil, fmt.Errorf("--zone-weights 占比必须为非负数：%q", part)
+        }
+        if _, dup := out[zone]; dup {
+            return nil, fmt.Errorf("--zone-weights 重复指定区域：%s", zone)
+        }
+        out[zone] = f
+        sum += f
+    }
+    if len(out) == 0 {
+        return nil, fmt.Errorf("必须通过 --zone-weights 指定区域占比，例：--zone-weights eu-1=70,eu-2=30")
+    }
+    if sum == 0 {
+        return nil, fmt.Errorf("--zone-weights 占比之和不能为 0")
+    }
+    return out, nil
+}
+
+// zoneTargetWeights 将各区域占比平均分配到区域内的 target，返回 target -> 权重；占比大于 0 的区域每个 target 至少为 1
+func zoneTargetWeights(shares map[string]float64, byZone map[string][]kong.Target) map[string]int {
+    sum := 0.0
+    for _, f := range shares { sum += f }
+    out := map[string]int{}
+    for zone, ts := range byZone {
+        share := shares[zone]
+        w := 0
+        if share > 0 {
+            w = int(math.Round(rebalanceTotalWeight * share / sum / float64(len(ts))))
+            if w < 1 { w = 1 }
+        }
+        for _, t := range ts { out[t.Target] = w }
+    }
+    return out
+}
+
+var upstreamRebalanceCmd = &cobra.Command{
+    Use:   "rebalance",
+    Short: "按区域（target 标签 zone=<区域>）重新分配 Upstream 的 target 权重",
+    Long: `根据 --zone-weights 的区域占比计算每个 target 的权重：区域占比按比例折算到总权重 1000，
+再平均分配给该区域内的 target；占比为 0 的区域权重置 0（摘流）。
+target 的区域来自标签 zone=<区域>（spec 中 targets[].zone 会自动写入该标签）；未标注区域的 target 保持不变。`,
+    Example: `# eu-1 承接 70% 流量，eu-2 承接 30%
+kongctl upstream rebalance --name user-up --zone-weights eu-1=70,eu-2=30
+
+# 预览权重变更
+kongctl upstream rebalance --name user-up --zone-weights eu-1=100,eu-2=0 --dry-run`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if rebalanceUpstream == "" { return fmt.Errorf("必须提供 --name") }
+        shares, err := parseZoneWeights(rebalanceZoneWeights)
+        if err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListTargets(ctx, rebalanceUpstream)
+        if err != nil {
+            return err
+        }
+        byZone := map[string][]kong.Target{}
+        var unzoned []string
+        for _, t := range list {
+            z := kong.TargetZone(t.Tags)
+            if z == "" { unzoned = append(unzoned, t.Target); continue }
+            if _, ok := shares[z]; !ok {
+                return fmt.Errorf("target %s 所在区域 %s 未在 --zone-weights 中指定", t.Target, z)
+            }
+            byZone[z] = append(byZone[z], t)
+        }
+        for z := range shares {
+            if len(byZone[z]) == 0 {
+                return fmt.Errorf("区域 %s 下没有 target（检查 target 标签 zone=%s）", z, z)
+            }
+        }
+        if len(unzoned) > 0 {
+            PrintWarn(cmd, "以下 target 未标注区域，权重保持不变：%s", strings.Join(unzoned, ", "))
+        }
+        weights := zoneTargetWeights(shares, byZone)
+
+        zones := make([]string, 0, len(byZone))
+        for z := range byZone { zones = append(zones, z) }
+        sort.Strings(zones)
+        changed := 0
+        for _, z := range zones {
+            cmd.Printf("%s（%g）\n", z, shares[z])
+            for _, t := range byZone[z] {
+                w := weights[t.Target]
+                mark := colorInfo("无变更")
+                if t.Weight != w { changed++; mark = colorWarn(fmt.Sprintf("%d -> %d", t.Weight, w)) }
+                cmd.Printf("  %-28s %s\n", t.Target, mark)
+            }
+        }
+        if changed == 0 {
+            PrintInfo(cmd, "权重已符合区域占比，无需调整")
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将调整 %d 个 target 的权重（Upstream %s）", changed, rebalanceUpstream)
+            return nil
+        }
+        for _, z := range zones {
+            for _, t := range byZone[z] {
+                w := weights[t.Target]
+                if t.Weight == w { continue }
+                key := t.ID
+                if key == "" { key = t.Target }
+                if _, err := client.UpdateTargetWeight(ctx, rebalanceUpstream, key, w); err != nil {
+                    return fmt.Errorf("更新 target %s 权重失败：%w", t.Target, err)
+                }
+            }
+        }
+        PrintSuccess(cmd, "已按区域调整 %d 个 target 的权重（Upstream %s）", changed, rebalanceUpstream)
+        return nil
+    },
+}
+
+func init() {
+    upstreamCmd.AddCommand(upstreamRebalanceCmd)
+    upstreamRebalanceCmd.Flags().StringVar(&rebalanceUpstream, "name", "", "Upstream 名称，例：--name user-up")
+    upstreamRebalanceCmd.Flags().StringVar(&rebalanceZoneWeights, "zone-weights", "", "区域占比，逗号分隔，例：--zone-weights eu-1=70,eu-2=30")
+    upstreamRebalanceCmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示权重变更，不实际修改")
+}
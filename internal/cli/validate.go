@@ -0,0 +1,428 @@
+package cli
+
+import (
+    _ "embed"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+)
+
+//go:embed spec.schema.json
+var specSchemaJSON []byte
+
+// jsonSchema 为 spec.schema.json 所用的 JSON Schema 子集
+type jsonSchema struct {
+    Type                 string                 `json:"type"`
+    Ref                  string                 `json:"$ref"`
+    Properties           map[string]*jsonSchema `json:"properties"`
+    PatternProperties    map[string]*jsonSchema `json:"patternProperties"`
+    AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+    Required             []string               `json:"required"`
+    Items                *jsonSchema            `json:"items"`
+    Enum                 []any                  `json:"enum"`
+    Minimum              *float64               `json:"minimum"`
+    Maximum              *float64               `json:"maximum"`
+    Defs                 map[string]*jsonSchema `json:"$defs"`
+}
+
+// schemaIssue 为一条校验问题，行列号取自 YAML/JSON 源文件
+type schemaIssue struct {
+    File    string `json:"file"`
+    Line    int    `json:"line"`
+    Column  int    `json:"column"`
+    Path    string `json:"path"`
+    Message string `json:"message"`
+}
+
+func (i schemaIssue) String() string {
+    loc := i.File
+    if i.Line > 0 { loc = fmt.Sprintf("%s:%d:%d", i.File, i.Line, i.Column) }
+    if i.Path == "" { return loc + "：" + i.Message }
+    return fmt.Sprintf("%s：%s：%s", loc, i.Path, i.Message)
+}
+
+type specValidator struct {
+    root   *jsonSchema
+    file   string
+    issues []schemaIssue
+}
+
+func loadSpecSchema() (*jsonSchema, error) {
+    var s jsonSchema
+    if err := json.Unmarshal(specSchemaJSON, &s); err != nil {
+        return nil, fmt.Errorf("内置 JSON Schema 解析失败：%w", err)
+    }
+    return &s, nil
+}
+
+func (v *specValidator) report(n *yaml.Node, path, format string, args ...any) {
+    v.issues = append(v.issues, schemaIssue{File: v.file, Line: n.Line, Column: n.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *specValidator) resolve(s *jsonSchema) *jsonSchema {
+    for s != nil && s.Ref != "" {
+        s = v.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+    }
+    return s
+}
+
+// nodeKind 返回节点的类型描述，用于类型错误提示
+func nodeKind(n *yaml.Node) string {
+    switch n.Kind {
+    case yaml.MappingNode:
+        return "对象"
+    case yaml.SequenceNode:
+        return "列表"
+    }
+    switch n.Tag {
+    case "!!int":
+        return "整数"
+    case "!!float":
+        return "数字"
+    case "!!bool":
+        return "布尔值"
+    case "!!null":
+        return "空值"
+    }
+    return "字符串"
+}
+
+var schemaTypeCN = map[string]string{"object": "对象", "array": "列表", "string": "字符串", "integer": "整数", "number": "数字", "boolean": "布尔值"}
+
+// mappingPairs 返回映射的键值对，展开 YAML 合并键（<<: *anchor）
+func mappingPairs(n *yaml.Node) [][2]*yaml.Node {
+    var out [][2]*yaml.Node
+    for i := 0; i+1 < len(n.Content); i += 2 {
+        k, val := n.Content[i], n.Content[i+1]
+        if k.Value == "<<" && k.Tag == "!!merge" {
+            srcs := []*yaml.Node{val}
+            if val.Kind == yaml.SequenceNode { srcs = val.Content }
+            for _, src := range srcs {
+                if src.Kind == yaml.AliasNode { src = src.Alias }
+                if src.Kind == yaml.MappingNode { out = append(out, mappingPairs(src)...) }
+            }
+            continue
+        }
+        out = append(out, [2]*yaml.Node{k, val})
+    }
+    return out
+}
+
+func joinPath(base, key string) string {
+    if base == "" { return key }
+    return base + "." + key
+}
+
+func (v *specValidator) check(n *yaml.Node, s *jsonSchema, path string) {
+    if n.Kind == yaml.AliasNode { n = n.Alias }
+    s = v.resolve(s)
+    if s == nil || (n.Kind == yaml.ScalarNode && n.Tag == "!!null") {
+        // 空值等同于省略该字段
+        return
+    }
+    okType := true
+    switch s.Type {
+    case "object":
+        okType = n.Kind == yaml.MappingNode
+    case "array":
+        okType = n.Kind == yaml.SequenceNode
+    case "string":
+        // 与 apply 的解析行为一致：数字/布尔等标量也可作为字符串读取
+        okType = n.Kind == yaml.ScalarNode
+    case "integer":
+        okType = n.Kind == yaml.ScalarNode && n.Tag == "!!int"
+    case "number":
+        okType = n.Kind == yaml.ScalarNode && (n.Tag == "!!int" || n.Tag == "!!float")
+    case "boolean":
+        okType = n.Kind == yaml.ScalarNode && n.Tag == "!!bool"
+    }
+    if !okType {
+        v.report(n, path, "类型错误：应为%s，实际为%s", schemaTypeCN[s.Type], nodeKind(n))
+        return
+    }
+    switch n.Kind {
+    case yaml.MappingNode:
+        v.checkObject(n, s, path)
+    case yaml.SequenceNode:
+        if s.Items != nil {
+            for i, item := range n.Content {
+                v.check(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+            }
+        }
+    case yaml.ScalarNode:
+        v.checkScalar(n, s, path)
+    }
+}
+
+func (v *specValidator) checkObject(n *yaml.Node, s *jsonSchema, path string) {
+    var extra *jsonSchema
+    closed := strings.TrimSpace(string(s.AdditionalProperties)) == "false"
+    if !closed && len(s.AdditionalProperties) > 0 && s.AdditionalProperties[0] == '{' {
+        extra = &jsonSchema{}
+        json.Unmarshal(s.AdditionalProperties, extra)
+    }
+    seen := map[string]bool{}
+    for _, kv := range mappingPairs(n) {
+        k, val := kv[0], kv[1]
+        seen[k.Value] = true
+        p := joinPath(path, k.Value)
+        if prop, ok := s.Properties[k.Value]; ok {
+            v.check(val, prop, p)
+            continue
+        }
+        matched := false
+        for pat, ps := range s.PatternProperties {
+            if ok, _ := regexp.MatchString(pat, k.Value); ok {
+                v.check(val, ps, p)
+                matched = true
+                break
+            }
+        }
+        switch {
+        case matched:
+        case extra != nil:
+            v.check(val, extra, p)
+        case closed:
+            msg := fmt.Sprintf("未知字段 %q", k.Value)
+            if hint := suggestField(k.Value, s.Properties); hint != "" { msg += fmt.Sprintf("（是否为 %s？）", hint) }
+            v.report(k, path, "%s", msg)
+        }
+    }
+    for _, r := range s.Required {
+        if !seen[r] { v.report(n, path, "缺少必填字段 %q", r) }
+    }
+}
+
+func (v *specValidator) checkScalar(n *yaml.Node, s *jsonSchema, path string) {
+    // apply 将空字符串与 0 视为未设置（沿用 Kong 默认值），export 也会输出这些零值
+    if n.Value == "" || (n.Value == "0" && n.Tag == "!!int") {
+        return
+    }
+    if len(s.Enum) > 0 {
+        found := false
+        var opts []string
+        for _, e := range s.Enum {
+            opt := fmt.Sprint(e)
+            opts = append(opts, opt)
+            if opt == n.Value { found = true }
+        }
+        if !found {
+            v.report(n, path, "取值 %q 无效（可选：%s）", n.Value, strings.Join(opts, "、"))
+            return
+        }
+    }
+    if s.Minimum != nil || s.Maximum != nil {
+        f, err := strconv.ParseFloat(n.Value, 64)
+        if err != nil { return }
+        if s.Minimum != nil && f < *s.Minimum {
+            v.report(n, path, "取值 %s 小于最小值 %v", n.Value, *s.Minimum)
+        }
+        if s.Maximum != nil && f > *s.Maximum {
+            v.report(n, path, "取值 %s 大于最大值 %v", n.Value, *s.Maximum)
+        }
+    }
+}
+
+// suggestField 为拼写错误的字段给出最相近的已知字段（编辑距离不超过 2）
+func suggestField(name string, props map[string]*jsonSchema) string {
+    best, bestD := "", 3
+    for k := range props {
+        if d := editDistance(name, k); d < bestD || (d == bestD && k < best) {
+            best, bestD = k, d
+        }
+    }
+    return best
+}
+
+func editDistance(a, b string) int {
+    prev := make([]int, len(b)+1)
+    for j := range prev { prev[j] = j }
+    for i := 1; i <= len(a); i++ {
+        cur := make([]int, len(b)+1)
+        cur[0] = i
+        for j := 1; j <= len(b); j++ {
+            cost := 1
+            if a[i-1] == b[j-1] { cost = 0 }
+            cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+        }
+        prev = cur
+    }
+    return prev[len(b)]
+}
+
+// validateSpecContent 按 spec 的三种顶层结构选择对应的 schema 校验
+func validateSpecContent(schema *jsonSchema, file string, content []byte) ([]schemaIssue, *yaml.Node, error) {
+    var doc yaml.Node
+    if err := yaml.Unmarshal(content, &doc); err != nil {
+        return nil, nil, fmt.Errorf("%s：解析失败：%w", file, err)
+    }
+    if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+        return []schemaIssue{{File: file, Message: "文件为空"}}, nil, nil
+    }
+    v := &specValidator{root: schema, file: file}
+    root := doc.Content[0]
+    route := &jsonSchema{Ref: "#/$defs/route"}
+    switch {
+    case root.Kind == yaml.SequenceNode:
+        v.check(root, &jsonSchema{Type: "array", Items: route}, "")
+    case root.Kind == yaml.MappingNode && !isTopLevelSpec(root):
+        v.check(root, route, "")
+    default:
+        v.check(root, schema, "")
+    }
+    sort.SliceStable(v.issues, func(i, j int) bool {
+        a, b := v.issues[i], v.issues[j]
+        if a.Line != b.Line { return a.Line < b.Line }
+        return a.Column < b.Column
+    })
+    return v.issues, root, nil
+}
+
+// isTopLevelSpec 判断映射是否为完整写法（含任一顶层字段或 x- 扩展字段）
+func isTopLevelSpec(n *yaml.Node) bool {
+    for _, kv := range mappingPairs(n) {
+        if specTopLevelKeys[kv[0].Value] || strings.HasPrefix(kv[0].Value, "x-") { return true }
+    }
+    return false
+}
+
+var (
+    validatePrintSchema bool
+    validateGateway     bool
+)
+
+var validateCmd = &cobra.Command{
+    Use:   "validate",
+    Short: "按内置 JSON Schema 离线校验 apply 文件（--gateway 另按网关的 schema 检查）",
+    Long: `按内置 JSON Schema 校验 apply/export 文件，报告未知字段、类型错误、缺少必填字段与非法取值，
+并给出行列号；include 引用的片段一并校验。校验通过后再按 apply 的解析规则检查一次。
+--gateway 另按当前网关版本的 schema 检查协议、超时等取值；schema 缓存在本地，离线时同样可用。
+--print-schema 输出内置 schema，可配置到编辑器（如 yaml-language-server）获得补全与实时校验。`,
+    Example: `# 校验文件
+kongctl validate -f spec.yaml
+
+# 校验模板渲染后的结果
+kongctl validate -f routes.tpl.yaml --values values/prod.yaml
+
+# 按当前网关版本的 schema 检查（离线时使用缓存）
+kongctl validate -f spec.yaml --gateway
+
+# 导出 schema 供编辑器使用
+kongctl validate --print-schema > kongctl.schema.json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if validatePrintSchema {
+            cmd.Print(string(specSchemaJSON))
+            return nil
+        }
+        if applyFile == "" {
+            return fmt.Errorf("必须通过 -f/--file 指定配置文件")
+        }
+        schema, err := loadSpecSchema()
+        if err != nil {
+            return err
+        }
+        content, err := readSpecFile(cmd, applyFile)
+        if err != nil {
+            return err
+        }
+        var issues []schemaIssue
+        visited := map[string]bool{}
+        var walk func(file string, content []byte) error
+        walk = func(file string, content []byte) error {
+            if renderEnabled() {
+                var err error
+                if content, err = renderSpecTemplate(content, file); err != nil {
+                    return fmt.Errorf("%s：%w", file, err)
+                }
+            }
+            found, root, err := validateSpecContent(schema, file, content)
+            if err != nil {
+                return err
+            }
+            issues = append(issues, found...)
+            inc := mappingValue(root, "include")
+            if inc == nil || inc.Kind != yaml.SequenceNode { return nil }
+            dir := "."
+            if file != "-" { dir = filepath.Dir(expandPath(file)) }
+            for _, item := range inc.Content {
+                p := expandPath(item.Value)
+                if !filepath.IsAbs(p) { p = filepath.Join(dir, p) }
+                matches, _ := filepath.Glob(p)
+                sort.Strings(matches)
+                for _, m := range matches {
+                    abs, _ := filepath.Abs(m)
+                    if visited[abs] { continue }
+                    visited[abs] = true
+                    b, err := os.ReadFile(m)
+                    if err != nil {
+                        return fmt.Errorf("读取 include 文件失败：%w", err)
+                    }
+                    if err := walk(m, normalizeText(b)); err != nil { return err }
+                }
+            }
+            return nil
+        }
+        if applyFile != "-" {
+            abs, _ := filepath.Abs(expandPath(applyFile))
+            visited[abs] = true
+        }
+        if err := walk(applyFile, content); err != nil {
+            return err
+        }
+        if len(issues) == 0 {
+            // schema 之外的约束（include 循环、重复定义与匹配冲突等）沿用 apply 的解析逻辑
+            if renderEnabled() {
+                if content, err = renderSpecTemplate(content, applyFile); err != nil {
+                    return err
+                }
+            }
+            spec, err := parseApplySpec(content)
+            if err == nil {
+                spec, err = resolveIncludes(spec, applyFile)
+            }
+            if err == nil {
+                err = checkSpecConflicts(spec)
+            }
+            if err != nil {
+                return err
+            }
+            if validateGateway {
+                found, err := validateAgainstGateway(cmd, spec)
+                if err != nil {
+                    return err
+                }
+                for _, msg := range found { issues = append(issues, schemaIssue{File: applyFile, Message: msg}) }
+            }
+        }
+        if outputJSON() {
+            if issues == nil { issues = []schemaIssue{} }
+            b, _ := json.MarshalIndent(map[string]any{"file": applyFile, "valid": len(issues) == 0, "issues": issues}, "", "  ")
+            cmd.Println(string(b))
+        } else {
+            for _, i := range issues {
+                cmd.Printf("%s %s\n", colorError(glyph("✘", "[ERROR]")), i)
+            }
+        }
+        if len(issues) > 0 {
+            return withCode("usage", "对照 kongctl apply example 或 kongctl validate --print-schema 修正字段", fmt.Errorf("校验未通过：%s 共 %d 个问题", applyFile, len(issues)))
+        }
+        PrintSuccess(cmd, "校验通过：%s", applyFile)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(validateCmd)
+    validateCmd.Flags().StringVarP(&applyFile, "file", "f", "", "待校验的文件路径（YAML/JSON，- 表示标准输入）")
+    validateCmd.Flags().BoolVar(&validatePrintSchema, "print-schema", false, "输出内置 JSON Schema")
+    validateCmd.Flags().BoolVar(&validateGateway, "gateway", false, "同时按当前网关的 /schemas 检查字段取值（按版本缓存，网关不可达时使用已缓存的 schema）")
+    addRenderFlags(validateCmd)
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "kongctl/internal/kong"
+)
+
+var (
+    vaultPrefix      string
+    vaultBackend     string
+    vaultConfig      string
+    vaultDescription string
+    vaultTags        []string
+)
+
+// vaultBackends 为 Kong 3.x 内置的 vault 后端；env 随 OSS 提供，hcv/aws/gcp 需 Kong Enterprise
+var vaultBackends = []string{"env", "hcv", "aws", "gcp"}
+
+var vaultCmd = &cobra.Command{
+    Use:   "vault",
+    Short: "管理 Vault（secret 后端，Kong 3.x）",
+    Long: `Vault 以 prefix 唯一标识，插件配置、凭证等字段可写作 {vault://<prefix>/<key>} 引用其中的 secret，
+由 Kong 在运行时解析。后端（--backend）为 env、hcv（HashiCorp Vault）、aws（Secrets Manager）或 gcp（Secret Manager），
+--config 为后端配置，例如 env 的 {"prefix": "MY_APP_"}、hcv 的 {"host": "vault.internal", "mount": "secret", "kv": "v2"}。
+声明式管理请在 apply spec 中使用 vaults。`,
+}
+
+// checkVault 校验 prefix 与后端：prefix 不能与后端名称相同（Kong 会拒绝），未知后端仅提示
+func checkVault(cmd *cobra.Command, prefix, backend string) error {
+    if prefix == "" {
+        return fmt.Errorf("vault 缺少 prefix")
+    }
+    if sliceContains(vaultBackends, prefix) {
+        return fmt.Errorf("vault prefix 不能与后端名称相同：%s（可用如 %s-secrets）", prefix, prefix)
+    }
+    if backend != "" && !sliceContains(vaultBackends, backend) {
+        PrintWarn(cmd, "未知的 vault 后端：%s（内置：%s），交由 Kong 校验", backend, strings.Join(vaultBackends, "、"))
+    }
+    return nil
+}
+
+// vaultDiff 比较 vault 当前配置与期望配置；config 只比较期望中给出的字段，空的 name/description/tags 不比较
+func vaultDiff(cur *kong.Vault, want kong.Vault) string {
+    diff := ""
+    if want.Name != "" && cur.Name != want.Name { diff += fmt.Sprintf("name: %s -> %s\n", cur.Name, want.Name) }
+    if want.Description != "" && cur.Description != want.Description { diff += fmt.Sprintf("description: %q -> %q\n", cur.Description, want.Description) }
+    diff += pluginConfigDiff("config", cur.Config, want.Config)
+    if len(want.Tags) > 0 && !sliceSetEqual(cur.Tags, want.Tags) { diff += diffSlice("tags", cur.Tags, want.Tags) }
+    return diff
+}
+
+var vaultSyncCmd = &cobra.Command{
+    Use:   "sync",
+    Short: "按 prefix 创建或更新 Vault（幂等）",
+    Example: `# 从环境变量 MY_APP_<KEY> 读取 secret，引用写作 {vault://app-env/db-password}
+kongctl vault sync --prefix app-env --backend env --config '{"prefix": "MY_APP_"}'
+
+# HashiCorp Vault（Kong Enterprise）
+kongctl vault sync --prefix hcv-prod --backend hcv --config @hcv.yaml --description "生产 KV" --dry-run`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if err := checkVault(cmd, vaultPrefix, vaultBackend); err != nil {
+            return err
+        }
+        var conf map[string]any
+        if vaultConfig != "" {
+            var err error
+            if conf, err = parsePluginConfig(cmd, vaultConfig); err != nil {
+                return err
+            }
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        want := kong.Vault{Name: vaultBackend, Prefix: vaultPrefix, Description: vaultDescription, Config: conf, Tags: vaultTags}
+        cur, exists, err := client.GetVault(ctx, vaultPrefix)
+        if err != nil {
+            return err
+        }
+        if !exists && vaultBackend == "" {
+            return fmt.Errorf("新建 Vault 必须提供 --backend（%s）", strings.Join(vaultBackends, "、"))
+        }
+        diff := ""
+        if exists {
+            if diff = vaultDiff(cur, want); diff == "" {
+                PrintInfo(cmd, "Vault 无变更，未写入：%s", vaultPrefix)
+                return nil
+            }
+        }
+        if showDiff || dryRun {
+            PrintInfo(cmd, "%sDiff: Vault %s", emojiDiff, vaultPrefix)
+            if !exists {
+                cmd.Printf("%s\n", colorInfo("+ name: "+vaultBackend))
+                diff = diffNested("config", nil, conf)
+            }
+            for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+                if l != "" { cmd.Printf("  %s\n", l) }
+            }
+        }
+        if dryRun {
+            action := "create"
+            if exists { action = "update" }
+            PrintInfo(cmd, "[dry-run] 将%s Vault：%s", actionCN(action), vaultPrefix)
+            return nil
+        }
+        action, _, err := client.CreateOrUpdateVault(ctx, want)
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s Vault：%s（引用写作 {vault://%s/<key>}）", actionCN(action), vaultPrefix, vaultPrefix)
+        return nil
+    },
+}
+
+var vaultListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Vault",
+    Example: `kongctl vault list
+kongctl vault list --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListVaults(ctx)
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Prefix < list[j].Prefix })
+        if outputJSON() {
+            if list == nil { list = []kong.Vault{} }
+            b, _ := json.MarshalIndent(list, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Vault")
+            return nil
+        }
+        cmd.Printf("%-20s %-8s %-32s %s\n", "PREFIX", "BACKEND", "REFERENCE", "DESCRIPTION")
+        for _, v := range list {
+            cmd.Printf("%-20s %-8s %-32s %s\n", v.Prefix, v.Name, "{vault://"+v.Prefix+"/<key>}", v.Description)
+        }
+        return nil
+    },
+}
+
+var vaultGetCmd = &cobra.Command{
+    Use:   "get <prefix|id>",
+    Short: "查看 Vault 配置（YAML，--output json 时为 JSON）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl vault get app-env`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        v, ok, err := client.GetVault(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return withCode("not_found", "使用 kongctl vault list 查看已有 Vault", fmt.Errorf("Vault 不存在：%s", args[0]))
+        }
+        if outputJSON() {
+            b, _ := json.MarshalIndent(v, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        var obj map[string]any
+        b, _ := json.Marshal(v)
+        _ = json.Unmarshal(b, &obj)
+        out, err := yaml.Marshal(obj)
+        if err != nil {
+            return err
+        }
+        cmd.Print(string(out))
+        return nil
+    },
+}
+
+var vaultDeleteCmd = &cobra.Command{
+    Use:   "delete <prefix|id>",
+    Short: "删除 Vault（引用它的 {vault://...} 将无法解析）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl vault delete app-env`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        v, ok, err := client.GetVault(ctx, args[0])
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Vault 不存在，无需删除：%s", args[0])
+            return nil
+        }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Vault：%s", v.Prefix)
+            return nil
+        }
+        if err := confirmDestructive(cmd, fmt.Sprintf("vault delete %s（引用 {vault://%s/...} 的配置将无法解析）", v.Prefix, v.Prefix)); err != nil {
+            return err
+        }
+        if err := client.DeleteVault(ctx, v.ID); err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Vault：%s", v.Prefix)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(vaultCmd)
+    vaultCmd.AddCommand(vaultSyncCmd, vaultListCmd, vaultGetCmd, vaultDeleteCmd)
+    vaultSyncCmd.Flags().StringVar(&vaultPrefix, "prefix", "", "Vault 的唯一前缀（{vault://<prefix>/<key>} 中的 prefix），例：--prefix app-env")
+    vaultSyncCmd.Flags().StringVar(&vaultBackend, "backend", "", "后端类型：env、hcv、aws、gcp（新建时必填）")
+    vaultSyncCmd.Flags().StringVar(&vaultConfig, "config", "", "后端配置：内联 JSON/YAML 或 @文件（只比较并下发给出的字段）")
+    vaultSyncCmd.Flags().StringVar(&vaultDescription, "description", "", "描述")
+    vaultSyncCmd.Flags().StringSliceVar(&vaultTags, "tags", nil, "Vault 标签（覆盖现有标签）")
+    vaultSyncCmd.Flags().BoolVar(&showDiff, "diff", false, "显示差异，例：--diff")
+    for _, c := range []*cobra.Command{vaultSyncCmd, vaultDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+    "context"
+    "crypto/tls"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+)
+
+var (
+    applyVerify        bool
+    applyVerifyProxy   string
+    applyVerifyTimeout time.Duration
+)
+
+// routeTest 为 route 上声明的冒烟测试：经 Kong 代理发送请求并检查状态码
+type routeTest struct {
+    Method       string            `yaml:"method,omitempty" json:"method,omitempty"`   // 默认 GET
+    Path         string            `yaml:"path,omitempty" json:"path,omitempty"`       // 默认取 route 第一个非正则 path
+    Host         string            `yaml:"host,omitempty" json:"host,omitempty"`       // 默认取 route 第一个 host（通配符替换为 kongctl-test）
+    Headers      map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+    ExpectStatus int               `yaml:"expect_status,omitempty" json:"expect_status,omitempty"` // 默认 200
+}
+
+// request 按 route 的匹配条件补全测试请求
+func (t routeTest) request(r applyRoute) (method, path, host string, want int, err error) {
+    method, path, host, want = strings.ToUpper(t.Method), t.Path, t.Host, t.ExpectStatus
+    if method == "" { method = http.MethodGet }
+    if want == 0 { want = http.StatusOK }
+    if path == "" {
+        for _, p := range r.Paths {
+            if !strings.HasPrefix(p, "~") { path = p; break }
+        }
+        if path == "" && len(r.Paths) > 0 {
+            return "", "", "", 0, fmt.Errorf("route %s 仅包含正则路径，tests 需指定 path", specRouteName(r))
+        }
+        if path == "" { path = "/" }
+    }
+    if host == "" && len(r.Hosts) > 0 { host = strings.Replace(r.Hosts[0], "*", "kongctl-test", 1) }
+    return method, path, host, want, nil
+}
+
+// verifyRouteTests 为 apply --verify：依次经代理发送各 route 的 tests，状态码不符时每秒重试，
+// 直到符合或超过 --verify-timeout（数据面同步新配置需要时间）；任一测试未通过时返回错误
+func verifyRouteTests(cmd *cobra.Command, spec applySpec) error {
+    // 变更已全部写入，检查点不再需要
+    applyCheckpoint.remove()
+    applyCheckpoint = nil
+    proxyURL := applyVerifyProxy
+    if proxyURL == "" { proxyURL = viper.GetString("proxy_url") }
+    if proxyURL == "" { proxyURL = "http://localhost:8000" }
+    if u, err := url.Parse(proxyURL); err != nil || u.Host == "" {
+        return withCode("usage", "", fmt.Errorf("--proxy-url 无效：%s", proxyURL))
+    }
+    total := 0
+    for _, r := range spec.Routes { total += len(r.Tests) }
+    if total == 0 {
+        PrintInfo(cmd, "--verify：spec 中的 routes 未声明 tests，跳过验证")
+        return nil
+    }
+    proxy := &http.Client{
+        Timeout:       10 * time.Second,
+        Transport:     &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: viper.GetBool("tls_skip_verify")}}, //nolint:gosec
+        CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+    }
+    ctx, cancel := context.WithTimeout(cmd.Context(), applyVerifyTimeout)
+    defer cancel()
+    PrintInfo(cmd, "--verify：经 %s 发送 %d 个冒烟测试", proxyURL, total)
+    failed := 0
+    for _, r := range spec.Routes {
+        name := specRouteName(r)
+        for _, t := range r.Tests {
+            method, path, host, want, err := t.request(r)
+            if err != nil {
+                return withCode("usage", "", err)
+            }
+            label := method + " " + path
+            if host != "" { label += "（Host: " + host + "）" }
+            got, err := sendRouteTest(ctx, proxy, strings.TrimRight(proxyURL, "/")+path, method, host, t.Headers, want)
+            switch {
+            case err != nil:
+                failed++
+                cmd.Printf("  %s %s [route %s]：%v\n", colorError(glyph("✗", "x")), label, name, err)
+            case got != want:
+                failed++
+                cmd.Printf("  %s %s [route %s]：期望 %d，实际 %d\n", colorError(glyph("✗", "x")), label, name, want, got)
+            default:
+                cmd.Printf("  %s %s [route %s] -> %d\n", colorSuccess(glyph("✓", "+")), label, name, got)
+            }
+        }
+    }
+    if failed > 0 {
+        return fmt.Errorf("%d/%d 个冒烟测试未通过（变更已生效，可用 kongctl rollback 恢复）", failed, total)
+    }
+    PrintSuccess(cmd, "冒烟测试全部通过（%d 个）", total)
+    return nil
+}
+
+// sendRouteTest 发送测试请求，状态码不是 want 时每秒重试直到 ctx 结束，返回最后一次的状态码
+func sendRouteTest(ctx context.Context, proxy *http.Client, target, method, host string, headers map[string]string, want int) (int, error) {
+    var status int
+    var lastErr error
+    for {
+        req, err := http.NewRequestWithContext(ctx, method, target, nil)
+        if err != nil {
+            return 0, err
+        }
+        if host != "" { req.Host = host }
+        for k, v := range headers { req.Header.Set(k, v) }
+        req.Header.Set("User-Agent", userAgent())
+        if resp, err := proxy.Do(req); err == nil {
+            _, _ = io.Copy(io.Discard, resp.Body)
+            resp.Body.Close()
+            status, lastErr = resp.StatusCode, nil
+            if status == want { return status, nil }
+        } else if ctx.Err() == nil {
+            lastErr = err
+        }
+        select {
+        case <-ctx.Done():
+            if status == 0 && lastErr != nil { return 0, fmt.Errorf("请求代理失败：%w", lastErr) }
+            if status == 0 { return 0, fmt.Errorf("在 --verify-timeout 内未收到响应") }
+            return status, nil
+        case <-time.After(time.Second):
+        }
+    }
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "kongctl/internal/kong"
+)
+
+var (
+    applyWait        bool
+    applyWaitTimeout time.Duration
+)
+
+// waitHealthInterval 为 apply --wait 轮询 /upstreams/{name}/health 的间隔
+const waitHealthInterval = 2 * time.Second
+
+// pendingTargets 返回尚未就绪的 target（upstream/target -> 当前状态）；
+// HEALTHCHECKS_OFF 表示该 upstream 未启用健康检查，Kong 会直接转发流量，视为就绪
+func pendingTargets(ctx context.Context, client *kong.Client, declared map[string]map[string]bool) (pending map[string]string, unchecked []string, err error) {
+    pending = map[string]string{}
+    for up, ts := range declared {
+        if len(ts) == 0 { continue }
+        list, err := client.UpstreamHealth(ctx, up)
+        if err != nil {
+            return nil, nil, fmt.Errorf("读取 upstream %s 的健康状态失败：%w", up, err)
+        }
+        got := map[string]string{}
+        for _, h := range list { got[h.Target] = strings.ToUpper(h.Health) }
+        for t := range ts {
+            switch st := got[t]; st {
+            case "HEALTHY":
+            case "HEALTHCHECKS_OFF":
+                if !sliceContains(unchecked, up) { unchecked = append(unchecked, up) }
+            case "":
+                pending[up+"/"+t] = "MISSING"
+            default:
+                pending[up+"/"+t] = st
+            }
+        }
+    }
+    sort.Strings(unchecked)
+    return pending, unchecked, nil
+}
+
+// waitHealthyTargets 在 apply 完成后轮询 spec 中声明的 targets，直到全部 HEALTHY 或超过 --wait-timeout
+func waitHealthyTargets(cmd *cobra.Command, client *kong.Client, spec applySpec) error {
+    // 变更已全部写入，检查点不再需要
+    applyCheckpoint.remove()
+    applyCheckpoint = nil
+    declared := collectDeclared(spec).targets
+    total := 0
+    for _, ts := range declared { total += len(ts) }
+    if total == 0 {
+        PrintInfo(cmd, "--wait：spec 中未声明 targets，无需等待")
+        return nil
+    }
+    ctx, cancel := context.WithTimeout(cmd.Context(), applyWaitTimeout)
+    defer cancel()
+    PrintInfo(cmd, "等待 %d 个 targets 健康检查通过（最长 %s）…", total, applyWaitTimeout)
+    for {
+        pending, unchecked, err := pendingTargets(ctx, client, declared)
+        if err != nil {
+            if ctx.Err() != nil { err = ctx.Err() }
+            return err
+        }
+        if len(pending) == 0 {
+            if len(unchecked) > 0 {
+                PrintWarn(cmd, "以下 upstream 未启用健康检查，无法确认 target 状态：%s", strings.Join(unchecked, ", "))
+            }
+            PrintSuccess(cmd, "全部 %d 个 targets 已就绪", total)
+            return nil
+        }
+        select {
+        case <-ctx.Done():
+            keys := make([]string, 0, len(pending))
+            for k := range pending { keys = append(keys, k) }
+            sort.Strings(keys)
+            for _, k := range keys {
+                cmd.Printf("  %s %s：%s\n", colorError(glyph("✘", "[PENDING]")), k, pending[k])
+            }
+            return fmt.Errorf("等待 %s 后仍有 %d/%d 个 targets 未通过健康检查", applyWaitTimeout, len(pending), total)
+        case <-time.After(waitHealthInterval):
+        }
+    }
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+    "fmt"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    applyWatch         bool
+    applyWatchInterval time.Duration
+)
+
+// watchActive 为 true 时 apply 处于 --watch 循环中的单轮调和；watchWrites 为该轮发出的写操作数
+var (
+    watchActive bool
+    watchWrites int
+)
+
+// checkWatchFlags 检查 --watch 的取值与组合：单轮调和须可无人值守地重复执行
+func checkWatchFlags() error {
+    if applyWatchInterval <= 0 {
+        return withCode("usage", "", fmt.Errorf("--interval 必须大于 0"))
+    }
+    if applyFile == "" || applyFile == "-" {
+        return withCode("usage", "", fmt.Errorf("--watch 需通过 -f 指定 spec 文件或目录（每轮重新读取，不支持标准输入）"))
+    }
+    if applyPlanFile != "" || applyPlanOut != "" || applyDetailedExit || applyResume || applyConfirm || applyWait {
+        return withCode("usage", "", fmt.Errorf("--watch 不能与 --plan/--out/--detailed-exitcode/--resume/--confirm/--wait 同时使用"))
+    }
+    return nil
+}
+
+// runWatch 为 apply --watch：按 --interval 周期重新读取 spec 并调和（隐含 --overwrite，远程被改动的字段会被改回），
+// 各轮结果逐行输出；单轮失败只记录并在下一轮重试，收到 SIGINT/SIGTERM 后退出
+func runWatch(cmd *cobra.Command, args []string) error {
+    if err := checkWatchFlags(); err != nil {
+        return err
+    }
+    // 无人值守：跳过 confirm: true 的交互确认；每轮都保存快照会使快照目录快速增长
+    applyOverwrite, applyYes, applyNoSnapshot = true, true, true
+    ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    cmd.SetContext(ctx)
+    watchActive = true
+    defer func() { watchActive = false }()
+
+    mode := "apply"
+    if syncScoped { mode = "sync" }
+    if dryRun { mode += " --dry-run" }
+    PrintInfo(cmd, "watch：每 %s 调和一次 %s（%s），Ctrl+C 退出", applyWatchInterval, applyFile, mode)
+    for cycle := 1; ; cycle++ {
+        start := time.Now()
+        watchWrites = 0
+        // cmd 为 apply 或 sync，watchActive 使其执行单轮调和
+        err := cmd.RunE(cmd, args)
+        if ctx.Err() != nil {
+            break
+        }
+        stamp := start.Format("2006-01-02 15:04:05")
+        elapsed := time.Since(start).Round(time.Millisecond)
+        switch {
+        case err != nil:
+            PrintWarn(cmd, "[%s] 第 %d 轮调和失败（耗时 %s），%s 后重试：%v", stamp, cycle, elapsed, applyWatchInterval, err)
+        case watchWrites > 0:
+            PrintSuccess(cmd, "[%s] 第 %d 轮调和完成：写入 %d 次（耗时 %s）", stamp, cycle, watchWrites, elapsed)
+        default:
+            PrintInfo(cmd, "[%s] 第 %d 轮调和完成：无变更（耗时 %s）", stamp, cycle, elapsed)
+        }
+        timer := time.NewTimer(applyWatchInterval)
+        select {
+        case <-ctx.Done():
+            timer.Stop()
+        case <-timer.C:
+        }
+        if ctx.Err() != nil {
+            break
+        }
+    }
+    PrintInfo(cmd, "watch：已收到退出信号，停止调和")
+    return nil
+}
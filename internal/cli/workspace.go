@@ -0,0 +1,209 @@
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "sort"
+    "time"
+
+    "github.com/spf13/cobra"
+    "github.com/spf13/viper"
+    "kongctl/internal/kong"
+)
+
+var (
+    workspaceComment string
+    workspaceCascade bool
+
+    applyCreateWorkspace bool
+)
+
+var workspaceCmd = &cobra.Command{
+    Use:   "workspace",
+    Short: "管理 Workspace（企业版）",
+    Long: `Workspace 为企业版的资源隔离单元，其他命令通过 --workspace（或上下文中的 workspace）指定操作的 workspace。
+create 幂等，可与 kongctl apply --workspace <name> --create-workspace 一起在一次调用中初始化 workspace 及其资源。`,
+}
+
+// checkWorkspaceName 校验 workspace 名称：default 为内置 workspace，不能创建或删除
+func checkWorkspaceName(name string) error {
+    if name == "default" {
+        return withCode("usage", "", fmt.Errorf("default 为内置 workspace，不能创建或删除"))
+    }
+    return nil
+}
+
+// ensureApplyWorkspace 在 apply --create-workspace 时确保 --workspace 已存在：不存在时创建；
+// dry-run 时仅提示并返回 true（workspace 尚不存在，计划基于空 workspace，无需预取远程状态）
+func ensureApplyWorkspace(cmd *cobra.Command, ctx context.Context, cfg kong.Config) (bool, error) {
+    ws := viper.GetString("workspace")
+    if ws == "" || ws == "default" {
+        PrintWarn(cmd, "--create-workspace 需要同时指定非 default 的 --workspace，已忽略")
+        return false, nil
+    }
+    // 与 apply 锁一致，使用独立的客户端，不计入调用预算与写操作摘要
+    cfg.MaxCalls = 0
+    client := kong.NewClient(cfg)
+    _, ok, err := client.GetWorkspace(ctx, ws)
+    if err != nil {
+        return false, fmt.Errorf("查询 workspace %s 失败：%w", ws, err)
+    }
+    if ok {
+        return false, nil
+    }
+    if dryRun {
+        PrintInfo(cmd, "[dry-run] 将创建 Workspace：%s（以下计划基于空的 workspace）", ws)
+        return true, nil
+    }
+    if _, _, err := client.EnsureWorkspace(ctx, kong.Workspace{Name: ws}); err != nil {
+        return false, fmt.Errorf("创建 workspace %s 失败：%w", ws, err)
+    }
+    PrintSuccess(cmd, "已创建 Workspace：%s", ws)
+    return false, nil
+}
+
+var workspaceListCmd = &cobra.Command{
+    Use:   "list",
+    Short: "列出 Workspace（* 标记当前 --workspace）",
+    Example: `kongctl workspace list
+kongctl workspace list --output json`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        list, err := client.ListWorkspaces(ctx)
+        if err != nil {
+            return err
+        }
+        sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+        if outputJSON() {
+            if list == nil { list = []kong.Workspace{} }
+            b, _ := json.MarshalIndent(list, "", "  ")
+            cmd.Println(string(b))
+            return nil
+        }
+        if len(list) == 0 {
+            PrintInfo(cmd, "未找到 Workspace（开源版 Kong 不支持 workspace）")
+            return nil
+        }
+        current := cfg.Workspace
+        if current == "" { current = "default" }
+        cmd.Printf("  %-24s %s\n", "NAME", "COMMENT")
+        for _, ws := range list {
+            mark := " "
+            if ws.Name == current { mark = "*" }
+            cmd.Printf("%s %-24s %s\n", mark, ws.Name, ws.Comment)
+        }
+        return nil
+    },
+}
+
+var workspaceCreateCmd = &cobra.Command{
+    Use:   "create <name>",
+    Short: "创建 Workspace（幂等：已存在时不报错，--comment 不同时更新）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl workspace create team-a --comment "A 团队"
+kongctl apply -f team-a.yaml --workspace team-a --create-workspace`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        name := args[0]
+        if err := checkWorkspaceName(name); err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        cur, exists, err := client.GetWorkspace(ctx, name)
+        if err != nil {
+            return err
+        }
+        if exists && (workspaceComment == "" || workspaceComment == cur.Comment) {
+            PrintInfo(cmd, "Workspace 已存在，无需创建：%s", name)
+            return nil
+        }
+        if dryRun {
+            if exists {
+                PrintInfo(cmd, "[dry-run] 将更新 Workspace：%s（comment: %q -> %q）", name, cur.Comment, workspaceComment)
+            } else {
+                PrintInfo(cmd, "[dry-run] 将创建 Workspace：%s", name)
+            }
+            return nil
+        }
+        action, _, err := client.EnsureWorkspace(ctx, kong.Workspace{Name: name, Comment: workspaceComment})
+        if err != nil {
+            return err
+        }
+        PrintSuccess(cmd, "已%s Workspace：%s（其他命令使用 --workspace %s）", actionCN(action), name, name)
+        return nil
+    },
+}
+
+var workspaceDeleteCmd = &cobra.Command{
+    Use:   "delete <name>",
+    Short: "删除 Workspace（包含实体时需 --cascade）",
+    Args:  cobra.ExactArgs(1),
+    Example: `kongctl workspace delete team-a
+kongctl workspace delete team-a --cascade`,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        name := args[0]
+        if err := checkWorkspaceName(name); err != nil {
+            return err
+        }
+        cfg, err := clientConfig(10 * time.Second)
+        if err != nil {
+            return err
+        }
+        client := kong.NewClient(cfg)
+        ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+        defer cancel()
+
+        ws, ok, err := client.GetWorkspace(ctx, name)
+        if err != nil {
+            return err
+        }
+        if !ok {
+            PrintInfo(cmd, "Workspace 不存在，无需删除：%s", name)
+            return nil
+        }
+        label := ws.Name
+        if workspaceCascade { label += "（含其中的全部实体）" }
+        if dryRun {
+            PrintInfo(cmd, "[dry-run] 将删除 Workspace：%s", label)
+            return nil
+        }
+        if err := confirmDestructive(cmd, "workspace delete "+label); err != nil {
+            return err
+        }
+        if err := client.DeleteWorkspace(ctx, ws.ID, workspaceCascade); err != nil {
+            var apiErr *kong.APIError
+            if !workspaceCascade && errors.As(err, &apiErr) && apiErr.Status == http.StatusBadRequest {
+                return withCode("bad_request", "先删除其中的实体，或使用 --cascade 一并删除", fmt.Errorf("Workspace %s 仍包含实体，未删除：%w", name, err))
+            }
+            return err
+        }
+        PrintSuccess(cmd, "已删除 Workspace：%s", label)
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(workspaceCmd)
+    workspaceCmd.AddCommand(workspaceListCmd, workspaceCreateCmd, workspaceDeleteCmd)
+    workspaceCreateCmd.Flags().StringVar(&workspaceComment, "comment", "", "说明")
+    workspaceDeleteCmd.Flags().BoolVar(&workspaceCascade, "cascade", false, "一并删除 workspace 中的全部实体（Kong 3.x）")
+    for _, c := range []*cobra.Command{workspaceCreateCmd, workspaceDeleteCmd} {
+        c.Flags().BoolVar(&dryRun, "dry-run", false, "仅显示计划，不实际变更")
+    }
+}
@@ -0,0 +1,76 @@
+package kong
+
+import (
+    "context"
+    "net/http"
+    "net/url"
+)
+
+// Certificate 为 TLS 证书与私钥（PEM），snis 为使用该证书的服务器名称
+type Certificate struct {
+    ID   string   `json:"id,omitempty"`
+    Cert string   `json:"cert,omitempty"`
+    Key  string   `json:"key,omitempty"`
+    Snis []string `json:"snis,omitempty"`
+    Tags []string `json:"tags,omitempty"`
+}
+
+type certificateList struct { Data []Certificate `json:"data"` }
+
+// ListCertificates 列出所有证书（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListCertificates(ctx context.Context) ([]Certificate, error) {
+    var lst certificateList
+    if _, err := c.getJSON(ctx, "/certificates?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// GetCertificate 按 id 获取证书
+func (c *Client) GetCertificate(ctx context.Context, id string) (*Certificate, bool, error) {
+    var cert Certificate
+    ok, err := c.getJSON(ctx, "/certificates/"+url.PathEscape(id), &cert)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &cert, true, nil
+}
+
+// FindCertificateBySNI 查找 snis 中包含 sni 的证书
+func (c *Client) FindCertificateBySNI(ctx context.Context, sni string) (*Certificate, bool, error) {
+    list, err := c.ListCertificates(ctx)
+    if err != nil {
+        return nil, false, err
+    }
+    for i := range list {
+        if HasTag(list[i].Snis, sni) { return &list[i], true, nil }
+    }
+    return nil, false, nil
+}
+
+// CreateCertificate 上传证书与私钥，snis 随之创建
+func (c *Client) CreateCertificate(ctx context.Context, cert Certificate) (Certificate, error) {
+    cert.Tags = c.createTags(cert.Tags)
+    var out Certificate
+    if err := c.doJSON(ctx, http.MethodPost, "/certificates", cert, &out); err != nil {
+        return Certificate{}, err
+    }
+    return out, nil
+}
+
+// UpdateCertificate 替换证书的 cert/key（续期或轮换），snis 非空时整体替换证书的 SNI 列表
+func (c *Client) UpdateCertificate(ctx context.Context, id string, cert Certificate) (Certificate, error) {
+    payload := map[string]any{"cert": cert.Cert, "key": cert.Key}
+    if len(cert.Snis) > 0 { payload["snis"] = cert.Snis }
+    if len(cert.Tags) > 0 { payload["tags"] = c.withTags(cert.Tags) }
+    var out Certificate
+    if err := c.doJSON(ctx, http.MethodPatch, "/certificates/"+url.PathEscape(id), payload, &out); err != nil {
+        return Certificate{}, err
+    }
+    return out, nil
+}
+
+// DeleteCertificate 按 id 删除证书（其 SNI 一并删除）
+func (c *Client) DeleteCertificate(ctx context.Context, id string) error {
+    return c.deleteJSON(ctx, "/certificates/"+url.PathEscape(id))
+}
@@ -9,7 +9,9 @@ import (
     "fmt"
     "io"
     "net/http"
+    "strconv"
     "strings"
+    "sync"
     "time"
 )
 
@@ -20,11 +22,125 @@ type Config struct {
     Workspace     string
     TLSSkipVerify bool
     Timeout       time.Duration
+    // MaxCalls 限制单个客户端的 Admin API 调用次数（0 表示不限制），超出后返回 ErrBudgetExceeded
+    MaxCalls int
+    // TokenSource 用于获取/刷新会过期的 token（如 Konnect PAT、OIDC）：Token 为空时首次请求前调用；
+    // 请求返回 401 时再调用一次并以新 token 重试该请求
+    TokenSource func(ctx context.Context) (string, error)
+    // Tags 为创建/更新 service、upstream、target、route 时附加的标签（与资源已有标签合并），
+    // 例如 apply --prune 使用的 managed-by 标签
+    Tags []string
+    // CreateTags 为仅在创建资源时附加的标签（如 managed-by），更新已有资源时不补加
+    CreateTags []string
+    // UserAgent 为所有请求的 User-Agent（如 kongctl/0.1.0），便于网关运维按来源区分流量
+    UserAgent string
+    // Headers 为所有请求附加的自定义请求头（不覆盖认证等已设置的请求头）
+    Headers map[string]string
+    // TimeoutHeader 非空时以该请求头发送客户端超时（毫秒），供代理/网关对齐超时设置
+    TimeoutHeader string
+    // Debug 非空时记录每个请求的方法、路径、状态码、耗时与 X-Kong-Admin-Latency
+    Debug io.Writer
+    // Transport 非空时代替默认的 HTTP transport 发送请求（如 apply --against 使用的 MemoryAdmin）
+    Transport http.RoundTripper
 }
 
 type Client struct {
     cfg    Config
     client *http.Client
+    stats  callStats
+
+    tokenMu sync.Mutex
+    token   string
+
+    // cache 为 Prefetch 预取的远程状态（未调用 Prefetch 时为 nil）
+    cacheMu sync.Mutex
+    cache   *stateCache
+}
+
+// callStats 记录已发出的 API 调用，用于预算控制与中止时的部分执行摘要
+type callStats struct {
+    mu     sync.Mutex
+    total  int
+    writes []string
+}
+
+// ErrBudgetExceeded 表示已达到 Config.MaxCalls 设置的调用上限
+var ErrBudgetExceeded = errors.New("已达到 API 调用次数上限")
+
+// reserve 占用一次调用额度；写操作（非 GET，schema 校验除外）记录为 "METHOD path"
+func (c *Client) reserve(method, path string) error {
+    return c.reserveCall(method, path, true)
+}
+
+// reserveRetry 为同一请求的重试（如 401 后换 token 重发）占用一次调用额度；
+// 首次请求未被接受，写操作记录不重复追加
+func (c *Client) reserveRetry(method, path string) error {
+    return c.reserveCall(method, path, false)
+}
+
+func (c *Client) reserveCall(method, path string, record bool) error {
+    c.stats.mu.Lock()
+    defer c.stats.mu.Unlock()
+    if c.cfg.MaxCalls > 0 && c.stats.total >= c.cfg.MaxCalls {
+        return fmt.Errorf("%w（%d 次），已中止：%s %s", ErrBudgetExceeded, c.cfg.MaxCalls, method, path)
+    }
+    c.stats.total++
+    // /schemas/<entity>/validate 只做校验，不计为写操作
+    if record && method != http.MethodGet && !strings.HasSuffix(path, "/validate") {
+        c.stats.writes = append(c.stats.writes, method+" "+path)
+    }
+    return nil
+}
+
+// Calls 返回已发出的 API 调用次数
+func (c *Client) Calls() int {
+    c.stats.mu.Lock()
+    defer c.stats.mu.Unlock()
+    return c.stats.total
+}
+
+// Writes 返回已发出的写操作（按发出顺序）
+func (c *Client) Writes() []string {
+    c.stats.mu.Lock()
+    defer c.stats.mu.Unlock()
+    return append([]string(nil), c.stats.writes...)
+}
+
+// withTags 返回 tags 与 Config.Tags 的并集（保持原有顺序）
+func (c *Client) withTags(tags []string) []string {
+    out := append([]string{}, tags...)
+    for _, t := range c.cfg.Tags {
+        if !HasTag(out, t) { out = append(out, t) }
+    }
+    return out
+}
+
+// ConfigTags 返回 Config.Tags 的副本（创建/更新资源时附加的标签）
+func (c *Client) ConfigTags() []string { return append([]string(nil), c.cfg.Tags...) }
+
+// createTags 返回创建资源时使用的标签：withTags 的结果再并上 Config.CreateTags
+func (c *Client) createTags(tags []string) []string {
+    out := c.withTags(tags)
+    for _, t := range c.cfg.CreateTags {
+        if t != "" && !HasTag(out, t) { out = append(out, t) }
+    }
+    return out
+}
+
+// missingTags 表示资源当前标签中缺少 Config.Tags 的某一项
+func (c *Client) missingTags(tags []string) bool {
+    for _, t := range c.cfg.Tags {
+        if !HasTag(tags, t) { return true }
+    }
+    return false
+}
+
+// HasTag 判断标签列表中是否包含 tag
+func HasTag(tags []string, tag string) bool {
+    for _, t := range tags {
+        if t == tag { return true }
+    }
+    return false
 }
 
 func NewClient(cfg Config) *Client {
@@ -32,27 +148,71 @@ func NewClient(cfg Config) *Client {
     if cfg.AdminURL != "" && !strings.HasPrefix(cfg.AdminURL, "http://") && !strings.HasPrefix(cfg.AdminURL, "https://") {
         cfg.AdminURL = "http://" + cfg.AdminURL
     }
-    tr := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.TLSSkipVerify}} //nolint:gosec
+    var tr http.RoundTripper = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.TLSSkipVerify}} //nolint:gosec
+    if cfg.Transport != nil { tr = cfg.Transport }
+    headers := map[string]string{}
+    for k, v := range cfg.Headers { headers[k] = v }
+    if cfg.TimeoutHeader != "" && cfg.Timeout > 0 {
+        headers[cfg.TimeoutHeader] = strconv.FormatInt(cfg.Timeout.Milliseconds(), 10)
+    }
     return &Client{
         cfg: cfg,
         client: &http.Client{
-            Transport: tr,
+            Transport: &headerTransport{base: tr, userAgent: cfg.UserAgent, headers: headers, debug: cfg.Debug},
             Timeout:   cfg.Timeout,
         },
+        token: cfg.Token,
+    }
+}
+
+// currentToken 返回当前 token；若为空且配置了 TokenSource，则先获取一次
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+    c.tokenMu.Lock()
+    defer c.tokenMu.Unlock()
+    if c.token == "" && c.cfg.TokenSource != nil {
+        t, err := c.cfg.TokenSource(ctx)
+        if err != nil {
+            return "", fmt.Errorf("获取 token 失败：%w", err)
+        }
+        c.token = t
+    }
+    return c.token, nil
+}
+
+// refreshToken 在 stale 失效后获取新 token；若其他请求已完成刷新则直接复用
+func (c *Client) refreshToken(ctx context.Context, stale string) (string, error) {
+    c.tokenMu.Lock()
+    defer c.tokenMu.Unlock()
+    if c.token != stale {
+        return c.token, nil
+    }
+    t, err := c.cfg.TokenSource(ctx)
+    if err != nil {
+        return "", err
+    }
+    c.token = t
+    return t, nil
+}
+
+func setAuth(req *http.Request, token string) {
+    if token != "" {
+        req.Header.Set("Kong-Admin-Token", token)
+        req.Header.Set("Authorization", "Bearer "+token)
     }
 }
 
 // Ping 尝试访问 /status 或根路径，验证连通性
 func (c *Client) Ping(ctx context.Context) error {
     paths := []string{"/status", "/"}
+    token, err := c.currentToken(ctx)
+    if err != nil {
+        return err
+    }
     var lastErr error
     for _, p := range paths {
         url := strings.TrimRight(c.cfg.AdminURL, "/") + p
         req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-        if c.cfg.Token != "" {
-            req.Header.Set("Kong-Admin-Token", c.cfg.Token)
-            req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
-        }
+        setAuth(req, token)
         resp, err := c.client.Do(req)
         if err != nil {
             lastErr = err
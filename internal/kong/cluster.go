@@ -0,0 +1,47 @@
+package kong
+
+import (
+    "context"
+    "strings"
+)
+
+// Status 为 /status 的部分字段；ConfigurationHash 为节点当前生效配置的哈希（DB-less/data plane 节点提供）
+type Status struct {
+    ConfigurationHash string `json:"configuration_hash,omitempty"`
+}
+
+// DataPlane 为控制面 /clustering/data-planes 中的一项
+type DataPlane struct {
+    ID         string `json:"id"`
+    Hostname   string `json:"hostname"`
+    IP         string `json:"ip,omitempty"`
+    Version    string `json:"version,omitempty"`
+    LastSeen   int64  `json:"last_seen,omitempty"`
+    ConfigHash string `json:"config_hash"`
+    SyncStatus string `json:"sync_status,omitempty"`
+}
+
+type dataPlaneList struct { Data []DataPlane `json:"data"` }
+
+// emptyConfigHash 为 Kong 尚未加载任何配置时返回的哈希
+var emptyConfigHash = strings.Repeat("0", 32)
+
+// GetStatus 读取节点 /status
+func (c *Client) GetStatus(ctx context.Context) (Status, error) {
+    var st Status
+    if _, err := c.getJSON(ctx, "/status", &st); err != nil {
+        return Status{}, err
+    }
+    if st.ConfigurationHash == emptyConfigHash { st.ConfigurationHash = "" }
+    return st, nil
+}
+
+// ListDataPlanes 列出控制面已连接的 data plane；非混合模式（接口不存在）时返回 (nil, false, nil)
+func (c *Client) ListDataPlanes(ctx context.Context) ([]DataPlane, bool, error) {
+    var lst dataPlaneList
+    ok, err := c.getJSON(ctx, "/clustering/data-planes?size=1000", &lst)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return lst.Data, true, nil
+}
@@ -0,0 +1,130 @@
+package kong
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strings"
+)
+
+type Consumer struct {
+    ID       string   `json:"id,omitempty"`
+    Username string   `json:"username,omitempty"`
+    CustomID string   `json:"custom_id,omitempty"`
+    Tags     []string `json:"tags,omitempty"`
+}
+
+type consumerList struct { Data []Consumer `json:"data"` }
+
+// GetConsumer 按 username 或 id 查询 Consumer
+func (c *Client) GetConsumer(ctx context.Context, nameOrID string) (*Consumer, bool, error) {
+    var cs Consumer
+    ok, err := c.getJSON(ctx, "/consumers/"+url.PathEscape(nameOrID), &cs)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &cs, true, nil
+}
+
+// ListConsumers 列出所有 Consumer（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListConsumers(ctx context.Context) ([]Consumer, error) {
+    var lst consumerList
+    if _, err := c.getJSON(ctx, "/consumers?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// ListConsumersByTags 列出同时带有全部 tags 的 Consumer
+func (c *Client) ListConsumersByTags(ctx context.Context, tags []string) ([]Consumer, error) {
+    var lst consumerList
+    if _, err := c.getJSON(ctx, "/consumers?size=1000&tags="+url.QueryEscape(strings.Join(tags, ",")), &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// DeleteConsumer 按 username 或 id 删除 Consumer（其下凭证一并删除）
+func (c *Client) DeleteConsumer(ctx context.Context, nameOrID string) error {
+    return c.deleteJSON(ctx, "/consumers/"+url.PathEscape(nameOrID))
+}
+
+// CreateOrUpdateConsumer 按 username 幂等创建或更新 Consumer（custom_id/tags）
+func (c *Client) CreateOrUpdateConsumer(ctx context.Context, desired Consumer) (string, Consumer, error) {
+    if desired.Username == "" && desired.CustomID == "" {
+        return "", Consumer{}, fmt.Errorf("consumer 需要 username 或 custom_id")
+    }
+    key := desired.Username
+    if key == "" { key = desired.CustomID }
+    cur, ok, err := c.GetConsumer(ctx, key)
+    if err != nil {
+        return "", Consumer{}, err
+    }
+    var out Consumer
+    if !ok {
+        desired.Tags = c.createTags(desired.Tags)
+        if err := c.doJSON(ctx, http.MethodPost, "/consumers", desired, &out); err != nil {
+            return "", Consumer{}, err
+        }
+        return "create", out, nil
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, "/consumers/"+cur.ID, desired, &out); err != nil {
+        return "", Consumer{}, err
+    }
+    return "update", out, nil
+}
+
+// Credential 为 consumer 凭证（key-auth、basic-auth、mtls-auth、acls 等），字段因类型而异
+type Credential map[string]any
+
+type credentialList struct { Data []Credential `json:"data"` }
+
+// CredentialIdentity 返回各凭证类型用于判定“同一凭证”的字段
+func CredentialIdentity(kind string) string {
+    switch kind {
+    case "key-auth", "jwt":
+        return "key"
+    case "basic-auth", "hmac-auth":
+        return "username"
+    case "acls":
+        return "group"
+    case "oauth2":
+        return "client_id"
+    case "mtls-auth":
+        return "subject_name"
+    }
+    return "id"
+}
+
+// ListCredentials 列出 consumer 下指定类型的凭证
+func (c *Client) ListCredentials(ctx context.Context, consumer, kind string) ([]Credential, error) {
+    var lst credentialList
+    if _, err := c.getJSON(ctx, "/consumers/"+url.PathEscape(consumer)+"/"+kind+"?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// CreateCredential 在 consumer 下创建凭证
+func (c *Client) CreateCredential(ctx context.Context, consumer, kind string, cred Credential) (Credential, error) {
+    var out Credential
+    if err := c.doJSON(ctx, http.MethodPost, "/consumers/"+url.PathEscape(consumer)+"/"+kind, cred, &out); err != nil {
+        return nil, err
+    }
+    return out, nil
+}
+
+// UpdateCredential 按 id 更新 consumer 下的凭证
+func (c *Client) UpdateCredential(ctx context.Context, consumer, kind, id string, cred Credential) (Credential, error) {
+    var out Credential
+    if err := c.doJSON(ctx, http.MethodPatch, "/consumers/"+url.PathEscape(consumer)+"/"+kind+"/"+id, cred, &out); err != nil {
+        return nil, err
+    }
+    return out, nil
+}
+
+// DeleteCredential 按 id 删除 consumer 下的凭证
+func (c *Client) DeleteCredential(ctx context.Context, consumer, kind, id string) error {
+    return c.deleteJSON(ctx, "/consumers/"+url.PathEscape(consumer)+"/"+kind+"/"+url.PathEscape(id))
+}
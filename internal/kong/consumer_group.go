@@ -0,0 +1,129 @@
+package kong
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/url"
+)
+
+// ConsumerGroup 为 Consumer 分组（Kong Enterprise 与 OSS 3.4+）；组上的插件（如 rate-limiting-advanced）作用于组内全部 Consumer
+type ConsumerGroup struct {
+    ID   string   `json:"id,omitempty"`
+    Name string   `json:"name,omitempty"`
+    Tags []string `json:"tags,omitempty"`
+}
+
+type consumerGroupList struct { Data []ConsumerGroup `json:"data"` }
+
+// consumerGroupView 兼容两种响应：GET /consumer_groups/<key> 返回 {consumer_group: {...}, consumers: [...]}，
+// PATCH/POST 返回实体本身
+type consumerGroupView struct {
+    ConsumerGroup
+    Nested *ConsumerGroup `json:"consumer_group"`
+}
+
+func (v consumerGroupView) group() ConsumerGroup {
+    if v.Nested != nil { return *v.Nested }
+    return v.ConsumerGroup
+}
+
+// GetConsumerGroup 按名称或 id 查询 Consumer Group
+func (c *Client) GetConsumerGroup(ctx context.Context, nameOrID string) (*ConsumerGroup, bool, error) {
+    var v consumerGroupView
+    ok, err := c.getJSON(ctx, "/consumer_groups/"+url.PathEscape(nameOrID), &v)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    g := v.group()
+    return &g, true, nil
+}
+
+// ListConsumerGroups 列出所有 Consumer Group（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]ConsumerGroup, error) {
+    var lst consumerGroupList
+    if _, err := c.getJSON(ctx, "/consumer_groups?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// CreateOrUpdateConsumerGroup 按名称幂等创建 Consumer Group，已存在时仅在 tags 不同时更新；无变化时返回 "none"
+func (c *Client) CreateOrUpdateConsumerGroup(ctx context.Context, desired ConsumerGroup) (string, ConsumerGroup, error) {
+    if desired.Name == "" {
+        return "", ConsumerGroup{}, fmt.Errorf("consumer group 名称不能为空")
+    }
+    cur, ok, err := c.GetConsumerGroup(ctx, desired.Name)
+    if err != nil {
+        return "", ConsumerGroup{}, err
+    }
+    var out consumerGroupView
+    if !ok {
+        desired.Tags = c.createTags(desired.Tags)
+        if err := c.doJSON(ctx, http.MethodPost, "/consumer_groups", desired, &out); err != nil {
+            return "", ConsumerGroup{}, err
+        }
+        return "create", out.group(), nil
+    }
+    payload := map[string]any{}
+    if len(desired.Tags) > 0 {
+        payload["tags"] = c.withTags(desired.Tags)
+    } else if c.missingTags(cur.Tags) {
+        payload["tags"] = c.withTags(cur.Tags)
+    }
+    if payload = prunePatch(cur, payload); len(payload) == 0 {
+        return "none", *cur, nil
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, "/consumer_groups/"+cur.ID, payload, &out); err != nil {
+        return "", ConsumerGroup{}, err
+    }
+    return "update", out.group(), nil
+}
+
+// DeleteConsumerGroup 按名称或 id 删除 Consumer Group（成员关系与组上的插件一并删除，Consumer 本身保留）
+func (c *Client) DeleteConsumerGroup(ctx context.Context, nameOrID string) error {
+    return c.deleteJSON(ctx, "/consumer_groups/"+url.PathEscape(nameOrID))
+}
+
+// ListConsumerGroupMembers 列出 Consumer Group 中的 Consumer
+func (c *Client) ListConsumerGroupMembers(ctx context.Context, group string) ([]Consumer, error) {
+    var lst consumerList
+    if _, err := c.getJSON(ctx, "/consumer_groups/"+url.PathEscape(group)+"/consumers?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// AddConsumerGroupMember 将 Consumer（username 或 id）加入 Consumer Group
+func (c *Client) AddConsumerGroupMember(ctx context.Context, group, consumer string) error {
+    return c.doJSON(ctx, http.MethodPost, "/consumer_groups/"+url.PathEscape(group)+"/consumers", map[string]any{"consumer": consumer}, nil)
+}
+
+// RemoveConsumerGroupMember 将 Consumer 移出 Consumer Group；不在组内时视为成功
+func (c *Client) RemoveConsumerGroupMember(ctx context.Context, group, consumer string) error {
+    return c.deleteJSON(ctx, "/consumer_groups/"+url.PathEscape(group)+"/consumers/"+url.PathEscape(consumer))
+}
+
+// ListConsumerGroupsOf 列出 Consumer 所属的 Consumer Group；网关不支持 Consumer Group 时返回空
+func (c *Client) ListConsumerGroupsOf(ctx context.Context, consumer string) ([]ConsumerGroup, error) {
+    var lst consumerGroupList
+    if _, err := c.getJSON(ctx, "/consumers/"+url.PathEscape(consumer)+"/consumer_groups?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// ListConsumerGroupPlugins 列出挂在指定 Consumer Group 上的插件
+func (c *Client) ListConsumerGroupPlugins(ctx context.Context, group string) ([]Plugin, error) {
+    return c.listPlugins(ctx, "/consumer_groups/"+url.PathEscape(group))
+}
+
+// GetConsumerGroupPlugin 获取 Consumer Group 上的同名插件
+func (c *Client) GetConsumerGroupPlugin(ctx context.Context, group, name string) (*Plugin, bool, error) {
+    return c.findPlugin(ctx, "/consumer_groups/"+url.PathEscape(group), name)
+}
+
+// CreateOrUpdateConsumerGroupPlugin 在 Consumer Group 上幂等创建或更新同名插件（config/enabled/tags）
+func (c *Client) CreateOrUpdateConsumerGroupPlugin(ctx context.Context, group string, desired Plugin) (string, Plugin, error) {
+    return c.createOrUpdatePlugin(ctx, "/consumer_groups/"+url.PathEscape(group), desired)
+}
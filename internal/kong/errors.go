@@ -0,0 +1,49 @@
+package kong
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+)
+
+// APIError 为 Admin API 返回的非 2xx 响应；Kong 的错误体形如
+// {"code": 5, "name": "unique constraint violation", "message": "...", "fields": {...}}
+type APIError struct {
+    Status  int
+    Method  string
+    Path    string
+    Body    string
+    Code    int            // Kong 错误码（如 2=schema violation、5=unique violation），无法解析时为 0
+    Name    string         // Kong 错误名称
+    Message string         // Kong 错误信息
+    Fields  map[string]any // 字段级错误（schema violation 等）
+}
+
+func (e *APIError) Error() string {
+    if e.Body == "" {
+        return fmt.Sprintf("HTTP %d", e.Status)
+    }
+    return fmt.Sprintf("HTTP %d: %s", e.Status, e.Body)
+}
+
+// newAPIError 读取响应体并尽量解析 Kong 错误结构
+func newAPIError(resp *http.Response) *APIError {
+    b, _ := io.ReadAll(resp.Body)
+    e := &APIError{Status: resp.StatusCode, Body: strings.TrimSpace(string(b))}
+    if resp.Request != nil {
+        e.Method = resp.Request.Method
+        if resp.Request.URL != nil { e.Path = resp.Request.URL.Path }
+    }
+    var kerr struct {
+        Code    int            `json:"code"`
+        Name    string         `json:"name"`
+        Message string         `json:"message"`
+        Fields  map[string]any `json:"fields"`
+    }
+    if json.Unmarshal(b, &kerr) == nil {
+        e.Code, e.Name, e.Message, e.Fields = kerr.Code, kerr.Name, kerr.Message, kerr.Fields
+    }
+    return e
+}
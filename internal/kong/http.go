@@ -7,34 +7,107 @@ import (
     "fmt"
     "io"
     "net/http"
+    "net/url"
     "strings"
+    "time"
 )
 
+// headerTransport 为每个请求设置 User-Agent 与自定义请求头；debug 非空时逐条记录请求结果、
+// 客户端耗时与网关返回的 X-Kong-Admin-Latency（Admin API 自身处理耗时，毫秒）
+type headerTransport struct {
+    base      http.RoundTripper
+    userAgent string
+    headers   map[string]string
+    debug     io.Writer
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+    req = req.Clone(req.Context())
+    if t.userAgent != "" { req.Header.Set("User-Agent", t.userAgent) }
+    for k, v := range t.headers {
+        if req.Header.Get(k) == "" { req.Header.Set(k, v) }
+    }
+    start := time.Now()
+    resp, err := t.base.RoundTrip(req)
+    if t.debug != nil {
+        elapsed := time.Since(start).Round(time.Millisecond)
+        if err != nil {
+            fmt.Fprintf(t.debug, "[http] %s %s -> 错误：%v（%s）\n", req.Method, req.URL.RequestURI(), err, elapsed)
+        } else {
+            latency := resp.Header.Get("X-Kong-Admin-Latency")
+            if latency == "" { latency = "-" }
+            fmt.Fprintf(t.debug, "[http] %s %s -> %d（%s，X-Kong-Admin-Latency=%s）\n", req.Method, req.URL.RequestURI(), resp.StatusCode, elapsed, latency)
+        }
+    }
+    return resp, err
+}
+
+// globalPaths 为不区分 workspace 的 Admin API 路径（首段）
+var globalPaths = map[string]bool{"": true, "status": true, "clustering": true, "workspaces": true, "license": true, "licenses": true}
+
+// endpoint 返回请求地址；指定了非 default 的 Workspace 时为 workspace 内的路径加上 /<workspace> 前缀（企业版）
 func (c *Client) endpoint(path string) string {
-    return strings.TrimRight(c.cfg.AdminURL, "/") + path
+    base := strings.TrimRight(c.cfg.AdminURL, "/")
+    if ws := c.cfg.Workspace; ws != "" && ws != "default" {
+        seg := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+        if i := strings.IndexAny(seg, "?#"); i >= 0 { seg = seg[:i] }
+        if !globalPaths[seg] { base += "/" + url.PathEscape(ws) }
+    }
+    return base + path
 }
 
 func (c *Client) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
-    var reader io.Reader
+    if err := c.reserve(method, path); err != nil {
+        return nil, err
+    }
+    var payload []byte
     if body != nil {
         b, err := json.Marshal(body)
         if err != nil {
             return nil, err
         }
-        reader = bytes.NewReader(b)
+        payload = b
+    }
+    if method != http.MethodGet {
+        c.invalidate(path, payload)
     }
-    req, err := http.NewRequestWithContext(ctx, method, c.endpoint(path), reader)
+    token, err := c.currentToken(ctx)
     if err != nil {
         return nil, err
     }
-    if body != nil {
-        req.Header.Set("Content-Type", "application/json")
+    send := func(token string) (*http.Response, error) {
+        var reader io.Reader
+        if payload != nil {
+            reader = bytes.NewReader(payload)
+        }
+        req, err := http.NewRequestWithContext(ctx, method, c.endpoint(path), reader)
+        if err != nil {
+            return nil, err
+        }
+        if payload != nil {
+            req.Header.Set("Content-Type", "application/json")
+        }
+        setAuth(req, token)
+        return c.client.Do(req)
+    }
+    resp, err := send(token)
+    if err != nil || resp.StatusCode != http.StatusUnauthorized || c.cfg.TokenSource == nil {
+        return resp, err
+    }
+    // token 可能已过期：重新获取一次并重试当前请求
+    fresh, rerr := c.refreshToken(ctx, token)
+    if rerr != nil {
+        resp.Body.Close()
+        return nil, fmt.Errorf("token 已失效且重新获取失败：%w", rerr)
     }
-    if c.cfg.Token != "" {
-        req.Header.Set("Kong-Admin-Token", c.cfg.Token)
-        req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+    if fresh == token {
+        return resp, nil
     }
-    return c.client.Do(req)
+    resp.Body.Close()
+    if err := c.reserveRetry(method, path); err != nil {
+        return nil, err
+    }
+    return send(fresh)
 }
 
 func (c *Client) doJSON(ctx context.Context, method, path string, body any, out any) error {
@@ -44,27 +117,104 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, out
     }
     defer resp.Body.Close()
     if resp.StatusCode/100 != 2 {
-        b, _ := io.ReadAll(resp.Body)
-        return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+        return newAPIError(resp)
     }
     if out != nil {
-        // 读取响应体以便在非 JSON 情况下提供友好错误提示
-        data, _ := io.ReadAll(resp.Body)
-        ct := resp.Header.Get("Content-Type")
-        // 粗略判断：Content-Type 非 JSON 或内容疑似 HTML
-        if ct != "" && !strings.Contains(strings.ToLower(ct), "json") || (len(data) > 0 && (bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")))) {
-            snippet := strings.TrimSpace(string(data))
-            if len(snippet) > 256 { snippet = snippet[:256] + "..." }
-            return fmt.Errorf("响应非 JSON（Content-Type=%s）。请检查 --admin-url 是否指向 Kong Admin API。响应片段：%s", ct, snippet)
-        }
-        if len(bytes.TrimSpace(data)) == 0 {
-            return nil
-        }
-        if err := json.Unmarshal(data, out); err != nil {
-            snippet := strings.TrimSpace(string(data))
-            if len(snippet) > 256 { snippet = snippet[:256] + "..." }
-            return fmt.Errorf("解析 JSON 失败：%v。请检查 --admin-url 是否正确。响应片段：%s", err, snippet)
-        }
+        return decodeJSON(resp, out)
+    }
+    return nil
+}
+
+// getJSON 发起 GET 请求并解析响应；404 时返回 (false, nil)
+func (c *Client) getJSON(ctx context.Context, path string, out any) (bool, error) {
+    resp, err := c.do(ctx, http.MethodGet, path, nil)
+    if err != nil {
+        return false, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode == http.StatusNotFound {
+        return false, nil
+    }
+    if resp.StatusCode/100 != 2 {
+        return false, newAPIError(resp)
+    }
+    return true, decodeJSON(resp, out)
+}
+
+// deleteJSON 发起 DELETE 请求；资源已不存在（404）时视为成功
+func (c *Client) deleteJSON(ctx context.Context, path string) error {
+    resp, err := c.do(ctx, http.MethodDelete, path, nil)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode == http.StatusNotFound || resp.StatusCode/100 == 2 {
+        return nil
+    }
+    return newAPIError(resp)
+}
+
+// decodeJSON 解析响应体；非 JSON（如指向了代理端口的 HTML）时给出友好提示
+func decodeJSON(resp *http.Response, out any) error {
+    // 读取响应体以便在非 JSON 情况下提供友好错误提示
+    data, _ := io.ReadAll(resp.Body)
+    ct := resp.Header.Get("Content-Type")
+    // 粗略判断：Content-Type 非 JSON 或内容疑似 HTML
+    if ct != "" && !strings.Contains(strings.ToLower(ct), "json") || (len(data) > 0 && (bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")))) {
+        snippet := strings.TrimSpace(string(data))
+        if len(snippet) > 256 { snippet = snippet[:256] + "..." }
+        return fmt.Errorf("响应非 JSON（Content-Type=%s）。请检查 --admin-url 是否指向 Kong Admin API。响应片段：%s", ct, snippet)
+    }
+    if len(bytes.TrimSpace(data)) == 0 {
+        return nil
+    }
+    if err := json.Unmarshal(data, out); err != nil {
+        snippet := strings.TrimSpace(string(data))
+        if len(snippet) > 256 { snippet = snippet[:256] + "..." }
+        return fmt.Errorf("解析 JSON 失败：%v。请检查 --admin-url 是否正确。响应片段：%s", err, snippet)
     }
     return nil
 }
+
+// GetRaw 按路径读取单个实体，原样返回 Admin API 的 JSON（不经结构体转换）；404 时返回 (nil, false, nil)
+func (c *Client) GetRaw(ctx context.Context, path string) (json.RawMessage, bool, error) {
+    var raw json.RawMessage
+    ok, err := c.getJSON(ctx, path, &raw)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return raw, true, nil
+}
+
+// PatchRaw 按路径对单个实体做部分更新，返回 Admin API 的原始 JSON
+func (c *Client) PatchRaw(ctx context.Context, path string, payload map[string]any) (json.RawMessage, error) {
+    var raw json.RawMessage
+    if err := c.doJSON(ctx, http.MethodPatch, path, payload, &raw); err != nil {
+        return nil, err
+    }
+    return raw, nil
+}
+
+// CreateRaw 向集合路径（如 /services）POST 创建实体，返回 Admin API 的原始 JSON
+func (c *Client) CreateRaw(ctx context.Context, path string, payload map[string]any) (json.RawMessage, error) {
+    var raw json.RawMessage
+    if err := c.doJSON(ctx, http.MethodPost, path, payload, &raw); err != nil {
+        return nil, err
+    }
+    return raw, nil
+}
+
+// Probe 发起请求并返回 HTTP 状态码，非 2xx 不视为错误（用于权限探测）；2xx 且 out 非空时解析响应
+func (c *Client) Probe(ctx context.Context, method, path string, body any, out any) (int, error) {
+    resp, err := c.do(ctx, method, path, body)
+    if err != nil {
+        return 0, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode/100 == 2 && out != nil {
+        if err := decodeJSON(resp, out); err != nil {
+            return resp.StatusCode, err
+        }
+    }
+    return resp.StatusCode, nil
+}
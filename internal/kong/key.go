@@ -0,0 +1,152 @@
+package kong
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/url"
+)
+
+// KeySet 为一组密钥（JWKS），供 jwt-signer、openid-connect 等插件按名称引用，name 全局唯一
+type KeySet struct {
+    ID   string   `json:"id,omitempty"`
+    Name string   `json:"name,omitempty"`
+    Tags []string `json:"tags,omitempty"`
+}
+
+// Key 为单个密钥：kid 在所属 key set 内唯一，密钥内容为 jwk（JSON 字符串）或 pem 二选一
+type Key struct {
+    ID   string     `json:"id,omitempty"`
+    Name string     `json:"name,omitempty"`
+    Kid  string     `json:"kid,omitempty"`
+    Set  *EntityRef `json:"set,omitempty"`
+    JWK  string     `json:"jwk,omitempty"`
+    PEM  *KeyPEM    `json:"pem,omitempty"`
+    Tags []string   `json:"tags,omitempty"`
+}
+
+// KeyPEM 为 PEM 格式的密钥对，只校验签名时可只提供 public_key
+type KeyPEM struct {
+    PrivateKey string `json:"private_key,omitempty"`
+    PublicKey  string `json:"public_key,omitempty"`
+}
+
+type keySetList struct { Data []KeySet `json:"data"` }
+type keyList struct { Data []Key `json:"data"` }
+
+// ListKeySets 列出所有 key set（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListKeySets(ctx context.Context) ([]KeySet, error) {
+    var lst keySetList
+    if _, err := c.getJSON(ctx, "/key-sets?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// GetKeySet 按名称或 id 查询 key set
+func (c *Client) GetKeySet(ctx context.Context, name string) (*KeySet, bool, error) {
+    var ks KeySet
+    ok, err := c.getJSON(ctx, "/key-sets/"+url.PathEscape(name), &ks)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &ks, true, nil
+}
+
+// CreateOrUpdateKeySet 按名称幂等创建 key set；已存在时只更新 tags（tags 为空时仅补上 managed-by 标签）
+func (c *Client) CreateOrUpdateKeySet(ctx context.Context, desired KeySet) (string, KeySet, error) {
+    if desired.Name == "" {
+        return "", KeySet{}, fmt.Errorf("key set 名称不能为空")
+    }
+    cur, ok, err := c.GetKeySet(ctx, desired.Name)
+    if err != nil {
+        return "", KeySet{}, err
+    }
+    var out KeySet
+    if !ok {
+        if err := c.doJSON(ctx, http.MethodPost, "/key-sets", KeySet{Name: desired.Name, Tags: c.createTags(desired.Tags)}, &out); err != nil {
+            return "", KeySet{}, err
+        }
+        return "create", out, nil
+    }
+    payload := map[string]any{}
+    if len(desired.Tags) > 0 {
+        payload["tags"] = c.withTags(desired.Tags)
+    } else if c.missingTags(cur.Tags) {
+        payload["tags"] = c.withTags(cur.Tags)
+    }
+    if len(payload) == 0 {
+        return "none", *cur, nil
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, "/key-sets/"+cur.ID, payload, &out); err != nil {
+        return "", KeySet{}, err
+    }
+    return "update", out, nil
+}
+
+// DeleteKeySet 按名称或 id 删除 key set（Kong 会一并删除其中的 key）
+func (c *Client) DeleteKeySet(ctx context.Context, name string) error {
+    return c.deleteJSON(ctx, "/key-sets/"+url.PathEscape(name))
+}
+
+// ListKeys 列出 key；set 非空时只列出该 key set 中的 key（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListKeys(ctx context.Context, set string) ([]Key, error) {
+    path := "/keys?size=1000"
+    if set != "" { path = "/key-sets/" + url.PathEscape(set) + "/keys?size=1000" }
+    var lst keyList
+    if _, err := c.getJSON(ctx, path, &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// GetKey 按名称或 id 查询 key
+func (c *Client) GetKey(ctx context.Context, name string) (*Key, bool, error) {
+    var k Key
+    ok, err := c.getJSON(ctx, "/keys/"+url.PathEscape(name), &k)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &k, true, nil
+}
+
+// FindKey 按 kid 查找 key：set 非空时在该 key set 中查找，否则只匹配不属于任何 key set 的 key
+func (c *Client) FindKey(ctx context.Context, set, kid string) (*Key, bool, error) {
+    list, err := c.ListKeys(ctx, set)
+    if err != nil {
+        return nil, false, err
+    }
+    for i := range list {
+        if list[i].Kid == kid && (set != "" || list[i].Set == nil) { return &list[i], true, nil }
+    }
+    return nil, false, nil
+}
+
+// CreateKey 创建 key
+func (c *Client) CreateKey(ctx context.Context, k Key) (Key, error) {
+    k.Tags = c.createTags(k.Tags)
+    var out Key
+    if err := c.doJSON(ctx, http.MethodPost, "/keys", k, &out); err != nil {
+        return Key{}, err
+    }
+    return out, nil
+}
+
+// UpdateKey 替换 key 的内容（轮换），name 与 tags 非空时一并更新
+func (c *Client) UpdateKey(ctx context.Context, id string, k Key) (Key, error) {
+    payload := map[string]any{"kid": k.Kid}
+    if k.Name != "" { payload["name"] = k.Name }
+    if k.JWK != "" { payload["jwk"] = k.JWK }
+    if k.PEM != nil { payload["pem"] = k.PEM }
+    if len(k.Tags) > 0 { payload["tags"] = c.withTags(k.Tags) }
+    var out Key
+    if err := c.doJSON(ctx, http.MethodPatch, "/keys/"+url.PathEscape(id), payload, &out); err != nil {
+        return Key{}, err
+    }
+    return out, nil
+}
+
+// DeleteKey 按名称或 id 删除 key
+func (c *Client) DeleteKey(ctx context.Context, name string) error {
+    return c.deleteJSON(ctx, "/keys/"+url.PathEscape(name))
+}
@@ -0,0 +1,44 @@
+package kong
+
+import (
+    "context"
+    "net/http"
+    "net/url"
+)
+
+// License 为企业版许可证，payload 为许可证文件的原始 JSON 字符串
+type License struct {
+    ID        string `json:"id,omitempty"`
+    Payload   string `json:"payload"`
+    CreatedAt int64  `json:"created_at,omitempty"`
+    UpdatedAt int64  `json:"updated_at,omitempty"`
+}
+
+type licenseList struct { Data []License `json:"data"` }
+
+// ListLicenses 列出已上传的许可证（通常只有一份）
+func (c *Client) ListLicenses(ctx context.Context) ([]License, error) {
+    var lst licenseList
+    if _, err := c.getJSON(ctx, "/licenses", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// CreateLicense 上传许可证
+func (c *Client) CreateLicense(ctx context.Context, payload string) (License, error) {
+    var out License
+    if err := c.doJSON(ctx, http.MethodPost, "/licenses", License{Payload: payload}, &out); err != nil {
+        return License{}, err
+    }
+    return out, nil
+}
+
+// UpdateLicense 替换已有许可证的内容（轮换）
+func (c *Client) UpdateLicense(ctx context.Context, id, payload string) (License, error) {
+    var out License
+    if err := c.doJSON(ctx, http.MethodPatch, "/licenses/"+url.PathEscape(id), map[string]any{"payload": payload}, &out); err != nil {
+        return License{}, err
+    }
+    return out, nil
+}
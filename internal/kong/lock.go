@@ -0,0 +1,55 @@
+package kong
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// LockTag 为锁实体携带的标签，便于在 Kong Manager 中识别
+const LockTag = "kongctl-lock"
+
+// Lock 为以 upstream 实体表示的网关级互斥锁：upstream 名称唯一，创建成功即持有；
+// 持有者与获取时间记录在标签 owner:<...>、since:<unix 秒> 中
+type Lock struct {
+    ID    string
+    Owner string
+    Since time.Time
+}
+
+func lockFromUpstream(up Upstream) Lock {
+    lk := Lock{ID: up.ID}
+    for _, t := range up.Tags {
+        if v, ok := strings.CutPrefix(t, "owner:"); ok { lk.Owner = v }
+        if v, ok := strings.CutPrefix(t, "since:"); ok {
+            if n, err := strconv.ParseInt(v, 10, 64); err == nil { lk.Since = time.Unix(n, 0) }
+        }
+    }
+    return lk
+}
+
+// AcquireLock 以创建名为 name 的 upstream 获取锁；acquired=false 时返回当前持有者的锁信息
+func (c *Client) AcquireLock(ctx context.Context, name, owner string) (lk Lock, acquired bool, err error) {
+    payload := Upstream{Name: name, Tags: []string{LockTag, "owner:" + owner, "since:" + strconv.FormatInt(time.Now().Unix(), 10)}}
+    var out Upstream
+    err = c.doJSON(ctx, http.MethodPost, "/upstreams", payload, &out)
+    var apiErr *APIError
+    if errors.As(err, &apiErr) && (apiErr.Status == http.StatusConflict || strings.Contains(apiErr.Name, "unique")) {
+        var cur Upstream
+        ok, gerr := c.getJSON(ctx, "/upstreams/"+name, &cur)
+        if gerr != nil { return Lock{}, false, gerr }
+        // 持有者恰好已释放：重试一次
+        if !ok { return c.AcquireLock(ctx, name, owner) }
+        return lockFromUpstream(cur), false, nil
+    }
+    if err != nil { return Lock{}, false, err }
+    return lockFromUpstream(out), true, nil
+}
+
+// ReleaseLock 删除锁实体
+func (c *Client) ReleaseLock(ctx context.Context, lk Lock) error {
+    return c.deleteJSON(ctx, "/upstreams/"+lk.ID)
+}
@@ -0,0 +1,324 @@
+package kong
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// memoryParents 为嵌套路径 /<集合>/<key>/<子集合> 中父资源在子资源上的外键字段
+var memoryParents = map[string]string{"upstreams": "upstream", "services": "service", "routes": "route", "consumers": "consumer", "certificates": "certificate", "consumer_groups": "consumer_group", "key-sets": "set"}
+
+// MemoryAdmin 为进程内的简易 Admin API（用作 Config.Transport）：按集合保存写入的实体并响应查询，
+// 写入时补上 Kong 的常用默认值（service 拆分 url、route 的 protocols/strip_path 等）。
+// apply --against 先将快照写入其中，再以只读方式在其上计算计划，无需访问网关
+type MemoryAdmin struct {
+    mu       sync.Mutex
+    data     map[string]map[string]map[string]any // 集合 -> id -> 实体
+    order    map[string][]string                  // 集合内实体的创建顺序
+    members  map[string][]string                  // consumer group id -> 成员 consumer id
+    readOnly bool
+}
+
+func NewMemoryAdmin() *MemoryAdmin {
+    return &MemoryAdmin{data: map[string]map[string]map[string]any{}, order: map[string][]string{}, members: map[string][]string{}}
+}
+
+// SetReadOnly 设置为只读：之后的写请求均返回错误
+func (m *MemoryAdmin) SetReadOnly(ro bool) {
+    m.mu.Lock()
+    m.readOnly = ro
+    m.mu.Unlock()
+}
+
+func (m *MemoryAdmin) RoundTrip(req *http.Request) (*http.Response, error) {
+    var body map[string]any
+    if req.Body != nil {
+        b, err := io.ReadAll(req.Body)
+        req.Body.Close()
+        if err != nil { return nil, err }
+        if len(bytes.TrimSpace(b)) > 0 {
+            if err := json.Unmarshal(b, &body); err != nil { return nil, err }
+        }
+    }
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if m.readOnly && req.Method != http.MethodGet {
+        return nil, fmt.Errorf("离线快照为只读，不能执行 %s %s", req.Method, req.URL.Path)
+    }
+    code, out := m.serve(req.Method, req.URL, body)
+    var b []byte
+    if out != nil {
+        var err error
+        if b, err = json.Marshal(out); err != nil { return nil, err }
+    }
+    return &http.Response{
+        StatusCode: code,
+        Status:     strconv.Itoa(code) + " " + http.StatusText(code),
+        Header:     http.Header{"Content-Type": {"application/json"}},
+        Body:       io.NopCloser(bytes.NewReader(b)),
+        Request:    req,
+    }, nil
+}
+
+func notFound() (int, any) { return http.StatusNotFound, map[string]any{"message": "Not found"} }
+
+func (m *MemoryAdmin) serve(method string, u *url.URL, body map[string]any) (int, any) {
+    parts := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+    if len(parts) == 0 { return http.StatusOK, map[string]any{"version": "snapshot"} }
+    if body == nil { body = map[string]any{} }
+    coll := parts[0]
+    if len(parts) >= 3 && (coll == "consumer_groups" && parts[2] == "consumers" || coll == "consumers" && parts[2] == "consumer_groups") {
+        return m.serveMembers(method, parts, body)
+    }
+    var fk, parentID string
+    if len(parts) >= 3 {
+        // 嵌套集合：/upstreams/<key>/targets、/consumers/<key>/key-auth、/routes/<key>/plugins 等
+        field, ok := memoryParents[coll]
+        if !ok { return notFound() }
+        parent := m.find(coll, parts[1], "", "")
+        if parent == nil { return notFound() }
+        fk, parentID, coll = field, parent["id"].(string), parts[2]
+        parts = parts[2:]
+    }
+    switch {
+    case len(parts) == 1 && method == http.MethodGet:
+        return http.StatusOK, map[string]any{"data": m.list(coll, fk, parentID, u.Query().Get("tags")), "next": nil}
+    case len(parts) == 1 && method == http.MethodPost:
+        if fk != "" { body[fk] = map[string]any{"id": parentID} }
+        if key := entityKey(coll, body); key != "" && fk == "" && m.find(coll, key, "", "") != nil {
+            return http.StatusConflict, map[string]any{"name": "unique constraint violation", "message": fmt.Sprintf("UNIQUE violation detected on '{name=\"%s\"}'", key)}
+        }
+        o, err := m.insert(coll, body)
+        if err != nil { return http.StatusBadRequest, map[string]any{"name": "schema violation", "message": err.Error()} }
+        return http.StatusCreated, o
+    case len(parts) != 2:
+        return notFound()
+    }
+    o := m.find(coll, parts[1], fk, parentID)
+    switch method {
+    case http.MethodGet:
+        if o == nil { return notFound() }
+        return http.StatusOK, o
+    case http.MethodDelete:
+        if o == nil { return notFound() }
+        m.remove(coll, o["id"].(string))
+        return http.StatusNoContent, nil
+    case http.MethodPut, http.MethodPatch:
+        if o == nil {
+            if method == http.MethodPatch { return notFound() }
+            if fk != "" { body[fk] = map[string]any{"id": parentID} }
+            if _, ok := body["name"]; !ok && coll != "consumers" && coll != "targets" { body["name"] = parts[1] }
+            created, err := m.insert(coll, body)
+            if err != nil { return http.StatusBadRequest, map[string]any{"name": "schema violation", "message": err.Error()} }
+            return http.StatusOK, created
+        }
+        for k, v := range body { o[k] = v }
+        if err := m.normalize(coll, o); err != nil { return http.StatusBadRequest, map[string]any{"name": "schema violation", "message": err.Error()} }
+        return http.StatusOK, o
+    }
+    return http.StatusMethodNotAllowed, map[string]any{"message": "Method not allowed"}
+}
+
+// serveMembers 处理 consumer group 的成员关系：/consumer_groups/<g>/consumers[/<c>] 与 /consumers/<c>/consumer_groups
+func (m *MemoryAdmin) serveMembers(method string, parts []string, body map[string]any) (int, any) {
+    if parts[0] == "consumers" {
+        c := m.find("consumers", parts[1], "", "")
+        if c == nil || len(parts) != 3 || method != http.MethodGet { return notFound() }
+        out := []any{}
+        for _, gid := range m.order["consumer_groups"] {
+            if memberIndex(m.members[gid], c["id"].(string)) >= 0 { out = append(out, m.data["consumer_groups"][gid]) }
+        }
+        return http.StatusOK, map[string]any{"data": out, "next": nil}
+    }
+    g := m.find("consumer_groups", parts[1], "", "")
+    if g == nil || len(parts) > 4 { return notFound() }
+    gid := g["id"].(string)
+    switch {
+    case len(parts) == 3 && method == http.MethodGet:
+        out := []any{}
+        for _, cid := range m.members[gid] { out = append(out, m.data["consumers"][cid]) }
+        return http.StatusOK, map[string]any{"data": out, "next": nil}
+    case len(parts) == 3 && method == http.MethodPost:
+        key, _ := body["consumer"].(string)
+        c := m.find("consumers", key, "", "")
+        if c == nil { return http.StatusNotFound, map[string]any{"message": fmt.Sprintf("Consumer '%s' not found", key)} }
+        cid := c["id"].(string)
+        if memberIndex(m.members[gid], cid) < 0 { m.members[gid] = append(m.members[gid], cid) }
+        return http.StatusCreated, map[string]any{"consumer_group": g, "consumers": []any{c}}
+    case len(parts) == 4 && method == http.MethodDelete:
+        c := m.find("consumers", parts[3], "", "")
+        if c == nil { return notFound() }
+        i := memberIndex(m.members[gid], c["id"].(string))
+        if i < 0 { return notFound() }
+        ids := m.members[gid]
+        m.members[gid] = append(ids[:i:i], ids[i+1:]...)
+        return http.StatusNoContent, nil
+    }
+    return notFound()
+}
+
+func memberIndex(ids []string, id string) int {
+    for i, x := range ids {
+        if x == id { return i }
+    }
+    return -1
+}
+
+func entityKey(coll string, o map[string]any) string {
+    fields := []string{"name", "username"}
+    if coll == "vaults" { fields = []string{"prefix"} }
+    for _, f := range fields {
+        if s, _ := o[f].(string); s != "" { return s }
+    }
+    return ""
+}
+
+// find 按 ID、name/username/custom_id（targets 为 target 地址，vaults 为 prefix）查找实体；fk 非空时只在该父资源下查找
+func (m *MemoryAdmin) find(coll, key, fk, parentID string) map[string]any {
+    byID := m.data[coll]
+    if o, ok := byID[key]; ok && ownedBy(o, fk, parentID) { return o }
+    fields := []string{"name", "username", "custom_id", "target"}
+    if coll == "vaults" { fields = []string{"prefix"} }
+    for _, id := range m.order[coll] {
+        o := byID[id]
+        if !ownedBy(o, fk, parentID) { continue }
+        for _, f := range fields {
+            if s, _ := o[f].(string); s != "" && s == key { return o }
+        }
+    }
+    return nil
+}
+
+func ownedBy(o map[string]any, fk, parentID string) bool {
+    if fk == "" { return true }
+    ref, _ := o[fk].(map[string]any)
+    return ref != nil && ref["id"] == parentID
+}
+
+// list 按创建顺序列出集合中的实体；tags 为逗号分隔时需同时包含
+func (m *MemoryAdmin) list(coll, fk, parentID, tags string) []any {
+    out := []any{}
+    for _, id := range m.order[coll] {
+        o := m.data[coll][id]
+        if !ownedBy(o, fk, parentID) || !hasTags(o, tags) { continue }
+        out = append(out, o)
+    }
+    return out
+}
+
+func hasTags(o map[string]any, tags string) bool {
+    if tags == "" { return true }
+    have, _ := o["tags"].([]any)
+    for _, t := range strings.Split(tags, ",") {
+        found := false
+        for _, h := range have {
+            if h == t { found = true; break }
+        }
+        if !found { return false }
+    }
+    return true
+}
+
+func (m *MemoryAdmin) insert(coll string, o map[string]any) (map[string]any, error) {
+    id, _ := o["id"].(string)
+    if id == "" { id = newID() }
+    o["id"] = id
+    if err := m.normalize(coll, o); err != nil { return nil, err }
+    if m.data[coll] == nil { m.data[coll] = map[string]map[string]any{} }
+    if _, ok := m.data[coll][id]; !ok { m.order[coll] = append(m.order[coll], id) }
+    m.data[coll][id] = o
+    return o, nil
+}
+
+func (m *MemoryAdmin) remove(coll, id string) {
+    delete(m.data[coll], id)
+    switch coll {
+    case "consumer_groups":
+        delete(m.members, id)
+    case "consumers":
+        for gid, ids := range m.members {
+            if i := memberIndex(ids, id); i >= 0 { m.members[gid] = append(ids[:i:i], ids[i+1:]...) }
+        }
+    }
+    ids := m.order[coll]
+    for i, x := range ids {
+        if x == id { m.order[coll] = append(ids[:i:i], ids[i+1:]...); break }
+    }
+}
+
+// normalize 补上 Kong 保存实体时的默认值，并将按名称引用的关联资源（route.service 等）转换为 ID
+func (m *MemoryAdmin) normalize(coll string, o map[string]any) error {
+    for field, parent := range map[string]string{"service": "services", "route": "routes", "consumer": "consumers", "upstream": "upstreams"} {
+        ref, ok := o[field].(map[string]any)
+        if !ok || ref["id"] != nil { continue }
+        name, _ := ref["name"].(string)
+        p := m.find(parent, name, "", "")
+        if p == nil { return fmt.Errorf("schema violation (%s: does not exist)", field) }
+        o[field] = map[string]any{"id": p["id"]}
+    }
+    setDefault := func(k string, v any) {
+        if _, ok := o[k]; !ok { o[k] = v }
+    }
+    switch coll {
+    case "services":
+        if raw, _ := o["url"].(string); raw != "" {
+            u, err := url.Parse(raw)
+            if err != nil { return fmt.Errorf("schema violation (url: %v)", err) }
+            delete(o, "url")
+            o["protocol"], o["host"] = u.Scheme, u.Hostname()
+            port, _ := strconv.Atoi(u.Port())
+            if port == 0 {
+                port = 80
+                if u.Scheme == "https" || u.Scheme == "grpcs" || u.Scheme == "tls" { port = 443 }
+            }
+            o["port"] = port
+            if u.Path != "" { o["path"] = u.Path } else { delete(o, "path") }
+        }
+        setDefault("protocol", "http")
+        setDefault("port", 80)
+        setDefault("retries", 5)
+        for _, k := range []string{"connect_timeout", "read_timeout", "write_timeout"} { setDefault(k, 60000) }
+    case "routes":
+        setDefault("protocols", []any{"http", "https"})
+        setDefault("path_handling", "v0")
+        setDefault("regex_priority", 0)
+        setDefault("strip_path", true)
+        setDefault("preserve_host", false)
+    case "targets":
+        setDefault("weight", 100)
+    case "upstreams":
+        setDefault("algorithm", "round-robin")
+        setDefault("slots", 10000)
+    case "plugins":
+        setDefault("enabled", true)
+    }
+    return nil
+}
+
+func newID() string {
+    b := make([]byte, 16)
+    _, _ = rand.Read(b)
+    h := hex.EncodeToString(b)
+    return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// Entity 返回 path（如 /routes/<name>、/upstreams/<name>/targets/<target>）对应实体的副本
+func (m *MemoryAdmin) Entity(path string) (map[string]any, bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    code, out := m.serve(http.MethodGet, &url.URL{Path: path}, nil)
+    o, ok := out.(map[string]any)
+    if code != http.StatusOK || !ok { return nil, false }
+    cp := make(map[string]any, len(o))
+    for k, v := range o { cp[k] = v }
+    return cp, true
+}
@@ -0,0 +1,70 @@
+package kong
+
+import (
+    "encoding/json"
+    "net/url"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// prunePatch 去掉 PATCH 请求体中与当前值相同的字段，只下发真正变化的部分；
+// 返回的请求体为空时表示无需写入。字符串列表按集合比较（Kong 不保证顺序），空列表与 null 视为相同
+func prunePatch(cur any, payload map[string]any) map[string]any {
+    var curMap map[string]any
+    if b, err := json.Marshal(cur); err == nil { _ = json.Unmarshal(b, &curMap) }
+    out := map[string]any{}
+    for k, v := range payload {
+        if !samePatchValue(curMap[k], v) { out[k] = v }
+    }
+    return out
+}
+
+func samePatchValue(cur, want any) bool {
+    var w any
+    if b, err := json.Marshal(want); err != nil {
+        return false
+    } else if err := json.Unmarshal(b, &w); err != nil {
+        return false
+    }
+    return reflect.DeepEqual(canonicalPatchValue(cur), canonicalPatchValue(w))
+}
+
+// canonicalPatchValue 将 JSON 值转为便于比较的形式：空列表/空对象记为 nil，字符串列表排序
+func canonicalPatchValue(v any) any {
+    switch x := v.(type) {
+    case []any:
+        if len(x) == 0 { return nil }
+        strs := make([]string, 0, len(x))
+        for _, e := range x {
+            s, ok := e.(string)
+            if !ok {
+                out := make([]any, len(x))
+                for i, e := range x { out[i] = canonicalPatchValue(e) }
+                return out
+            }
+            strs = append(strs, s)
+        }
+        sort.Strings(strs)
+        return strs
+    case map[string]any:
+        if len(x) == 0 { return nil }
+        out := make(map[string]any, len(x))
+        for k, e := range x { out[k] = canonicalPatchValue(e) }
+        return out
+    }
+    return v
+}
+
+// serviceURLMatches 判断 Service 当前的 protocol/host/port/path 是否与 url 一致（未写端口时按协议默认端口）
+func serviceURLMatches(cur *Service, raw string) bool {
+    u, err := url.Parse(raw)
+    if err != nil || u.Host == "" { return false }
+    port := 80
+    if strings.EqualFold(u.Scheme, "https") { port = 443 }
+    if p := u.Port(); p != "" {
+        if port, err = strconv.Atoi(p); err != nil { return false }
+    }
+    return strings.EqualFold(cur.Protocol, u.Scheme) && strings.EqualFold(cur.Host, u.Hostname()) && cur.Port == port && cur.Path == u.Path
+}
@@ -0,0 +1,179 @@
+package kong
+
+import (
+    "context"
+    "net/http"
+    "net/url"
+)
+
+type Plugin struct {
+    ID       string         `json:"id,omitempty"`
+    Name     string         `json:"name,omitempty"`
+    Config   map[string]any `json:"config,omitempty"`
+    Enabled  *bool          `json:"enabled,omitempty"`
+    Tags     []string       `json:"tags,omitempty"`
+    Route    *EntityRef     `json:"route,omitempty"`
+    Service  *EntityRef     `json:"service,omitempty"`
+    Consumer *EntityRef     `json:"consumer,omitempty"`
+    ConsumerGroup *EntityRef `json:"consumer_group,omitempty"`
+}
+
+// Global 判断插件是否为全局插件（未绑定 route/service/consumer/consumer_group）
+func (p Plugin) Global() bool { return p.Route == nil && p.Service == nil && p.Consumer == nil && p.ConsumerGroup == nil }
+
+type pluginList struct { Data []Plugin `json:"data"` }
+
+// ListRoutePlugins 列出挂在指定 Route 上的插件
+func (c *Client) ListRoutePlugins(ctx context.Context, route string) ([]Plugin, error) {
+    return c.listPlugins(ctx, "/routes/"+url.PathEscape(route))
+}
+
+// ListServicePlugins 列出挂在指定 Service 上的插件
+func (c *Client) ListServicePlugins(ctx context.Context, service string) ([]Plugin, error) {
+    return c.listPlugins(ctx, "/services/"+url.PathEscape(service))
+}
+
+// ListConsumerPlugins 列出挂在指定 Consumer 上的插件
+func (c *Client) ListConsumerPlugins(ctx context.Context, consumer string) ([]Plugin, error) {
+    return c.listPlugins(ctx, "/consumers/"+url.PathEscape(consumer))
+}
+
+// ListPlugins 列出网关上的全部插件（含全局与绑定到各资源的插件）
+func (c *Client) ListPlugins(ctx context.Context) ([]Plugin, error) {
+    return c.listPlugins(ctx, "")
+}
+
+// ListGlobalPlugins 列出全局插件（未绑定 route/service/consumer/consumer_group）
+func (c *Client) ListGlobalPlugins(ctx context.Context) ([]Plugin, error) {
+    lst, err := c.listPlugins(ctx, "")
+    if err != nil {
+        return nil, err
+    }
+    var out []Plugin
+    for _, p := range lst {
+        if p.Global() { out = append(out, p) }
+    }
+    return out, nil
+}
+
+// GetPlugin 按 id 获取插件；不存在时返回 (nil, false, nil)
+func (c *Client) GetPlugin(ctx context.Context, id string) (*Plugin, bool, error) {
+    var p Plugin
+    ok, err := c.getJSON(ctx, "/plugins/"+url.PathEscape(id), &p)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &p, true, nil
+}
+
+// GetRoutePlugin 按插件名称查找 Route 上的插件；不存在时返回 (nil, false, nil)
+func (c *Client) GetRoutePlugin(ctx context.Context, route, name string) (*Plugin, bool, error) {
+    return c.findPlugin(ctx, "/routes/"+url.PathEscape(route), name)
+}
+
+// GetServicePlugin 按插件名称查找 Service 上的插件；不存在时返回 (nil, false, nil)
+func (c *Client) GetServicePlugin(ctx context.Context, service, name string) (*Plugin, bool, error) {
+    return c.findPlugin(ctx, "/services/"+url.PathEscape(service), name)
+}
+
+// GetConsumerPlugin 按插件名称查找 Consumer 上的插件；不存在时返回 (nil, false, nil)
+func (c *Client) GetConsumerPlugin(ctx context.Context, consumer, name string) (*Plugin, bool, error) {
+    return c.findPlugin(ctx, "/consumers/"+url.PathEscape(consumer), name)
+}
+
+// GetGlobalPlugin 按插件名称查找全局插件；不存在时返回 (nil, false, nil)
+func (c *Client) GetGlobalPlugin(ctx context.Context, name string) (*Plugin, bool, error) {
+    return c.findPlugin(ctx, "", name)
+}
+
+// CreateOrUpdateRoutePlugin 在 Route 上幂等创建或更新同名插件（config/enabled/tags）
+func (c *Client) CreateOrUpdateRoutePlugin(ctx context.Context, route string, desired Plugin) (string, Plugin, error) {
+    return c.createOrUpdatePlugin(ctx, "/routes/"+url.PathEscape(route), desired)
+}
+
+// CreateOrUpdateServicePlugin 在 Service 上幂等创建或更新同名插件（config/enabled/tags）
+func (c *Client) CreateOrUpdateServicePlugin(ctx context.Context, service string, desired Plugin) (string, Plugin, error) {
+    return c.createOrUpdatePlugin(ctx, "/services/"+url.PathEscape(service), desired)
+}
+
+// CreateOrUpdateConsumerPlugin 在 Consumer 上幂等创建或更新同名插件（config/enabled/tags）
+func (c *Client) CreateOrUpdateConsumerPlugin(ctx context.Context, consumer string, desired Plugin) (string, Plugin, error) {
+    return c.createOrUpdatePlugin(ctx, "/consumers/"+url.PathEscape(consumer), desired)
+}
+
+// CreateOrUpdateGlobalPlugin 幂等创建或更新同名的全局插件（config/enabled/tags）
+func (c *Client) CreateOrUpdateGlobalPlugin(ctx context.Context, desired Plugin) (string, Plugin, error) {
+    return c.createOrUpdatePlugin(ctx, "", desired)
+}
+
+// DeletePlugin 按 id 删除插件
+func (c *Client) DeletePlugin(ctx context.Context, id string) error {
+    return c.deleteJSON(ctx, "/plugins/"+id)
+}
+
+func (c *Client) listPlugins(ctx context.Context, scope string) ([]Plugin, error) {
+    var lst pluginList
+    if _, err := c.getJSON(ctx, scope+"/plugins?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// findPlugin 在 scope（如 /routes/<name>）下按名称查找插件；scope 为空时只查找全局插件
+func (c *Client) findPlugin(ctx context.Context, scope, name string) (*Plugin, bool, error) {
+    list := c.listPlugins
+    if scope == "" { list = func(ctx context.Context, _ string) ([]Plugin, error) { return c.ListGlobalPlugins(ctx) } }
+    lst, err := list(ctx, scope)
+    if err != nil {
+        return nil, false, err
+    }
+    for i := range lst {
+        if lst[i].Name == name { return &lst[i], true, nil }
+    }
+    return nil, false, nil
+}
+
+func (c *Client) createOrUpdatePlugin(ctx context.Context, scope string, desired Plugin) (string, Plugin, error) {
+    cur, ok, err := c.findPlugin(ctx, scope, desired.Name)
+    if err != nil {
+        return "", Plugin{}, err
+    }
+    var out Plugin
+    if !ok {
+        desired.Tags = c.createTags(desired.Tags)
+        if err := c.doJSON(ctx, http.MethodPost, scope+"/plugins", desired, &out); err != nil {
+            return "", Plugin{}, err
+        }
+        return "create", out, nil
+    }
+    payload := map[string]any{"config": desired.Config}
+    if desired.Enabled != nil { payload["enabled"] = *desired.Enabled }
+    if len(desired.Tags) > 0 {
+        payload["tags"] = c.withTags(desired.Tags)
+    } else if c.missingTags(cur.Tags) {
+        payload["tags"] = c.withTags(cur.Tags)
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, "/plugins/"+cur.ID, payload, &out); err != nil {
+        return "", Plugin{}, err
+    }
+    return "update", out, nil
+}
+
+// PurgeProxyCache 清除 proxy-cache 缓存：key 为空时清除全部，否则清除指定插件下的单个缓存项；
+// 缓存项不存在（404）时返回 false
+func (c *Client) PurgeProxyCache(ctx context.Context, pluginID, key string) (bool, error) {
+    path := "/proxy-cache"
+    if key != "" { path += "/" + url.PathEscape(pluginID) + "/caches/" + url.PathEscape(key) }
+    resp, err := c.do(ctx, http.MethodDelete, path, nil)
+    if err != nil {
+        return false, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode == http.StatusNotFound {
+        return false, nil
+    }
+    if resp.StatusCode/100 != 2 {
+        return false, newAPIError(resp)
+    }
+    return true, nil
+}
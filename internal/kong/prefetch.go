@@ -0,0 +1,154 @@
+package kong
+
+import (
+    "context"
+    "encoding/json"
+    "strings"
+    "sync"
+)
+
+// listPageSize 与 List* 的 size 参数一致；返回满页时视为可能存在分页，不使用该类资源的预取结果
+const listPageSize = 1000
+
+// stateCache 为 Prefetch 预取的远程状态：按名称与 ID 索引 upstreams/services/routes，
+// targets 按 upstream 在首次查询时缓存。写操作（非 GET）会使涉及的资源失效，之后的查询回落到 Admin API。
+type stateCache struct {
+    mu        sync.Mutex
+    upstreams map[string]Upstream // nil 表示未预取该类资源
+    services  map[string]Service
+    routes    map[string]Route
+    targets   map[string][]Target
+    stale     map[string]bool // "<kind>/<名称或 ID>"
+}
+
+// Prefetch 一次性列出 upstreams、services、routes 并建立索引，之后的 GetUpstream/GetService/GetRoute
+// 直接读取索引（不存在的资源也无需再请求），ListTargets 每个 upstream 只请求一次；
+// 用于 apply 等按 spec 逐项查询的场景，将 N 次查询减少为少量列表请求。
+func (c *Client) Prefetch(ctx context.Context) error {
+    ups, err := c.ListUpstreams(ctx)
+    if err != nil { return err }
+    svcs, err := c.ListServices(ctx)
+    if err != nil { return err }
+    rts, err := c.ListRoutes(ctx)
+    if err != nil { return err }
+    sc := &stateCache{targets: map[string][]Target{}, stale: map[string]bool{}}
+    if len(ups) < listPageSize {
+        sc.upstreams = map[string]Upstream{}
+        for _, u := range ups { index(sc.upstreams, u.Name, u.ID, u) }
+    }
+    if len(svcs) < listPageSize {
+        sc.services = map[string]Service{}
+        for _, s := range svcs { index(sc.services, s.Name, s.ID, s) }
+    }
+    if len(rts) < listPageSize {
+        sc.routes = map[string]Route{}
+        for _, r := range rts { index(sc.routes, r.Name, r.ID, r) }
+    }
+    c.cacheMu.Lock()
+    c.cache = sc
+    c.cacheMu.Unlock()
+    return nil
+}
+
+func index[T any](m map[string]T, name, id string, v T) {
+    if name != "" { m[name] = v }
+    if id != "" { m[id] = v }
+}
+
+// lookupCached 在预取索引中查找资源；hit=false 表示未预取或已失效，需请求 Admin API
+func lookupCached[T any](c *Client, kind, key string, pick func(*stateCache) map[string]T) (v *T, found, hit bool) {
+    c.cacheMu.Lock()
+    sc := c.cache
+    c.cacheMu.Unlock()
+    if sc == nil { return nil, false, false }
+    sc.mu.Lock()
+    defer sc.mu.Unlock()
+    m := pick(sc)
+    if m == nil || sc.stale[kind+"/"+key] { return nil, false, false }
+    cur, ok := m[key]
+    if !ok { return nil, false, true }
+    return &cur, true, true
+}
+
+func (c *Client) cachedUpstream(key string) (*Upstream, bool, bool) {
+    return lookupCached(c, "upstreams", key, func(sc *stateCache) map[string]Upstream { return sc.upstreams })
+}
+
+func (c *Client) cachedService(key string) (*Service, bool, bool) {
+    return lookupCached(c, "services", key, func(sc *stateCache) map[string]Service { return sc.services })
+}
+
+func (c *Client) cachedRoute(key string) (*Route, bool, bool) {
+    return lookupCached(c, "routes", key, func(sc *stateCache) map[string]Route { return sc.routes })
+}
+
+// cachedTargets 返回已缓存的 upstream targets
+func (c *Client) cachedTargets(upstream string) ([]Target, bool) {
+    c.cacheMu.Lock()
+    sc := c.cache
+    c.cacheMu.Unlock()
+    if sc == nil { return nil, false }
+    sc.mu.Lock()
+    defer sc.mu.Unlock()
+    list, ok := sc.targets[upstream]
+    return append([]Target(nil), list...), ok
+}
+
+func (c *Client) storeTargets(upstream string, list []Target) {
+    c.cacheMu.Lock()
+    sc := c.cache
+    c.cacheMu.Unlock()
+    if sc == nil { return }
+    sc.mu.Lock()
+    sc.targets[upstream] = append([]Target(nil), list...)
+    sc.mu.Unlock()
+}
+
+// invalidate 在写操作后使 path 与请求体（name/id）涉及的资源失效
+func (c *Client) invalidate(path string, payload []byte) {
+    c.cacheMu.Lock()
+    sc := c.cache
+    c.cacheMu.Unlock()
+    if sc == nil { return }
+    path, _, _ = strings.Cut(path, "?")
+    seg := strings.Split(strings.Trim(path, "/"), "/")
+    var body struct {
+        Name string `json:"name"`
+        ID   string `json:"id"`
+    }
+    _ = json.Unmarshal(payload, &body)
+    sc.mu.Lock()
+    defer sc.mu.Unlock()
+    mark := func(kind, key string) {
+        if key == "" { return }
+        keys := []string{key}
+        switch kind {
+        case "upstreams":
+            if u, ok := sc.upstreams[key]; ok { keys = append(keys, u.Name, u.ID) }
+            for _, k := range keys { delete(sc.targets, k) }
+        case "services":
+            if s, ok := sc.services[key]; ok { keys = append(keys, s.Name, s.ID) }
+        case "routes":
+            if r, ok := sc.routes[key]; ok { keys = append(keys, r.Name, r.ID) }
+        }
+        for _, k := range keys {
+            if k != "" { sc.stale[kind+"/"+k] = true }
+        }
+    }
+    switch {
+    case len(seg) >= 3 && seg[0] == "upstreams" && seg[2] == "targets":
+        // targets 变化后重新列出；upstream 本身不受影响
+        if u, ok := sc.upstreams[seg[1]]; ok {
+            delete(sc.targets, u.Name)
+            delete(sc.targets, u.ID)
+        }
+        delete(sc.targets, seg[1])
+    case len(seg) == 3 && seg[0] == "services" && seg[2] == "routes":
+        mark("routes", body.Name)
+        mark("routes", body.ID)
+    case len(seg) <= 2 && (seg[0] == "upstreams" || seg[0] == "services" || seg[0] == "routes"):
+        if len(seg) == 2 { mark(seg[0], seg[1]) }
+        mark(seg[0], body.Name)
+        mark(seg[0], body.ID)
+    }
+}
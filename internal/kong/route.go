@@ -31,13 +31,39 @@ type Route struct {
         ID string `json:"id,omitempty"`
         Name string `json:"name,omitempty"`
     } `json:"service,omitempty"`
+    CreatedAt int64 `json:"created_at,omitempty"`
 }
 
 type routeList struct {
     Data []Route `json:"data"`
 }
 
+// NormalizeHeaders 按 Kong 语义规范化 headers 匹配条件：
+// 头名称不区分大小写（Kong 存储为小写），同名头的值为 OR 集合；值的比较同样不区分大小写
+// （与 apply.matchHeaders 一致），仅大小写不同的值视为重复，保留首次出现的写法与顺序。
+func NormalizeHeaders(h map[string][]string) map[string][]string {
+    if len(h) == 0 {
+        return h
+    }
+    out := make(map[string][]string, len(h))
+    for k, vs := range h {
+        key := strings.ToLower(strings.TrimSpace(k))
+        for _, v := range vs {
+            dup := false
+            for _, x := range out[key] {
+                if strings.EqualFold(x, v) { dup = true; break }
+            }
+            if !dup { out[key] = append(out[key], v) }
+        }
+        if _, ok := out[key]; !ok { out[key] = []string{} }
+    }
+    return out
+}
+
 func (c *Client) GetRoute(ctx context.Context, name string) (*Route, bool, error) {
+    if cur, ok, hit := c.cachedRoute(name); hit {
+        return cur, ok, nil
+    }
     var rt Route
     resp, err := c.do(ctx, http.MethodGet, "/routes/"+name, nil)
     if err != nil {
@@ -48,7 +74,7 @@ func (c *Client) GetRoute(ctx context.Context, name string) (*Route, bool, error
         return nil, false, nil
     }
     if resp.StatusCode/100 != 2 {
-        return nil, false, fmt.Errorf("HTTP %d", resp.StatusCode)
+        return nil, false, newAPIError(resp)
     }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
@@ -62,6 +88,7 @@ func (c *Client) GetRoute(ctx context.Context, name string) (*Route, bool, error
         if len(snippet) > 256 { snippet = snippet[:256] + "..." }
         return nil, false, fmt.Errorf("解析 JSON 失败：%v。请检查 --admin-url 是否正确。响应片段：%s", err, snippet)
     }
+    rt.Headers = NormalizeHeaders(rt.Headers)
     return &rt, true, nil
 }
 
@@ -70,7 +97,7 @@ func (c *Client) ListRoutes(ctx context.Context) ([]Route, error) {
     resp, err := c.do(ctx, http.MethodGet, "/routes?size=1000", nil)
     if err != nil { return nil, err }
     defer resp.Body.Close()
-    if resp.StatusCode/100 != 2 { return nil, fmt.Errorf("HTTP %d", resp.StatusCode) }
+    if resp.StatusCode/100 != 2 { return nil, newAPIError(resp) }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
     if ct != "" && !strings.Contains(strings.ToLower(ct), "json") || (len(data) > 0 && bytes.HasPrefix(bytes.TrimSpace(data), []byte("<"))) {
@@ -84,6 +111,9 @@ func (c *Client) ListRoutes(ctx context.Context) ([]Route, error) {
         if len(snippet) > 256 { snippet = snippet[:256] + "..." }
         return nil, fmt.Errorf("解析 JSON 失败：%v。请检查 --admin-url 是否正确。响应片段：%s", err, snippet)
     }
+    for i := range lst.Data {
+        lst.Data[i].Headers = NormalizeHeaders(lst.Data[i].Headers)
+    }
     return lst.Data, nil
 }
 
@@ -104,6 +134,7 @@ func (c *Client) CreateOrUpdateRoute(ctx context.Context, desired Route) (action
         }
         desired.Service.ID = svc.ID
     }
+    desired.Headers = NormalizeHeaders(desired.Headers)
     if desired.Service.ID == "" {
         return "", Route{}, fmt.Errorf("route 需要关联 service id 或 name")
     }
@@ -112,12 +143,13 @@ func (c *Client) CreateOrUpdateRoute(ctx context.Context, desired Route) (action
         return "", Route{}, err
     } else if !ok {
         // 创建
+        desired.Tags = c.createTags(desired.Tags)
         if err := c.doJSON(ctx, http.MethodPost, "/routes", desired, &rt); err != nil {
             return "", Route{}, err
         }
         return "create", rt, nil
     } else {
-        // 更新（直接 PATCH 目标字段）
+        // 更新（PATCH 目标字段）
         payload := map[string]any{
             "hosts": desired.Hosts,
             "paths": desired.Paths,
@@ -131,19 +163,40 @@ func (c *Client) CreateOrUpdateRoute(ctx context.Context, desired Route) (action
         if desired.ResponseBuffering != nil { payload["response_buffering"] = *desired.ResponseBuffering }
         if len(desired.Headers) > 0 { payload["headers"] = desired.Headers }
         if len(desired.Snis) > 0 { payload["snis"] = desired.Snis }
-        if len(desired.Tags) > 0 { payload["tags"] = desired.Tags }
+        if len(desired.Tags) > 0 {
+            payload["tags"] = c.withTags(desired.Tags)
+        } else if c.missingTags(cur.Tags) {
+            payload["tags"] = c.withTags(cur.Tags)
+        }
         if desired.PathHandling != "" {
             payload["path_handling"] = desired.PathHandling
         }
         if desired.StripPath != nil {
             payload["strip_path"] = *desired.StripPath
         }
-        if desired.Service.ID != "" {
+        if desired.Service.ID != "" && desired.Service.ID != cur.Service.ID {
             payload["service"] = map[string]any{"id": desired.Service.ID}
         }
+        // 只下发变化的字段，全部一致时不发送 PATCH
+        if payload = prunePatch(cur, payload); len(payload) == 0 {
+            return "none", *cur, nil
+        }
         if err := c.doJSON(ctx, http.MethodPatch, "/routes/"+cur.Name, payload, &rt); err != nil {
             return "", Route{}, err
         }
         return "update", rt, nil
     }
 }
+
+// PatchRoute 按名称或 id 对路由做部分更新（payload 为待修改字段）
+func (c *Client) PatchRoute(ctx context.Context, nameOrID string, payload map[string]any) (rt Route, err error) {
+    if err := c.doJSON(ctx, http.MethodPatch, "/routes/"+nameOrID, payload, &rt); err != nil {
+        return Route{}, err
+    }
+    return rt, nil
+}
+
+// DeleteRoute 按名称或 id 删除路由
+func (c *Client) DeleteRoute(ctx context.Context, nameOrID string) error {
+    return c.deleteJSON(ctx, "/routes/"+nameOrID)
+}
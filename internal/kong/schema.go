@@ -0,0 +1,71 @@
+package kong
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+)
+
+// Info 为 Admin API 根路径返回的部分字段
+type Info struct {
+    Version  string `json:"version"`
+    Hostname string `json:"hostname,omitempty"`
+}
+
+// GetInfo 读取 Admin API 根路径（版本号等）
+func (c *Client) GetInfo(ctx context.Context) (Info, error) {
+    var info Info
+    if err := c.doJSON(ctx, http.MethodGet, "/", nil, &info); err != nil {
+        return Info{}, err
+    }
+    if info.Version == "" {
+        return Info{}, fmt.Errorf("Admin API 根路径未返回版本号")
+    }
+    return info, nil
+}
+
+// GetSchema 读取 /schemas/<entity> 的原始 JSON（entity 如 routes、services、plugins/rate-limiting）；
+// 不存在时返回 (nil, false, nil)
+func (c *Client) GetSchema(ctx context.Context, entity string) (json.RawMessage, bool, error) {
+    var raw json.RawMessage
+    ok, err := c.getJSON(ctx, "/schemas/"+entity, &raw)
+    if err != nil || !ok { return nil, ok, err }
+    return raw, true, nil
+}
+
+// SchemaField 为 Kong schema 中单个字段的定义（只解析 kongctl 校验与 plugin schema 展示用到的部分）
+type SchemaField struct {
+    Type        string                   `json:"type"`
+    Required    bool                     `json:"required,omitempty"`
+    Default     any                      `json:"default,omitempty"`
+    Description string                   `json:"description,omitempty"`
+    OneOf       []any                    `json:"one_of,omitempty"`
+    Between     []float64                `json:"between,omitempty"`
+    Elements    *SchemaField             `json:"elements,omitempty"`
+    Fields      []map[string]SchemaField `json:"fields,omitempty"` // record 类型的子字段
+}
+
+// EntitySchema 为 /schemas/<entity> 的响应；fields 为 [{"<name>": {...}}, ...] 形式的有序列表
+type EntitySchema struct {
+    Fields []map[string]SchemaField `json:"fields"`
+}
+
+// Field 返回指定名称的字段定义
+func (s EntitySchema) Field(name string) (SchemaField, bool) {
+    for _, f := range s.Fields {
+        if def, ok := f[name]; ok { return def, true }
+    }
+    return SchemaField{}, false
+}
+
+// ValidateEntity 将实体提交到 /schemas/<entity>/validate，由 Kong 按自身的 schema 校验（不写入）；
+// 网关不提供该接口时返回 supported=false，校验未通过时返回 *APIError
+func (c *Client) ValidateEntity(ctx context.Context, entity string, payload map[string]any) (supported bool, err error) {
+    resp, err := c.do(ctx, http.MethodPost, "/schemas/"+entity+"/validate", payload)
+    if err != nil { return false, err }
+    defer resp.Body.Close()
+    if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed { return false, nil }
+    if resp.StatusCode/100 != 2 { return true, newAPIError(resp) }
+    return true, nil
+}
@@ -22,6 +22,8 @@ type Service struct {
     ConnectTimeout int `json:"connect_timeout,omitempty"`
     ReadTimeout    int `json:"read_timeout,omitempty"`
     WriteTimeout   int `json:"write_timeout,omitempty"`
+    Tags     []string `json:"tags,omitempty"`
+    CreatedAt int64  `json:"created_at,omitempty"`
 }
 
 type serviceList struct {
@@ -30,6 +32,9 @@ type serviceList struct {
 
 // GetService 通过名称查询 Service（若不存在返回 (nil, false, nil)）
 func (c *Client) GetService(ctx context.Context, name string) (*Service, bool, error) {
+    if cur, ok, hit := c.cachedService(name); hit {
+        return cur, ok, nil
+    }
     var svc Service
     resp, err := c.do(ctx, http.MethodGet, "/services/"+name, nil)
     if err != nil {
@@ -40,7 +45,7 @@ func (c *Client) GetService(ctx context.Context, name string) (*Service, bool, e
         return nil, false, nil
     }
     if resp.StatusCode/100 != 2 {
-        return nil, false, fmt.Errorf("HTTP %d", resp.StatusCode)
+        return nil, false, newAPIError(resp)
     }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
@@ -62,7 +67,7 @@ func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
     resp, err := c.do(ctx, http.MethodGet, "/services?size=1000", nil)
     if err != nil { return nil, err }
     defer resp.Body.Close()
-    if resp.StatusCode/100 != 2 { return nil, fmt.Errorf("HTTP %d", resp.StatusCode) }
+    if resp.StatusCode/100 != 2 { return nil, newAPIError(resp) }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
     if ct != "" && !strings.Contains(strings.ToLower(ct), "json") || (len(data) > 0 && bytes.HasPrefix(bytes.TrimSpace(data), []byte("<"))) {
@@ -88,16 +93,20 @@ func (c *Client) CreateOrUpdateService(ctx context.Context, name, url string) (a
         return "", Service{}, err
     } else if !ok {
         // 创建
-        payload := Service{Name: name, URL: url}
+        payload := Service{Name: name, URL: url, Tags: c.createTags(nil)}
         if err := c.doJSON(ctx, http.MethodPost, "/services", payload, &svc); err != nil {
             return "", Service{}, err
         }
         return "create", svc, nil
     } else {
-        // 更新：若 URL 相同则跳过
-        // 注意：GET 返回可能没有 url 字段，Kong 会拆成 protocol/host/port/path；这里直接 patch url
-        payload := map[string]any{"url": url}
-        // 简易策略：直接 PATCH，若无变更 Kong 会返回 200
+        // 更新：GET 返回没有 url 字段（Kong 拆成 protocol/host/port/path），按拆分后的字段比较，
+        // URL 与标签均无变化时不发送 PATCH
+        payload := map[string]any{}
+        if !serviceURLMatches(cur, url) { payload["url"] = url }
+        if c.missingTags(cur.Tags) { payload["tags"] = c.withTags(cur.Tags) }
+        if len(payload) == 0 {
+            return "none", *cur, nil
+        }
         if err := c.doJSON(ctx, http.MethodPatch, "/services/"+cur.Name, payload, &svc); err != nil {
             return "", Service{}, err
         }
@@ -113,10 +122,11 @@ func (c *Client) CreateOrUpdateServiceViaUpstream(ctx context.Context, name, ups
     if protocol == "" { protocol = "http" }
     if port == 0 { if protocol == "https" { port = 443 } else { port = 80 } }
 
-    if _, ok, err := c.GetService(ctx, name); err != nil {
+    cur, ok, err := c.GetService(ctx, name)
+    if err != nil {
         return "", Service{}, err
     } else if !ok {
-        payload := Service{Name: name, Protocol: protocol, Host: upstreamName, Port: port, Path: path}
+        payload := Service{Name: name, Protocol: protocol, Host: upstreamName, Port: port, Path: path, Tags: c.createTags(nil)}
         if err := c.doJSON(ctx, http.MethodPost, "/services", payload, &svc); err != nil {
             return "", Service{}, err
         }
@@ -128,6 +138,11 @@ func (c *Client) CreateOrUpdateServiceViaUpstream(ctx context.Context, name, ups
         "port": port,
         "path": path,
     }
+    if c.missingTags(cur.Tags) { payload["tags"] = c.withTags(cur.Tags) }
+    // 只下发变化的字段，全部一致时不发送 PATCH
+    if payload = prunePatch(cur, payload); len(payload) == 0 {
+        return "none", *cur, nil
+    }
     if err := c.doJSON(ctx, http.MethodPatch, "/services/"+name, payload, &svc); err != nil {
         return "", Service{}, err
     }
@@ -149,3 +164,24 @@ func (c *Client) UpdateServiceExtras(ctx context.Context, name string, retries,
     }
     return svc, nil
 }
+
+// UpdateServiceTags 以 PATCH 替换 Service 的标签（保留 Config.Tags）
+func (c *Client) UpdateServiceTags(ctx context.Context, name string, tags []string) (svc Service, err error) {
+    if err := c.doJSON(ctx, http.MethodPatch, "/services/"+name, map[string]any{"tags": c.withTags(tags)}, &svc); err != nil {
+        return Service{}, err
+    }
+    return svc, nil
+}
+
+// PatchService 按名称或 id 对 Service 做部分更新（payload 为待修改字段）
+func (c *Client) PatchService(ctx context.Context, nameOrID string, payload map[string]any) (svc Service, err error) {
+    if err := c.doJSON(ctx, http.MethodPatch, "/services/"+nameOrID, payload, &svc); err != nil {
+        return Service{}, err
+    }
+    return svc, nil
+}
+
+// DeleteService 按名称或 id 删除 Service（需先删除其下的 routes）
+func (c *Client) DeleteService(ctx context.Context, nameOrID string) error {
+    return c.deleteJSON(ctx, "/services/"+nameOrID)
+}
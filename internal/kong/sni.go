@@ -0,0 +1,53 @@
+package kong
+
+import (
+    "context"
+    "net/http"
+    "net/url"
+)
+
+// SNI 为绑定到证书的服务器名称（TLS 握手时按 SNI 选择证书），name 全局唯一
+type SNI struct {
+    ID          string     `json:"id,omitempty"`
+    Name        string     `json:"name"`
+    Certificate *EntityRef `json:"certificate,omitempty"`
+    Tags        []string   `json:"tags,omitempty"`
+}
+
+type sniList struct { Data []SNI `json:"data"` }
+
+// ListSNIs 列出 SNI；certificate 非空时只列出该证书上的 SNI（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListSNIs(ctx context.Context, certificate string) ([]SNI, error) {
+    path := "/snis?size=1000"
+    if certificate != "" { path = "/certificates/" + url.PathEscape(certificate) + "/snis?size=1000" }
+    var lst sniList
+    if _, err := c.getJSON(ctx, path, &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// GetSNI 按名称或 id 获取 SNI
+func (c *Client) GetSNI(ctx context.Context, name string) (*SNI, bool, error) {
+    var s SNI
+    ok, err := c.getJSON(ctx, "/snis/"+url.PathEscape(name), &s)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &s, true, nil
+}
+
+// CreateSNI 将服务器名称绑定到证书
+func (c *Client) CreateSNI(ctx context.Context, name, certificate string, tags []string) (SNI, error) {
+    payload := SNI{Name: name, Certificate: &EntityRef{ID: certificate}, Tags: c.createTags(tags)}
+    var out SNI
+    if err := c.doJSON(ctx, http.MethodPost, "/snis", payload, &out); err != nil {
+        return SNI{}, err
+    }
+    return out, nil
+}
+
+// DeleteSNI 按名称或 id 删除 SNI（证书保留）
+func (c *Client) DeleteSNI(ctx context.Context, name string) error {
+    return c.deleteJSON(ctx, "/snis/"+url.PathEscape(name))
+}
@@ -14,13 +14,16 @@ type Target struct {
     ID     string `json:"id,omitempty"`
     Target string `json:"target"` // host:port
     Weight int    `json:"weight,omitempty"`
+    Tags   []string `json:"tags,omitempty"`
+    CreatedAt float64 `json:"created_at,omitempty"`
 }
 
-func (c *Client) AddTarget(ctx context.Context, upstreamName, target string, weight int) (Target, error) {
+// AddTarget 向 upstream 添加 target；tags 可携带 zone=<区域> 等元数据
+func (c *Client) AddTarget(ctx context.Context, upstreamName, target string, weight int, tags ...string) (Target, error) {
     if upstreamName == "" || target == "" {
         return Target{}, fmt.Errorf("必须提供 upstream 与 target")
     }
-    payload := Target{Target: target, Weight: weight}
+    payload := Target{Target: target, Weight: weight, Tags: c.createTags(tags)}
     var out Target
     if err := c.doJSON(ctx, http.MethodPost, "/upstreams/"+upstreamName+"/targets", payload, &out); err != nil {
         return Target{}, err
@@ -30,11 +33,22 @@ func (c *Client) AddTarget(ctx context.Context, upstreamName, target string, wei
 
 type targetList struct { Data []Target `json:"data"` }
 
+// ListTargets 列出 upstream 的 targets；同一地址存在多条历史记录时只保留最新一条
 func (c *Client) ListTargets(ctx context.Context, upstreamName string) ([]Target, error) {
+    list, err := c.ListTargetRecords(ctx, upstreamName)
+    if err != nil { return nil, err }
+    latest, _ := CollapseTargets(list)
+    return latest, nil
+}
+
+// ListTargetRecords 列出 upstream 下的全部 target 记录；在 targets 仅追加不修改的 Kong 版本上，
+// 同一地址的每次权重变更都会留下一条记录
+func (c *Client) ListTargetRecords(ctx context.Context, upstreamName string) ([]Target, error) {
+    if list, ok := c.cachedTargets(upstreamName); ok { return list, nil }
     resp, err := c.do(ctx, http.MethodGet, "/upstreams/"+upstreamName+"/targets", nil)
     if err != nil { return nil, err }
     defer resp.Body.Close()
-    if resp.StatusCode/100 != 2 { return nil, fmt.Errorf("HTTP %d", resp.StatusCode) }
+    if resp.StatusCode/100 != 2 { return nil, newAPIError(resp) }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
     if ct != "" && !strings.Contains(strings.ToLower(ct), "json") || (len(data) > 0 && bytes.HasPrefix(bytes.TrimSpace(data), []byte("<"))) {
@@ -48,19 +62,107 @@ func (c *Client) ListTargets(ctx context.Context, upstreamName string) ([]Target
         if len(snippet) > 256 { snippet = snippet[:256] + "..." }
         return nil, fmt.Errorf("解析 JSON 失败：%v。请检查 --admin-url 是否正确。响应片段：%s", err, snippet)
     }
+    c.storeTargets(upstreamName, tl.Data)
     return tl.Data, nil
 }
 
-// EnsureTarget 若不存在则添加；若存在且权重不同，再添加同名 Target 以覆盖（Kong 将采用最新记录）。
-func (c *Client) EnsureTarget(ctx context.Context, upstreamName, target string, weight int) (added bool, err error) {
+// CollapseTargets 将同一地址的多条记录合并为最新一条（created_at 最大，相同时取列表中靠后的），
+// 保持各地址首次出现的顺序；dups 为存在重复的地址及其记录数
+func CollapseTargets(list []Target) (latest []Target, dups map[string]int) {
+    pos := map[string]int{}
+    count := map[string]int{}
+    for _, t := range list {
+        count[t.Target]++
+        i, ok := pos[t.Target]
+        if !ok {
+            pos[t.Target] = len(latest)
+            latest = append(latest, t)
+        } else if t.CreatedAt >= latest[i].CreatedAt {
+            latest[i] = t
+        }
+    }
+    for addr, n := range count {
+        if n > 1 {
+            if dups == nil { dups = map[string]int{} }
+            dups[addr] = n
+        }
+    }
+    return latest, dups
+}
+
+// EnsureTarget 若不存在则添加；若存在且权重不同，再添加同名 Target 以覆盖（Kong 将采用最新记录）；
+// 权重相同但 tags 中的 zone= 标签不同时，通过 UpdateTargetTags 修改该标签。
+func (c *Client) EnsureTarget(ctx context.Context, upstreamName, target string, weight int, tags ...string) (added bool, err error) {
     list, err := c.ListTargets(ctx, upstreamName)
     if err != nil { return false, err }
+    zone := TargetZone(tags)
     for i := range list {
         if list[i].Target == target && (list[i].Weight == weight || weight == 0) {
-            return false, nil
+            if zone == "" || TargetZone(list[i].Tags) == zone {
+                return false, nil
+            }
+            _, err := c.UpdateTargetTags(ctx, upstreamName, list[i].ID, WithTargetZone(list[i].Tags, zone))
+            return false, err
         }
     }
-    _, err = c.AddTarget(ctx, upstreamName, target, weight)
+    _, err = c.AddTarget(ctx, upstreamName, target, weight, tags...)
     if err != nil { return false, err }
     return true, nil
 }
+
+// UpdateTargetWeight 修改已有 target 的权重（按 id 或 host:port）
+func (c *Client) UpdateTargetWeight(ctx context.Context, upstreamName, target string, weight int) (Target, error) {
+    var out Target
+    if err := c.doJSON(ctx, http.MethodPatch, "/upstreams/"+upstreamName+"/targets/"+target, map[string]any{"weight": weight}, &out); err != nil {
+        return Target{}, err
+    }
+    return out, nil
+}
+
+// UpdateTargetTags 以 PATCH 替换 target 的标签（按 id 或 host:port）
+func (c *Client) UpdateTargetTags(ctx context.Context, upstreamName, target string, tags []string) (Target, error) {
+    var out Target
+    if err := c.doJSON(ctx, http.MethodPatch, "/upstreams/"+upstreamName+"/targets/"+target, map[string]any{"tags": tags}, &out); err != nil {
+        return Target{}, err
+    }
+    return out, nil
+}
+
+// TargetZone 返回 target 标签中 zone=<区域> 的取值
+func TargetZone(tags []string) string {
+    for _, t := range tags {
+        if z, ok := strings.CutPrefix(t, "zone="); ok { return z }
+    }
+    return ""
+}
+
+// WithTargetZone 返回将 zone= 标签替换为 zone 后的标签副本
+func WithTargetZone(tags []string, zone string) []string {
+    out := make([]string, 0, len(tags)+1)
+    for _, t := range tags {
+        if !strings.HasPrefix(t, "zone=") { out = append(out, t) }
+    }
+    return append(out, "zone="+zone)
+}
+
+// DeleteTarget 从 upstream 中删除 target（按 id 或 host:port）
+func (c *Client) DeleteTarget(ctx context.Context, upstreamName, target string) error {
+    return c.deleteJSON(ctx, "/upstreams/"+upstreamName+"/targets/"+target)
+}
+
+// TargetHealth 为 /upstreams/{name}/health 中单个 target 的健康状态
+type TargetHealth struct {
+    Target string `json:"target"`
+    Weight int    `json:"weight"`
+    // Health 取值：HEALTHY、UNHEALTHY、DNS_ERROR、HEALTHCHECKS_OFF
+    Health string `json:"health"`
+}
+
+// UpstreamHealth 读取 upstream 下各 target 的健康状态（不走缓存）
+func (c *Client) UpstreamHealth(ctx context.Context, upstreamName string) ([]TargetHealth, error) {
+    var out struct { Data []TargetHealth `json:"data"` }
+    ok, err := c.getJSON(ctx, "/upstreams/"+upstreamName+"/health", &out)
+    if err != nil { return nil, err }
+    if !ok { return nil, fmt.Errorf("upstream 不存在：%s", upstreamName) }
+    return out.Data, nil
+}
@@ -11,13 +11,41 @@ import (
 )
 
 type Upstream struct {
-    ID   string `json:"id,omitempty"`
-    Name string `json:"name,omitempty"`
+    ID   string   `json:"id,omitempty"`
+    Name string   `json:"name,omitempty"`
+    Tags []string `json:"tags,omitempty"`
+    // HostHeader 为代理请求与主动健康检查探测使用的 Host 头
+    HostHeader        string        `json:"host_header,omitempty"`
+    ClientCertificate *EntityRef    `json:"client_certificate,omitempty"` // 向后端发起 mTLS 时使用的客户端证书
+    Healthchecks      *Healthchecks `json:"healthchecks,omitempty"`
+    Algorithm         string        `json:"algorithm,omitempty"`
+    CreatedAt         int64         `json:"created_at,omitempty"`
+}
+
+// EntityRef 为外键引用（{"id": "..."}）
+type EntityRef struct {
+    ID string `json:"id"`
+}
+
+// Healthchecks 为 upstream 健康检查配置中 kongctl 管理的部分
+type Healthchecks struct {
+    Active *ActiveHealthcheck `json:"active,omitempty"`
+}
+
+// ActiveHealthcheck 为主动健康检查的探测方式；Kong 默认 type=http、http_path=/、https_verify_certificate=true
+type ActiveHealthcheck struct {
+    Type                   string  `json:"type,omitempty"` // http/https/tcp/grpc/grpcs
+    HTTPPath               string  `json:"http_path,omitempty"`
+    HTTPSVerifyCertificate *bool   `json:"https_verify_certificate,omitempty"`
+    HTTPSSni               *string `json:"https_sni,omitempty"`
 }
 
 type upstreamList struct { Data []Upstream `json:"data"` }
 
 func (c *Client) GetUpstream(ctx context.Context, name string) (*Upstream, bool, error) {
+    if cur, ok, hit := c.cachedUpstream(name); hit {
+        return cur, ok, nil
+    }
     var up Upstream
     resp, err := c.do(ctx, http.MethodGet, "/upstreams/"+name, nil)
     if err != nil {
@@ -28,7 +56,7 @@ func (c *Client) GetUpstream(ctx context.Context, name string) (*Upstream, bool,
         return nil, false, nil
     }
     if resp.StatusCode/100 != 2 {
-        return nil, false, fmt.Errorf("HTTP %d", resp.StatusCode)
+        return nil, false, newAPIError(resp)
     }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
@@ -49,17 +77,25 @@ func (c *Client) CreateOrUpdateUpstream(ctx context.Context, name string) (strin
     if name == "" {
         return "", Upstream{}, fmt.Errorf("upstream 名称不能为空")
     }
-    if _, ok, err := c.GetUpstream(ctx, name); err != nil {
+    cur, ok, err := c.GetUpstream(ctx, name)
+    if err != nil {
         return "", Upstream{}, err
     } else if !ok {
-        payload := Upstream{Name: name}
+        payload := Upstream{Name: name, Tags: c.createTags(nil)}
         var out Upstream
         if err := c.doJSON(ctx, http.MethodPost, "/upstreams", payload, &out); err != nil {
             return "", Upstream{}, err
         }
         return "create", out, nil
     }
-    // 简化：存在则认为已同步（如需变更哈希策略可扩展 PATCH）
+    // 简化：存在则认为已同步（如需变更哈希策略可扩展 PATCH）；仅补齐缺失的标签
+    if c.missingTags(cur.Tags) {
+        var out Upstream
+        if err := c.doJSON(ctx, http.MethodPatch, "/upstreams/"+name, map[string]any{"tags": c.withTags(cur.Tags)}, &out); err != nil {
+            return "", Upstream{}, err
+        }
+        return "update", out, nil
+    }
     return "update", Upstream{Name: name}, nil
 }
 
@@ -68,7 +104,7 @@ func (c *Client) ListUpstreams(ctx context.Context) ([]Upstream, error) {
     resp, err := c.do(ctx, http.MethodGet, "/upstreams?size=1000", nil)
     if err != nil { return nil, err }
     defer resp.Body.Close()
-    if resp.StatusCode/100 != 2 { return nil, fmt.Errorf("HTTP %d", resp.StatusCode) }
+    if resp.StatusCode/100 != 2 { return nil, newAPIError(resp) }
     data, _ := io.ReadAll(resp.Body)
     ct := resp.Header.Get("Content-Type")
     if ct != "" && !strings.Contains(strings.ToLower(ct), "json") || (len(data) > 0 && bytes.HasPrefix(bytes.TrimSpace(data), []byte("<"))) {
@@ -84,3 +120,17 @@ func (c *Client) ListUpstreams(ctx context.Context) ([]Upstream, error) {
     }
     return lst.Data, nil
 }
+
+// PatchUpstream 按名称或 id 对 Upstream 做部分更新（payload 为待修改字段）
+func (c *Client) PatchUpstream(ctx context.Context, nameOrID string, payload map[string]any) (Upstream, error) {
+    var out Upstream
+    if err := c.doJSON(ctx, http.MethodPatch, "/upstreams/"+nameOrID, payload, &out); err != nil {
+        return Upstream{}, err
+    }
+    return out, nil
+}
+
+// DeleteUpstream 按名称或 id 删除 Upstream（其下 targets 一并删除）
+func (c *Client) DeleteUpstream(ctx context.Context, nameOrID string) error {
+    return c.deleteJSON(ctx, "/upstreams/"+nameOrID)
+}
@@ -0,0 +1,78 @@
+package kong
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/url"
+)
+
+// Vault 为 Kong 3.x 的 secret 后端配置：name 为后端类型（env、hcv、aws、gcp 等），prefix 全局唯一，
+// 其他实体通过 {vault://<prefix>/<key>} 引用其中的 secret
+type Vault struct {
+    ID          string         `json:"id,omitempty"`
+    Name        string         `json:"name,omitempty"`
+    Prefix      string         `json:"prefix,omitempty"`
+    Description string         `json:"description,omitempty"`
+    Config      map[string]any `json:"config,omitempty"`
+    Tags        []string       `json:"tags,omitempty"`
+}
+
+type vaultList struct { Data []Vault `json:"data"` }
+
+// ListVaults 列出所有 vault（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListVaults(ctx context.Context) ([]Vault, error) {
+    var lst vaultList
+    if _, err := c.getJSON(ctx, "/vaults?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// GetVault 按 prefix 或 id 查询 vault
+func (c *Client) GetVault(ctx context.Context, prefixOrID string) (*Vault, bool, error) {
+    var v Vault
+    ok, err := c.getJSON(ctx, "/vaults/"+url.PathEscape(prefixOrID), &v)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &v, true, nil
+}
+
+// CreateOrUpdateVault 按 prefix 幂等创建 vault；已存在时以 PATCH 下发 name、description、config（只含给出的字段）与 tags，
+// 是否需要更新由调用方比较后决定
+func (c *Client) CreateOrUpdateVault(ctx context.Context, desired Vault) (string, Vault, error) {
+    if desired.Prefix == "" {
+        return "", Vault{}, fmt.Errorf("vault prefix 不能为空")
+    }
+    cur, ok, err := c.GetVault(ctx, desired.Prefix)
+    if err != nil {
+        return "", Vault{}, err
+    }
+    var out Vault
+    if !ok {
+        desired.Tags = c.createTags(desired.Tags)
+        if err := c.doJSON(ctx, http.MethodPost, "/vaults", desired, &out); err != nil {
+            return "", Vault{}, err
+        }
+        return "create", out, nil
+    }
+    payload := map[string]any{}
+    if desired.Name != "" { payload["name"] = desired.Name }
+    if desired.Description != "" { payload["description"] = desired.Description }
+    if len(desired.Config) > 0 { payload["config"] = desired.Config }
+    if len(desired.Tags) > 0 {
+        payload["tags"] = c.withTags(desired.Tags)
+    } else if c.missingTags(cur.Tags) {
+        payload["tags"] = c.withTags(cur.Tags)
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, "/vaults/"+cur.ID, payload, &out); err != nil {
+        return "", Vault{}, err
+    }
+    return "update", out, nil
+}
+
+// DeleteVault 按 prefix 或 id 删除 vault（引用它的 {vault://...} 将无法解析）
+func (c *Client) DeleteVault(ctx context.Context, prefixOrID string) error {
+    return c.deleteJSON(ctx, "/vaults/"+url.PathEscape(prefixOrID))
+}
@@ -0,0 +1,69 @@
+package kong
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/url"
+)
+
+// Workspace 为企业版的 workspace：name 全局唯一，其他实体的路径以 /<name> 为前缀
+type Workspace struct {
+    ID      string         `json:"id,omitempty"`
+    Name    string         `json:"name"`
+    Comment string         `json:"comment,omitempty"`
+    Meta    map[string]any `json:"meta,omitempty"`
+}
+
+type workspaceList struct { Data []Workspace `json:"data"` }
+
+// ListWorkspaces 列出所有 workspace（简单版，不处理分页，默认 size=1000）
+func (c *Client) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+    var lst workspaceList
+    if _, err := c.getJSON(ctx, "/workspaces?size=1000", &lst); err != nil {
+        return nil, err
+    }
+    return lst.Data, nil
+}
+
+// GetWorkspace 按名称或 id 查询 workspace
+func (c *Client) GetWorkspace(ctx context.Context, name string) (*Workspace, bool, error) {
+    var ws Workspace
+    ok, err := c.getJSON(ctx, "/workspaces/"+url.PathEscape(name), &ws)
+    if err != nil || !ok {
+        return nil, ok, err
+    }
+    return &ws, true, nil
+}
+
+// EnsureWorkspace 按名称幂等创建 workspace；已存在且 comment 不同时更新 comment（为空时不修改）。返回 create/update/none
+func (c *Client) EnsureWorkspace(ctx context.Context, desired Workspace) (string, Workspace, error) {
+    if desired.Name == "" {
+        return "", Workspace{}, fmt.Errorf("workspace 名称不能为空")
+    }
+    cur, ok, err := c.GetWorkspace(ctx, desired.Name)
+    if err != nil {
+        return "", Workspace{}, err
+    }
+    var out Workspace
+    if !ok {
+        if err := c.doJSON(ctx, http.MethodPost, "/workspaces", desired, &out); err != nil {
+            return "", Workspace{}, err
+        }
+        return "create", out, nil
+    }
+    if desired.Comment == "" || desired.Comment == cur.Comment {
+        return "none", *cur, nil
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, "/workspaces/"+cur.ID, map[string]any{"comment": desired.Comment}, &out); err != nil {
+        return "", Workspace{}, err
+    }
+    return "update", out, nil
+}
+
+// DeleteWorkspace 按名称或 id 删除 workspace；Kong 拒绝删除仍包含实体的 workspace，cascade 时一并删除其中的实体（Kong 3.x）
+func (c *Client) DeleteWorkspace(ctx context.Context, name string, cascade bool) error {
+    path := "/workspaces/" + url.PathEscape(name)
+    if cascade { path += "?cascade=true" }
+    return c.deleteJSON(ctx, path)
+}
@@ -0,0 +1,179 @@
+// Package testutil 通过 docker CLI 启动一次性的 Kong（Postgres 模式），供 kongctl selftest 等集成验证使用
+package testutil
+
+import (
+    "bytes"
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "os/exec"
+    "strings"
+    "time"
+)
+
+// Label 为本包创建的容器与网络携带的标签，进程被强制终止时可据此手动清理：
+// docker rm -f $(docker ps -aq --filter label=kongctl.selftest)
+const Label = "kongctl.selftest"
+
+// Options 为启动参数；零值字段使用默认值
+type Options struct {
+    Image         string                        // Kong 镜像，默认 kong:3.4
+    PostgresImage string                        // Postgres 镜像，默认 postgres:13
+    Logf          func(format string, a ...any) // 进度输出，可为 nil
+}
+
+// Kong 为已启动的一次性 Kong 实例
+type Kong struct {
+    AdminURL string
+    ProxyURL string
+    Version  string
+    Image    string
+
+    Network    string
+    containers []string
+}
+
+// DockerAvailable 检查 docker CLI 可用且能连接到 daemon
+func DockerAvailable(ctx context.Context) error {
+    if _, err := exec.LookPath("docker"); err != nil {
+        return fmt.Errorf("未找到 docker 命令：%w", err)
+    }
+    if _, err := docker(ctx, "version", "--format", "{{.Server.Version}}"); err != nil {
+        return fmt.Errorf("无法连接 docker daemon：%w", err)
+    }
+    return nil
+}
+
+// docker 执行 docker CLI 并返回去除首尾空白的标准输出；失败时错误中带上 stderr
+func docker(ctx context.Context, args ...string) (string, error) {
+    var stdout, stderr bytes.Buffer
+    c := exec.CommandContext(ctx, "docker", args...)
+    c.Stdout, c.Stderr = &stdout, &stderr
+    if err := c.Run(); err != nil {
+        if msg := strings.TrimSpace(stderr.String()); msg != "" {
+            return "", fmt.Errorf("docker %s：%s", args[0], msg)
+        }
+        return "", fmt.Errorf("docker %s：%w", args[0], err)
+    }
+    return strings.TrimSpace(stdout.String()), nil
+}
+
+// StartKong 创建独立网络，依次启动 Postgres、执行 migrations bootstrap 并启动 Kong，
+// 直到 Admin API 可访问后返回；失败时已创建的资源会被清理
+func StartKong(ctx context.Context, opts Options) (_ *Kong, err error) {
+    if opts.Image == "" { opts.Image = "kong:3.4" }
+    if opts.PostgresImage == "" { opts.PostgresImage = "postgres:13" }
+    logf := opts.Logf
+    if logf == nil { logf = func(string, ...any) {} }
+
+    id := make([]byte, 4)
+    _, _ = rand.Read(id)
+    prefix := "kongctl-selftest-" + hex.EncodeToString(id)
+    k := &Kong{Image: opts.Image, Network: prefix}
+    defer func() {
+        if err != nil { k.Close() }
+    }()
+
+    if _, err := docker(ctx, "network", "create", "--label", Label, k.Network); err != nil {
+        return nil, err
+    }
+    pg := prefix + "-db"
+    logf("启动 Postgres（%s）", opts.PostgresImage)
+    if _, err := docker(ctx, "run", "-d", "--name", pg, "--label", Label, "--network", k.Network,
+        "-e", "POSTGRES_USER=kong", "-e", "POSTGRES_DB=kong", "-e", "POSTGRES_PASSWORD=kong", opts.PostgresImage); err != nil {
+        return nil, err
+    }
+    k.containers = append(k.containers, pg)
+    // 初始化阶段的临时实例只监听 unix socket，经 TCP 检查才能确认正式实例已就绪
+    if err := poll(ctx, func() error {
+        _, err := docker(ctx, "exec", pg, "pg_isready", "-h", "127.0.0.1", "-U", "kong")
+        return err
+    }); err != nil {
+        return nil, fmt.Errorf("等待 Postgres 就绪失败：%w", err)
+    }
+
+    env := []string{"-e", "KONG_DATABASE=postgres", "-e", "KONG_PG_HOST=" + pg, "-e", "KONG_PG_USER=kong", "-e", "KONG_PG_PASSWORD=kong"}
+    logf("执行 kong migrations bootstrap（%s）", opts.Image)
+    args := append([]string{"run", "--rm", "--label", Label, "--network", k.Network}, env...)
+    if _, err := docker(ctx, append(args, opts.Image, "kong", "migrations", "bootstrap")...); err != nil {
+        return nil, err
+    }
+
+    name := prefix + "-kong"
+    logf("启动 Kong（%s）", opts.Image)
+    args = append([]string{"run", "-d", "--name", name, "--label", Label, "--network", k.Network,
+        "-e", "KONG_ADMIN_LISTEN=0.0.0.0:8001", "-e", "KONG_PROXY_LISTEN=0.0.0.0:8000",
+        "-p", "127.0.0.1::8001", "-p", "127.0.0.1::8000"}, env...)
+    if _, err := docker(ctx, append(args, opts.Image)...); err != nil {
+        return nil, err
+    }
+    k.containers = append(k.containers, name)
+    for _, p := range []struct {
+        port string
+        dst  *string
+    }{{"8001/tcp", &k.AdminURL}, {"8000/tcp", &k.ProxyURL}} {
+        addr, err := docker(ctx, "port", name, p.port)
+        if err != nil {
+            return nil, err
+        }
+        // 同时映射 IPv4/IPv6 时输出多行，取第一行
+        *p.dst = "http://" + strings.SplitN(addr, "\n", 2)[0]
+    }
+    if err := poll(ctx, k.readVersion); err != nil {
+        logs, _ := docker(context.Background(), "logs", "--tail", "20", name)
+        return nil, fmt.Errorf("等待 Kong Admin API 就绪失败：%w\n%s", err, logs)
+    }
+    return k, nil
+}
+
+// readVersion 读取 Admin API 根路径返回的版本号
+func (k *Kong) readVersion() error {
+    resp, err := (&http.Client{Timeout: 5 * time.Second}).Get(k.AdminURL + "/")
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return fmt.Errorf("HTTP %d", resp.StatusCode)
+    }
+    var info struct{ Version string `json:"version"` }
+    if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+        return err
+    }
+    k.Version = info.Version
+    return nil
+}
+
+// poll 每秒重试 fn，直到成功或 ctx 结束（返回最后一次的错误）
+func poll(ctx context.Context, fn func() error) error {
+    for {
+        err := fn()
+        if err == nil {
+            return nil
+        }
+        select {
+        case <-ctx.Done():
+            return errors.Join(ctx.Err(), err)
+        case <-time.After(time.Second):
+        }
+    }
+}
+
+// Close 删除实例的容器与网络；调用方的 ctx 此时可能已取消，因此使用独立的超时
+func (k *Kong) Close() error {
+    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+    defer cancel()
+    var errs []error
+    if len(k.containers) > 0 {
+        if _, err := docker(ctx, append([]string{"rm", "-f", "-v"}, k.containers...)...); err != nil { errs = append(errs, err) }
+    }
+    if _, err := docker(ctx, "network", "rm", k.Network); err != nil { errs = append(errs, err) }
+    return errors.Join(errs...)
+}
+
+// Names 返回实例的容器名（--keep 时提示用户手动清理）
+func (k *Kong) Names() []string { return append([]string(nil), k.containers...) }